@@ -1,75 +1,293 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
 	"os"
-	"strings"
+	"os/signal"
+	"runtime/debug"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 
 	"github.com/deividr/zion-api/internal/application/use-cases/upload"
+	"github.com/deividr/zion-api/internal/config"
 	"github.com/deividr/zion-api/internal/controller"
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/infra/barcode"
+	"github.com/deividr/zion-api/internal/infra/buildinfo"
 	"github.com/deividr/zion-api/internal/infra/database"
+	"github.com/deividr/zion-api/internal/infra/escpos"
+	"github.com/deividr/zion-api/internal/infra/events"
 	ordersControllers "github.com/deividr/zion-api/internal/infra/factory/controllers/orders"
 	"github.com/deividr/zion-api/internal/infra/factory/services"
+	"github.com/deividr/zion-api/internal/infra/logger"
+	"github.com/deividr/zion-api/internal/infra/metrics"
+	"github.com/deividr/zion-api/internal/infra/queue"
 	"github.com/deividr/zion-api/internal/infra/repository/postgres"
+	"github.com/deividr/zion-api/internal/infra/scheduler"
+	"github.com/deividr/zion-api/internal/infra/spreadsheet"
+	"github.com/deividr/zion-api/internal/infra/tracing"
+	"github.com/deividr/zion-api/internal/infra/webhook"
 	"github.com/deividr/zion-api/internal/middleware"
 	"github.com/deividr/zion-api/internal/usecase"
 )
 
 func main() {
 	godotenv.Load()
+	log := logger.New()
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:], os.Stdout))
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Error("Unable to start", err)
+		os.Exit(1)
+	}
+	if err := logger.Configure(cfg.Logging.Format, cfg.Logging.Level); err != nil {
+		log.Error("Unable to configure logger", err)
+		os.Exit(1)
+	}
+
+	// Setup tracing
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, buildinfo.Get())
+	if err != nil {
+		log.Error("Unable to setup tracing", err)
+		os.Exit(1)
+	}
 
 	// Setup database connection
-	dbPool := database.GetConnection()
+	postgres.SetQueryTimeout(cfg.Database.QueryTimeout)
+	dbPool, err := database.GetConnection(cfg.Database)
+	if err != nil {
+		log.Error("Unable to connect to database", err)
+		os.Exit(1)
+	}
 	defer dbPool.Close()
 
+	if err := ensureSchema(dbPool, cfg.Database); err != nil {
+		log.Error("Database schema is not up to date", err)
+		os.Exit(1)
+	}
+
+	// Setup scheduled jobs
+	jobScheduler, err := setupScheduler(dbPool, cfg)
+	if err != nil {
+		log.Error("Unable to setup scheduled jobs", err)
+		os.Exit(1)
+	}
+	jobScheduler.Start()
+
+	// Setup background workers; workers acompanha as goroutines que usam o pool até o shutdown
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	var workers sync.WaitGroup
+
+	orderEventHub := events.NewPgOrderEventHub(dbPool)
+	workers.Go(func() { orderEventHub.Run(workersCtx) })
+
+	backgroundJobRepo := postgres.NewPgBackgroundJobRepository(dbPool)
+	webhookUseCase := setupWebhooks(dbPool)
+	workers.Go(func() { webhook.NewWorker(webhookUseCase, 5*time.Second).Run(workersCtx) })
+
+	jobQueue := setupJobQueue(dbPool, cfg, backgroundJobRepo, webhookUseCase)
+	jobQueue.Start()
+
 	// Setup router
-	r := gin.Default()
+	r := gin.New()
+	r.Use(middleware.Metrics())
+	r.Use(otelgin.Middleware(cfg.Tracing.ServiceName, otelgin.WithFilter(middleware.TraceFilter)))
+	r.Use(middleware.RequestID())
+	r.Use(middleware.AccessLog())
+	r.Use(gin.CustomRecoveryWithWriter(io.Discard, func(ctx *gin.Context, recovered any) {
+		logger.FromContext(ctx.Request.Context()).Event(zerolog.ErrorLevel).
+			Interface("panic", recovered).
+			Bytes("stack", debug.Stack()).
+			Msg("Panic handling request")
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	// Os streams e o long polling das impressoras controlam o próprio prazo (ver ConnDeadline)
+	r.Use(middleware.RequestDeadline(cfg.Server.RequestTimeout, "/orders/events", "/print/agent/jobs"))
 
 	// CORS configuration
 	corsConfig := cors.Config{
-		AllowOrigins:     strings.Split(os.Getenv("ALLOWED_ORIGINS"), ","),
+		AllowOrigins:     cfg.CORS.AllowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", middleware.RequestIdHeader},
+		ExposeHeaders:    []string{"Content-Length", middleware.RequestIdHeader},
 		AllowCredentials: true,
 	}
 
 	r.Use(cors.New(corsConfig))
 
+	// Rotas públicas usadas pelo orquestrador e pelo monitoramento
+	healthRoutes(r, dbPool, cfg)
+
 	// Grupo de rotas protegidas
 	protected := r.Group("")
-	protected.Use(middleware.AuthMiddleware(os.Getenv("CLERK_PEM_PUBLIC_KEY")))
+	protected.Use(middleware.AuthMiddleware(cfg.Clerk.PEMPublicKey, "/orders/events"))
 
 	productRoutes(protected, dbPool)
 	customerRoutes(protected, dbPool)
 	categoryRoutes(protected, dbPool)
-	orderRoutes(protected, dbPool)
-	uploadRoutes(protected)
+	orderRoutes(protected, dbPool, cfg)
+	orderEventRoutes(protected, orderEventHub)
+	kitchenRoutes(protected, dbPool)
+	printRoutes(protected, dbPool, cfg)
+	searchRoutes(protected, dbPool)
+	uploadRoutes(protected, cfg)
+	jobRoutes(protected, dbPool, jobScheduler)
+	webhookRoutes(protected, webhookUseCase)
+	queueRoutes(protected, backgroundJobRepo)
+
+	srv := &http.Server{
+		Addr:              cfg.Addr(),
+		Handler:           r,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
+	metricsSrv := setupMetrics(dbPool, cfg, backgroundJobRepo)
+
+	// Encerra os streams SSE para que o Shutdown não fique esperando conexões que nunca ficam ociosas
+	srv.RegisterOnShutdown(stopWorkers)
+
+	go func() {
+		log.Info("Listening on " + srv.Addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("HTTP server failed", err)
+			os.Exit(1)
+		}
+	}()
+
+	go func() {
+		log.Info("Serving metrics on " + metricsSrv.Addr)
+		if err := metricsSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("Metrics server failed", err)
+		}
+	}()
+
+	signalCtx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+	<-signalCtx.Done()
+	log.Info("Shutting down, draining requests and background jobs")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Error("Error shutting down HTTP server", err)
+	}
+	if err := metricsSrv.Shutdown(shutdownCtx); err != nil {
+		log.Error("Error shutting down metrics server", err)
+	}
+	if err := jobQueue.Shutdown(shutdownCtx); err != nil {
+		log.Error("Background jobs did not finish before shutdown timeout", err)
+	}
+	if err := jobScheduler.Stop(shutdownCtx); err != nil {
+		log.Error("Scheduled jobs did not finish before shutdown timeout", err)
+	}
+	if err := waitWorkers(shutdownCtx, stopWorkers, &workers); err != nil {
+		log.Error("Order events and webhook workers did not finish before shutdown timeout", err)
+	}
 
-	r.Run(":8000")
+	// Só fecha o pool depois que requisições e workers pararam de usá-lo
+	dbPool.Close()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		log.Error("Error flushing traces", err)
+	}
+	log.Info("Shutdown complete")
+}
+
+// waitWorkers cancela o hub de eventos e o worker de webhooks, se o Shutdown do servidor ainda
+// não o fez, e espera que terminem, para que nenhum deles use o pool depois de fechado.
+func waitWorkers(ctx context.Context, stop context.CancelFunc, workers *sync.WaitGroup) error {
+	stop()
+
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// setupMetrics cria o servidor do /metrics, separado da API para não expor os números do
+// negócio publicamente.
+func setupMetrics(pool *pgxpool.Pool, cfg *config.Config, backgroundJobRepo domain.BackgroundJobRepository) *http.Server {
+	metricsRepo := postgres.NewPgMetricsRepository(pool)
+	if err := metrics.Register(metrics.NewBusinessCollector(metricsRepo, backgroundJobRepo)); err != nil {
+		panic(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", metrics.Handler())
+
+	return &http.Server{
+		Addr:              cfg.Metrics.Addr(),
+		Handler:           mux,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+	}
+}
+
+func healthRoutes(router *gin.Engine, pool *pgxpool.Pool, cfg *config.Config) {
+	// Setup services
+	checks, err := services.NewReadinessChecks(pool, cfg.Storage)
+	if err != nil {
+		panic(err)
+	}
+
+	// Setup use cases
+	healthUseCase := usecase.NewHealthUseCase(checks, buildinfo.Get())
+
+	// Setup controllers
+	healthController := controller.NewHealthController(healthUseCase)
+
+	router.GET("/healthz", healthController.Healthz)
+	router.GET("/readyz", healthController.Readyz)
+	router.GET("/version", healthController.Version)
 }
 
 func productRoutes(router *gin.RouterGroup, pool *pgxpool.Pool) {
 	// Setup repositories
 	productRepo := postgres.NewPgProductRepository(pool)
+	categoryRepo := postgres.NewPgCategoryProductRepository(pool)
 	// Setup use cases
 	productUseCase := usecase.NewProductUseCase(productRepo)
+	productImportUseCase := usecase.NewProductImportUseCase(spreadsheet.NewReader(), productRepo, productRepo, categoryRepo)
 	// Setup controllers
-	productController := controller.NewProductController(productUseCase)
+	productController := controller.NewProductController(productUseCase, productImportUseCase)
 	router.GET("/products", productController.GetAll)
 	router.GET("/products/:id", productController.GetById)
 	router.PUT("/products/:id", productController.Update)
 	router.DELETE("/products/:id", productController.Delete)
 	router.POST("/products", productController.Create)
+	router.POST("/products/import", productController.Import)
 }
 
-func uploadRoutes(router *gin.RouterGroup) {
+func uploadRoutes(router *gin.RouterGroup, cfg *config.Config) {
 	// Setup repositories
-	uploadRepo, err := services.NewTigrisUploadRepository()
+	uploadRepo, err := services.NewTigrisUploadRepository(cfg.Storage)
 	if err != nil {
 		panic(err)
 	}
@@ -87,16 +305,28 @@ func customerRoutes(router *gin.RouterGroup, pool *pgxpool.Pool) {
 	// Setup repositories
 	customerRepo := postgres.NewPgCustomerRepository(pool)
 	addressRepo := postgres.NewPgAddressRepository(pool)
+	customerMergeRepo := postgres.NewPgCustomerMergeRepository(pool)
+	customerNoteRepo := postgres.NewPgCustomerNoteRepository(pool)
 
 	// Setup use cases
 	customerUseCase := usecase.NewCustomerUseCase(customerRepo)
+	customerImportUseCase := usecase.NewCustomerImportUseCase(spreadsheet.NewReader(), customerRepo)
 	addressUseCase := usecase.NewAddressUseCase(addressRepo)
+	customerMergeUseCase := usecase.NewCustomerMergeUseCase(customerMergeRepo)
+	customerNoteUseCase := usecase.NewCustomerNoteUseCase(customerNoteRepo, customerRepo)
 
 	// Setup controllers
-	customerController := controller.NewCustomerController(customerUseCase, addressUseCase)
+	customerController := controller.NewCustomerController(customerUseCase, addressUseCase, customerImportUseCase)
 	addressController := controller.NewAddressController(addressUseCase)
+	customerMergeController := controller.NewCustomerMergeController(customerMergeUseCase)
+	customerNoteController := controller.NewCustomerNoteController(customerNoteUseCase)
 
 	router.GET("/customers", customerController.GetAll)
+	router.GET("/customers/lookup", customerController.GetByPhone)
+	router.GET("/customers/duplicates", customerMergeController.GetDuplicates)
+	router.GET("/customers/merges", customerMergeController.GetMerges)
+	router.POST("/customers/merge", customerMergeController.Merge)
+	router.POST("/customers/import", customerController.Import)
 	router.GET("/customers/:id", customerController.GetById)
 	router.PUT("/customers/:id", customerController.Update)
 	router.DELETE("/customers/:id", customerController.Delete)
@@ -106,6 +336,11 @@ func customerRoutes(router *gin.RouterGroup, pool *pgxpool.Pool) {
 	router.PUT("customers/:id/addresses/:addressId", addressController.Update)
 	router.DELETE("customers/:id/addresses/:addressId", addressController.Delete)
 	router.POST("customers/:id/addresses", addressController.Create)
+
+	router.PUT("customers/:id/preferences", customerController.UpdatePreferences)
+	router.GET("customers/:id/notes", customerNoteController.GetByCustomerId)
+	router.POST("customers/:id/notes", customerNoteController.Create)
+	router.DELETE("customers/:id/notes/:noteId", customerNoteController.Delete)
 }
 
 func categoryRoutes(router *gin.RouterGroup, pool *pgxpool.Pool) {
@@ -122,25 +357,184 @@ func categoryRoutes(router *gin.RouterGroup, pool *pgxpool.Pool) {
 	router.GET("/categories/:id", categoryController.GetById)
 	router.PUT("/categories/:id", categoryController.Update)
 	router.DELETE("/categories/:id", categoryController.Delete)
+	router.POST("/categories", categoryController.Create)
 }
 
-func orderRoutes(router *gin.RouterGroup, pool *pgxpool.Pool) {
+func orderRoutes(router *gin.RouterGroup, pool *pgxpool.Pool, cfg *config.Config) {
 	// Setup repositories
 	orderRepo := postgres.NewPgOrderRepository(pool)
 	addressRepo := postgres.NewPgAddressRepository(pool)
 	customerRepo := postgres.NewPgCustomerRepository(pool)
+	notificationRepo := postgres.NewPgNotificationRepository(pool)
 
 	// Setup use cases
+	notificationUseCase := usecase.NewNotificationUseCase(notificationRepo, services.NewNotificationSenders(cfg.Notification))
 	orderUseCase := usecase.NewOrderUseCase(orderRepo, addressRepo, customerRepo)
 
+	pickupUseCase := usecase.NewPickupUseCase(orderRepo, orderUseCase, barcode.NewPNGRenderer())
+
 	// Setup controllers
 	orderController := controller.NewOrderController(orderUseCase)
+	notificationController := controller.NewNotificationController(notificationUseCase)
+	pickupController := controller.NewPickupController(pickupUseCase)
 
 	orderByIdController := ordersControllers.GetOrderByIdControllerFactory(pool)
 
 	router.GET("/orders", orderController.GetAll)
 	router.GET("/orders/:id", orderByIdController.Handle)
 	router.PUT("/orders/:id", orderController.Update)
+	router.PUT("/orders/:id/status", orderController.UpdateStatus)
+	router.GET("/orders/:id/notifications", notificationController.GetByOrderId)
+	router.GET("/orders/:id/pickup-code", pickupController.GetCodeImage)
+	router.POST("/orders/scan/:code", pickupController.Scan)
 	router.DELETE("/orders/:id", orderController.Delete)
 	router.POST("/orders", orderController.Create)
 }
+
+func kitchenRoutes(router *gin.RouterGroup, pool *pgxpool.Pool) {
+	// Setup repositories
+	kitchenRepo := postgres.NewPgKitchenRepository(pool)
+
+	// Setup use cases
+	kitchenUseCase := usecase.NewKitchenUseCase(kitchenRepo)
+
+	// Setup controllers
+	kitchenController := controller.NewKitchenController(kitchenUseCase)
+
+	router.GET("/kitchen/tickets", kitchenController.GetTickets)
+	router.GET("/kitchen/batches", kitchenController.GetBatches)
+	router.PUT("/kitchen/tickets/:id/status", kitchenController.UpdateTicketStatus)
+	router.POST("/kitchen/batches/bump", kitchenController.BumpBatch)
+}
+
+func printRoutes(router *gin.RouterGroup, pool *pgxpool.Pool, cfg *config.Config) {
+	// Setup repositories
+	printJobRepo := postgres.NewPgPrintJobRepository(pool)
+	orderRepo := postgres.NewPgOrderRepository(pool)
+	customerNoteRepo := postgres.NewPgCustomerNoteRepository(pool)
+	kitchenRepo := postgres.NewPgKitchenRepository(pool)
+
+	// Setup use cases
+	renderer := escpos.NewRenderer(escpos.Columns80mm, cfg.StoreName)
+	printUseCase := usecase.NewPrintUseCase(printJobRepo, orderRepo, customerNoteRepo, kitchenRepo, renderer)
+
+	// Setup controllers
+	printController := controller.NewPrintController(printUseCase)
+
+	router.POST("/orders/:id/print", printController.PrintOrder)
+	router.POST("/print/production-list", printController.PrintProductionList)
+	router.GET("/print/jobs", printController.GetAll)
+	router.GET("/print/jobs/:id/raw", printController.GetRaw)
+	router.POST("/print/jobs/:id/reprint", printController.Reprint)
+	router.POST("/print/jobs/:id/ack", printController.Ack)
+	// O long polling do agente pode passar dos timeouts padrão
+	router.GET("/print/agent/jobs", middleware.ConnDeadline(usecase.PrintJobMaxWait+cfg.Server.WriteTimeout), printController.Claim)
+}
+
+func orderEventRoutes(router *gin.RouterGroup, hub *events.PgOrderEventHub) {
+	// Setup use cases
+	orderEventUseCase := usecase.NewOrderEventUseCase(hub)
+
+	// Setup controllers
+	orderEventController := controller.NewOrderEventController(orderEventUseCase)
+
+	// O stream fica aberto indefinidamente, então não pode ter os timeouts do servidor
+	router.GET("/orders/events", middleware.ConnDeadline(0), orderEventController.Stream)
+}
+
+func searchRoutes(router *gin.RouterGroup, pool *pgxpool.Pool) {
+	// Setup repositories
+	searchRepo := postgres.NewPgSearchRepository(pool)
+
+	// Setup use cases
+	searchUseCase := usecase.NewSearchUseCase(searchRepo)
+
+	// Setup controllers
+	searchController := controller.NewSearchController(searchUseCase)
+
+	router.GET("/search", searchController.Search)
+}
+
+func setupScheduler(pool *pgxpool.Pool, cfg *config.Config) (*scheduler.Scheduler, error) {
+	// Setup repositories
+	orderRepo := postgres.NewPgOrderRepository(pool)
+	notificationRepo := postgres.NewPgNotificationRepository(pool)
+	jobRunRepo := postgres.NewPgJobRunRepository(pool)
+
+	// Setup use cases
+	notificationUseCase := usecase.NewNotificationUseCase(notificationRepo, services.NewNotificationSenders(cfg.Notification))
+	pickupReminderUseCase := usecase.NewPickupReminderUseCase(orderRepo, notificationRepo, notificationUseCase)
+
+	jobScheduler := scheduler.New(pool, jobRunRepo)
+
+	if err := jobScheduler.Register(usecase.PickupReminderJobName, cfg.Jobs.PickupReminderSchedule, pickupReminderUseCase.Run); err != nil {
+		return nil, err
+	}
+
+	return jobScheduler, nil
+}
+
+func jobRoutes(router *gin.RouterGroup, pool *pgxpool.Pool, jobScheduler *scheduler.Scheduler) {
+	// Setup repositories
+	jobRunRepo := postgres.NewPgJobRunRepository(pool)
+
+	// Setup use cases
+	jobUseCase := usecase.NewJobUseCase(jobScheduler, jobRunRepo)
+
+	// Setup controllers
+	jobController := controller.NewJobController(jobUseCase)
+
+	router.GET("/admin/jobs", jobController.GetAll)
+	router.GET("/admin/jobs/:name/runs", jobController.GetRuns)
+	router.POST("/admin/jobs/:name/run", jobController.Trigger)
+}
+
+func setupWebhooks(pool *pgxpool.Pool) *usecase.WebhookUseCase {
+	// Setup repositories
+	subscriptionRepo := postgres.NewPgWebhookSubscriptionRepository(pool)
+	deliveryRepo := postgres.NewPgWebhookDeliveryRepository(pool)
+
+	// Setup use cases
+	return usecase.NewWebhookUseCase(subscriptionRepo, deliveryRepo, webhook.NewHTTPSender())
+}
+
+func webhookRoutes(router *gin.RouterGroup, webhookUseCase *usecase.WebhookUseCase) {
+	// Setup controllers
+	webhookController := controller.NewWebhookController(webhookUseCase)
+
+	router.GET("/webhooks", webhookController.GetAll)
+	router.GET("/webhooks/:id", webhookController.GetById)
+	router.PUT("/webhooks/:id", webhookController.Update)
+	router.DELETE("/webhooks/:id", webhookController.Delete)
+	router.POST("/webhooks", webhookController.Create)
+	router.GET("/webhooks/:id/deliveries", webhookController.GetDeliveries)
+	router.POST("/webhooks/:id/deliveries/:deliveryId/replay", webhookController.Replay)
+}
+
+func setupJobQueue(pool *pgxpool.Pool, cfg *config.Config, backgroundJobRepo *postgres.PgBackgroundJobRepository, webhookUseCase *usecase.WebhookUseCase) *queue.WorkerPool {
+	// Setup repositories
+	orderRepo := postgres.NewPgOrderRepository(pool)
+	notificationRepo := postgres.NewPgNotificationRepository(pool)
+
+	// Setup use cases
+	notificationUseCase := usecase.NewNotificationUseCase(notificationRepo, services.NewNotificationSenders(cfg.Notification))
+	orderJobHandler := usecase.NewOrderJobHandler(orderRepo, notificationUseCase, webhookUseCase)
+
+	jobQueue := queue.NewWorkerPool(backgroundJobRepo, cfg.Jobs.QueueWorkers)
+	jobQueue.Register(domain.BackgroundJobOrderNotification, orderJobHandler.HandleNotification)
+	jobQueue.Register(domain.BackgroundJobWebhookPublish, orderJobHandler.HandleWebhookPublish)
+
+	return jobQueue
+}
+
+func queueRoutes(router *gin.RouterGroup, backgroundJobRepo *postgres.PgBackgroundJobRepository) {
+	// Setup use cases
+	backgroundJobUseCase := usecase.NewBackgroundJobUseCase(backgroundJobRepo)
+
+	// Setup controllers
+	backgroundJobController := controller.NewBackgroundJobController(backgroundJobUseCase)
+
+	router.GET("/admin/queue", backgroundJobController.GetStats)
+	router.GET("/admin/queue/dead", backgroundJobController.GetDead)
+	router.POST("/admin/queue/jobs/:id/retry", backgroundJobController.Retry)
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/deividr/zion-api/internal/config"
+	"github.com/deividr/zion-api/internal/infra/database"
+	"github.com/deividr/zion-api/internal/infra/database/migrations"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migrateTimeout limita a espera pelo lock e a execução das migrations na inicialização.
+const migrateTimeout = 5 * time.Minute
+
+const migrateUsage = `usage: zion migrate <command>
+
+commands:
+  up          apply all pending migrations
+  down [N]    revert the last N migrations (default 1)
+  status      list embedded migrations and whether they are applied
+  version     print the schema version recorded in the database`
+
+// runMigrate executa o subcomando "migrate" e devolve o código de saída do processo.
+func runMigrate(args []string, out io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	cfg, err := config.LoadDatabase()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	migrator, err := database.NewMigrator(cfg.URL)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer migrator.Close()
+
+	switch args[0] {
+	case "up":
+		err = migrator.Up()
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				fmt.Fprintf(os.Stderr, "invalid number of migrations %q\n", args[1])
+				return 2
+			}
+		}
+		err = migrator.Down(steps)
+	case "status":
+		err = printMigrationStatus(migrator, out)
+	case "version":
+		err = printMigrationVersion(migrator, out)
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func printMigrationStatus(migrator *database.Migrator, out io.Writer) error {
+	statuses, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+	if err := printMigrationVersion(migrator, out); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS")
+	for _, status := range statuses {
+		state := "pending"
+		if status.Applied {
+			state = "applied"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", status.Version, status.Name, state)
+	}
+	return w.Flush()
+}
+
+func printMigrationVersion(migrator *database.Migrator, out io.Writer) error {
+	version, dirty, err := migrator.Version()
+	if err != nil {
+		return err
+	}
+	expected, err := migrations.LatestVersion()
+	if err != nil {
+		return err
+	}
+
+	state := ""
+	if dirty {
+		state = " (dirty)"
+	}
+	fmt.Fprintf(out, "database: %d%s\nbinary:   %d\n", version, state, expected)
+	return nil
+}
+
+// ensureSchema aplica as migrations pendentes quando DATABASE_AUTO_MIGRATE está ligado e recusa
+// subir a API com o schema atrás do código, sujo ou sem migrations aplicadas.
+func ensureSchema(pool *pgxpool.Pool, cfg config.Database) error {
+	ctx, cancel := context.WithTimeout(context.Background(), migrateTimeout)
+	defer cancel()
+
+	if cfg.AutoMigrate {
+		if err := database.MigrateWithLock(ctx, pool, cfg.URL); err != nil {
+			return err
+		}
+	}
+
+	expected, err := migrations.LatestVersion()
+	if err != nil {
+		return err
+	}
+	if _, err := database.NewMigrationCheck(pool, expected).Check(ctx); err != nil {
+		return fmt.Errorf("%w: run \"zion migrate up\" or set DATABASE_AUTO_MIGRATE=true", err)
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/deividr/zion-api/internal/config"
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/infra/factory/services"
+	"github.com/deividr/zion-api/internal/infra/repository/postgres"
+	"github.com/deividr/zion-api/internal/usecase"
+)
+
+// maintenance monta o caso de uso das rotinas administrativas. O envio de notificações só é
+// configurado quando withNotifier é verdadeiro, para que os outros comandos não dependam das
+// variáveis dos canais.
+func (c *cli) maintenance(withNotifier bool) (*usecase.MaintenanceUseCase, error) {
+	pool, err := c.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	notificationRepo := postgres.NewPgNotificationRepository(pool)
+	var notifier usecase.OrderNotifier
+	if withNotifier {
+		notificationCfg, err := config.LoadNotification()
+		if err != nil {
+			return nil, err
+		}
+		notifier = usecase.NewNotificationUseCase(notificationRepo, services.NewNotificationSenders(*notificationCfg))
+	}
+
+	return usecase.NewMaintenanceUseCase(
+		postgres.NewPgMaintenanceRepository(pool),
+		postgres.NewPgOrderRepository(pool),
+		notificationRepo,
+		notifier,
+	), nil
+}
+
+// reseedOrderNumbers move a sequência dos números de pedido para depois do maior número usado.
+func reseedOrderNumbers(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags("orders reseed-numbers")
+	start := fs.Int("start", 0, "minimum next order number, e.g. to continue the legacy numbering")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	uc, err := c.maintenance(false)
+	if err != nil {
+		return err
+	}
+
+	if !c.dryRun && !c.yes {
+		preview, err := uc.ReseedOrderNumbers(ctx, *start, true)
+		if err != nil {
+			return err
+		}
+		if err := c.confirm(fmt.Sprintf("Highest order number is %d. Set the next order number to %d?", preview.MaxNumber, preview.NextNumber)); err != nil {
+			return err
+		}
+	}
+
+	seed, err := uc.ReseedOrderNumbers(ctx, *start, c.dryRun)
+	if err != nil {
+		return err
+	}
+	return c.print(seed, func(w io.Writer) {
+		fmt.Fprintf(w, "Highest order number: %d\nNext order number:    %d%s\n", seed.MaxNumber, seed.NextNumber, dryRunNote(seed.Applied))
+	})
+}
+
+// purgeDeleted apaga de vez os registros com exclusão lógica mais antigos que a retenção.
+func purgeDeleted(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags("purge")
+	entity := fs.String("entity", "", "what to purge: orders, products, customers or categories")
+	olderThan := fs.Duration("older-than", 30*24*time.Hour, "only purge rows deleted longer ago than this")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+	if !domain.IsValidPurgeEntity(*entity) {
+		fmt.Fprintln(c.errOut, domain.NewInvalidPurgeEntityError(*entity))
+		fs.Usage()
+		return errUsage
+	}
+
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	uc, err := c.maintenance(false)
+	if err != nil {
+		return err
+	}
+
+	if !c.dryRun && !c.yes {
+		preview, err := uc.PurgeDeleted(ctx, *entity, *olderThan, true)
+		if err != nil {
+			return err
+		}
+		if preview.Purged == 0 {
+			return c.print(preview, func(w io.Writer) { printPurge(w, preview) })
+		}
+		if err := c.confirm(fmt.Sprintf("Permanently delete %d %s deleted before %s? This cannot be undone.", preview.Purged, *entity, preview.DeletedBefore.Format(time.RFC3339))); err != nil {
+			return err
+		}
+		// Mantém o mesmo corte da prévia, sem incluir o que venceu enquanto o operador decidia
+		*olderThan = time.Since(preview.DeletedBefore)
+	}
+
+	result, err := uc.PurgeDeleted(ctx, *entity, *olderThan, c.dryRun)
+	if err != nil {
+		return err
+	}
+	return c.print(result, func(w io.Writer) { printPurge(w, result) })
+}
+
+func printPurge(w io.Writer, result *domain.PurgeResult) {
+	fmt.Fprintf(w, "Purged %d %s deleted before %s%s\n", result.Purged, result.Entity, result.DeletedBefore.Format(time.RFC3339), dryRunNote(result.Applied))
+	if result.Skipped > 0 {
+		fmt.Fprintf(w, "Kept %d %s that are still referenced by other records\n", result.Skipped, result.Entity)
+	}
+}
+
+// resendNotifications reenvia as notificações que falharam em todos os canais.
+func resendNotifications(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags("notifications resend")
+	orderId := fs.String("order", "", "only resend notifications of this order")
+	since := fs.Duration("since", 24*time.Hour, "only resend notifications created within this period")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	uc, err := c.maintenance(!c.dryRun)
+	if err != nil {
+		return err
+	}
+
+	if !c.dryRun && !c.yes {
+		preview, err := uc.ResendNotifications(ctx, *since, *orderId, true)
+		if err != nil {
+			return err
+		}
+		pending := len(preview.Notifications) - preview.Skipped
+		if pending == 0 {
+			return c.print(preview, func(w io.Writer) { printResend(w, preview) })
+		}
+		printResend(c.errOut, preview)
+		if err := c.confirm(fmt.Sprintf("Resend %d notifications to customers?", pending)); err != nil {
+			return err
+		}
+	}
+
+	result, err := uc.ResendNotifications(ctx, *since, *orderId, c.dryRun)
+	if err != nil {
+		return err
+	}
+	if err := c.print(result, func(w io.Writer) { printResend(w, result) }); err != nil {
+		return err
+	}
+	if result.Failed > 0 {
+		return fmt.Errorf("%d notifications failed again", result.Failed)
+	}
+	return nil
+}
+
+func printResend(w io.Writer, result *usecase.ResendNotificationsResult) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ORDER\tNUMBER\tTEMPLATE\tLAST CHANNEL\tLAST ATTEMPT\tSTATUS\tERROR")
+	for _, resend := range result.Notifications {
+		errorMessage := ""
+		if resend.Error != nil {
+			errorMessage = *resend.Error
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", resend.OrderId, resend.OrderNumber, resend.Template, resend.LastChannel, resend.LastAttemptAt, resend.Status, errorMessage)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "%d sent, %d failed, %d skipped%s\n", result.Sent, result.Failed, result.Skipped, dryRunNote(result.Applied))
+}
+
+// createCategory cria uma categoria de produtos com as mesmas validações da API.
+func createCategory(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags("categories create")
+	name := fs.String("name", "", "category name (required)")
+	description := fs.String("description", "", "category description")
+	parent := fs.String("parent", "", "id of the parent category")
+	displayOrder := fs.Int("display-order", 0, "position of the category in listings")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+	if *name == "" {
+		fmt.Fprintln(c.errOut, "--name is required")
+		fs.Usage()
+		return errUsage
+	}
+
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	pool, err := c.connect()
+	if err != nil {
+		return err
+	}
+	uc := usecase.NewCategoryProductUseCase(postgres.NewPgCategoryProductRepository(pool))
+
+	category := domain.CategoryProduct{Name: *name, Description: *description, DisplayOrder: *displayOrder}
+	if *parent != "" {
+		category.ParentId = parent
+		if _, err := uc.GetById(ctx, *parent); err != nil {
+			return fmt.Errorf("parent category %s not found: %w", *parent, err)
+		}
+	}
+
+	created := &category
+	if !c.dryRun {
+		if created, err = uc.Create(ctx, category); err != nil {
+			return err
+		}
+	}
+	return c.print(created, func(w io.Writer) {
+		id := created.Id
+		if id == "" {
+			id = "-"
+		}
+		fmt.Fprintf(w, "Category %q created with id %s%s\n", created.Name, id, dryRunNote(!c.dryRun))
+	})
+}
@@ -0,0 +1,425 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/deividr/zion-api/internal/config"
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/infra/database"
+	"github.com/deividr/zion-api/internal/infra/legacy"
+	"github.com/deividr/zion-api/internal/infra/repository/postgres"
+	"github.com/deividr/zion-api/internal/usecase"
+)
+
+// legacyLockKey impede dois comandos do legado ao mesmo tempo, que disputariam os checkpoints
+// e as versões gravadas pela sincronização.
+const legacyLockKey = "zion-legacy"
+
+// legacySource abre a conexão com o MySQL do sistema antigo.
+func (c *cli) legacySource() (*legacy.MySQLSource, error) {
+	cfg, err := config.LoadLegacy()
+	if err != nil {
+		return nil, err
+	}
+	return legacy.NewMySQLSource(*cfg)
+}
+
+// lockLegacy segura o advisory lock da importação até a função devolvida ser chamada.
+func (c *cli) lockLegacy(ctx context.Context) (func(), error) {
+	unlock, acquired, err := database.TryAdvisoryLock(ctx, c.pool, legacyLockKey)
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return nil, fmt.Errorf("another legacy command is running")
+	}
+	return unlock, nil
+}
+
+// legacyTimeout remove o prazo padrão quando --timeout não foi informado: os comandos do
+// legado percorrem o banco inteiro e levam bem mais que os outros.
+func (c *cli) legacyTimeout(fs *flag.FlagSet) {
+	if !isFlagSet(fs, "timeout") {
+		c.timeout = 0
+	}
+}
+
+// importLegacy importa o MySQL legado em etapas, continuando do último checkpoint.
+func importLegacy(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags("legacy import")
+	batchSize := fs.Int("batch-size", 500, "legacy rows written per transaction")
+	restart := fs.Bool("restart", false, "discard checkpoints and the error report and import from the beginning")
+	reportPath := fs.String("report", "legacy-import-errors.csv", "file that receives every skipped legacy row and why (legacy-import-dry-run-errors.csv with --dry-run)")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+	c.legacyTimeout(fs)
+	// O dry-run não sobrescreve o relatório da última importação de verdade
+	if c.dryRun && !isFlagSet(fs, "report") {
+		*reportPath = "legacy-import-dry-run-errors.csv"
+	}
+
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	source, err := c.legacySource()
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	pool, err := c.connect()
+	if err != nil {
+		return err
+	}
+	unlock, err := c.lockLegacy(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if !c.dryRun {
+		question := "Import legacy data? Records imported before will be overwritten with the legacy values."
+		if *restart {
+			question = "Discard the import progress and import everything again? Records imported before will be overwritten with the legacy values."
+		}
+		if err := c.confirm(question); err != nil {
+			return err
+		}
+	}
+
+	uc := usecase.NewLegacyImportUseCase(source, postgres.NewPgLegacyImportRepository(pool), postgres.NewPgMaintenanceRepository(pool))
+	result, err := uc.Run(ctx, usecase.LegacyImportOptions{BatchSize: *batchSize, DryRun: c.dryRun, Restart: *restart})
+	if result == nil {
+		return err
+	}
+	// Uma importação interrompida também grava o relatório com o que foi ignorado até ali
+	if reportErr := writeLegacyErrorReport(*reportPath, result.Errors); reportErr != nil {
+		return errors.Join(err, reportErr)
+	}
+	if err != nil {
+		return fmt.Errorf("%w (%d skipped rows written to %s)", err, len(result.Errors), *reportPath)
+	}
+	return c.print(result, func(w io.Writer) {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "STAGE\tIMPORTED\tSKIPPED\tNOTE")
+		for _, stage := range result.Stages {
+			note := ""
+			switch {
+			case stage.AlreadyCompleted:
+				note = "completed in a previous run"
+			case stage.ResumedFrom > 0:
+				note = fmt.Sprintf("resumed after legacy key %d", stage.ResumedFrom)
+			}
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%s\n", stage.Stage, stage.Imported, stage.Skipped, note)
+		}
+		tw.Flush()
+		if result.OrderNumbers != nil {
+			fmt.Fprintf(w, "Next order number: %d\n", result.OrderNumbers.NextNumber)
+		}
+		fmt.Fprintf(w, "%d skipped rows written to %s%s\n", len(result.Errors), *reportPath, dryRunNote(result.Applied))
+	})
+}
+
+// reconcileLegacy compara o legado com o Postgres e grava as divergências em um relatório.
+func reconcileLegacy(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags("legacy reconcile")
+	batchSize := fs.Int("batch-size", 500, "legacy rows compared per query")
+	reportPath := fs.String("report", "legacy-reconcile.csv", "file that receives every difference found")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+	c.legacyTimeout(fs)
+
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	source, err := c.legacySource()
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	pool, err := c.connect()
+	if err != nil {
+		return err
+	}
+	// Comparar no meio de uma importação apontaria divergências que ainda vão ser resolvidas
+	unlock, err := c.lockLegacy(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	uc := usecase.NewLegacyReconcileUseCase(source, postgres.NewPgLegacyReconcileRepository(pool), postgres.NewPgLegacyImportRepository(pool))
+	result, err := uc.Run(ctx, *batchSize)
+	if err != nil {
+		return err
+	}
+
+	if err := writeLegacyReconcileReport(*reportPath, result.Diffs); err != nil {
+		return err
+	}
+	err = c.print(result, func(w io.Writer) {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "STAGE\tLEGACY\tPOSTGRES\tMISSING\tEXTRA\tMISMATCHED")
+		for _, count := range result.Counts {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\n", count.Stage, count.Legacy, count.Postgres, count.Missing, count.Extra, count.Mismatched)
+		}
+		tw.Flush()
+		fmt.Fprintf(w, "Orders total: legacy %.2f, postgres %.2f\n", float64(result.LegacyOrdersTotal)/100, float64(result.PostgresOrdersTotal)/100)
+		fmt.Fprintf(w, "%d differences written to %s\n", len(result.Diffs), *reportPath)
+	})
+	if err != nil {
+		return err
+	}
+	if len(result.Diffs) > 0 {
+		return fmt.Errorf("%w: %d, see %s", errDifferences, len(result.Diffs), *reportPath)
+	}
+	return nil
+}
+
+// syncLegacy traz periodicamente para o Postgres o que foi digitado no legado desde a última
+// execução, até o comando ser interrompido ou o prazo de --timeout acabar.
+func syncLegacy(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags("legacy sync")
+	batchSize := fs.Int("batch-size", 500, "legacy rows written per transaction")
+	interval := fs.Duration("interval", time.Minute, "time between sync cycles")
+	lookback := fs.Duration("lookback", 72*time.Hour, "keep re-reading legacy orders whose pickup date is within this period")
+	once := fs.Bool("once", false, "run a single cycle and exit")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+	c.legacyTimeout(fs)
+	// Um dry run não grava nada, então repetir o ciclo mostraria sempre o mesmo resultado
+	if c.dryRun {
+		*once = true
+	}
+
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	source, err := c.legacySource()
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	pool, err := c.connect()
+	if err != nil {
+		return err
+	}
+	unlock, err := c.lockLegacy(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	uc := usecase.NewLegacySyncUseCase(source, postgres.NewPgLegacySyncRepository(pool), postgres.NewPgLegacyImportRepository(pool))
+	options := usecase.LegacySyncOptions{BatchSize: *batchSize, Lookback: *lookback, DryRun: c.dryRun}
+	for {
+		result, err := uc.Sync(ctx, options)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			return nil
+		case err != nil && *once:
+			return err
+		case err != nil:
+			// Uma falha do MySQL ou do Postgres não derruba o processo; o próximo ciclo tenta de novo
+			fmt.Fprintf(c.errOut, "%s sync failed: %v\n", time.Now().Format(time.DateTime), err)
+		default:
+			if err := c.print(result, func(w io.Writer) { printLegacySyncResult(w, result) }); err != nil {
+				return err
+			}
+		}
+		if *once {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(*interval):
+		}
+	}
+}
+
+func printLegacySyncResult(w io.Writer, result *usecase.LegacySyncResult) {
+	parts := []string{}
+	for _, stage := range result.Stages {
+		parts = append(parts, fmt.Sprintf("%s %d/%d", stage.Stage, stage.Synced, stage.Checked))
+	}
+	fmt.Fprintf(w, "%s synced (written/checked): %s; %d failed, %d new conflicts%s\n",
+		time.Now().Format(time.DateTime), strings.Join(parts, ", "), len(result.Errors), len(result.Conflicts), dryRunNote(result.Applied))
+	for _, syncError := range result.Errors {
+		fmt.Fprintf(w, "  %s %s failed: %s\n", syncError.Stage, syncError.OldId, syncError.Reason)
+	}
+	for _, conflict := range result.Conflicts {
+		kind := legacyConflictKind(conflict.Stage)
+		fmt.Fprintf(w, "  legacy %s %d was also edited in the API, run legacy resolve --%s %d\n", kind, conflict.OldId, kind, conflict.OldId)
+	}
+}
+
+// legacyConflictKind é o nome no singular da etapa, usado nas mensagens e na flag do resolve.
+func legacyConflictKind(stage string) string {
+	if stage == domain.LegacyStageCustomers {
+		return "customer"
+	}
+	return "order"
+}
+
+// listLegacyConflicts lista os pedidos e clientes alterados no legado e na API que aguardam resolução.
+func listLegacyConflicts(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags("legacy conflicts")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	pool, err := c.connect()
+	if err != nil {
+		return err
+	}
+
+	// Listar não lê o legado, então a conexão com o MySQL não é aberta
+	uc := usecase.NewLegacySyncUseCase(nil, postgres.NewPgLegacySyncRepository(pool), postgres.NewPgLegacyImportRepository(pool))
+	conflicts, err := uc.Conflicts(ctx)
+	if err != nil {
+		return err
+	}
+	return c.print(conflicts, func(w io.Writer) {
+		if len(conflicts) == 0 {
+			fmt.Fprintln(w, "No open conflicts.")
+			return
+		}
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "KIND\tLEGACY ID\tRECORD ID\tDETECTED AT")
+		for _, conflict := range conflicts {
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", legacyConflictKind(conflict.Stage), conflict.OldId, conflict.RecordId, conflict.DetectedAt.Local().Format(time.DateTime))
+		}
+		tw.Flush()
+	})
+}
+
+// resolveLegacyConflict escolhe qual versão de um pedido ou cliente em conflito fica no Postgres.
+func resolveLegacyConflict(ctx context.Context, c *cli, args []string) error {
+	fs := c.flags("legacy resolve")
+	orderId := fs.Int("order", 0, "legacy order number (cd_pedido) in conflict")
+	customerId := fs.Int("customer", 0, "legacy customer number (cd_cliente) in conflict")
+	keep := fs.String("keep", "", "version to keep: legacy overwrites the API edits, api discards the legacy changes")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+	if (*orderId > 0) == (*customerId > 0) || (*keep != domain.LegacySyncKeepLegacy && *keep != domain.LegacySyncKeepApi) {
+		fmt.Fprintln(c.errOut, "one of --order or --customer and --keep legacy|api are required")
+		fs.Usage()
+		return errUsage
+	}
+	stage, oldId := domain.LegacyStageOrders, *orderId
+	if *customerId > 0 {
+		stage, oldId = domain.LegacyStageCustomers, *customerId
+	}
+	kind := legacyConflictKind(stage)
+
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	if c.dryRun {
+		return c.print(map[string]any{kind: oldId, "keep": *keep, "applied": false}, func(w io.Writer) {
+			fmt.Fprintf(w, "Would keep the %s version of legacy %s %d%s\n", *keep, kind, oldId, dryRunNote(false))
+		})
+	}
+
+	question := fmt.Sprintf("Keep the API version of legacy %s %d? The legacy changes will be ignored.", kind, oldId)
+	if *keep == domain.LegacySyncKeepLegacy {
+		question = fmt.Sprintf("Overwrite %s %d with the legacy version? The edits made in the API will be lost.", kind, oldId)
+	}
+	if err := c.confirm(question); err != nil {
+		return err
+	}
+
+	var source domain.LegacySyncSource
+	if *keep == domain.LegacySyncKeepLegacy {
+		mysql, err := c.legacySource()
+		if err != nil {
+			return err
+		}
+		defer mysql.Close()
+		source = mysql
+	}
+	pool, err := c.connect()
+	if err != nil {
+		return err
+	}
+
+	uc := usecase.NewLegacySyncUseCase(source, postgres.NewPgLegacySyncRepository(pool), postgres.NewPgLegacyImportRepository(pool))
+	conflict, err := uc.ResolveConflict(ctx, stage, oldId, *keep)
+	if err != nil {
+		return err
+	}
+	return c.print(conflict, func(w io.Writer) {
+		fmt.Fprintf(w, "Legacy %s %d resolved keeping the %s version\n", kind, conflict.OldId, *keep)
+	})
+}
+
+// writeLegacyErrorReport grava o relatório de linhas ignoradas em CSV, aberto direto em planilhas.
+func writeLegacyErrorReport(path string, importErrors []domain.LegacyImportError) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("unable to create error report: %w", err)
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	w.Write([]string{"stage", "legacy_id", "reason"})
+	for _, importError := range importErrors {
+		w.Write([]string{importError.Stage, importError.OldId, importError.Reason})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("unable to write error report: %w", err)
+	}
+	return file.Close()
+}
+
+// writeLegacyReconcileReport grava as divergências em CSV; valores monetários em centavos.
+func writeLegacyReconcileReport(path string, diffs []usecase.LegacyReconcileDiff) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("unable to create reconciliation report: %w", err)
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	w.Write([]string{"stage", "legacy_id", "field", "legacy", "postgres", "note"})
+	for _, diff := range diffs {
+		w.Write([]string{diff.Stage, strconv.Itoa(diff.OldId), diff.Field, diff.Legacy, diff.Current, diff.Note})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("unable to write reconciliation report: %w", err)
+	}
+	return file.Close()
+}
+
+func isFlagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
@@ -0,0 +1,236 @@
+// zionctl reúne as tarefas operacionais que antes exigiam SQL manual no banco de produção,
+// usando os mesmos casos de uso e repositórios da API.
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+
+	"github.com/deividr/zion-api/internal/config"
+	"github.com/deividr/zion-api/internal/infra/database"
+	"github.com/deividr/zion-api/internal/infra/logger"
+)
+
+const usage = `usage: zionctl <command> [flags]
+
+commands:
+  orders reseed-numbers [--start N]              move the order number sequence past the highest order
+  purge --entity E [--older-than D]              permanently delete soft-deleted orders, products, customers or categories
+  notifications resend [--order ID] [--since D]  resend notifications that failed on every channel
+  categories create --name N [--description D] [--parent ID] [--display-order N]
+  legacy import [--restart] [--batch-size N] [--report FILE]
+                                                 import the legacy MySQL database, resuming from the last checkpoint
+  legacy reconcile [--batch-size N] [--report FILE]
+                                                 compare the legacy MySQL database with the imported data
+  legacy sync [--interval D] [--lookback D] [--once] [--batch-size N]
+                                                 keep pulling new and changed legacy rows until interrupted
+  legacy conflicts                               list orders and customers changed both in the legacy system and in the API
+  legacy resolve --order|--customer N --keep legacy|api
+                                                 choose which version of a conflicting row to keep
+
+common flags:
+  --dry-run        show what would change without writing anything
+  --json           print the result as JSON
+  --yes            skip the confirmation prompt of destructive commands
+  --timeout D      abort the command after D (default 5m, no limit for legacy commands; 0 disables)
+
+Run "zionctl <command> --help" for the flags of each command.`
+
+// errUsage indica argumentos inválidos; a mensagem já foi escrita pelo FlagSet.
+var errUsage = errors.New("invalid usage")
+
+// errAborted indica que o operador não confirmou a operação.
+var errAborted = errors.New("aborted")
+
+// errDifferences indica que a reconciliação terminou e encontrou divergências.
+var errDifferences = errors.New("differences found")
+
+// cli guarda as opções comuns e a conexão, aberta apenas pelos comandos que usam o banco.
+type cli struct {
+	in      *bufio.Reader
+	out     io.Writer
+	errOut  io.Writer
+	dryRun  bool
+	json    bool
+	yes     bool
+	timeout time.Duration
+	pool    *pgxpool.Pool
+}
+
+type command func(ctx context.Context, c *cli, args []string) error
+
+var commands = map[string]command{
+	"orders reseed-numbers": reseedOrderNumbers,
+	"purge":                 purgeDeleted,
+	"notifications resend":  resendNotifications,
+	"categories create":     createCategory,
+	"legacy import":         importLegacy,
+	"legacy reconcile":      reconcileLegacy,
+	"legacy sync":           syncLegacy,
+	"legacy conflicts":      listLegacyConflicts,
+	"legacy resolve":        resolveLegacyConflict,
+}
+
+func main() {
+	godotenv.Load()
+	// O stdout fica reservado para o resultado, que pode ser JSON consumido por outros programas
+	if err := logger.ConfigureOutput(os.Stderr, logger.FormatConsole, "warn"); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executa o comando e devolve o código de saída: 2 para uso inválido, 1 para falhas,
+// 3 quando o operador não confirma e 4 quando a reconciliação encontra divergências.
+func run(ctx context.Context, args []string, in io.Reader, out io.Writer, errOut io.Writer) int {
+	cmd, rest, ok := findCommand(args)
+	if !ok {
+		fmt.Fprintln(errOut, usage)
+		return 2
+	}
+
+	c := &cli{in: bufio.NewReader(in), out: out, errOut: errOut}
+	defer c.close()
+
+	err := cmd(ctx, c, rest)
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	case errors.Is(err, errAborted):
+		fmt.Fprintln(errOut, "Aborted, nothing was changed.")
+		return 3
+	case errors.Is(err, errDifferences):
+		fmt.Fprintln(errOut, err)
+		return 4
+	default:
+		fmt.Fprintln(errOut, "Error:", err)
+		return 1
+	}
+}
+
+// findCommand aceita comandos de uma ou duas palavras, como "purge" e "orders reseed-numbers".
+func findCommand(args []string) (command, []string, bool) {
+	if len(args) >= 2 {
+		if cmd, ok := commands[args[0]+" "+args[1]]; ok {
+			return cmd, args[2:], true
+		}
+	}
+	if len(args) >= 1 {
+		if cmd, ok := commands[args[0]]; ok {
+			return cmd, args[1:], true
+		}
+	}
+	return nil, nil, false
+}
+
+// flags cria o FlagSet do comando já com as opções comuns.
+func (c *cli) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("zionctl "+name, flag.ContinueOnError)
+	fs.SetOutput(c.errOut)
+	fs.BoolVar(&c.dryRun, "dry-run", false, "show what would change without writing anything")
+	fs.BoolVar(&c.json, "json", false, "print the result as JSON")
+	fs.BoolVar(&c.yes, "yes", false, "skip the confirmation prompt")
+	fs.DurationVar(&c.timeout, "timeout", 5*time.Minute, "abort the command after this duration")
+	return fs
+}
+
+func (c *cli) parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(c.errOut, "unexpected arguments: %s\n", strings.Join(fs.Args(), " "))
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+// connect abre o pool sem o limite por consulta da API: operações como o expurgo podem
+// demorar, e o prazo do comando inteiro é dado por --timeout.
+func (c *cli) connect() (*pgxpool.Pool, error) {
+	cfg, err := config.LoadDatabase()
+	if err != nil {
+		return nil, err
+	}
+	cfg.QueryTimeout = 0
+	// Uma conexão pode ficar presa ao advisory lock enquanto as outras fazem o trabalho
+	cfg.MaxConns = 4
+
+	pool, err := database.GetConnection(*cfg)
+	if err != nil {
+		return nil, err
+	}
+	c.pool = pool
+	return pool, nil
+}
+
+func (c *cli) close() {
+	if c.pool != nil {
+		c.pool.Close()
+	}
+}
+
+// confirm pergunta antes de uma operação destrutiva. Com --yes não pergunta; sem terminal, o
+// fim da entrada conta como recusa.
+func (c *cli) confirm(question string) error {
+	if c.yes {
+		return nil
+	}
+	fmt.Fprintf(c.errOut, "%s [y/N] ", question)
+	answer, err := c.in.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes", "s", "sim":
+		return nil
+	}
+	return errAborted
+}
+
+// print escreve o resultado em JSON com --json ou no formato de texto de cada comando.
+func (c *cli) print(result any, text func(w io.Writer)) error {
+	if c.json {
+		encoder := json.NewEncoder(c.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+	text(c.out)
+	return nil
+}
+
+func (c *cli) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+// dryRunNote marca as saídas de texto que não alteraram nada.
+func dryRunNote(applied bool) string {
+	if applied {
+		return ""
+	}
+	return " (dry run, nothing was changed)"
+}
@@ -1,26 +1,36 @@
 package orders
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/deividr/zion-api/internal/domain"
 )
 
 type GetOrderByIdUseCase struct {
-	orderRepository domain.OrderRepository
+	orderRepository        domain.OrderRepository
+	customerNoteRepository domain.CustomerNoteRepository
 }
 
-func (uc *GetOrderByIdUseCase) Execute(id string) (*domain.Order, error) {
-	order, err := uc.orderRepository.FindById(id)
+func (uc *GetOrderByIdUseCase) Execute(ctx context.Context, id string) (*domain.Order, error) {
+	order, err := uc.orderRepository.FindById(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching order by id: %v", err)
 	}
 
+	notes, err := uc.customerNoteRepository.FindByCustomerId(ctx, order.Customer.Id)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching customer notes: %v", err)
+	}
+
+	order.CustomerAlerts = domain.BuildCustomerAlerts(order.Customer, notes)
+
 	return order, nil
 }
 
-func NewGetOrderByIdUseCase(orderRepository domain.OrderRepository) *GetOrderByIdUseCase {
+func NewGetOrderByIdUseCase(orderRepository domain.OrderRepository, customerNoteRepository domain.CustomerNoteRepository) *GetOrderByIdUseCase {
 	return &GetOrderByIdUseCase{
-		orderRepository: orderRepository,
+		orderRepository:        orderRepository,
+		customerNoteRepository: customerNoteRepository,
 	}
 }
@@ -1,6 +1,8 @@
 package upload
 
 import (
+	"context"
+
 	"github.com/deividr/zion-api/internal/domain/services"
 	"github.com/google/uuid"
 )
@@ -15,7 +17,7 @@ func NewUploadUseCase(uploadRepo services.UploadRepository) *UploadUseCase {
 	}
 }
 
-func (uc *UploadUseCase) Execute() (*services.PresignedURLResponse, error) {
+func (uc *UploadUseCase) Execute(ctx context.Context) (*services.PresignedURLResponse, error) {
 	objectKey := uuid.New().String()
-	return uc.uploadRepo.GetPresignedURL(objectKey)
+	return uc.uploadRepo.GetPresignedURL(ctx, objectKey)
 }
@@ -1,6 +1,7 @@
 package upload
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -12,7 +13,7 @@ type mockUploadRepository struct {
 	err      error
 }
 
-func (m *mockUploadRepository) GetPresignedURL(objectKey string) (*services.PresignedURLResponse, error) {
+func (m *mockUploadRepository) GetPresignedURL(ctx context.Context, objectKey string) (*services.PresignedURLResponse, error) {
 	return m.response, m.err
 }
 
@@ -27,7 +28,7 @@ func TestUploadUseCase_Execute(t *testing.T) {
 		}
 		useCase := NewUploadUseCase(mockRepo)
 
-		response, err := useCase.Execute()
+		response, err := useCase.Execute(context.Background())
 		if err != nil {
 			t.Errorf("expected no error, but got %v", err)
 		}
@@ -48,7 +49,7 @@ func TestUploadUseCase_Execute(t *testing.T) {
 		}
 		useCase := NewUploadUseCase(mockRepo)
 
-		_, err := useCase.Execute()
+		_, err := useCase.Execute(context.Background())
 
 		if err == nil {
 			t.Error("expected an error, but got nil")
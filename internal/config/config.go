@@ -0,0 +1,491 @@
+// Package config carrega e valida na inicialização todas as variáveis de ambiente da API,
+// para que uma configuração incompleta falhe no deploy e não na primeira requisição.
+package config
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain/services"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/robfig/cron/v3"
+)
+
+type Config struct {
+	Port         string
+	Server       Server
+	Metrics      Metrics
+	Database     Database
+	CORS         CORS
+	Clerk        Clerk
+	Storage      Storage
+	Notification Notification
+	Jobs         Jobs
+	Tracing      Tracing
+	Logging      Logging
+	StoreName    string
+}
+
+type Server struct {
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	ShutdownTimeout   time.Duration
+	// RequestTimeout é o prazo do contexto de cada requisição, menor que WriteTimeout para que
+	// a API ainda consiga responder 504 antes de o servidor derrubar a conexão.
+	RequestTimeout time.Duration
+}
+
+// Metrics é servido em uma porta própria, fora do proxy público.
+type Metrics struct {
+	Port string
+}
+
+func (m Metrics) Addr() string {
+	return ":" + m.Port
+}
+
+// Database.QueryTimeout limita cada operação dos repositórios, incluindo a espera por uma
+// conexão livre no pool; zero desliga o limite. Com AutoMigrate a API aplica as migrations
+// pendentes ao iniciar.
+type Database struct {
+	URL          string
+	MaxConns     int32
+	QueryTimeout time.Duration
+	AutoMigrate  bool
+}
+
+type CORS struct {
+	AllowedOrigins []string
+}
+
+type Clerk struct {
+	PEMPublicKey string
+}
+
+// Storage são as credenciais do bucket Tigris (API compatível com S3).
+type Storage struct {
+	Bucket          string
+	Endpoint        string
+	PublicURLBase   string
+	Region          string
+	AccessKeyId     string
+	SecretAccessKey string
+}
+
+type Notification struct {
+	Channels []string
+	WhatsApp WhatsApp
+	SMS      SMS
+	SMTP     SMTP
+}
+
+type WhatsApp struct {
+	APIURL        string
+	PhoneNumberId string
+	AccessToken   string
+}
+
+type SMS struct {
+	APIURL string
+	APIKey string
+	Sender string
+}
+
+type SMTP struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+type Jobs struct {
+	PickupReminderSchedule string
+	QueueWorkers           int
+}
+
+// Legacy é o MySQL do sistema antigo, lido apenas pelos comandos de importação do zionctl.
+type Legacy struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	Database string
+}
+
+const (
+	TracingExporterNone   = "none"
+	TracingExporterStdout = "stdout"
+	TracingExporterOTLP   = "otlp"
+)
+
+// Tracing segue os nomes de variáveis do OpenTelemetry. O exporter OTLP também lê as demais
+// variáveis OTEL_EXPORTER_OTLP_* (headers, protocolo) diretamente do ambiente.
+type Tracing struct {
+	Exporter    string
+	Endpoint    string
+	ServiceName string
+	SampleRatio float64
+}
+
+// Logging usa JSON por padrão, o formato lido pelo agregador de logs em produção; em
+// desenvolvimento LOG_FORMAT=console deixa a saída legível no terminal.
+type Logging struct {
+	Format string
+	Level  string
+}
+
+// Load lê as variáveis de ambiente e devolve todos os problemas encontrados de uma vez.
+func Load() (*Config, error) {
+	l := &loader{}
+
+	cfg := &Config{
+		Port: l.port("PORT", "8000"),
+		Server: Server{
+			ReadHeaderTimeout: l.duration("SERVER_READ_HEADER_TIMEOUT", 5*time.Second),
+			ReadTimeout:       l.duration("SERVER_READ_TIMEOUT", 15*time.Second),
+			WriteTimeout:      l.duration("SERVER_WRITE_TIMEOUT", 30*time.Second),
+			IdleTimeout:       l.duration("SERVER_IDLE_TIMEOUT", 60*time.Second),
+			ShutdownTimeout:   l.duration("SERVER_SHUTDOWN_TIMEOUT", 25*time.Second),
+			RequestTimeout:    l.duration("SERVER_REQUEST_TIMEOUT", 20*time.Second),
+		},
+		Metrics: Metrics{
+			Port: l.port("METRICS_PORT", "9091"),
+		},
+		Database: l.database(),
+		CORS: CORS{
+			AllowedOrigins: l.origins("ALLOWED_ORIGINS"),
+		},
+		Clerk: Clerk{
+			PEMPublicKey: l.pem("CLERK_PEM_PUBLIC_KEY"),
+		},
+		Storage: Storage{
+			Bucket:          l.required("BUCKET_NAME"),
+			Endpoint:        l.url("AWS_ENDPOINT_URL_S3", true, "http", "https"),
+			PublicURLBase:   l.url("TIGRIS_PUBLIC_URL_BASE", false, "http", "https"),
+			Region:          l.required("AWS_REGION"),
+			AccessKeyId:     l.required("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: l.required("AWS_SECRET_ACCESS_KEY"),
+		},
+		Notification: l.notification(),
+		Jobs: Jobs{
+			PickupReminderSchedule: l.cron("PICKUP_REMINDER_SCHEDULE", "0 10 * * *"),
+			QueueWorkers:           l.positiveInt("QUEUE_WORKERS", 4),
+		},
+		Tracing: Tracing{
+			Exporter:    l.oneOf("OTEL_TRACES_EXPORTER", TracingExporterNone, TracingExporterNone, TracingExporterStdout, TracingExporterOTLP),
+			Endpoint:    l.url("OTEL_EXPORTER_OTLP_ENDPOINT", false, "http", "https"),
+			ServiceName: l.optional("OTEL_SERVICE_NAME", "zion-api"),
+			SampleRatio: l.ratio("OTEL_TRACES_SAMPLER_ARG", 1),
+		},
+		Logging: Logging{
+			Format: l.oneOf("LOG_FORMAT", "json", "json", "console"),
+			Level:  l.oneOf("LOG_LEVEL", "info", "debug", "info", "warn", "error"),
+		},
+		StoreName: os.Getenv("STORE_NAME"),
+	}
+
+	if cfg.Tracing.Exporter == TracingExporterOTLP && cfg.Tracing.Endpoint == "" {
+		l.fail("OTEL_TRACES_EXPORTER is otlp but OTEL_EXPORTER_OTLP_ENDPOINT is required")
+	}
+	if cfg.Server.RequestTimeout >= cfg.Server.WriteTimeout {
+		l.fail("SERVER_REQUEST_TIMEOUT must be lower than SERVER_WRITE_TIMEOUT")
+	}
+	if cfg.Database.QueryTimeout >= cfg.Server.RequestTimeout {
+		l.fail("DATABASE_QUERY_TIMEOUT must be lower than SERVER_REQUEST_TIMEOUT")
+	}
+	if cfg.Metrics.Port == cfg.Port {
+		l.fail("METRICS_PORT must be different from PORT")
+	}
+
+	if len(l.errs) > 0 {
+		return nil, fmt.Errorf("invalid configuration:\n  - %s", strings.Join(l.errs, "\n  - "))
+	}
+	return cfg, nil
+}
+
+// LoadDatabase lê apenas as variáveis do banco, para comandos como o migrate que não sobem a API.
+func LoadDatabase() (*Database, error) {
+	l := &loader{}
+	database := l.database()
+
+	if len(l.errs) > 0 {
+		return nil, fmt.Errorf("invalid configuration:\n  - %s", strings.Join(l.errs, "\n  - "))
+	}
+	return &database, nil
+}
+
+// LoadNotification lê apenas os canais de notificação, para comandos que reenviam mensagens
+// sem subir a API.
+func LoadNotification() (*Notification, error) {
+	l := &loader{}
+	notification := l.notification()
+
+	if len(l.errs) > 0 {
+		return nil, fmt.Errorf("invalid configuration:\n  - %s", strings.Join(l.errs, "\n  - "))
+	}
+	return &notification, nil
+}
+
+// LoadLegacy lê a conexão com o MySQL legado.
+func LoadLegacy() (*Legacy, error) {
+	l := &loader{}
+	legacy := Legacy{
+		Host:     l.required("MYSQL_HOST"),
+		Port:     l.port("MYSQL_PORT", "3306"),
+		User:     l.required("MYSQL_USER"),
+		Password: os.Getenv("MYSQL_PASSWORD"),
+		Database: l.required("MYSQL_DATABASE"),
+	}
+
+	if len(l.errs) > 0 {
+		return nil, fmt.Errorf("invalid configuration:\n  - %s", strings.Join(l.errs, "\n  - "))
+	}
+	return &legacy, nil
+}
+
+// Addr é o endereço em que o servidor HTTP escuta.
+func (c *Config) Addr() string {
+	return ":" + c.Port
+}
+
+type loader struct {
+	errs []string
+}
+
+func (l *loader) fail(format string, args ...any) {
+	l.errs = append(l.errs, fmt.Sprintf(format, args...))
+}
+
+func (l *loader) optional(key string, fallback string) string {
+	if value := strings.TrimSpace(os.Getenv(key)); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func (l *loader) required(key string) string {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		l.fail("%s is required", key)
+	}
+	return value
+}
+
+func (l *loader) list(key string) []string {
+	values := []string{}
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func (l *loader) port(key string, fallback string) string {
+	value := l.optional(key, fallback)
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		l.fail("%s must be a port number between 1 and 65535, got %q", key, value)
+	}
+	return value
+}
+
+func (l *loader) positiveInt(key string, fallback int) int {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return fallback
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 1 {
+		l.fail("%s must be a positive integer, got %q", key, raw)
+		return fallback
+	}
+	return value
+}
+
+func (l *loader) database() Database {
+	return Database{
+		URL:          l.connString("DATABASE_URL"),
+		MaxConns:     int32(l.positiveInt("DATABASE_MAX_CONNS", 10)),
+		QueryTimeout: l.duration("DATABASE_QUERY_TIMEOUT", 5*time.Second),
+		AutoMigrate:  l.boolean("DATABASE_AUTO_MIGRATE", false),
+	}
+}
+
+func (l *loader) boolean(key string, fallback bool) bool {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return fallback
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		l.fail("%s must be true or false, got %q", key, raw)
+		return fallback
+	}
+	return value
+}
+
+func (l *loader) oneOf(key string, fallback string, values ...string) string {
+	value := l.optional(key, fallback)
+	for _, allowed := range values {
+		if value == allowed {
+			return value
+		}
+	}
+	l.fail("%s must be one of %s, got %q", key, strings.Join(values, ", "), value)
+	return fallback
+}
+
+// ratio aceita valores entre 0 e 1, como a proporção de traces amostrados.
+func (l *loader) ratio(key string, fallback float64) float64 {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return fallback
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || value < 0 || value > 1 {
+		l.fail("%s must be a number between 0 and 1, got %q", key, raw)
+		return fallback
+	}
+	return value
+}
+
+// duration aceita o formato do Go ("30s", "1m30s").
+func (l *loader) duration(key string, fallback time.Duration) time.Duration {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return fallback
+	}
+	value, err := time.ParseDuration(raw)
+	if err != nil || value <= 0 {
+		l.fail("%s must be a positive duration such as \"30s\", got %q", key, raw)
+		return fallback
+	}
+	return value
+}
+
+// cron aceita expressões de cinco campos ou descritores como "@daily", as mesmas do scheduler.
+func (l *loader) cron(key string, fallback string) string {
+	value := l.optional(key, fallback)
+	if _, err := cron.ParseStandard(value); err != nil {
+		l.fail("%s must be a valid cron expression, got %q: %v", key, value, err)
+		return fallback
+	}
+	return value
+}
+
+func (l *loader) url(key string, required bool, schemes ...string) string {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		if required {
+			l.fail("%s is required", key)
+		}
+		return ""
+	}
+
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Host == "" {
+		l.fail("%s must be a valid URL", key)
+		return value
+	}
+	for _, scheme := range schemes {
+		if parsed.Scheme == scheme {
+			return value
+		}
+	}
+	l.fail("%s must use one of the schemes %s, got %q", key, strings.Join(schemes, ", "), parsed.Scheme)
+	return value
+}
+
+func (l *loader) connString(key string) string {
+	value := l.required(key)
+	if value == "" {
+		return ""
+	}
+	if _, err := pgxpool.ParseConfig(value); err != nil {
+		l.fail("%s is not a valid Postgres connection string: %v", key, err)
+	}
+	return value
+}
+
+func (l *loader) origins(key string) []string {
+	origins := l.list(key)
+	if len(origins) == 0 {
+		l.fail("%s is required (comma separated list of origins)", key)
+	}
+	for _, origin := range origins {
+		parsed, err := url.Parse(origin)
+		if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			l.fail("%s has an invalid origin %q, expected scheme://host[:port]", key, origin)
+		}
+	}
+	return origins
+}
+
+func (l *loader) pem(key string) string {
+	value := l.required(key)
+	if value == "" {
+		return ""
+	}
+	if block, _ := pem.Decode([]byte(value)); block == nil || block.Type != "PUBLIC KEY" {
+		l.fail("%s must be a PEM encoded public key (-----BEGIN PUBLIC KEY-----)", key)
+	}
+	return value
+}
+
+// notification lê os canais de notificação habilitados e as credenciais de cada provedor.
+func (l *loader) notification() Notification {
+	notification := Notification{
+		Channels: l.list("NOTIFICATION_CHANNELS"),
+		WhatsApp: WhatsApp{
+			APIURL:        l.optional("WHATSAPP_API_URL", "https://graph.facebook.com/v21.0"),
+			PhoneNumberId: os.Getenv("WHATSAPP_PHONE_NUMBER_ID"),
+			AccessToken:   os.Getenv("WHATSAPP_ACCESS_TOKEN"),
+		},
+		SMS: SMS{
+			APIURL: os.Getenv("SMS_API_URL"),
+			APIKey: os.Getenv("SMS_API_KEY"),
+			Sender: os.Getenv("SMS_SENDER"),
+		},
+		SMTP: SMTP{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     l.port("SMTP_PORT", "587"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("SMTP_FROM"),
+		},
+	}
+	l.validateNotification(notification)
+	return notification
+}
+
+// validateNotification garante que cada canal habilitado tenha as credenciais que usa.
+func (l *loader) validateNotification(notification Notification) {
+	for _, channel := range notification.Channels {
+		switch channel {
+		case services.NotificationChannelWhatsApp:
+			if notification.WhatsApp.PhoneNumberId == "" || notification.WhatsApp.AccessToken == "" {
+				l.fail("NOTIFICATION_CHANNELS has whatsapp but WHATSAPP_PHONE_NUMBER_ID and WHATSAPP_ACCESS_TOKEN are required")
+			}
+		case services.NotificationChannelSMS:
+			if notification.SMS.APIURL == "" || notification.SMS.APIKey == "" {
+				l.fail("NOTIFICATION_CHANNELS has sms but SMS_API_URL and SMS_API_KEY are required")
+			}
+		case services.NotificationChannelEmail:
+			if notification.SMTP.Host == "" || notification.SMTP.From == "" {
+				l.fail("NOTIFICATION_CHANNELS has email but SMTP_HOST and SMTP_FROM are required")
+			}
+		case services.NotificationChannelLog:
+		default:
+			l.fail("NOTIFICATION_CHANNELS has unknown channel %q, expected whatsapp, sms, email or log", channel)
+		}
+	}
+}
@@ -25,7 +25,7 @@ func NewAddressController(addressUseCase *usecase.AddressUseCase) *AddressContro
 func (c *AddressController) GetByCustomerId(ctx *gin.Context) {
 	customerId := ctx.Param("id")
 
-	addresses, err := c.addressUseCase.GetByCustomerId(customerId)
+	addresses, err := c.addressUseCase.GetByCustomerId(ctx.Request.Context(), customerId)
 	if err != nil {
 		ctx.IndentedJSON(http.StatusNotFound, gin.H{"message": "Addresses not found"})
 		return
@@ -40,14 +40,14 @@ func (c *AddressController) Update(ctx *gin.Context) {
 
 	var updateData domain.NewAddress
 	if err := ctx.BindJSON(&updateData); err != nil {
-		c.logger.Error("Invalid address data for update", err)
+		c.logger.Ctx(ctx.Request.Context()).Error("Invalid address data for update", err)
 		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"message": "Invalid address data"})
 		return
 	}
 
-	updatedAddress, err := c.addressUseCase.Update(customerId, addressId, updateData)
+	updatedAddress, err := c.addressUseCase.Update(ctx.Request.Context(), customerId, addressId, updateData)
 	if err != nil {
-		c.logger.Error("Failed to update address", err)
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to update address", err)
 		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"message": "Failed to update address"})
 		return
 	}
@@ -59,9 +59,9 @@ func (c *AddressController) Delete(ctx *gin.Context) {
 	customerId := ctx.Param("id")
 	addressId := ctx.Param("addressId")
 
-	err := c.addressUseCase.Delete(customerId, addressId)
+	err := c.addressUseCase.Delete(ctx.Request.Context(), customerId, addressId)
 	if err != nil {
-		c.logger.Error("Failed to delete address", err)
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to delete address", err)
 		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"message": "Failed to delete address"})
 		return
 	}
@@ -73,12 +73,12 @@ func (c *AddressController) Create(ctx *gin.Context) {
 
 	var newAddress domain.NewAddress
 	if err := ctx.BindJSON(&newAddress); err != nil {
-		c.logger.Error("Invalid address data for creation", err)
+		c.logger.Ctx(ctx.Request.Context()).Error("Invalid address data for creation", err)
 		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"message": "Invalid address data"})
 		return
 	}
 
-	createdAddress, err := c.addressUseCase.Create(customerId, newAddress)
+	createdAddress, err := c.addressUseCase.Create(ctx.Request.Context(), customerId, newAddress)
 	if err != nil {
 		var duplicateAddrErr *domain.DuplicateAddressError
 		if errors.As(err, &duplicateAddrErr) {
@@ -89,7 +89,7 @@ func (c *AddressController) Create(ctx *gin.Context) {
 			return
 		}
 
-		c.logger.Error("Failed to create address", err)
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to create address", err)
 		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"message": "Failed to create address"})
 		return
 	}
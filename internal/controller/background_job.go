@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/infra/logger"
+	"github.com/deividr/zion-api/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type BackgroundJobController struct {
+	useCase *usecase.BackgroundJobUseCase
+	logger  *logger.Logger
+}
+
+func NewBackgroundJobController(useCase *usecase.BackgroundJobUseCase) *BackgroundJobController {
+	return &BackgroundJobController{
+		useCase: useCase,
+		logger:  logger.New(),
+	}
+}
+
+func (c *BackgroundJobController) GetStats(ctx *gin.Context) {
+	stats, err := c.useCase.GetStats(ctx.Request.Context())
+	if err != nil {
+		c.logger.Ctx(ctx.Request.Context()).Error("Error fetching queue stats", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Fetching queue stats fatal failed"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, gin.H{"queues": stats})
+}
+
+func (c *BackgroundJobController) GetDead(ctx *gin.Context) {
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit params"})
+		return
+	}
+
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page params"})
+		return
+	}
+
+	jobs, pagination, err := c.useCase.GetDead(ctx.Request.Context(), domain.Pagination{Limit: limit, Page: page})
+	if err != nil {
+		c.logger.Ctx(ctx.Request.Context()).Error("Error fetching dead background jobs", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Fetching dead background jobs fatal failed"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, gin.H{"jobs": jobs, "pagination": pagination})
+}
+
+func (c *BackgroundJobController) Retry(ctx *gin.Context) {
+	if err := c.useCase.Retry(ctx.Request.Context(), ctx.Param("id")); err != nil {
+		var notFoundErr *domain.BackgroundJobNotFoundError
+		if errors.As(err, &notFoundErr) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message": notFoundErr.Error(), "error": "job_not_found"})
+			return
+		}
+
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to retry background job", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to retry background job"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusAccepted, gin.H{"message": "Background job requeued"})
+}
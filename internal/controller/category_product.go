@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/deividr/zion-api/internal/domain"
@@ -22,9 +23,9 @@ func NewCategoryProductController(useCase *usecase.CategoryProductUseCase) *Cate
 }
 
 func (c *CategoryProductController) GetAll(ctx *gin.Context) {
-	categories, err := c.useCase.GetAll()
+	categories, err := c.useCase.GetAll(ctx.Request.Context())
 	if err != nil {
-		c.logger.Error("Error fetching categories", err)
+		c.logger.Ctx(ctx.Request.Context()).Error("Error fetching categories", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Fetching categories fatal failed"})
 		return
 	}
@@ -35,7 +36,7 @@ func (c *CategoryProductController) GetAll(ctx *gin.Context) {
 func (c *CategoryProductController) GetById(ctx *gin.Context) {
 	id := ctx.Param("id")
 
-	category, err := c.useCase.GetById(id)
+	category, err := c.useCase.GetById(ctx.Request.Context(), id)
 	if err != nil {
 		ctx.IndentedJSON(http.StatusNotFound, gin.H{"message": "Category not found"})
 		return
@@ -51,9 +52,15 @@ func (c *CategoryProductController) Update(ctx *gin.Context) {
 		return
 	}
 
-	err := c.useCase.Update(category)
+	err := c.useCase.Update(ctx.Request.Context(), category)
 	if err != nil {
-		c.logger.Error("Failed to update category", err)
+		var invalidParentErr *domain.InvalidCategoryParentError
+		if errors.As(err, &invalidParentErr) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid parent category", "error": "invalid_parent"})
+			return
+		}
+
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to update category", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to update category"})
 		return
 	}
@@ -63,9 +70,37 @@ func (c *CategoryProductController) Update(ctx *gin.Context) {
 
 func (c *CategoryProductController) Delete(ctx *gin.Context) {
 	id := ctx.Param("id")
-	err := c.useCase.Delete(id)
+
+	var reassignTo *string
+	if target := ctx.Query("reassignTo"); target != "" {
+		reassignTo = &target
+	}
+
+	err := c.useCase.Delete(ctx.Request.Context(), id, reassignTo)
 	if err != nil {
-		c.logger.Error("Failed to delete category", err)
+		var hasProductsErr *domain.CategoryHasProductsError
+		if errors.As(err, &hasProductsErr) {
+			ctx.JSON(http.StatusConflict, gin.H{
+				"message":      "Category still has products, provide reassignTo to move them",
+				"error":        "category_has_products",
+				"productCount": hasProductsErr.ProductCount,
+			})
+			return
+		}
+
+		var invalidReassignErr *domain.InvalidCategoryReassignError
+		if errors.As(err, &invalidReassignErr) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid category to move the products to", "error": "invalid_reassign"})
+			return
+		}
+
+		var notFoundErr *domain.CategoryNotFoundError
+		if errors.As(err, &notFoundErr) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message": "Category not found"})
+			return
+		}
+
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to delete category", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to delete category"})
 		return
 	}
@@ -79,9 +114,15 @@ func (c *CategoryProductController) Create(ctx *gin.Context) {
 		return
 	}
 
-	createdCategory, err := c.useCase.Create(newCategory)
+	createdCategory, err := c.useCase.Create(ctx.Request.Context(), newCategory)
 	if err != nil {
-		c.logger.Error("Failed to create category", err)
+		var invalidParentErr *domain.InvalidCategoryParentError
+		if errors.As(err, &invalidParentErr) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid parent category", "error": "invalid_parent"})
+			return
+		}
+
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to create category", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to create category"})
 		return
 	}
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type mockCategoryProductRepository struct {
+	domain.CategoryProductRepository
+	categories map[string]domain.CategoryProduct
+	deleteErr  error
+}
+
+func (m *mockCategoryProductRepository) FindById(ctx context.Context, id string) (*domain.CategoryProduct, error) {
+	category, ok := m.categories[id]
+	if !ok {
+		return nil, domain.NewCategoryNotFoundError(id)
+	}
+	return &category, nil
+}
+
+func (m *mockCategoryProductRepository) Delete(ctx context.Context, id string, reassignTo *string) error {
+	if m.deleteErr != nil {
+		return m.deleteErr
+	}
+	if _, ok := m.categories[id]; !ok {
+		return domain.NewCategoryNotFoundError(id)
+	}
+	return nil
+}
+
+func newCategoryProductRouter(repo *mockCategoryProductRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	categoryController := NewCategoryProductController(usecase.NewCategoryProductUseCase(repo))
+	router := gin.New()
+	router.DELETE("/categories/:id", categoryController.Delete)
+	return router
+}
+
+func TestCategoryProductController(t *testing.T) {
+	categories := map[string]domain.CategoryProduct{"c1": {Id: "c1"}, "c2": {Id: "c2"}}
+
+	t.Run("should delete the category moving its products", func(t *testing.T) {
+		router := newCategoryProductRouter(&mockCategoryProductRepository{categories: categories})
+
+		status, response := serve(router, http.MethodDelete, "/categories/c1?reassignTo=c2", "")
+		if status != http.StatusOK {
+			t.Errorf("expected 200, but got %d %v", status, response)
+		}
+	})
+
+	t.Run("should respond 400 when the products cannot be moved to the target", func(t *testing.T) {
+		router := newCategoryProductRouter(&mockCategoryProductRepository{categories: categories})
+
+		for _, target := range []string{"c1", "missing"} {
+			status, response := serve(router, http.MethodDelete, "/categories/c1?reassignTo="+target, "")
+			if status != http.StatusBadRequest || response["error"] != "invalid_reassign" {
+				t.Errorf("reassignTo=%s: expected 400 invalid_reassign, but got %d %v", target, status, response)
+			}
+		}
+	})
+
+	t.Run("should respond 404 when the category does not exist", func(t *testing.T) {
+		router := newCategoryProductRouter(&mockCategoryProductRepository{categories: categories})
+
+		for _, path := range []string{"/categories/missing", "/categories/missing?reassignTo=c2"} {
+			status, _ := serve(router, http.MethodDelete, path, "")
+			if status != http.StatusNotFound {
+				t.Errorf("%s: expected 404, but got %d", path, status)
+			}
+		}
+	})
+
+	t.Run("should respond 409 when the category still has products", func(t *testing.T) {
+		router := newCategoryProductRouter(&mockCategoryProductRepository{categories: categories, deleteErr: domain.NewCategoryHasProductsError("c1", 3)})
+
+		status, response := serve(router, http.MethodDelete, "/categories/c1", "")
+		if status != http.StatusConflict || response["error"] != "category_has_products" {
+			t.Errorf("expected 409 category_has_products, but got %d %v", status, response)
+		}
+	})
+
+	t.Run("should respond 500 when the repository fails", func(t *testing.T) {
+		router := newCategoryProductRouter(&mockCategoryProductRepository{categories: categories, deleteErr: errors.New("connection refused")})
+
+		status, _ := serve(router, http.MethodDelete, "/categories/c1", "")
+		if status != http.StatusInternalServerError {
+			t.Errorf("expected 500, but got %d", status)
+		}
+	})
+}
@@ -3,7 +3,6 @@ package controller
 import (
 	"database/sql"
 	"errors"
-	"fmt"
 	"net/http"
 	"strconv"
 
@@ -16,13 +15,15 @@ import (
 type CustomerController struct {
 	customerUseCase *usecase.CustomerUseCase
 	addressUseCase  *usecase.AddressUseCase
+	importUseCase   *usecase.CustomerImportUseCase
 	logger          *logger.Logger
 }
 
-func NewCustomerController(customerUseCase *usecase.CustomerUseCase, addressUseCase *usecase.AddressUseCase) *CustomerController {
+func NewCustomerController(customerUseCase *usecase.CustomerUseCase, addressUseCase *usecase.AddressUseCase, importUseCase *usecase.CustomerImportUseCase) *CustomerController {
 	return &CustomerController{
 		customerUseCase: customerUseCase,
 		addressUseCase:  addressUseCase,
+		importUseCase:   importUseCase,
 		logger:          logger.New(),
 	}
 }
@@ -30,24 +31,25 @@ func NewCustomerController(customerUseCase *usecase.CustomerUseCase, addressUseC
 func (c *CustomerController) GetAll(ctx *gin.Context) {
 	limit, err := strconv.Atoi(ctx.Query("limit"))
 	if err != nil {
-		c.logger.Warn("Invalid limit parameter")
+		c.logger.Ctx(ctx.Request.Context()).Warn("Invalid limit parameter")
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit params"})
 		return
 	}
 
 	page, err := strconv.Atoi(ctx.Query("page"))
 	if err != nil {
-		c.logger.Warn("Invalid page parameter")
+		c.logger.Ctx(ctx.Request.Context()).Warn("Invalid page parameter")
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit page"})
 		return
 	}
 
 	customers, pagination, err := c.customerUseCase.GetAll(
+		ctx.Request.Context(),
 		domain.Pagination{Limit: limit, Page: page},
 		domain.FindAllCustomerFilters{Name: ctx.Query("name"), Phone: ctx.Query("phone"), Email: ctx.Query("email")},
 	)
 	if err != nil {
-		c.logger.Error("Error fetching customers", err)
+		c.logger.Ctx(ctx.Request.Context()).Error("Error fetching customers", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Fetching customers fatal failed"})
 		return
 	}
@@ -58,15 +60,15 @@ func (c *CustomerController) GetAll(ctx *gin.Context) {
 func (c *CustomerController) GetById(ctx *gin.Context) {
 	id := ctx.Param("id")
 
-	customer, err := c.customerUseCase.GetById(id)
+	customer, err := c.customerUseCase.GetById(ctx.Request.Context(), id)
 	if err != nil {
 		ctx.IndentedJSON(http.StatusNotFound, gin.H{"message": "Customer not found"})
 		return
 	}
 
-	addresses, err := c.addressUseCase.GetByCustomerId(customer.Id)
+	addresses, err := c.addressUseCase.GetByCustomerId(ctx.Request.Context(), customer.Id)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		fmt.Println(err)
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to fetch customer addresses", err)
 		ctx.IndentedJSON(http.StatusNotFound, gin.H{"message": "Error to get address by customer id"})
 		return
 	}
@@ -74,17 +76,42 @@ func (c *CustomerController) GetById(ctx *gin.Context) {
 	ctx.IndentedJSON(http.StatusOK, gin.H{"customer": customer, "addresses": addresses})
 }
 
+func (c *CustomerController) GetByPhone(ctx *gin.Context) {
+	customer, err := c.customerUseCase.GetByPhone(ctx.Request.Context(), ctx.Query("phone"))
+	if err != nil {
+		if c.handlePhoneError(ctx, err) {
+			return
+		}
+
+		var notFoundErr *domain.CustomerNotFoundError
+		if errors.As(err, &notFoundErr) {
+			ctx.IndentedJSON(http.StatusNotFound, gin.H{"message": "Customer not found"})
+			return
+		}
+
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to fetch customer by phone", err)
+		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch customer"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, customer)
+}
+
 func (c *CustomerController) Update(ctx *gin.Context) {
 	var customer domain.Customer
 	if err := ctx.BindJSON(&customer); err != nil {
-		c.logger.Error("Invalid customer data for update", err)
+		c.logger.Ctx(ctx.Request.Context()).Error("Invalid customer data for update", err)
 		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"message": "Invalid customer data"})
 		return
 	}
 
-	err := c.customerUseCase.Update(customer)
+	err := c.customerUseCase.Update(ctx.Request.Context(), customer)
 	if err != nil {
-		c.logger.Error("Failed to update customer", err)
+		if c.handlePhoneError(ctx, err) {
+			return
+		}
+
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to update customer", err)
 		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"message": "Failed to update customer"})
 		return
 	}
@@ -92,11 +119,36 @@ func (c *CustomerController) Update(ctx *gin.Context) {
 	ctx.IndentedJSON(http.StatusOK, gin.H{"message": "Customer updated successfully"})
 }
 
+func (c *CustomerController) UpdatePreferences(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var preferences domain.CustomerPreferences
+	if err := ctx.BindJSON(&preferences); err != nil {
+		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"message": "Invalid preferences data"})
+		return
+	}
+
+	updated, err := c.customerUseCase.UpdatePreferences(ctx.Request.Context(), id, preferences)
+	if err != nil {
+		var invalidTagErr *domain.InvalidCustomerTagError
+		if errors.As(err, &invalidTagErr) {
+			ctx.IndentedJSON(http.StatusBadRequest, gin.H{"message": invalidTagErr.Error(), "error": "invalid_tag"})
+			return
+		}
+
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to update customer preferences", err)
+		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"message": "Failed to update customer preferences"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, updated)
+}
+
 func (c *CustomerController) Delete(ctx *gin.Context) {
 	id := ctx.Param("id")
-	err := c.customerUseCase.Delete(id)
+	err := c.customerUseCase.Delete(ctx.Request.Context(), id)
 	if err != nil {
-		c.logger.Error("Failed to delete customer", err)
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to delete customer", err)
 		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"message": "Failed to delete customer"})
 		return
 	}
@@ -106,17 +158,65 @@ func (c *CustomerController) Delete(ctx *gin.Context) {
 func (c *CustomerController) Create(ctx *gin.Context) {
 	var newCustomer domain.NewCustomer
 	if err := ctx.BindJSON(&newCustomer); err != nil {
-		c.logger.Error("Invalid customer data for creation", err)
+		c.logger.Ctx(ctx.Request.Context()).Error("Invalid customer data for creation", err)
 		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"message": "Invalid customer data"})
 		return
 	}
 
-	createdCustomer, err := c.customerUseCase.Create(newCustomer)
+	createdCustomer, err := c.customerUseCase.Create(ctx.Request.Context(), newCustomer)
 	if err != nil {
-		c.logger.Error("Failed to create customer", err)
+		if c.handlePhoneError(ctx, err) {
+			return
+		}
+
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to create customer", err)
 		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"message": "Failed to create customer"})
 		return
 	}
 
 	ctx.IndentedJSON(http.StatusCreated, createdCustomer)
 }
+
+// Import recebe a planilha de clientes. Sem confirm=true só devolve a prévia.
+func (c *CustomerController) Import(ctx *gin.Context) {
+	data, confirm, ok := readImportRequest(ctx)
+	if !ok {
+		return
+	}
+
+	preview, err := c.importUseCase.Import(ctx.Request.Context(), data, confirm)
+	if err != nil {
+		if handleImportError(ctx, c.logger, preview, err) {
+			return
+		}
+
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to import customers", err)
+		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"message": "Failed to import customers"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, preview)
+}
+
+// handlePhoneError responde os erros de validação de telefone e informa se o erro foi tratado.
+func (c *CustomerController) handlePhoneError(ctx *gin.Context, err error) bool {
+	var invalidPhoneErr *domain.InvalidPhoneError
+	if errors.As(err, &invalidPhoneErr) {
+		ctx.IndentedJSON(http.StatusBadRequest, gin.H{
+			"message": "Invalid phone number, inform the area code (DDD) and the number",
+			"error":   "invalid_phone",
+		})
+		return true
+	}
+
+	var duplicatePhoneErr *domain.DuplicatePhoneError
+	if errors.As(err, &duplicatePhoneErr) {
+		ctx.IndentedJSON(http.StatusConflict, gin.H{
+			"message": "This phone is already registered for another customer",
+			"error":   "duplicate_phone",
+		})
+		return true
+	}
+
+	return false
+}
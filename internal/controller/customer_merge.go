@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/infra/logger"
+	"github.com/deividr/zion-api/internal/middleware"
+	"github.com/deividr/zion-api/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type CustomerMergeController struct {
+	useCase *usecase.CustomerMergeUseCase
+	logger  *logger.Logger
+}
+
+func NewCustomerMergeController(useCase *usecase.CustomerMergeUseCase) *CustomerMergeController {
+	return &CustomerMergeController{
+		useCase: useCase,
+		logger:  logger.New(),
+	}
+}
+
+func (c *CustomerMergeController) GetDuplicates(ctx *gin.Context) {
+	limit := 0
+	if rawLimit := ctx.Query("limit"); rawLimit != "" {
+		parsed, err := strconv.Atoi(rawLimit)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit params"})
+			return
+		}
+		limit = parsed
+	}
+
+	duplicates, err := c.useCase.GetDuplicates(ctx.Request.Context(), limit)
+	if err != nil {
+		c.logger.Ctx(ctx.Request.Context()).Error("Error fetching duplicate customers", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Fetching duplicate customers fatal failed"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, gin.H{"duplicates": duplicates})
+}
+
+func (c *CustomerMergeController) GetMerges(ctx *gin.Context) {
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit params"})
+		return
+	}
+
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page params"})
+		return
+	}
+
+	merges, pagination, err := c.useCase.GetMerges(ctx.Request.Context(), domain.Pagination{Limit: limit, Page: page})
+	if err != nil {
+		c.logger.Ctx(ctx.Request.Context()).Error("Error fetching customer merges", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Fetching customer merges fatal failed"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, gin.H{"merges": merges, "pagination": pagination})
+}
+
+func (c *CustomerMergeController) Merge(ctx *gin.Context) {
+	var input usecase.MergeCustomersInput
+	if err := ctx.BindJSON(&input); err != nil {
+		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"message": "Invalid merge data"})
+		return
+	}
+
+	merge, err := c.useCase.Merge(ctx.Request.Context(), input, middleware.GetUserId(ctx))
+	if err != nil {
+		var invalidMergeErr *domain.InvalidMergeError
+		if errors.As(err, &invalidMergeErr) {
+			ctx.IndentedJSON(http.StatusBadRequest, gin.H{"message": invalidMergeErr.Reason, "error": "invalid_merge"})
+			return
+		}
+
+		var notFoundErr *domain.CustomerNotFoundError
+		if errors.As(err, &notFoundErr) {
+			ctx.IndentedJSON(http.StatusNotFound, gin.H{"message": notFoundErr.Error(), "error": "customer_not_found"})
+			return
+		}
+
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to merge customers", err)
+		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"message": "Failed to merge customers"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, merge)
+}
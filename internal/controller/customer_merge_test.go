@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type mockCustomerMergeRepository struct {
+	domain.CustomerMergeRepository
+	customers  map[string]bool
+	pagination domain.Pagination
+	merged     bool
+}
+
+func (m *mockCustomerMergeRepository) FindMerges(ctx context.Context, pagination domain.Pagination) ([]domain.CustomerMerge, domain.Pagination, error) {
+	m.pagination = pagination
+	return []domain.CustomerMerge{}, pagination, nil
+}
+
+func (m *mockCustomerMergeRepository) Merge(ctx context.Context, survivorId string, mergedIds []string, mergedBy string) (*domain.CustomerMerge, error) {
+	for _, id := range append([]string{survivorId}, mergedIds...) {
+		if !m.customers[id] {
+			return nil, domain.NewCustomerNotFoundError(id)
+		}
+	}
+	m.merged = true
+	return &domain.CustomerMerge{SurvivorId: survivorId, MergedCustomerIds: mergedIds}, nil
+}
+
+func newCustomerMergeRouter(repo *mockCustomerMergeRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	customerMergeController := NewCustomerMergeController(usecase.NewCustomerMergeUseCase(repo))
+	router := gin.New()
+	router.GET("/customers/merges", customerMergeController.GetMerges)
+	router.POST("/customers/merge", customerMergeController.Merge)
+	return router
+}
+
+func TestCustomerMergeController(t *testing.T) {
+	customers := map[string]bool{"c1": true, "c2": true}
+
+	t.Run("should merge existing customers", func(t *testing.T) {
+		repo := &mockCustomerMergeRepository{customers: customers}
+
+		status, response := serve(newCustomerMergeRouter(repo), http.MethodPost, "/customers/merge", `{"survivorId": "c1", "mergedIds": ["c2"]}`)
+		if status != http.StatusOK || !repo.merged {
+			t.Errorf("expected 200 and the customers merged, but got %d %v", status, response)
+		}
+	})
+
+	t.Run("should respond 404 when the survivor or a merged customer does not exist", func(t *testing.T) {
+		for _, body := range []string{
+			`{"survivorId": "missing", "mergedIds": ["c2"]}`,
+			`{"survivorId": "c1", "mergedIds": ["c2", "missing"]}`,
+		} {
+			status, response := serve(newCustomerMergeRouter(&mockCustomerMergeRepository{customers: customers}), http.MethodPost, "/customers/merge", body)
+			if status != http.StatusNotFound || response["error"] != "customer_not_found" {
+				t.Errorf("%s: expected 404 customer_not_found, but got %d %v", body, status, response)
+			}
+		}
+	})
+
+	t.Run("should respond 400 when the merge is invalid", func(t *testing.T) {
+		for _, body := range []string{
+			`{"survivorId": "c1", "mergedIds": ["c1"]}`,
+			`{"survivorId": "", "mergedIds": ["c2"]}`,
+			`{"survivorId": "c1", "mergedIds": []}`,
+		} {
+			repo := &mockCustomerMergeRepository{customers: customers}
+			status, response := serve(newCustomerMergeRouter(repo), http.MethodPost, "/customers/merge", body)
+			if status != http.StatusBadRequest || response["error"] != "invalid_merge" || repo.merged {
+				t.Errorf("%s: expected 400 invalid_merge, but got %d %v", body, status, response)
+			}
+		}
+	})
+
+	t.Run("should list the merges with the default pagination", func(t *testing.T) {
+		repo := &mockCustomerMergeRepository{}
+
+		status, _ := serve(newCustomerMergeRouter(repo), http.MethodGet, "/customers/merges", "")
+		if status != http.StatusOK || repo.pagination.Limit != 20 || repo.pagination.Page != 1 {
+			t.Errorf("expected 200 with limit 20 and page 1, but got %d %+v", status, repo.pagination)
+		}
+
+		status, _ = serve(newCustomerMergeRouter(repo), http.MethodGet, "/customers/merges?limit=abc", "")
+		if status != http.StatusBadRequest {
+			t.Errorf("expected 400 for an invalid limit, but got %d", status)
+		}
+	})
+}
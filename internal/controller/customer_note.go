@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/infra/logger"
+	"github.com/deividr/zion-api/internal/middleware"
+	"github.com/deividr/zion-api/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type CustomerNoteController struct {
+	useCase *usecase.CustomerNoteUseCase
+	logger  *logger.Logger
+}
+
+func NewCustomerNoteController(useCase *usecase.CustomerNoteUseCase) *CustomerNoteController {
+	return &CustomerNoteController{
+		useCase: useCase,
+		logger:  logger.New(),
+	}
+}
+
+func (c *CustomerNoteController) GetByCustomerId(ctx *gin.Context) {
+	customerId := ctx.Param("id")
+
+	notes, err := c.useCase.GetByCustomerId(ctx.Request.Context(), customerId)
+	if err != nil {
+		c.logger.Ctx(ctx.Request.Context()).Error("Error fetching customer notes", err)
+		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch customer notes"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, gin.H{"notes": notes})
+}
+
+func (c *CustomerNoteController) Create(ctx *gin.Context) {
+	customerId := ctx.Param("id")
+
+	var newNote domain.NewCustomerNote
+	if err := ctx.BindJSON(&newNote); err != nil {
+		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"message": "Invalid note data"})
+		return
+	}
+
+	note, err := c.useCase.Create(ctx.Request.Context(), customerId, middleware.GetUserId(ctx), newNote)
+	if err != nil {
+		var invalidNoteErr *domain.InvalidCustomerNoteError
+		if errors.As(err, &invalidNoteErr) {
+			ctx.IndentedJSON(http.StatusBadRequest, gin.H{"message": invalidNoteErr.Error(), "error": "invalid_note"})
+			return
+		}
+
+		var notFoundErr *domain.CustomerNotFoundError
+		if errors.As(err, &notFoundErr) {
+			ctx.IndentedJSON(http.StatusNotFound, gin.H{"message": "Customer not found"})
+			return
+		}
+
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to create customer note", err)
+		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"message": "Failed to create customer note"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusCreated, note)
+}
+
+func (c *CustomerNoteController) Delete(ctx *gin.Context) {
+	customerId := ctx.Param("id")
+	noteId := ctx.Param("noteId")
+
+	if err := c.useCase.Delete(ctx.Request.Context(), customerId, noteId); err != nil {
+		var notFoundErr *domain.CustomerNoteNotFoundError
+		if errors.As(err, &notFoundErr) {
+			ctx.IndentedJSON(http.StatusNotFound, gin.H{"message": "Customer note not found"})
+			return
+		}
+
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to delete customer note", err)
+		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"message": "Failed to delete customer note"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, gin.H{"message": "Customer note deleted successfully"})
+}
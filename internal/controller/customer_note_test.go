@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type mockCustomerNoteRepository struct {
+	domain.CustomerNoteRepository
+	created bool
+	notes   map[string]string
+}
+
+func (m *mockCustomerNoteRepository) Create(ctx context.Context, customerId string, author string, note domain.NewCustomerNote) (*domain.CustomerNote, error) {
+	m.created = true
+	return &domain.CustomerNote{CustomerId: customerId, Content: note.Content, Author: author}, nil
+}
+
+// Delete só apaga a nota em notes que pertence ao cliente, como o DELETE do repositório
+func (m *mockCustomerNoteRepository) Delete(ctx context.Context, customerId string, noteId string) error {
+	if m.notes[noteId] != customerId {
+		return domain.NewCustomerNoteNotFoundError(noteId)
+	}
+	delete(m.notes, noteId)
+	return nil
+}
+
+func newCustomerNoteRouter(repo *mockCustomerNoteRepository, customerErr error) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	customerNoteController := NewCustomerNoteController(usecase.NewCustomerNoteUseCase(repo, &mockCustomerRepository{err: customerErr}))
+	router := gin.New()
+	router.POST("/customers/:id/notes", customerNoteController.Create)
+	router.DELETE("/customers/:id/notes/:noteId", customerNoteController.Delete)
+	return router
+}
+
+func TestCustomerNoteController(t *testing.T) {
+	t.Run("should create the note with the trimmed content", func(t *testing.T) {
+		repo := &mockCustomerNoteRepository{}
+
+		status, response := serve(newCustomerNoteRouter(repo, nil), http.MethodPost, "/customers/c1/notes", `{"content": " sem cebola "}`)
+		if status != http.StatusCreated || response["content"] != "sem cebola" {
+			t.Errorf("expected 201 with the trimmed content, but got %d %v", status, response)
+		}
+	})
+
+	t.Run("should respond 400 when the content is empty", func(t *testing.T) {
+		repo := &mockCustomerNoteRepository{}
+
+		status, response := serve(newCustomerNoteRouter(repo, nil), http.MethodPost, "/customers/c1/notes", `{"content": "  "}`)
+		if status != http.StatusBadRequest || response["error"] != "invalid_note" || repo.created {
+			t.Errorf("expected 400 invalid_note, but got %d %v", status, response)
+		}
+	})
+
+	t.Run("should respond 404 only when the customer does not exist", func(t *testing.T) {
+		status, _ := serve(newCustomerNoteRouter(&mockCustomerNoteRepository{}, domain.NewCustomerNotFoundError("c1")), http.MethodPost, "/customers/c1/notes", `{"content": "sem cebola"}`)
+		if status != http.StatusNotFound {
+			t.Errorf("expected 404, but got %d", status)
+		}
+
+		status, _ = serve(newCustomerNoteRouter(&mockCustomerNoteRepository{}, errors.New("connection refused")), http.MethodPost, "/customers/c1/notes", `{"content": "sem cebola"}`)
+		if status != http.StatusInternalServerError {
+			t.Errorf("expected 500, but got %d", status)
+		}
+	})
+
+	t.Run("should delete the note of the customer", func(t *testing.T) {
+		repo := &mockCustomerNoteRepository{notes: map[string]string{"n1": "c1"}}
+
+		status, _ := serve(newCustomerNoteRouter(repo, nil), http.MethodDelete, "/customers/c1/notes/n1", "")
+		if status != http.StatusOK || len(repo.notes) != 0 {
+			t.Errorf("expected 200 and the note deleted, but got %d %v", status, repo.notes)
+		}
+	})
+
+	t.Run("should respond 404 when the note does not exist or belongs to another customer", func(t *testing.T) {
+		repo := &mockCustomerNoteRepository{notes: map[string]string{"n1": "c2"}}
+
+		for _, path := range []string{"/customers/c1/notes/n1", "/customers/c1/notes/missing"} {
+			status, response := serve(newCustomerNoteRouter(repo, nil), http.MethodDelete, path, "")
+			if status != http.StatusNotFound || response["message"] != "Customer note not found" {
+				t.Errorf("expected 404 for %s, but got %d %v", path, status, response)
+			}
+		}
+		if len(repo.notes) != 1 {
+			t.Errorf("expected the note of the other customer to be kept, but got %v", repo.notes)
+		}
+	})
+}
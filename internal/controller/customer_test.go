@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type mockCustomerRepository struct {
+	domain.CustomerRepository
+	err error
+}
+
+func (m *mockCustomerRepository) Create(ctx context.Context, customer domain.NewCustomer, jobs []domain.NewBackgroundJob) (*domain.Customer, error) {
+	return nil, m.err
+}
+
+func (m *mockCustomerRepository) Update(ctx context.Context, customer domain.Customer, jobs []domain.NewBackgroundJob) error {
+	return m.err
+}
+
+func (m *mockCustomerRepository) FindByPhone(ctx context.Context, phone string) (*domain.Customer, error) {
+	return nil, m.err
+}
+
+func (m *mockCustomerRepository) FindById(ctx context.Context, id string) (*domain.Customer, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &domain.Customer{Id: id}, nil
+}
+
+func newCustomerRouter(err error) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	customerController := NewCustomerController(usecase.NewCustomerUseCase(&mockCustomerRepository{err: err}), nil, nil)
+	router := gin.New()
+	router.GET("/customers/lookup", customerController.GetByPhone)
+	router.PUT("/customers/:id", customerController.Update)
+	router.POST("/customers", customerController.Create)
+	return router
+}
+
+func serve(router *gin.Engine, method string, path string, body string) (int, map[string]string) {
+	res := httptest.NewRecorder()
+	router.ServeHTTP(res, httptest.NewRequest(method, path, strings.NewReader(body)))
+
+	response := map[string]string{}
+	_ = json.Unmarshal(res.Body.Bytes(), &response)
+	return res.Code, response
+}
+
+func TestCustomerController(t *testing.T) {
+	body := `{"id": "c1", "name": "Maria", "phone": "(11) 99999-0000", "phone2": "(11) 98888-0000"}`
+
+	t.Run("should respond 409 when the phone belongs to another customer", func(t *testing.T) {
+		router := newCustomerRouter(domain.NewDuplicatePhoneError("+5511988880000"))
+
+		for _, method := range []string{http.MethodPost, http.MethodPut} {
+			path := "/customers"
+			if method == http.MethodPut {
+				path = "/customers/c1"
+			}
+
+			status, response := serve(router, method, path, body)
+			if status != http.StatusConflict || response["error"] != "duplicate_phone" {
+				t.Errorf("%s: expected 409 duplicate_phone, but got %d %v", method, status, response)
+			}
+		}
+	})
+
+	t.Run("should respond 404 only when no customer has the phone", func(t *testing.T) {
+		status, _ := serve(newCustomerRouter(domain.NewCustomerNotFoundError("+5511999990000")), http.MethodGet, "/customers/lookup?phone=11999990000", "")
+		if status != http.StatusNotFound {
+			t.Errorf("expected 404, but got %d", status)
+		}
+
+		status, _ = serve(newCustomerRouter(errors.New("connection refused")), http.MethodGet, "/customers/lookup?phone=11999990000", "")
+		if status != http.StatusInternalServerError {
+			t.Errorf("expected 500, but got %d", status)
+		}
+	})
+}
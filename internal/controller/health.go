@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/infra/logger"
+	"github.com/deividr/zion-api/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type HealthController struct {
+	useCase *usecase.HealthUseCase
+	logger  *logger.Logger
+}
+
+func NewHealthController(useCase *usecase.HealthUseCase) *HealthController {
+	return &HealthController{
+		useCase: useCase,
+		logger:  logger.New(),
+	}
+}
+
+// Healthz indica apenas que o processo está de pé; não consulta dependências.
+func (c *HealthController) Healthz(ctx *gin.Context) {
+	ctx.IndentedJSON(http.StatusOK, gin.H{"status": domain.HealthStatusOk})
+}
+
+// Readyz responde 503 quando alguma dependência crítica falha.
+func (c *HealthController) Readyz(ctx *gin.Context) {
+	report := c.useCase.Ready(ctx.Request.Context())
+
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+		for _, check := range report.Checks {
+			if check.Error != nil {
+				c.logger.Ctx(ctx.Request.Context()).Warn("Readiness check " + check.Name + " failed: " + *check.Error)
+			}
+		}
+	}
+
+	ctx.IndentedJSON(status, report)
+}
+
+func (c *HealthController) Version(ctx *gin.Context) {
+	ctx.IndentedJSON(http.StatusOK, c.useCase.Version())
+}
@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/infra/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportFileSize limita a planilha enviada; uma tabela de preços tem poucos kilobytes.
+const maxImportFileSize = 10 << 20
+
+// readImportRequest lê o arquivo do campo "file" e o parâmetro confirm. Responde 400 e
+// devolve false quando a requisição é inválida.
+func readImportRequest(ctx *gin.Context) ([]byte, bool, bool) {
+	confirm := false
+	if value := ctx.Query("confirm"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			ctx.IndentedJSON(http.StatusBadRequest, gin.H{"message": "Invalid confirm parameter, use true or false"})
+			return nil, false, false
+		}
+		confirm = parsed
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportFileSize)
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			ctx.IndentedJSON(http.StatusRequestEntityTooLarge, gin.H{"message": "The file is larger than 10 MB"})
+			return nil, false, false
+		}
+		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"message": "Send the spreadsheet in the file field of a multipart form"})
+		return nil, false, false
+	}
+	file, err := header.Open()
+	if err != nil {
+		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"message": "Unable to read the uploaded file"})
+		return nil, false, false
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"message": "Unable to read the uploaded file"})
+		return nil, false, false
+	}
+	return data, confirm, true
+}
+
+// handleImportError responde os erros de validação da planilha e informa se o erro foi
+// tratado. Linhas inválidas voltam com a prévia, para que o usuário veja o que corrigir.
+func handleImportError[T any](ctx *gin.Context, log *logger.Logger, preview *domain.ImportPreview[T], err error) bool {
+	var invalidSpreadsheetErr *domain.InvalidSpreadsheetError
+	if errors.As(err, &invalidSpreadsheetErr) {
+		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"message": invalidSpreadsheetErr.Reason, "error": "invalid_spreadsheet"})
+		return true
+	}
+
+	var invalidRowsErr *domain.InvalidImportRowsError
+	if errors.As(err, &invalidRowsErr) {
+		ctx.IndentedJSON(http.StatusUnprocessableEntity, gin.H{"message": invalidRowsErr.Error(), "error": "invalid_rows", "preview": preview})
+		return true
+	}
+
+	var duplicatePhoneErr *domain.DuplicatePhoneError
+	if errors.As(err, &duplicatePhoneErr) {
+		log.Ctx(ctx.Request.Context()).Warn("Import aborted by a phone registered in the meantime")
+		ctx.IndentedJSON(http.StatusConflict, gin.H{
+			"message": "A phone of the spreadsheet was registered for another customer, review the import again",
+			"error":   "duplicate_phone",
+		})
+		return true
+	}
+
+	var deletedErr *domain.ImportRowDeletedError
+	if errors.As(err, &deletedErr) {
+		ctx.IndentedJSON(http.StatusConflict, gin.H{
+			"message": deletedErr.Error() + ", review the import again",
+			"error":   "row_deleted",
+		})
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/infra/logger"
+	"github.com/deividr/zion-api/internal/middleware"
+	"github.com/deividr/zion-api/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type JobController struct {
+	useCase *usecase.JobUseCase
+	logger  *logger.Logger
+}
+
+func NewJobController(useCase *usecase.JobUseCase) *JobController {
+	return &JobController{
+		useCase: useCase,
+		logger:  logger.New(),
+	}
+}
+
+func (c *JobController) GetAll(ctx *gin.Context) {
+	jobs, err := c.useCase.GetAll(ctx.Request.Context())
+	if err != nil {
+		c.logger.Ctx(ctx.Request.Context()).Error("Error fetching jobs", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Fetching jobs fatal failed"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, gin.H{"jobs": jobs})
+}
+
+func (c *JobController) GetRuns(ctx *gin.Context) {
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit params"})
+		return
+	}
+
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page params"})
+		return
+	}
+
+	runs, pagination, err := c.useCase.GetRuns(ctx.Request.Context(), ctx.Param("name"), domain.Pagination{Limit: limit, Page: page})
+	if err != nil {
+		var notFoundErr *domain.JobNotFoundError
+		if errors.As(err, &notFoundErr) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message": notFoundErr.Error(), "error": "job_not_found"})
+			return
+		}
+
+		c.logger.Ctx(ctx.Request.Context()).Error("Error fetching job runs", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Fetching job runs fatal failed"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, gin.H{"runs": runs, "pagination": pagination})
+}
+
+func (c *JobController) Trigger(ctx *gin.Context) {
+	run, err := c.useCase.Trigger(ctx.Request.Context(), ctx.Param("name"), middleware.GetUserId(ctx))
+	if err != nil {
+		var notFoundErr *domain.JobNotFoundError
+		if errors.As(err, &notFoundErr) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message": notFoundErr.Error(), "error": "job_not_found"})
+			return
+		}
+
+		var runningErr *domain.JobAlreadyRunningError
+		if errors.As(err, &runningErr) {
+			ctx.JSON(http.StatusConflict, gin.H{"message": runningErr.Error(), "error": "job_already_running"})
+			return
+		}
+
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to trigger job", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to trigger job"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusAccepted, run)
+}
@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/infra/logger"
+	"github.com/deividr/zion-api/internal/middleware"
+	"github.com/deividr/zion-api/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type KitchenController struct {
+	useCase *usecase.KitchenUseCase
+	logger  *logger.Logger
+}
+
+func NewKitchenController(useCase *usecase.KitchenUseCase) *KitchenController {
+	return &KitchenController{
+		useCase: useCase,
+		logger:  logger.New(),
+	}
+}
+
+// GetTickets lista as linhas de produção. Filtros opcionais: pickupDateStart, pickupDateEnd
+// (RFC3339) e includeDone; sem período são usados os pedidos de hoje e amanhã.
+func (c *KitchenController) GetTickets(ctx *gin.Context) {
+	filters, ok := c.parseFilters(ctx)
+	if !ok {
+		return
+	}
+
+	tickets, err := c.useCase.GetTickets(ctx.Request.Context(), filters)
+	if err != nil {
+		c.logger.Ctx(ctx.Request.Context()).Error("Error fetching kitchen tickets", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Fetching kitchen tickets fatal failed"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, gin.H{"tickets": tickets})
+}
+
+func (c *KitchenController) GetBatches(ctx *gin.Context) {
+	filters, ok := c.parseFilters(ctx)
+	if !ok {
+		return
+	}
+
+	batches, err := c.useCase.GetBatches(ctx.Request.Context(), filters)
+	if err != nil {
+		c.logger.Ctx(ctx.Request.Context()).Error("Error fetching kitchen batches", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Fetching kitchen batches fatal failed"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, gin.H{"batches": batches})
+}
+
+type updateKitchenTicketStatusInput struct {
+	Status string `json:"status"`
+}
+
+func (c *KitchenController) UpdateTicketStatus(ctx *gin.Context) {
+	var input updateKitchenTicketStatusInput
+	if err := ctx.BindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid kitchen ticket status data"})
+		return
+	}
+
+	progress, err := c.useCase.SetTicketStatus(ctx.Request.Context(), ctx.Param("id"), input.Status, middleware.GetUserId(ctx))
+	if err != nil {
+		c.handleTicketError(ctx, err)
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, progress)
+}
+
+type bumpKitchenBatchInput struct {
+	TicketIds []string `json:"ticketIds"`
+}
+
+func (c *KitchenController) BumpBatch(ctx *gin.Context) {
+	var input bumpKitchenBatchInput
+	if err := ctx.BindJSON(&input); err != nil || len(input.TicketIds) == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid kitchen batch data"})
+		return
+	}
+
+	results, err := c.useCase.BumpBatch(ctx.Request.Context(), input.TicketIds, middleware.GetUserId(ctx))
+	if err != nil {
+		c.handleTicketError(ctx, err)
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, gin.H{"tickets": results})
+}
+
+func (c *KitchenController) handleTicketError(ctx *gin.Context, err error) {
+	var invalidStatusErr *domain.InvalidProductionStatusError
+	if errors.As(err, &invalidStatusErr) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": invalidStatusErr.Error(), "error": "invalid_production_status"})
+		return
+	}
+
+	var notFoundErr *domain.KitchenTicketNotFoundError
+	if errors.As(err, &notFoundErr) {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": notFoundErr.Error(), "error": "kitchen_ticket_not_found"})
+		return
+	}
+
+	c.logger.Ctx(ctx.Request.Context()).Error("Failed to update kitchen ticket", err)
+	ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to update kitchen ticket"})
+}
+
+func (c *KitchenController) parseFilters(ctx *gin.Context) (domain.KitchenFilters, bool) {
+	var filters domain.KitchenFilters
+
+	if raw := ctx.Query("pickupDateStart"); raw != "" {
+		pickupDateStart, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pickupDateStart params"})
+			return filters, false
+		}
+		filters.PickupDateStart = pickupDateStart
+	}
+
+	if raw := ctx.Query("pickupDateEnd"); raw != "" {
+		pickupDateEnd, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pickupDateEnd params"})
+			return filters, false
+		}
+		filters.PickupDateEnd = pickupDateEnd
+	}
+
+	if raw := ctx.Query("includeDone"); raw != "" {
+		includeDone, err := strconv.ParseBool(raw)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid includeDone params"})
+			return filters, false
+		}
+		filters.IncludeDone = includeDone
+	}
+
+	return filters, true
+}
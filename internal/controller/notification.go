@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/deividr/zion-api/internal/infra/logger"
+	"github.com/deividr/zion-api/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationController struct {
+	useCase *usecase.NotificationUseCase
+	logger  *logger.Logger
+}
+
+func NewNotificationController(useCase *usecase.NotificationUseCase) *NotificationController {
+	return &NotificationController{
+		useCase: useCase,
+		logger:  logger.New(),
+	}
+}
+
+func (c *NotificationController) GetByOrderId(ctx *gin.Context) {
+	orderId := ctx.Param("id")
+
+	notifications, err := c.useCase.GetByOrderId(ctx.Request.Context(), orderId)
+	if err != nil {
+		c.logger.Ctx(ctx.Request.Context()).Error("Error fetching order notifications", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Fetching order notifications fatal failed"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, gin.H{"notifications": notifications})
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -50,9 +51,9 @@ func (c *OrderController) GetAll(ctx *gin.Context) {
 		return
 	}
 
-	orders, pagination, err := c.useCase.GetAll(domain.Pagination{Limit: limit, Page: page}, domain.FindAllOrderFilters{Search: &search, PickupDateStart: pickupDateStart, PickupDateEnd: pickupDateEnd})
+	orders, pagination, err := c.useCase.GetAll(ctx.Request.Context(), domain.Pagination{Limit: limit, Page: page}, domain.FindAllOrderFilters{Search: &search, PickupDateStart: pickupDateStart, PickupDateEnd: pickupDateEnd})
 	if err != nil {
-		c.logger.Error("Error fetching orders", err)
+		c.logger.Ctx(ctx.Request.Context()).Error("Error fetching orders", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Fetching orders fatal failed"})
 		return
 	}
@@ -67,8 +68,8 @@ func (c *OrderController) Update(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.useCase.Update(input); err != nil {
-		c.logger.Error("Failed to update order", err)
+	if err := c.useCase.Update(ctx.Request.Context(), input); err != nil {
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to update order", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to update order"})
 		return
 	}
@@ -76,11 +77,52 @@ func (c *OrderController) Update(ctx *gin.Context) {
 	ctx.IndentedJSON(http.StatusOK, gin.H{"message": "Order updated successfully"})
 }
 
+type updateOrderStatusInput struct {
+	Status string `json:"status"`
+}
+
+func (c *OrderController) UpdateStatus(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var input updateOrderStatusInput
+	if err := ctx.BindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid order status data"})
+		return
+	}
+
+	order, err := c.useCase.UpdateStatus(ctx.Request.Context(), id, input.Status)
+	if err != nil {
+		var invalidStatusErr *domain.InvalidOrderStatusError
+		if errors.As(err, &invalidStatusErr) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": invalidStatusErr.Error(), "error": "invalid_status"})
+			return
+		}
+
+		var notFoundErr *domain.OrderNotFoundError
+		if errors.As(err, &notFoundErr) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message": notFoundErr.Error(), "error": "order_not_found"})
+			return
+		}
+
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to update order status", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to update order status"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, order)
+}
+
 func (c *OrderController) Delete(ctx *gin.Context) {
 	id := ctx.Param("id")
-	err := c.useCase.Delete(id)
+	err := c.useCase.Delete(ctx.Request.Context(), id)
 	if err != nil {
-		c.logger.Error("Failed to delete order", err)
+		var notFoundErr *domain.OrderNotFoundError
+		if errors.As(err, &notFoundErr) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message": notFoundErr.Error(), "error": "order_not_found"})
+			return
+		}
+
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to delete order", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to delete order"})
 		return
 	}
@@ -94,9 +136,9 @@ func (c *OrderController) Create(ctx *gin.Context) {
 		return
 	}
 
-	createdOrder, err := c.useCase.Create(input)
+	createdOrder, err := c.useCase.Create(ctx.Request.Context(), input)
 	if err != nil {
-		c.logger.Error("Failed to create order", err)
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to create order", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to create order"})
 		return
 	}
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/infra/logger"
+	"github.com/deividr/zion-api/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+const orderEventsHeartbeat = 20 * time.Second
+
+type OrderEventController struct {
+	useCase *usecase.OrderEventUseCase
+	logger  *logger.Logger
+}
+
+func NewOrderEventController(useCase *usecase.OrderEventUseCase) *OrderEventController {
+	return &OrderEventController{
+		useCase: useCase,
+		logger:  logger.New(),
+	}
+}
+
+// Stream mantém a conexão aberta enviando os eventos de pedidos via Server-Sent Events.
+// Filtros opcionais: pickupDateStart, pickupDateEnd (RFC3339) e status (separados por vírgula).
+func (c *OrderEventController) Stream(ctx *gin.Context) {
+	var filter domain.OrderEventFilter
+
+	if raw := ctx.Query("pickupDateStart"); raw != "" {
+		pickupDateStart, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pickupDateStart params"})
+			return
+		}
+		filter.PickupDateStart = &pickupDateStart
+	}
+
+	if raw := ctx.Query("pickupDateEnd"); raw != "" {
+		pickupDateEnd, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pickupDateEnd params"})
+			return
+		}
+		filter.PickupDateEnd = &pickupDateEnd
+	}
+
+	if raw := ctx.Query("status"); raw != "" {
+		for _, status := range strings.Split(raw, ",") {
+			if status = strings.TrimSpace(status); status != "" {
+				filter.Statuses = append(filter.Statuses, status)
+			}
+		}
+	}
+
+	events, unsubscribe, err := c.useCase.Subscribe(ctx.Request.Context(), filter)
+	if err != nil {
+		var invalidStatusErr *domain.InvalidOrderStatusError
+		if errors.As(err, &invalidStatusErr) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": invalidStatusErr.Error(), "error": "invalid_status"})
+			return
+		}
+
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to subscribe to order events", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to subscribe to order events"})
+		return
+	}
+	defer unsubscribe()
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+
+	heartbeat := time.NewTicker(orderEventsHeartbeat)
+	defer heartbeat.Stop()
+
+	ctx.SSEvent("connected", gin.H{"filters": gin.H{
+		"pickupDateStart": filter.PickupDateStart,
+		"pickupDateEnd":   filter.PickupDateEnd,
+		"status":          filter.Statuses,
+	}})
+	ctx.Writer.Flush()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			ctx.SSEvent(event.Type, event)
+			return true
+		case <-heartbeat.C:
+			// Comentário SSE mantém a conexão viva através de proxies sem gerar evento no cliente.
+			_, err := io.WriteString(w, ": ping\n\n")
+			return err == nil
+		}
+	})
+}
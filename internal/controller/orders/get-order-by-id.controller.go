@@ -14,7 +14,7 @@ type GetOrderByIdController struct {
 func (c *GetOrderByIdController) Handle(ctx *gin.Context) {
 	id := ctx.Param("id")
 
-	order, err := c.useCase.Execute(id)
+	order, err := c.useCase.Execute(ctx.Request.Context(), id)
 	if err != nil {
 		ctx.IndentedJSON(http.StatusNotFound, gin.H{"message": "Order not found"})
 		return
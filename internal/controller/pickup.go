@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/infra/logger"
+	"github.com/deividr/zion-api/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type PickupController struct {
+	useCase *usecase.PickupUseCase
+	logger  *logger.Logger
+}
+
+func NewPickupController(useCase *usecase.PickupUseCase) *PickupController {
+	return &PickupController{
+		useCase: useCase,
+		logger:  logger.New(),
+	}
+}
+
+func (c *PickupController) Scan(ctx *gin.Context) {
+	order, err := c.useCase.Scan(ctx.Request.Context(), ctx.Param("code"))
+	if err != nil {
+		var notFoundErr *domain.PickupCodeNotFoundError
+		if errors.As(err, &notFoundErr) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message": notFoundErr.Error(), "error": "pickup_code_not_found"})
+			return
+		}
+
+		var notPickableErr *domain.OrderNotPickableError
+		if errors.As(err, &notPickableErr) {
+			ctx.JSON(http.StatusConflict, gin.H{"message": notPickableErr.Error(), "error": "order_not_pickable"})
+			return
+		}
+
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to scan pickup code", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to scan pickup code"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, order)
+}
+
+// GetCodeImage devolve o PNG do código de retirada. Parâmetros opcionais: format (qr ou code128)
+// e size (largura em pixels).
+func (c *PickupController) GetCodeImage(ctx *gin.Context) {
+	size, err := strconv.Atoi(ctx.DefaultQuery("size", "0"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid size params"})
+		return
+	}
+
+	image, err := c.useCase.GetCodeImage(ctx.Request.Context(), ctx.Param("id"), ctx.DefaultQuery("format", domain.PickupCodeFormatQR), size)
+	if err != nil {
+		var formatErr *domain.InvalidPickupCodeFormatError
+		if errors.As(err, &formatErr) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": formatErr.Error(), "error": "invalid_format"})
+			return
+		}
+
+		var notFoundErr *domain.OrderNotFoundError
+		if errors.As(err, &notFoundErr) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message": notFoundErr.Error(), "error": "order_not_found"})
+			return
+		}
+
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to render pickup code", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to render pickup code"})
+		return
+	}
+
+	ctx.Header("Cache-Control", "private, max-age=86400")
+	ctx.Data(http.StatusOK, "image/png", image)
+}
@@ -0,0 +1,217 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/infra/logger"
+	"github.com/deividr/zion-api/internal/middleware"
+	"github.com/deividr/zion-api/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type PrintController struct {
+	useCase *usecase.PrintUseCase
+	logger  *logger.Logger
+}
+
+func NewPrintController(useCase *usecase.PrintUseCase) *PrintController {
+	return &PrintController{
+		useCase: useCase,
+		logger:  logger.New(),
+	}
+}
+
+func (c *PrintController) GetAll(ctx *gin.Context) {
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit params"})
+		return
+	}
+
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page params"})
+		return
+	}
+
+	filters := domain.PrintJobFilters{
+		Printer: ctx.Query("printer"),
+		Status:  ctx.Query("status"),
+		OrderId: ctx.Query("orderId"),
+	}
+
+	jobs, pagination, err := c.useCase.GetAll(ctx.Request.Context(), filters, domain.Pagination{Limit: limit, Page: page})
+	if err != nil {
+		c.logger.Ctx(ctx.Request.Context()).Error("Error fetching print jobs", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Fetching print jobs fatal failed"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, gin.H{"jobs": jobs, "pagination": pagination})
+}
+
+// GetRaw devolve os bytes ESC/POS do job, para agentes que enviam o arquivo direto à impressora.
+func (c *PrintController) GetRaw(ctx *gin.Context) {
+	job, err := c.useCase.GetById(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		c.handleError(ctx, err, "Failed to fetch print job")
+		return
+	}
+
+	ctx.Data(http.StatusOK, "application/octet-stream", job.Payload)
+}
+
+type printOrderInput struct {
+	Kind    string `json:"kind"`
+	Printer string `json:"printer"`
+}
+
+func (c *PrintController) PrintOrder(ctx *gin.Context) {
+	var input printOrderInput
+	if err := ctx.ShouldBindJSON(&input); err != nil && ctx.Request.ContentLength > 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid print data"})
+		return
+	}
+
+	job, err := c.useCase.PrintOrder(ctx.Request.Context(), ctx.Param("id"), input.Kind, input.Printer, middleware.GetUserId(ctx))
+	if err != nil {
+		c.handleError(ctx, err, "Failed to print order")
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusCreated, job)
+}
+
+type printProductionListInput struct {
+	Date    string `json:"date"`
+	Printer string `json:"printer"`
+}
+
+// PrintProductionList recebe a data no formato YYYY-MM-DD; sem data é impressa a produção de hoje.
+func (c *PrintController) PrintProductionList(ctx *gin.Context) {
+	var input printProductionListInput
+	if err := ctx.ShouldBindJSON(&input); err != nil && ctx.Request.ContentLength > 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid print data"})
+		return
+	}
+
+	var date time.Time
+	if input.Date != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", input.Date, domain.StoreLocation)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid date, expected YYYY-MM-DD"})
+			return
+		}
+		date = parsed
+	}
+
+	job, err := c.useCase.PrintProductionList(ctx.Request.Context(), date, input.Printer, middleware.GetUserId(ctx))
+	if err != nil {
+		c.handleError(ctx, err, "Failed to print production list")
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusCreated, job)
+}
+
+type reprintInput struct {
+	Printer string `json:"printer"`
+}
+
+func (c *PrintController) Reprint(ctx *gin.Context) {
+	var input reprintInput
+	if err := ctx.ShouldBindJSON(&input); err != nil && ctx.Request.ContentLength > 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid print data"})
+		return
+	}
+
+	job, err := c.useCase.Reprint(ctx.Request.Context(), ctx.Param("id"), input.Printer, middleware.GetUserId(ctx))
+	if err != nil {
+		c.handleError(ctx, err, "Failed to reprint")
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusCreated, job)
+}
+
+// Claim é consultado pelo agente de impressão. Parâmetros: printer (obrigatório), agent,
+// limit (até 20) e wait (segundos de long polling, até 30).
+func (c *PrintController) Claim(ctx *gin.Context) {
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "1"))
+	if err != nil || limit < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit params"})
+		return
+	}
+
+	wait, err := strconv.Atoi(ctx.DefaultQuery("wait", "0"))
+	if err != nil || wait < 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wait params"})
+		return
+	}
+
+	agentId := ctx.DefaultQuery("agent", ctx.ClientIP())
+
+	jobs, err := c.useCase.Claim(ctx.Request.Context(), ctx.Query("printer"), agentId, limit, time.Duration(wait)*time.Second)
+	if err != nil {
+		c.handleError(ctx, err, "Failed to claim print jobs")
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, gin.H{"jobs": jobs})
+}
+
+type ackPrintJobInput struct {
+	Printed bool   `json:"printed"`
+	Error   string `json:"error"`
+}
+
+func (c *PrintController) Ack(ctx *gin.Context) {
+	var input ackPrintJobInput
+	if err := ctx.BindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid print job ack data"})
+		return
+	}
+
+	// O agente se identifica como no Claim, para que só quem reservou o job possa confirmá-lo
+	agentId := ctx.DefaultQuery("agent", ctx.ClientIP())
+
+	if err := c.useCase.Ack(ctx.Request.Context(), ctx.Param("id"), agentId, input.Printed, input.Error); err != nil {
+		c.handleError(ctx, err, "Failed to ack print job")
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c *PrintController) handleError(ctx *gin.Context, err error, message string) {
+	var invalidErr *domain.InvalidPrintJobError
+	if errors.As(err, &invalidErr) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": invalidErr.Error(), "error": "invalid_print_job"})
+		return
+	}
+
+	var notFoundErr *domain.PrintJobNotFoundError
+	if errors.As(err, &notFoundErr) {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": notFoundErr.Error(), "error": "print_job_not_found"})
+		return
+	}
+
+	var notClaimedErr *domain.PrintJobNotClaimedError
+	if errors.As(err, &notClaimedErr) {
+		ctx.JSON(http.StatusConflict, gin.H{"message": notClaimedErr.Error(), "error": "print_job_not_claimed"})
+		return
+	}
+
+	var orderNotFoundErr *domain.OrderNotFoundError
+	if errors.As(err, &orderNotFoundErr) {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": orderNotFoundErr.Error(), "error": "order_not_found"})
+		return
+	}
+
+	c.logger.Ctx(ctx.Request.Context()).Error(message, err)
+	ctx.JSON(http.StatusInternalServerError, gin.H{"message": message})
+}
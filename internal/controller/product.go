@@ -10,21 +10,23 @@ import (
 )
 
 type ProductController struct {
-	useCase *usecase.ProductUseCase
-	logger  *logger.Logger
+	useCase       *usecase.ProductUseCase
+	importUseCase *usecase.ProductImportUseCase
+	logger        *logger.Logger
 }
 
-func NewProductController(useCase *usecase.ProductUseCase) *ProductController {
+func NewProductController(useCase *usecase.ProductUseCase, importUseCase *usecase.ProductImportUseCase) *ProductController {
 	return &ProductController{
-		useCase: useCase,
-		logger:  logger.New(),
+		useCase:       useCase,
+		importUseCase: importUseCase,
+		logger:        logger.New(),
 	}
 }
 
 func (c *ProductController) GetAll(ctx *gin.Context) {
-	products, err := c.useCase.GetAll(domain.FindAllProductFilters{Name: ctx.Query("name")})
+	products, err := c.useCase.GetAll(ctx.Request.Context(), domain.FindAllProductFilters{Name: ctx.Query("name")})
 	if err != nil {
-		c.logger.Error("Error fetching products", err)
+		c.logger.Ctx(ctx.Request.Context()).Error("Error fetching products", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Fetching products fatal failed"})
 		return
 	}
@@ -35,7 +37,7 @@ func (c *ProductController) GetAll(ctx *gin.Context) {
 func (c *ProductController) GetById(ctx *gin.Context) {
 	id := ctx.Param("id")
 
-	product, err := c.useCase.GetById(id)
+	product, err := c.useCase.GetById(ctx.Request.Context(), id)
 	if err != nil {
 		ctx.IndentedJSON(http.StatusNotFound, gin.H{"message": "Product not found"})
 		return
@@ -51,9 +53,9 @@ func (c *ProductController) Update(ctx *gin.Context) {
 		return
 	}
 
-	err := c.useCase.Update(product)
+	err := c.useCase.Update(ctx.Request.Context(), product)
 	if err != nil {
-		c.logger.Error("Failed to update product", err)
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to update product", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to update product"})
 		return
 	}
@@ -63,9 +65,9 @@ func (c *ProductController) Update(ctx *gin.Context) {
 
 func (c *ProductController) Delete(ctx *gin.Context) {
 	id := ctx.Param("id")
-	err := c.useCase.Delete(id)
+	err := c.useCase.Delete(ctx.Request.Context(), id)
 	if err != nil {
-		c.logger.Error("Failed to delete product", err)
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to delete product", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to delete product"})
 		return
 	}
@@ -79,12 +81,33 @@ func (c *ProductController) Create(ctx *gin.Context) {
 		return
 	}
 
-	createdProduct, err := c.useCase.Create(newProduct)
+	createdProduct, err := c.useCase.Create(ctx.Request.Context(), newProduct)
 	if err != nil {
-		c.logger.Error("Failed to create product", err)
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to create product", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to create product"})
 		return
 	}
 
 	ctx.IndentedJSON(http.StatusCreated, createdProduct)
 }
+
+// Import recebe a planilha de produtos. Sem confirm=true só devolve a prévia.
+func (c *ProductController) Import(ctx *gin.Context) {
+	data, confirm, ok := readImportRequest(ctx)
+	if !ok {
+		return
+	}
+
+	preview, err := c.importUseCase.Import(ctx.Request.Context(), data, confirm)
+	if err != nil {
+		if handleImportError(ctx, c.logger, preview, err) {
+			return
+		}
+
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to import products", err)
+		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"message": "Failed to import products"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, preview)
+}
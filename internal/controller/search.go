@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/deividr/zion-api/internal/infra/logger"
+	"github.com/deividr/zion-api/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// maxSearchLimit limita os resultados de cada tipo; a busca roda uma consulta por tipo.
+const maxSearchLimit = 50
+
+type SearchController struct {
+	useCase *usecase.SearchUseCase
+	logger  *logger.Logger
+}
+
+func NewSearchController(useCase *usecase.SearchUseCase) *SearchController {
+	return &SearchController{
+		useCase: useCase,
+		logger:  logger.New(),
+	}
+}
+
+func (c *SearchController) Search(ctx *gin.Context) {
+	term := strings.TrimSpace(ctx.Query("q"))
+	if len([]rune(term)) < 2 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Search term must have at least 2 characters"})
+		return
+	}
+
+	limit := 0
+	if rawLimit := ctx.Query("limit"); rawLimit != "" {
+		parsed, err := strconv.Atoi(rawLimit)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit params"})
+			return
+		}
+		limit = min(parsed, maxSearchLimit)
+	}
+
+	result, err := c.useCase.Search(ctx.Request.Context(), term, limit)
+	if err != nil {
+		c.logger.Ctx(ctx.Request.Context()).Error("Error searching", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Search fatal failed"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, result)
+}
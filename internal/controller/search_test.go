@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type mockSearchRepository struct {
+	limits []int
+}
+
+func (m *mockSearchRepository) SearchCustomers(ctx context.Context, term string, limit int) ([]domain.Customer, error) {
+	m.limits = append(m.limits, limit)
+	return []domain.Customer{}, nil
+}
+
+func (m *mockSearchRepository) SearchProducts(ctx context.Context, term string, limit int) ([]domain.Product, error) {
+	m.limits = append(m.limits, limit)
+	return []domain.Product{}, nil
+}
+
+func (m *mockSearchRepository) SearchOrders(ctx context.Context, term string, limit int) ([]domain.SearchOrder, error) {
+	m.limits = append(m.limits, limit)
+	return []domain.SearchOrder{}, nil
+}
+
+func newSearchRouter(repo *mockSearchRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	searchController := NewSearchController(usecase.NewSearchUseCase(repo))
+	router := gin.New()
+	router.GET("/search", searchController.Search)
+	return router
+}
+
+func TestSearchController(t *testing.T) {
+	t.Run("should clamp the limit of each result type", func(t *testing.T) {
+		repo := &mockSearchRepository{}
+
+		status, _ := serve(newSearchRouter(repo), http.MethodGet, "/search?q=maria&limit=100000", "")
+		if status != http.StatusOK {
+			t.Fatalf("expected 200, but got %d", status)
+		}
+		for _, limit := range repo.limits {
+			if limit != maxSearchLimit {
+				t.Errorf("expected every search to be limited to %d, but got %v", maxSearchLimit, repo.limits)
+				break
+			}
+		}
+	})
+
+	t.Run("should keep a limit below the maximum", func(t *testing.T) {
+		repo := &mockSearchRepository{}
+
+		serve(newSearchRouter(repo), http.MethodGet, "/search?q=maria&limit=5", "")
+		if len(repo.limits) != 3 || repo.limits[0] != 5 {
+			t.Errorf("expected limit 5, but got %v", repo.limits)
+		}
+	})
+
+	t.Run("should reject an invalid limit", func(t *testing.T) {
+		status, _ := serve(newSearchRouter(&mockSearchRepository{}), http.MethodGet, "/search?q=maria&limit=abc", "")
+		if status != http.StatusBadRequest {
+			t.Errorf("expected 400, but got %d", status)
+		}
+	})
+}
@@ -18,7 +18,7 @@ func NewUploadController(uploadUseCase *upload.UploadUseCase) *UploadController
 }
 
 func (c *UploadController) GetPresignedURL(ctx *gin.Context) {
-	response, err := c.uploadUseCase.Execute()
+	response, err := c.uploadUseCase.Execute(ctx.Request.Context())
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/infra/logger"
+	"github.com/deividr/zion-api/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookController struct {
+	useCase *usecase.WebhookUseCase
+	logger  *logger.Logger
+}
+
+func NewWebhookController(useCase *usecase.WebhookUseCase) *WebhookController {
+	return &WebhookController{
+		useCase: useCase,
+		logger:  logger.New(),
+	}
+}
+
+func (c *WebhookController) GetAll(ctx *gin.Context) {
+	subscriptions, err := c.useCase.GetAll(ctx.Request.Context())
+	if err != nil {
+		c.logger.Ctx(ctx.Request.Context()).Error("Error fetching webhook subscriptions", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Fetching webhook subscriptions fatal failed"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, gin.H{"webhooks": subscriptions, "eventTypes": domain.WebhookEventTypes})
+}
+
+func (c *WebhookController) GetById(ctx *gin.Context) {
+	subscription, err := c.useCase.GetById(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		if c.handleWebhookNotFound(ctx, err) {
+			return
+		}
+
+		c.logger.Ctx(ctx.Request.Context()).Error("Error fetching webhook subscription", err)
+		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch webhook subscription"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, subscription)
+}
+
+func (c *WebhookController) Create(ctx *gin.Context) {
+	var input domain.NewWebhookSubscription
+	if err := ctx.BindJSON(&input); err != nil {
+		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"message": "Invalid webhook data"})
+		return
+	}
+
+	subscription, err := c.useCase.Create(ctx.Request.Context(), input)
+	if err != nil {
+		if c.handleInvalidWebhook(ctx, err) {
+			return
+		}
+
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to create webhook subscription", err)
+		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"message": "Failed to create webhook subscription"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusCreated, subscription)
+}
+
+func (c *WebhookController) Update(ctx *gin.Context) {
+	var input domain.NewWebhookSubscription
+	if err := ctx.BindJSON(&input); err != nil {
+		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"message": "Invalid webhook data"})
+		return
+	}
+
+	subscription, err := c.useCase.Update(ctx.Request.Context(), ctx.Param("id"), input)
+	if err != nil {
+		if c.handleInvalidWebhook(ctx, err) || c.handleWebhookNotFound(ctx, err) {
+			return
+		}
+
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to update webhook subscription", err)
+		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"message": "Failed to update webhook subscription"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, subscription)
+}
+
+func (c *WebhookController) Delete(ctx *gin.Context) {
+	if err := c.useCase.Delete(ctx.Request.Context(), ctx.Param("id")); err != nil {
+		if c.handleWebhookNotFound(ctx, err) {
+			return
+		}
+
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to delete webhook subscription", err)
+		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"message": "Failed to delete webhook subscription"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, gin.H{"message": "Webhook subscription deleted successfully"})
+}
+
+func (c *WebhookController) GetDeliveries(ctx *gin.Context) {
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit params"})
+		return
+	}
+
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page params"})
+		return
+	}
+
+	deliveries, pagination, err := c.useCase.GetDeliveries(ctx.Request.Context(), ctx.Param("id"), domain.Pagination{Limit: limit, Page: page})
+	if err != nil {
+		c.logger.Ctx(ctx.Request.Context()).Error("Error fetching webhook deliveries", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Fetching webhook deliveries fatal failed"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, gin.H{"deliveries": deliveries, "pagination": pagination})
+}
+
+func (c *WebhookController) Replay(ctx *gin.Context) {
+	delivery, err := c.useCase.Replay(ctx.Request.Context(), ctx.Param("id"), ctx.Param("deliveryId"))
+	if err != nil {
+		if c.handleInvalidWebhook(ctx, err) {
+			return
+		}
+
+		c.logger.Ctx(ctx.Request.Context()).Error("Failed to replay webhook delivery", err)
+		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"message": "Failed to replay webhook delivery"})
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusAccepted, delivery)
+}
+
+func (c *WebhookController) handleInvalidWebhook(ctx *gin.Context, err error) bool {
+	var invalidWebhookErr *domain.InvalidWebhookError
+	if errors.As(err, &invalidWebhookErr) {
+		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"message": invalidWebhookErr.Reason, "error": "invalid_webhook"})
+		return true
+	}
+	return false
+}
+
+func (c *WebhookController) handleWebhookNotFound(ctx *gin.Context, err error) bool {
+	var notFoundErr *domain.WebhookSubscriptionNotFoundError
+	if errors.As(err, &notFoundErr) {
+		ctx.IndentedJSON(http.StatusNotFound, gin.H{"message": "Webhook subscription not found"})
+		return true
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type mockWebhookSubscriptionRepository struct {
+	domain.WebhookSubscriptionRepository
+	err error
+}
+
+func (m *mockWebhookSubscriptionRepository) FindById(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	return nil, m.err
+}
+
+func (m *mockWebhookSubscriptionRepository) Delete(ctx context.Context, id string) error {
+	return m.err
+}
+
+func newWebhookRouter(err error) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	webhookController := NewWebhookController(usecase.NewWebhookUseCase(&mockWebhookSubscriptionRepository{err: err}, nil, nil))
+	router := gin.New()
+	router.GET("/webhooks/:id", webhookController.GetById)
+	router.DELETE("/webhooks/:id", webhookController.Delete)
+	return router
+}
+
+func TestWebhookController(t *testing.T) {
+	t.Run("should respond 404 when the subscription does not exist", func(t *testing.T) {
+		router := newWebhookRouter(domain.NewWebhookSubscriptionNotFoundError("w1"))
+
+		for _, method := range []string{http.MethodGet, http.MethodDelete} {
+			if status, _ := serve(router, method, "/webhooks/w1", ""); status != http.StatusNotFound {
+				t.Errorf("%s: expected 404, but got %d", method, status)
+			}
+		}
+	})
+
+	t.Run("should respond 500 when fetching the subscription fails", func(t *testing.T) {
+		status, _ := serve(newWebhookRouter(errors.New("connection refused")), http.MethodGet, "/webhooks/w1", "")
+		if status != http.StatusInternalServerError {
+			t.Errorf("expected 500, but got %d", status)
+		}
+	})
+}
@@ -1,5 +1,7 @@
 package domain
 
+import "context"
+
 type NewAddress struct {
 	Cep              string  `json:"cep"`
 	Street           *string `json:"street"`
@@ -27,12 +29,12 @@ type Address struct {
 }
 
 type AddressRepository interface {
-	FindAll(Pagination) ([]Address, Pagination, error)
-	FindById(id string) (*Address, error)
-	FindBy(filters map[string]any) ([]Address, error)
-	FindByCustomerId(customerId string) ([]Address, error)
-	Update(Address) error
-	UpdateDefaultAddress(customerId string, addressId string) error
-	Delete(customerId string, addressId string) error
-	Create(customerId string, product NewAddress) (*Address, error)
+	FindAll(context.Context, Pagination) ([]Address, Pagination, error)
+	FindById(ctx context.Context, id string) (*Address, error)
+	FindBy(ctx context.Context, filters map[string]any) ([]Address, error)
+	FindByCustomerId(ctx context.Context, customerId string) ([]Address, error)
+	Update(context.Context, Address) error
+	UpdateDefaultAddress(ctx context.Context, customerId string, addressId string) error
+	Delete(ctx context.Context, customerId string, addressId string) error
+	Create(ctx context.Context, customerId string, product NewAddress) (*Address, error)
 }
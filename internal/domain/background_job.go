@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+const (
+	BackgroundJobOrderNotification = "order.notification"
+	BackgroundJobWebhookPublish    = "webhook.publish"
+)
+
+const (
+	BackgroundJobPending = "pending"
+	BackgroundJobRunning = "running"
+	BackgroundJobDone    = "done"
+	BackgroundJobDead    = "dead"
+)
+
+const (
+	BackgroundJobDefaultMaxAttempts = 10
+	backgroundJobBaseRetryDelay     = 5 * time.Second
+	backgroundJobMaxRetryDelay      = 10 * time.Minute
+)
+
+type NewBackgroundJob struct {
+	Kind        string
+	Payload     any
+	MaxAttempts int
+}
+
+type BackgroundJob struct {
+	Id          string          `json:"id"`
+	Kind        string          `json:"kind"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"maxAttempts"`
+	RunAt       time.Time       `json:"runAt"`
+	LockedBy    *string         `json:"lockedBy"`
+	LockedAt    *time.Time      `json:"lockedAt"`
+	LastError   *string         `json:"lastError"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	FinishedAt  *time.Time      `json:"finishedAt"`
+}
+
+// QueueStats resume a fila por tipo de job.
+type QueueStats struct {
+	Kind            string     `json:"kind"`
+	Pending         int        `json:"pending"`
+	Running         int        `json:"running"`
+	Dead            int        `json:"dead"`
+	OldestPendingAt *time.Time `json:"oldestPendingAt"`
+}
+
+// OrderNotificationJob envia uma notificação do pedido ao cliente.
+type OrderNotificationJob struct {
+	OrderId  string `json:"orderId"`
+	Template string `json:"template"`
+}
+
+// WebhookPublishJob agenda as entregas de um evento para os webhooks assinantes. Eventos de
+// pedido levam apenas o id, e o pedido é carregado atualizado no momento da publicação.
+type WebhookPublishJob struct {
+	EventType string          `json:"eventType"`
+	OrderId   string          `json:"orderId,omitempty"`
+	Data      json.RawMessage `json:"data,omitempty"`
+}
+
+// BackgroundJobRetryDelay calcula a espera antes de uma nova tentativa: 5s, 10s, 20s... até 10min.
+func BackgroundJobRetryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := backgroundJobBaseRetryDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= backgroundJobMaxRetryDelay {
+			return backgroundJobMaxRetryDelay
+		}
+	}
+	return delay
+}
+
+type BackgroundJobRepository interface {
+	Enqueue(context.Context, NewBackgroundJob) (*BackgroundJob, error)
+	// Claim reserva até limit jobs prontos para execução usando FOR UPDATE SKIP LOCKED. Jobs em
+	// execução cujo lease expirou voltam a ser entregues, cobrindo workers que morreram no meio.
+	Claim(ctx context.Context, workerId string, kinds []string, limit int, lease time.Duration) ([]BackgroundJob, error)
+	// Complete e Fail só gravam o resultado se o job ainda estiver reservado pela mesma execução
+	// (locked_by e attempts do Claim); senão devolvem BackgroundJobLeaseLostError.
+	Complete(ctx context.Context, job BackgroundJob) error
+	// Fail registra o erro e reagenda o job, ou o move para a dead-letter quando dead é true.
+	Fail(ctx context.Context, job BackgroundJob, errorMessage string, retryIn time.Duration, dead bool) error
+	Stats(ctx context.Context) ([]QueueStats, error)
+	FindDead(context.Context, Pagination) ([]BackgroundJob, Pagination, error)
+	Retry(ctx context.Context, id string) error
+}
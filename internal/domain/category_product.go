@@ -1,15 +1,20 @@
 package domain
 
+import "context"
+
 type CategoryProduct struct {
-	Id          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	Id           string  `json:"id"`
+	Name         string  `json:"name"`
+	Description  string  `json:"description"`
+	ParentId     *string `json:"parentId"`
+	DisplayOrder int     `json:"displayOrder"`
+	ProductCount int     `json:"productCount"`
 }
 
 type CategoryProductRepository interface {
-	FindAll() ([]CategoryProduct, error)
-	FindById(id string) (*CategoryProduct, error)
-	Update(CategoryProduct) error
-	Delete(id string) error
-	Create(CategoryProduct) (*CategoryProduct, error)
+	FindAll(ctx context.Context) ([]CategoryProduct, error)
+	FindById(ctx context.Context, id string) (*CategoryProduct, error)
+	Update(context.Context, CategoryProduct) error
+	Delete(ctx context.Context, id string, reassignTo *string) error
+	Create(context.Context, CategoryProduct) (*CategoryProduct, error)
 }
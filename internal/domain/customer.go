@@ -1,8 +1,13 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type NewCustomer struct {
+	// Id pode vir do caso de uso para que os jobs do outbox já o referenciem; vazio, o banco gera.
+	Id        string    `json:"-"`
 	Name      string    `json:"name"`
 	Phone     string    `json:"phone"`
 	Phone2    *string   `json:"phone2"`
@@ -11,12 +16,24 @@ type NewCustomer struct {
 }
 
 type Customer struct {
-	Id        string    `json:"id"`
-	Name      string    `json:"name"`
-	Phone     string    `json:"phone"`
-	Phone2    *string   `json:"phone2"`
-	Email     *string   `json:"email"`
-	CreatedAt time.Time `json:"createdAt"`
+	Id                 string    `json:"id"`
+	Name               string    `json:"name"`
+	Phone              string    `json:"phone"`
+	Phone2             *string   `json:"phone2"`
+	Email              *string   `json:"email"`
+	PhoneDisplay       string    `json:"phoneDisplay,omitempty"`
+	Phone2Display      *string   `json:"phone2Display,omitempty"`
+	Tags               []string  `json:"tags"`
+	DietaryPreferences []string  `json:"dietaryPreferences"`
+	CreatedAt          time.Time `json:"createdAt"`
+}
+
+func (c *Customer) SetDisplayPhones() {
+	c.PhoneDisplay = FormatPhone(c.Phone)
+	if c.Phone2 != nil {
+		display := FormatPhone(*c.Phone2)
+		c.Phone2Display = &display
+	}
 }
 
 type FindAllCustomerFilters struct {
@@ -26,9 +43,12 @@ type FindAllCustomerFilters struct {
 }
 
 type CustomerRepository interface {
-	FindAll(Pagination, FindAllCustomerFilters) ([]Customer, Pagination, error)
-	FindById(id string) (*Customer, error)
-	Update(Customer) error
-	Delete(id string) error
-	Create(customer NewCustomer) (*Customer, error)
+	FindAll(context.Context, Pagination, FindAllCustomerFilters) ([]Customer, Pagination, error)
+	FindById(ctx context.Context, id string) (*Customer, error)
+	FindByPhone(ctx context.Context, phone string) (*Customer, error)
+	// Update, Delete e Create gravam os jobs na mesma transação da escrita do cliente (outbox).
+	Update(ctx context.Context, customer Customer, jobs []NewBackgroundJob) error
+	UpdatePreferences(ctx context.Context, id string, preferences CustomerPreferences) error
+	Delete(ctx context.Context, id string, jobs []NewBackgroundJob) error
+	Create(ctx context.Context, customer NewCustomer, jobs []NewBackgroundJob) (*Customer, error)
 }
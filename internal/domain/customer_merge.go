@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	DuplicateReasonPhone   = "same_phone"
+	DuplicateReasonName    = "similar_name"
+	DuplicateReasonAddress = "shared_address"
+)
+
+type DuplicateCandidate struct {
+	Customer       Customer `json:"customer"`
+	Duplicate      Customer `json:"duplicate"`
+	Reasons        []string `json:"reasons"`
+	NameSimilarity float64  `json:"nameSimilarity"`
+}
+
+type CustomerMerge struct {
+	Id                string     `json:"id"`
+	SurvivorId        string     `json:"survivorId"`
+	MergedCustomerIds []string   `json:"mergedCustomerIds"`
+	MergedCustomers   []Customer `json:"mergedCustomers"`
+	MovedOrders       int        `json:"movedOrders"`
+	MovedAddresses    int        `json:"movedAddresses"`
+	MergedBy          string     `json:"mergedBy"`
+	CreatedAt         time.Time  `json:"createdAt"`
+}
+
+type CustomerMergeRepository interface {
+	FindDuplicates(ctx context.Context, limit int) ([]DuplicateCandidate, error)
+	FindMerges(context.Context, Pagination) ([]CustomerMerge, Pagination, error)
+	Merge(ctx context.Context, survivorId string, mergedIds []string, mergedBy string) (*CustomerMerge, error)
+}
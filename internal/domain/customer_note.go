@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	CustomerTagVIP       = "vip"
+	CustomerTagWholesale = "wholesale"
+	CustomerTagAllergy   = "allergy"
+)
+
+var CustomerTags = []string{CustomerTagVIP, CustomerTagWholesale, CustomerTagAllergy}
+
+const (
+	CustomerAlertNote    = "note"
+	CustomerAlertTag     = "tag"
+	CustomerAlertDietary = "dietary"
+)
+
+type NewCustomerNote struct {
+	Content string `json:"content"`
+	IsAlert bool   `json:"isAlert"`
+}
+
+type CustomerNote struct {
+	Id         string    `json:"id"`
+	CustomerId string    `json:"customerId"`
+	Content    string    `json:"content"`
+	Author     string    `json:"author"`
+	IsAlert    bool      `json:"isAlert"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type CustomerPreferences struct {
+	Tags               []string `json:"tags"`
+	DietaryPreferences []string `json:"dietaryPreferences"`
+}
+
+type CustomerAlert struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// BuildCustomerAlerts reúne as informações do cliente que precisam aparecer no ticket
+// da cozinha: notas marcadas como alerta, tags sensíveis e preferências alimentares.
+func BuildCustomerAlerts(customer Customer, notes []CustomerNote) []CustomerAlert {
+	alerts := []CustomerAlert{}
+
+	for _, tag := range customer.Tags {
+		switch tag {
+		case CustomerTagAllergy:
+			alerts = append(alerts, CustomerAlert{Type: CustomerAlertTag, Message: "Cliente com alergia"})
+		case CustomerTagVIP:
+			alerts = append(alerts, CustomerAlert{Type: CustomerAlertTag, Message: "Cliente VIP"})
+		}
+	}
+
+	for _, preference := range customer.DietaryPreferences {
+		alerts = append(alerts, CustomerAlert{Type: CustomerAlertDietary, Message: preference})
+	}
+
+	for _, note := range notes {
+		if note.IsAlert {
+			alerts = append(alerts, CustomerAlert{Type: CustomerAlertNote, Message: note.Content})
+		}
+	}
+
+	return alerts
+}
+
+// NormalizeCustomerPreferences valida as tags e remove valores vazios ou repetidos.
+func NormalizeCustomerPreferences(preferences CustomerPreferences) (CustomerPreferences, error) {
+	tags := []string{}
+	for _, tag := range preferences.Tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || slices.Contains(tags, tag) {
+			continue
+		}
+		if !slices.Contains(CustomerTags, tag) {
+			return CustomerPreferences{}, NewInvalidCustomerTagError(tag)
+		}
+		tags = append(tags, tag)
+	}
+
+	dietaryPreferences := []string{}
+	for _, preference := range preferences.DietaryPreferences {
+		preference = strings.TrimSpace(preference)
+		if preference == "" || slices.Contains(dietaryPreferences, preference) {
+			continue
+		}
+		dietaryPreferences = append(dietaryPreferences, preference)
+	}
+
+	return CustomerPreferences{Tags: tags, DietaryPreferences: dietaryPreferences}, nil
+}
+
+// NormalizeCustomerNote remove os espaços das pontas do conteúdo e recusa notas vazias.
+func NormalizeCustomerNote(note NewCustomerNote) (NewCustomerNote, error) {
+	note.Content = strings.TrimSpace(note.Content)
+	if note.Content == "" {
+		return NewCustomerNote{}, NewInvalidCustomerNoteError("content is required")
+	}
+	return note, nil
+}
+
+type CustomerNoteRepository interface {
+	FindByCustomerId(ctx context.Context, customerId string) ([]CustomerNote, error)
+	Create(ctx context.Context, customerId string, author string, note NewCustomerNote) (*CustomerNote, error)
+	Delete(ctx context.Context, customerId string, noteId string) error
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBuildCustomerAlerts(t *testing.T) {
+	customer := Customer{
+		Tags:               []string{CustomerTagWholesale, CustomerTagAllergy},
+		DietaryPreferences: []string{"Sem lactose"},
+	}
+	notes := []CustomerNote{
+		{Content: "Alérgico a nozes", IsAlert: true},
+		{Content: "Prefere retirar pela manhã", IsAlert: false},
+	}
+
+	alerts := BuildCustomerAlerts(customer, notes)
+
+	expected := []CustomerAlert{
+		{Type: CustomerAlertTag, Message: "Cliente com alergia"},
+		{Type: CustomerAlertDietary, Message: "Sem lactose"},
+		{Type: CustomerAlertNote, Message: "Alérgico a nozes"},
+	}
+
+	if len(alerts) != len(expected) {
+		t.Fatalf("expected %d alerts, but got %d: %v", len(expected), len(alerts), alerts)
+	}
+
+	for i := range expected {
+		if alerts[i] != expected[i] {
+			t.Errorf("expected alert %v, but got %v", expected[i], alerts[i])
+		}
+	}
+}
+
+func TestNormalizeCustomerPreferences(t *testing.T) {
+	t.Run("should lowercase and deduplicate tags", func(t *testing.T) {
+		preferences, err := NormalizeCustomerPreferences(CustomerPreferences{
+			Tags:               []string{"VIP", " vip ", "allergy", ""},
+			DietaryPreferences: []string{"Vegano", "Vegano", " "},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+
+		if len(preferences.Tags) != 2 || preferences.Tags[0] != "vip" || preferences.Tags[1] != "allergy" {
+			t.Errorf("unexpected tags %v", preferences.Tags)
+		}
+
+		if len(preferences.DietaryPreferences) != 1 {
+			t.Errorf("unexpected dietary preferences %v", preferences.DietaryPreferences)
+		}
+	})
+
+	t.Run("should reject unknown tags", func(t *testing.T) {
+		_, err := NormalizeCustomerPreferences(CustomerPreferences{Tags: []string{"gold"}})
+
+		var invalidTagErr *InvalidCustomerTagError
+		if !errors.As(err, &invalidTagErr) {
+			t.Errorf("expected InvalidCustomerTagError, but got %v", err)
+		}
+	})
+}
+
+func TestNormalizeCustomerNote(t *testing.T) {
+	t.Run("should trim the content", func(t *testing.T) {
+		note, err := NormalizeCustomerNote(NewCustomerNote{Content: "  sem cebola ", IsAlert: true})
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if note.Content != "sem cebola" || !note.IsAlert {
+			t.Errorf("unexpected note %+v", note)
+		}
+	})
+
+	t.Run("should reject empty content", func(t *testing.T) {
+		_, err := NormalizeCustomerNote(NewCustomerNote{Content: " \n "})
+
+		var invalidNoteErr *InvalidCustomerNoteError
+		if !errors.As(err, &invalidNoteErr) {
+			t.Errorf("expected InvalidCustomerNoteError, but got %v", err)
+		}
+	})
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type DuplicateAddressError struct {
 	CustomerID string
@@ -19,3 +22,452 @@ func NewDuplicateAddressError(customerID, cep, number string) *DuplicateAddressE
 		Number:     number,
 	}
 }
+
+type CategoryHasProductsError struct {
+	CategoryID   string
+	ProductCount int
+}
+
+func (e *CategoryHasProductsError) Error() string {
+	return fmt.Sprintf("category %s still has %d products", e.CategoryID, e.ProductCount)
+}
+
+func NewCategoryHasProductsError(categoryID string, productCount int) *CategoryHasProductsError {
+	return &CategoryHasProductsError{
+		CategoryID:   categoryID,
+		ProductCount: productCount,
+	}
+}
+
+type InvalidCategoryParentError struct {
+	CategoryID string
+	ParentID   string
+}
+
+func (e *InvalidCategoryParentError) Error() string {
+	return fmt.Sprintf("category %s cannot have %s as parent", e.CategoryID, e.ParentID)
+}
+
+func NewInvalidCategoryParentError(categoryID, parentID string) *InvalidCategoryParentError {
+	return &InvalidCategoryParentError{
+		CategoryID: categoryID,
+		ParentID:   parentID,
+	}
+}
+
+type CategoryNotFoundError struct {
+	Id string
+}
+
+func (e *CategoryNotFoundError) Error() string {
+	return fmt.Sprintf("category %s not found", e.Id)
+}
+
+func NewCategoryNotFoundError(id string) *CategoryNotFoundError {
+	return &CategoryNotFoundError{Id: id}
+}
+
+type InvalidCategoryReassignError struct {
+	CategoryID string
+	ReassignTo string
+}
+
+func (e *InvalidCategoryReassignError) Error() string {
+	return fmt.Sprintf("products of category %s cannot be moved to %s", e.CategoryID, e.ReassignTo)
+}
+
+func NewInvalidCategoryReassignError(categoryID, reassignTo string) *InvalidCategoryReassignError {
+	return &InvalidCategoryReassignError{
+		CategoryID: categoryID,
+		ReassignTo: reassignTo,
+	}
+}
+
+type InvalidPhoneError struct {
+	Phone string
+}
+
+func (e *InvalidPhoneError) Error() string {
+	return fmt.Sprintf("phone %q is not a valid phone number", e.Phone)
+}
+
+func NewInvalidPhoneError(phone string) *InvalidPhoneError {
+	return &InvalidPhoneError{Phone: phone}
+}
+
+type DuplicatePhoneError struct {
+	Phone string
+}
+
+func (e *DuplicatePhoneError) Error() string {
+	return fmt.Sprintf("phone %s already belongs to another customer", e.Phone)
+}
+
+func NewDuplicatePhoneError(phone string) *DuplicatePhoneError {
+	return &DuplicatePhoneError{Phone: phone}
+}
+
+type CustomerNotFoundError struct {
+	Id string
+}
+
+func (e *CustomerNotFoundError) Error() string {
+	return fmt.Sprintf("customer %s not found", e.Id)
+}
+
+func NewCustomerNotFoundError(id string) *CustomerNotFoundError {
+	return &CustomerNotFoundError{Id: id}
+}
+
+type InvalidMergeError struct {
+	Reason string
+}
+
+func (e *InvalidMergeError) Error() string {
+	return fmt.Sprintf("invalid customer merge: %s", e.Reason)
+}
+
+func NewInvalidMergeError(reason string) *InvalidMergeError {
+	return &InvalidMergeError{Reason: reason}
+}
+
+type InvalidCustomerTagError struct {
+	Tag string
+}
+
+func (e *InvalidCustomerTagError) Error() string {
+	return fmt.Sprintf("tag %q is not valid, expected one of %s", e.Tag, strings.Join(CustomerTags, ", "))
+}
+
+func NewInvalidCustomerTagError(tag string) *InvalidCustomerTagError {
+	return &InvalidCustomerTagError{Tag: tag}
+}
+
+type InvalidCustomerNoteError struct {
+	Reason string
+}
+
+func (e *InvalidCustomerNoteError) Error() string {
+	return fmt.Sprintf("invalid customer note: %s", e.Reason)
+}
+
+func NewInvalidCustomerNoteError(reason string) *InvalidCustomerNoteError {
+	return &InvalidCustomerNoteError{Reason: reason}
+}
+
+type CustomerNoteNotFoundError struct {
+	Id string
+}
+
+func (e *CustomerNoteNotFoundError) Error() string {
+	return fmt.Sprintf("customer note %s not found", e.Id)
+}
+
+func NewCustomerNoteNotFoundError(id string) *CustomerNoteNotFoundError {
+	return &CustomerNoteNotFoundError{Id: id}
+}
+
+type InvalidOrderStatusError struct {
+	Status string
+}
+
+func (e *InvalidOrderStatusError) Error() string {
+	return fmt.Sprintf("order status %q is not valid, expected one of %s", e.Status, strings.Join(OrderStatuses, ", "))
+}
+
+func NewInvalidOrderStatusError(status string) *InvalidOrderStatusError {
+	return &InvalidOrderStatusError{Status: status}
+}
+
+type JobNotFoundError struct {
+	Name string
+}
+
+func (e *JobNotFoundError) Error() string {
+	return fmt.Sprintf("job %q is not registered", e.Name)
+}
+
+func NewJobNotFoundError(name string) *JobNotFoundError {
+	return &JobNotFoundError{Name: name}
+}
+
+type JobAlreadyRunningError struct {
+	Name string
+}
+
+func (e *JobAlreadyRunningError) Error() string {
+	return fmt.Sprintf("job %q is already running", e.Name)
+}
+
+func NewJobAlreadyRunningError(name string) *JobAlreadyRunningError {
+	return &JobAlreadyRunningError{Name: name}
+}
+
+type InvalidWebhookError struct {
+	Reason string
+}
+
+func (e *InvalidWebhookError) Error() string {
+	return fmt.Sprintf("invalid webhook subscription: %s", e.Reason)
+}
+
+func NewInvalidWebhookError(reason string) *InvalidWebhookError {
+	return &InvalidWebhookError{Reason: reason}
+}
+
+type WebhookSubscriptionNotFoundError struct {
+	Id string
+}
+
+func (e *WebhookSubscriptionNotFoundError) Error() string {
+	return fmt.Sprintf("webhook subscription %s not found", e.Id)
+}
+
+func NewWebhookSubscriptionNotFoundError(id string) *WebhookSubscriptionNotFoundError {
+	return &WebhookSubscriptionNotFoundError{Id: id}
+}
+
+// PermanentJobError marca falhas que novas tentativas não resolvem: o job vai direto para a
+// dead-letter em vez de ser reagendado.
+type PermanentJobError struct {
+	Err error
+}
+
+func (e *PermanentJobError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentJobError) Unwrap() error {
+	return e.Err
+}
+
+func NewPermanentJobError(err error) *PermanentJobError {
+	return &PermanentJobError{Err: err}
+}
+
+// BackgroundJobLeaseLostError indica que o lease do job expirou e outro worker o reservou; o
+// resultado da execução antiga é descartado.
+type BackgroundJobLeaseLostError struct {
+	Id string
+}
+
+func (e *BackgroundJobLeaseLostError) Error() string {
+	return fmt.Sprintf("background job %s was claimed again after its lease expired", e.Id)
+}
+
+func NewBackgroundJobLeaseLostError(id string) *BackgroundJobLeaseLostError {
+	return &BackgroundJobLeaseLostError{Id: id}
+}
+
+type NoNotificationChannelError struct {
+	CustomerId string
+}
+
+func (e *NoNotificationChannelError) Error() string {
+	return fmt.Sprintf("no notification channel available for customer %s", e.CustomerId)
+}
+
+func NewNoNotificationChannelError(customerId string) *NoNotificationChannelError {
+	return &NoNotificationChannelError{CustomerId: customerId}
+}
+
+type BackgroundJobNotFoundError struct {
+	Id string
+}
+
+func (e *BackgroundJobNotFoundError) Error() string {
+	return fmt.Sprintf("dead background job %s not found", e.Id)
+}
+
+func NewBackgroundJobNotFoundError(id string) *BackgroundJobNotFoundError {
+	return &BackgroundJobNotFoundError{Id: id}
+}
+
+type InvalidProductionStatusError struct {
+	Status string
+}
+
+func (e *InvalidProductionStatusError) Error() string {
+	return fmt.Sprintf("production status %q is not valid, expected %s or %s", e.Status, ProductionStatusPending, ProductionStatusDone)
+}
+
+func NewInvalidProductionStatusError(status string) *InvalidProductionStatusError {
+	return &InvalidProductionStatusError{Status: status}
+}
+
+type KitchenTicketNotFoundError struct {
+	Id string
+}
+
+func (e *KitchenTicketNotFoundError) Error() string {
+	return fmt.Sprintf("kitchen ticket %s not found", e.Id)
+}
+
+func NewKitchenTicketNotFoundError(id string) *KitchenTicketNotFoundError {
+	return &KitchenTicketNotFoundError{Id: id}
+}
+
+type PickupCodeNotFoundError struct {
+	Code string
+}
+
+func (e *PickupCodeNotFoundError) Error() string {
+	return fmt.Sprintf("no order found with pickup code %s", e.Code)
+}
+
+func NewPickupCodeNotFoundError(code string) *PickupCodeNotFoundError {
+	return &PickupCodeNotFoundError{Code: code}
+}
+
+type OrderNotFoundError struct {
+	Id string
+}
+
+func (e *OrderNotFoundError) Error() string {
+	return fmt.Sprintf("order %s not found", e.Id)
+}
+
+func NewOrderNotFoundError(id string) *OrderNotFoundError {
+	return &OrderNotFoundError{Id: id}
+}
+
+type OrderNotPickableError struct {
+	Number string
+	Status string
+}
+
+func (e *OrderNotPickableError) Error() string {
+	return fmt.Sprintf("order %s cannot be picked up, current status is %s", e.Number, e.Status)
+}
+
+func NewOrderNotPickableError(number string, status string) *OrderNotPickableError {
+	return &OrderNotPickableError{Number: number, Status: status}
+}
+
+type InvalidPickupCodeFormatError struct {
+	Format string
+}
+
+func (e *InvalidPickupCodeFormatError) Error() string {
+	return fmt.Sprintf("invalid pickup code format %q, expected one of: %s", e.Format, strings.Join(PickupCodeFormats, ", "))
+}
+
+func NewInvalidPickupCodeFormatError(format string) *InvalidPickupCodeFormatError {
+	return &InvalidPickupCodeFormatError{Format: format}
+}
+
+type InvalidPrintJobError struct {
+	Reason string
+}
+
+func (e *InvalidPrintJobError) Error() string {
+	return fmt.Sprintf("invalid print job: %s", e.Reason)
+}
+
+func NewInvalidPrintJobError(reason string) *InvalidPrintJobError {
+	return &InvalidPrintJobError{Reason: reason}
+}
+
+type InvalidPurgeEntityError struct {
+	Entity string
+}
+
+func (e *InvalidPurgeEntityError) Error() string {
+	return fmt.Sprintf("invalid purge entity %q, expected one of: %s", e.Entity, strings.Join(PurgeEntities, ", "))
+}
+
+func NewInvalidPurgeEntityError(entity string) *InvalidPurgeEntityError {
+	return &InvalidPurgeEntityError{Entity: entity}
+}
+
+type PrintJobNotFoundError struct {
+	Id string
+}
+
+func (e *PrintJobNotFoundError) Error() string {
+	return fmt.Sprintf("print job %s not found", e.Id)
+}
+
+func NewPrintJobNotFoundError(id string) *PrintJobNotFoundError {
+	return &PrintJobNotFoundError{Id: id}
+}
+
+// PrintJobNotClaimedError indica um ack de um job que não está sendo impresso pelo agente: o
+// job ainda não foi reservado, já foi confirmado ou o lease expirou e outro agente o reservou.
+type PrintJobNotClaimedError struct {
+	Id      string
+	AgentId string
+}
+
+func (e *PrintJobNotClaimedError) Error() string {
+	return fmt.Sprintf("print job %s is not being printed by agent %s", e.Id, e.AgentId)
+}
+
+func NewPrintJobNotClaimedError(id string, agentId string) *PrintJobNotClaimedError {
+	return &PrintJobNotClaimedError{Id: id, AgentId: agentId}
+}
+
+type LegacySyncConflictNotFoundError struct {
+	Stage string
+	OldId int
+}
+
+func (e *LegacySyncConflictNotFoundError) Error() string {
+	return fmt.Sprintf("no open sync conflict for legacy %s %d", e.Stage, e.OldId)
+}
+
+func NewLegacySyncConflictNotFoundError(stage string, oldId int) *LegacySyncConflictNotFoundError {
+	return &LegacySyncConflictNotFoundError{Stage: stage, OldId: oldId}
+}
+
+type InvalidLegacySyncKeepError struct {
+	Keep string
+}
+
+func (e *InvalidLegacySyncKeepError) Error() string {
+	return fmt.Sprintf("invalid side to keep %q, expected %s or %s", e.Keep, LegacySyncKeepLegacy, LegacySyncKeepApi)
+}
+
+func NewInvalidLegacySyncKeepError(keep string) *InvalidLegacySyncKeepError {
+	return &InvalidLegacySyncKeepError{Keep: keep}
+}
+
+type InvalidSpreadsheetError struct {
+	Reason string
+}
+
+func (e *InvalidSpreadsheetError) Error() string {
+	return fmt.Sprintf("invalid spreadsheet: %s", e.Reason)
+}
+
+func NewInvalidSpreadsheetError(reason string) *InvalidSpreadsheetError {
+	return &InvalidSpreadsheetError{Reason: reason}
+}
+
+// InvalidImportRowsError impede a gravação de uma importação com linhas inválidas; Invalid é
+// quantas linhas precisam ser corrigidas.
+type InvalidImportRowsError struct {
+	Invalid int
+}
+
+func (e *InvalidImportRowsError) Error() string {
+	return fmt.Sprintf("%d rows are invalid, fix them before confirming the import", e.Invalid)
+}
+
+func NewInvalidImportRowsError(invalid int) *InvalidImportRowsError {
+	return &InvalidImportRowsError{Invalid: invalid}
+}
+
+// ImportRowDeletedError indica que o registro de uma linha de atualização foi excluído entre a
+// prévia e a confirmação da importação.
+type ImportRowDeletedError struct {
+	Name string
+}
+
+func (e *ImportRowDeletedError) Error() string {
+	return fmt.Sprintf("%q was deleted during the import", e.Name)
+}
+
+func NewImportRowDeletedError(name string) *ImportRowDeletedError {
+	return &ImportRowDeletedError{Name: name}
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+const (
+	HealthStatusOk       = "ok"
+	HealthStatusDegraded = "degraded"
+	HealthStatusFail     = "fail"
+)
+
+// HealthCheckResult é o resultado de uma dependência. Falhas em checks não críticos deixam a
+// API degradada, mas ainda pronta para receber tráfego.
+type HealthCheckResult struct {
+	Name      string         `json:"name"`
+	Status    string         `json:"status"`
+	Critical  bool           `json:"critical"`
+	LatencyMs int64          `json:"latencyMs"`
+	Error     *string        `json:"error,omitempty"`
+	Details   map[string]any `json:"details,omitempty"`
+}
+
+type ReadinessReport struct {
+	Status    string              `json:"status"`
+	Checks    []HealthCheckResult `json:"checks"`
+	CheckedAt time.Time           `json:"checkedAt"`
+}
+
+// Ready indica se a instância pode receber tráfego.
+func (r ReadinessReport) Ready() bool {
+	return r.Status != HealthStatusFail
+}
+
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"buildTime"`
+	GoVersion string `json:"goVersion"`
+}
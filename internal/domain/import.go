@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Ação aplicada a cada linha de uma planilha importada.
+const (
+	ImportActionCreate    = "create"
+	ImportActionUpdate    = "update"
+	ImportActionUnchanged = "unchanged"
+	ImportActionInvalid   = "invalid"
+)
+
+// MaxImportRows limita as linhas de uma planilha, que é validada e gravada de uma vez.
+const MaxImportRows = 5000
+
+// SpreadsheetRow é uma linha da planilha enviada. Number é o número da linha no arquivo, o
+// mesmo que o usuário vê no editor, para que os erros apontem a linha certa.
+type SpreadsheetRow struct {
+	Number int
+	Cells  []string
+}
+
+type ImportRowError struct {
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportRow é o resultado da validação de uma linha. Id é o registro existente que será
+// atualizado e Changes os campos que mudam nele.
+type ImportRow[T any] struct {
+	Row     int              `json:"row"`
+	Action  string           `json:"action"`
+	Id      *string          `json:"id"`
+	Record  T                `json:"record"`
+	Changes []string         `json:"changes"`
+	Errors  []ImportRowError `json:"errors"`
+}
+
+type ImportSummary struct {
+	Rows      int `json:"rows"`
+	Create    int `json:"create"`
+	Update    int `json:"update"`
+	Unchanged int `json:"unchanged"`
+	Invalid   int `json:"invalid"`
+}
+
+// ImportPreview mostra o que a importação faria. Applied indica se ela foi gravada.
+type ImportPreview[T any] struct {
+	Summary        ImportSummary  `json:"summary"`
+	IgnoredColumns []string       `json:"ignoredColumns"`
+	Rows           []ImportRow[T] `json:"rows"`
+	Applied        bool           `json:"applied"`
+}
+
+type ProductImportRepository interface {
+	// Import cria e atualiza os produtos e grava os jobs em uma única transação. Os produtos
+	// criados já vêm com o id.
+	Import(ctx context.Context, creates []NewProduct, updates []Product, jobs []NewBackgroundJob) error
+}
+
+type CustomerImportRepository interface {
+	// FindByPhones devolve os clientes ativos com algum dos telefones, no principal ou no secundário.
+	FindByPhones(ctx context.Context, phones []string) ([]Customer, error)
+	// DeletedPhones devolve quais dos telefones pertencem a clientes excluídos, que continuam
+	// ocupando o telefone.
+	DeletedPhones(ctx context.Context, phones []string) ([]string, error)
+	// Import cria e atualiza os clientes e grava os jobs em uma única transação. Os clientes
+	// criados já vêm com o id.
+	Import(ctx context.Context, creates []NewCustomer, updates []Customer, jobs []NewBackgroundJob) error
+}
+
+// ImportKey normaliza nomes e cabeçalhos para comparação: sem acentos, sem diferença entre
+// maiúsculas e minúsculas e com os espaços repetidos reduzidos a um.
+func ImportKey(value string) string {
+	// O transformer guarda estado, então não pode ser compartilhado entre requisições
+	removeAccents := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	key, _, err := transform.String(removeAccents, value)
+	if err != nil {
+		key = value
+	}
+	return strings.Join(strings.Fields(strings.ToLower(key)), " ")
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	JobRunStatusRunning   = "running"
+	JobRunStatusSucceeded = "succeeded"
+	JobRunStatusFailed    = "failed"
+	JobRunStatusSkipped   = "skipped"
+)
+
+const (
+	JobTriggerSchedule = "schedule"
+	JobTriggerManual   = "manual"
+)
+
+type JobRun struct {
+	Id          string     `json:"id"`
+	JobName     string     `json:"jobName"`
+	Status      string     `json:"status"`
+	TriggeredBy string     `json:"triggeredBy"`
+	Result      *string    `json:"result"`
+	Error       *string    `json:"error"`
+	StartedAt   time.Time  `json:"startedAt"`
+	FinishedAt  *time.Time `json:"finishedAt"`
+}
+
+type JobInfo struct {
+	Name     string     `json:"name"`
+	Schedule string     `json:"schedule"`
+	NextRun  *time.Time `json:"nextRun"`
+	LastRun  *JobRun    `json:"lastRun"`
+}
+
+type JobRunRepository interface {
+	Start(ctx context.Context, jobName string, triggeredBy string) (*JobRun, error)
+	// StartScheduled reivindica o horário do cron antes de iniciar a execução; devolve nil quando
+	// outra instância já executou ou está executando o mesmo horário.
+	StartScheduled(ctx context.Context, jobName string, scheduledFor time.Time) (*JobRun, error)
+	Finish(ctx context.Context, id string, status string, result *string, errorMessage *string) error
+	FindLastByJob(ctx context.Context, jobName string) (*JobRun, error)
+	FindByJob(ctx context.Context, jobName string, pagination Pagination) ([]JobRun, Pagination, error)
+}
@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	ProductionStatusPending = "pending"
+	ProductionStatusDone    = "done"
+)
+
+// KitchenTicket é uma linha de produção: um OrderProduct com o contexto do pedido que a cozinha precisa.
+type KitchenTicket struct {
+	Id                string            `json:"id"`
+	OrderId           string            `json:"orderId"`
+	OrderNumber       string            `json:"orderNumber"`
+	OrderStatus       string            `json:"orderStatus"`
+	PickupDate        time.Time         `json:"pickupDate"`
+	CustomerName      string            `json:"customerName"`
+	ProductId         string            `json:"productId"`
+	Name              string            `json:"name"`
+	Quantity          int               `json:"quantity"`
+	UnityType         string            `json:"unityType"`
+	SubProducts       []OrderSubProduct `json:"subProducts"`
+	Observations      *string           `json:"observations"`
+	OrderObservations *string           `json:"orderObservations"`
+	CustomerAlerts    []CustomerAlert   `json:"customerAlerts"`
+	ProductionStatus  string            `json:"productionStatus"`
+	DoneAt            *time.Time        `json:"doneAt"`
+	DoneBy            *string           `json:"doneBy"`
+}
+
+type KitchenBatchTicket struct {
+	TicketId     string    `json:"ticketId"`
+	OrderId      string    `json:"orderId"`
+	OrderNumber  string    `json:"orderNumber"`
+	CustomerName string    `json:"customerName"`
+	PickupDate   time.Time `json:"pickupDate"`
+	Quantity     int       `json:"quantity"`
+}
+
+// KitchenBatch agrupa linhas idênticas de pedidos diferentes para serem produzidas juntas.
+type KitchenBatch struct {
+	Key           string               `json:"key"`
+	ProductId     string               `json:"productId"`
+	Name          string               `json:"name"`
+	UnityType     string               `json:"unityType"`
+	SubProducts   []string             `json:"subProducts"`
+	Observations  *string              `json:"observations"`
+	TotalQuantity int                  `json:"totalQuantity"`
+	FirstPickupAt time.Time            `json:"firstPickupAt"`
+	Tickets       []KitchenBatchTicket `json:"tickets"`
+}
+
+// KitchenOrderProgress é a situação do pedido após a alteração de uma linha.
+type KitchenOrderProgress struct {
+	TicketId       string `json:"ticketId"`
+	OrderId        string `json:"orderId"`
+	OrderStatus    string `json:"orderStatus"`
+	PendingTickets int    `json:"pendingTickets"`
+}
+
+type KitchenFilters struct {
+	PickupDateStart time.Time
+	PickupDateEnd   time.Time
+	IncludeDone     bool
+}
+
+// GroupKitchenTickets agrupa as linhas pendentes por produto, unidade, sub-produtos e observação.
+// Os lotes saem ordenados pela retirada mais próxima, e as linhas de cada lote também.
+func GroupKitchenTickets(tickets []KitchenTicket) []KitchenBatch {
+	batches := []KitchenBatch{}
+	index := map[string]int{}
+
+	sorted := make([]KitchenTicket, len(tickets))
+	copy(sorted, tickets)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].PickupDate.Before(sorted[j].PickupDate) })
+
+	for _, ticket := range sorted {
+		if ticket.ProductionStatus == ProductionStatusDone {
+			continue
+		}
+
+		key, subProducts := kitchenBatchKey(ticket)
+		i, ok := index[key]
+		if !ok {
+			batches = append(batches, KitchenBatch{
+				Key:           key,
+				ProductId:     ticket.ProductId,
+				Name:          ticket.Name,
+				UnityType:     ticket.UnityType,
+				SubProducts:   subProducts,
+				Observations:  ticket.Observations,
+				FirstPickupAt: ticket.PickupDate,
+				Tickets:       []KitchenBatchTicket{},
+			})
+			i = len(batches) - 1
+			index[key] = i
+		}
+
+		batches[i].TotalQuantity += ticket.Quantity
+		batches[i].Tickets = append(batches[i].Tickets, KitchenBatchTicket{
+			TicketId:     ticket.Id,
+			OrderId:      ticket.OrderId,
+			OrderNumber:  ticket.OrderNumber,
+			CustomerName: ticket.CustomerName,
+			PickupDate:   ticket.PickupDate,
+			Quantity:     ticket.Quantity,
+		})
+	}
+
+	return batches
+}
+
+func kitchenBatchKey(ticket KitchenTicket) (string, []string) {
+	subProductIds := make([]string, 0, len(ticket.SubProducts))
+	subProducts := make([]string, 0, len(ticket.SubProducts))
+	for _, subProduct := range ticket.SubProducts {
+		subProductIds = append(subProductIds, subProduct.ProductId)
+		subProducts = append(subProducts, subProduct.Name)
+	}
+	sort.Strings(subProductIds)
+	sort.Strings(subProducts)
+
+	observations := ""
+	if ticket.Observations != nil {
+		observations = strings.ToLower(strings.TrimSpace(*ticket.Observations))
+	}
+
+	key := strings.Join([]string{ticket.ProductId, strings.TrimSpace(ticket.UnityType), strings.Join(subProductIds, "+"), observations}, "|")
+	return key, subProducts
+}
+
+// KitchenOrderStatus devolve o status que o pedido assume depois que uma linha muda, ou vazio
+// quando ele não muda: a última linha finalizada leva o pedido pendente para pronto e uma linha
+// reaberta leva o pedido pronto de volta para pendente.
+func KitchenOrderStatus(orderStatus string, pendingTickets int) string {
+	switch {
+	case pendingTickets == 0 && orderStatus == OrderStatusPending:
+		return OrderStatusReady
+	case pendingTickets > 0 && orderStatus == OrderStatusReady:
+		return OrderStatusPending
+	}
+	return ""
+}
+
+type KitchenRepository interface {
+	FindTickets(ctx context.Context, filters KitchenFilters) ([]KitchenTicket, error)
+	// SetTicketsStatus altera o status de produção das linhas em uma única transação e devolve,
+	// para cada linha, o status do pedido e quantas linhas dele continuam pendentes. Quando o
+	// status do pedido muda (KitchenOrderStatus), o pedido é atualizado na mesma transação e os
+	// jobs que orderJobs devolver para o novo status vão para o outbox.
+	SetTicketsStatus(ctx context.Context, ticketIds []string, status string, userId string, orderJobs func(orderId string, status string) []NewBackgroundJob) ([]KitchenOrderProgress, error)
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestGroupKitchenTickets(t *testing.T) {
+	early := time.Date(2026, 10, 20, 10, 0, 0, 0, time.UTC)
+	late := time.Date(2026, 10, 20, 15, 0, 0, 0, time.UTC)
+	noOnion := "Sem cebola"
+	noOnionLower := " sem cebola "
+
+	t.Run("should group identical lines regardless of sub-product order and observation case", func(t *testing.T) {
+		tickets := []KitchenTicket{
+			{
+				Id: "t1", OrderId: "o1", ProductId: "lasanha", Quantity: 2, UnityType: "UN", PickupDate: late,
+				SubProducts:  []OrderSubProduct{{ProductId: "molho"}, {ProductId: "queijo"}},
+				Observations: &noOnion, ProductionStatus: ProductionStatusPending,
+			},
+			{
+				Id: "t2", OrderId: "o2", ProductId: "lasanha", Quantity: 1, UnityType: "UN", PickupDate: early,
+				SubProducts:  []OrderSubProduct{{ProductId: "queijo"}, {ProductId: "molho"}},
+				Observations: &noOnionLower, ProductionStatus: ProductionStatusPending,
+			},
+		}
+
+		batches := GroupKitchenTickets(tickets)
+		if len(batches) != 1 {
+			t.Fatalf("expected 1 batch, but got %d", len(batches))
+		}
+		if batches[0].TotalQuantity != 3 {
+			t.Errorf("expected total quantity 3, but got %d", batches[0].TotalQuantity)
+		}
+		if !batches[0].FirstPickupAt.Equal(early) {
+			t.Errorf("expected first pickup at %v, but got %v", early, batches[0].FirstPickupAt)
+		}
+		if batches[0].Tickets[0].TicketId != "t2" {
+			t.Errorf("expected earliest ticket first, but got %s", batches[0].Tickets[0].TicketId)
+		}
+	})
+
+	t.Run("should split lines with different unity, sub-products or observations", func(t *testing.T) {
+		tickets := []KitchenTicket{
+			{Id: "t1", ProductId: "lasanha", Quantity: 1, UnityType: "UN", PickupDate: early},
+			{Id: "t2", ProductId: "lasanha", Quantity: 500, UnityType: "KG", PickupDate: early},
+			{Id: "t3", ProductId: "lasanha", Quantity: 1, UnityType: "UN", PickupDate: early, Observations: &noOnion},
+			{Id: "t4", ProductId: "lasanha", Quantity: 1, UnityType: "UN", PickupDate: early, SubProducts: []OrderSubProduct{{ProductId: "molho"}}},
+		}
+
+		if batches := GroupKitchenTickets(tickets); len(batches) != 4 {
+			t.Errorf("expected 4 batches, but got %d", len(batches))
+		}
+	})
+
+	t.Run("should skip lines already done", func(t *testing.T) {
+		tickets := []KitchenTicket{
+			{Id: "t1", ProductId: "lasanha", Quantity: 1, UnityType: "UN", PickupDate: early, ProductionStatus: ProductionStatusDone},
+		}
+
+		if batches := GroupKitchenTickets(tickets); len(batches) != 0 {
+			t.Errorf("expected no batches, but got %d", len(batches))
+		}
+	})
+}
+
+func TestKitchenOrderStatus(t *testing.T) {
+	for _, tc := range []struct {
+		orderStatus    string
+		pendingTickets int
+		expected       string
+	}{
+		{OrderStatusPending, 0, OrderStatusReady},
+		{OrderStatusPending, 2, ""},
+		{OrderStatusReady, 1, OrderStatusPending},
+		{OrderStatusReady, 0, ""},
+		{OrderStatusPickedUp, 1, ""},
+	} {
+		t.Run(fmt.Sprintf("should return %q for a %s order with %d pending tickets", tc.expected, tc.orderStatus, tc.pendingTickets), func(t *testing.T) {
+			if status := KitchenOrderStatus(tc.orderStatus, tc.pendingTickets); status != tc.expected {
+				t.Errorf("expected %q, but got %q", tc.expected, status)
+			}
+		})
+	}
+}
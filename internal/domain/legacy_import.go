@@ -0,0 +1,211 @@
+package domain
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+)
+
+// Etapas da importação do sistema legado (MySQL), na ordem em que precisam rodar: cada etapa
+// depende dos registros gravados pelas anteriores.
+const (
+	LegacyStageCategories = "categories"
+	LegacyStageProducts   = "products"
+	LegacyStageCustomers  = "customers"
+	LegacyStageAddresses  = "addresses"
+	LegacyStageOrders     = "orders"
+)
+
+var LegacyImportStages = []string{LegacyStageCategories, LegacyStageProducts, LegacyStageCustomers, LegacyStageAddresses, LegacyStageOrders}
+
+// LegacyProductRecord e os demais *Record são as linhas como estão no MySQL legado.
+type LegacyProductRecord struct {
+	OldId     int
+	Name      string
+	UnityType string
+	Value     float64
+}
+
+type LegacyCustomerRecord struct {
+	OldId     int
+	Name      string
+	Phone     string
+	Phone2    *string
+	Email     *string
+	CreatedAt *time.Time
+}
+
+type LegacyAddressRecord struct {
+	OldId            int
+	OldCustomerId    *int
+	Cep              string
+	Street           string
+	Number           string
+	Neighborhood     string
+	City             string
+	State            string
+	AditionalDetails string
+	Distance         *string
+}
+
+type LegacyOrderRecord struct {
+	OldId         int
+	Number        int
+	PickupDate    *time.Time
+	CreatedAt     *time.Time
+	OldCustomerId int
+	OrderLocal    *string
+	Observations  *string
+	IsPickedUp    bool
+	Items         []LegacyOrderItemRecord
+}
+
+// LegacyOrderItemRecord traz o nome e a unidade do produto para que a quantidade possa ser
+// convertida sem consultar o Postgres.
+type LegacyOrderItemRecord struct {
+	OldProductId    int
+	ProductName     string
+	UnityType       string
+	OldSubProductId *int
+	Quantity        float64
+	// Value é o preço do produto no legado, em reais; o item não guardava o preço da venda.
+	Value float64
+}
+
+// LegacyCategory e os demais tipos abaixo são as linhas já validadas e convertidas para o
+// modelo novo, prontas para gravar.
+type LegacyCategory struct {
+	Name        string
+	Description string
+}
+
+type LegacyProduct struct {
+	OldId     int
+	Name      string
+	Value     int
+	UnityType string
+	Category  string
+}
+
+type LegacyCustomer struct {
+	OldId     int
+	Name      string
+	Phone     string
+	Phone2    *string
+	Email     *string
+	CreatedAt *time.Time
+}
+
+type LegacyAddress struct {
+	OldId            int
+	OldCustomerId    int
+	Cep              string
+	Street           string
+	Number           string
+	Neighborhood     string
+	City             string
+	State            string
+	AditionalDetails string
+	Distance         int
+}
+
+type LegacyOrder struct {
+	OldId         int
+	Number        int
+	PickupDate    time.Time
+	CreatedAt     time.Time
+	OldCustomerId int
+	OrderLocal    *string
+	Observations  *string
+	Status        string
+	IsDelivery    bool
+	Items         []LegacyOrderItem
+}
+
+// LegacyOrderItem identifica o item pela OldKey, já que o item_pedido do legado não tem id:
+// produto, molho e a ocorrência do par no pedido, como "12:7:1" ou "12:-:1" sem molho.
+type LegacyOrderItem struct {
+	OldKey          string
+	OldProductId    int
+	OldSubProductId *int
+	Quantity        int
+}
+
+// LegacyImportError é uma linha do legado que não foi importada e o motivo.
+type LegacyImportError struct {
+	Stage  string `json:"stage"`
+	OldId  string `json:"oldId"`
+	Reason string `json:"reason"`
+}
+
+// LegacyImportCheckpoint é o progresso salvo de uma etapa: LastOldId é a maior chave do legado
+// já processada, então uma execução interrompida continua da linha seguinte.
+type LegacyImportCheckpoint struct {
+	Stage       string     `json:"stage"`
+	LastOldId   int        `json:"lastOldId"`
+	Imported    int        `json:"imported"`
+	Skipped     int        `json:"skipped"`
+	CompletedAt *time.Time `json:"completedAt"`
+}
+
+// LegacyImportBatch é um lote de uma etapa. Skipped são as linhas recusadas na validação e
+// LastOldId a maior chave do lote, gravada como checkpoint junto com as linhas. Fingerprints,
+// na ordem de Rows, registra a versão importada de cada linha como ponto de partida da
+// sincronização; fica vazio nas categorias, que não vêm do legado.
+type LegacyImportBatch[T any] struct {
+	Stage        string
+	Rows         []T
+	Fingerprints []string
+	Skipped      []LegacyImportError
+	LastOldId    int
+}
+
+// LegacySource lê o MySQL legado em lotes ordenados pela chave primária, a partir da primeira
+// chave maior que afterId.
+type LegacySource interface {
+	Products(ctx context.Context, afterId int, limit int) ([]LegacyProductRecord, error)
+	Customers(ctx context.Context, afterId int, limit int) ([]LegacyCustomerRecord, error)
+	Addresses(ctx context.Context, afterId int, limit int) ([]LegacyAddressRecord, error)
+	Orders(ctx context.Context, afterId int, limit int) ([]LegacyOrderRecord, error)
+}
+
+// LegacyImportRepository grava os lotes com upsert pelo old_id. Cada lote é gravado em uma
+// transação junto com o checkpoint e os erros, e cada linha em um savepoint próprio: uma
+// linha com erro é registrada em Errors sem desfazer as outras.
+type LegacyImportRepository interface {
+	Checkpoints(ctx context.Context) (map[string]LegacyImportCheckpoint, error)
+	Reset(ctx context.Context) error
+	ImportCategories(ctx context.Context, batch LegacyImportBatch[LegacyCategory]) (*LegacyImportCheckpoint, error)
+	ImportProducts(ctx context.Context, batch LegacyImportBatch[LegacyProduct]) (*LegacyImportCheckpoint, error)
+	ImportCustomers(ctx context.Context, batch LegacyImportBatch[LegacyCustomer]) (*LegacyImportCheckpoint, error)
+	ImportAddresses(ctx context.Context, batch LegacyImportBatch[LegacyAddress]) (*LegacyImportCheckpoint, error)
+	ImportOrders(ctx context.Context, batch LegacyImportBatch[LegacyOrder]) (*LegacyImportCheckpoint, error)
+	CompleteStage(ctx context.Context, stage string) error
+	Errors(ctx context.Context) ([]LegacyImportError, error)
+	// DryRun devolve um repositório que grava em uma transação desfeita ao chamar a função
+	// devolvida, para simular a importação inteira sem alterar o banco.
+	DryRun(ctx context.Context) (LegacyImportRepository, func(), error)
+}
+
+// LegacyQuantity converte a quantidade de um item do legado como os scripts de carga faziam,
+// para que os pedidos carregados por eles não mudem ao serem reimportados. Em UN as frações são
+// arredondadas para cima. Nas demais unidades (KG, LT...) valores abaixo de 10 estão em quilos
+// ou litros e viram gramas ou mililitros; os outros já estão na unidade menor. É o CASE do
+// script de carga de pedidos:
+//
+//	CASE WHEN st_unidade = 'UN' THEN (CASE WHEN MOD(q, 1) != 0 THEN CEIL(q) ELSE q END)
+//	     WHEN q < 10 THEN ROUND(q * 1000)
+//	     ELSE q END
+//
+// convertido com CAST AS SIGNED, que arredonda. A unidade é comparada como no MySQL, ignorando
+// maiúsculas e os espaços à direita. A importação e a reconciliação usam esta mesma conversão.
+func LegacyQuantity(unityType string, quantity float64) int {
+	if strings.EqualFold(strings.TrimRight(unityType, " "), "UN") {
+		return int(math.Ceil(quantity))
+	}
+	if quantity < 10 {
+		return int(math.Round(quantity * 1000))
+	}
+	return int(math.Round(quantity))
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "testing"
+
+func TestLegacyQuantity(t *testing.T) {
+	cases := []struct {
+		unityType string
+		quantity  float64
+		expected  int
+	}{
+		{"KG", 1.5, 1500},
+		{"KG", 0.35, 350},
+		{"KG", 750, 750},
+		{"KG ", 2, 2000},
+		{"UN", 3, 3},
+		{"UN", 2.5, 3},
+		{"un ", 12.5, 13},
+		{"un", 12.2, 13},
+		{"LT", 2, 2000},
+		{"LT", 12.4, 12},
+		{"LT", 12.6, 13},
+		// O MySQL só ignora os espaços à direita na comparação com 'UN'
+		{" UN", 2, 2000},
+		{"", 0.5, 500},
+	}
+
+	for _, c := range cases {
+		if quantity := LegacyQuantity(c.unityType, c.quantity); quantity != c.expected {
+			t.Errorf("expected %v %s to convert to %d, but got %d", c.quantity, c.unityType, c.expected, quantity)
+		}
+	}
+}
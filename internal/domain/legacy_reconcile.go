@@ -0,0 +1,59 @@
+package domain
+
+import "context"
+
+// LegacyReconcileProduct e os demais tipos abaixo são o estado atual no Postgres das linhas
+// importadas do legado, buscadas pelo old_id para comparar com o MySQL. Copies conta quantas
+// linhas têm o mesmo old_id; quando há mais de uma, os demais campos são da linha não apagada.
+type LegacyReconcileProduct struct {
+	OldId     int
+	Value     int
+	UnityType string
+	Copies    int
+}
+
+type LegacyReconcileCustomer struct {
+	OldId  int
+	Phone  string
+	Phone2 *string
+	Copies int
+}
+
+// LegacyReconcileAddress traz os old_ids dos clientes ligados ao endereço, incluindo os
+// clientes mesclados neles, já que a mesclagem move os endereços para o cliente mantido.
+type LegacyReconcileAddress struct {
+	OldId          int
+	CustomerOldIds []int
+	Copies         int
+}
+
+type LegacyReconcileOrder struct {
+	OldId  int
+	Number int
+	// CustomerOldIds segue a mesma regra dos endereços: o cliente do pedido e os mesclados nele.
+	CustomerOldIds []int
+	Status         string
+	IsDeleted      bool
+	Items          []LegacyReconcileOrderItem
+	Copies         int
+}
+
+// LegacyReconcileOrderItem usa zero em OldProductId para produtos criados no sistema novo.
+type LegacyReconcileOrderItem struct {
+	OldProductId     int
+	OldSubProductIds []int
+	Quantity         int
+	UnityType        string
+	Price            int
+}
+
+// LegacyReconcileRepository lê o Postgres para a reconciliação com o legado. Os métodos por
+// old_id devolvem só as linhas encontradas, indexadas pelo old_id.
+type LegacyReconcileRepository interface {
+	// Counts devolve, por etapa da importação, quantos old_ids distintos existem no Postgres.
+	Counts(ctx context.Context) (map[string]int, error)
+	Products(ctx context.Context, oldIds []int) (map[int]LegacyReconcileProduct, error)
+	Customers(ctx context.Context, oldIds []int) (map[int]LegacyReconcileCustomer, error)
+	Addresses(ctx context.Context, oldIds []int) (map[int]LegacyReconcileAddress, error)
+	Orders(ctx context.Context, oldIds []int) (map[int]LegacyReconcileOrder, error)
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Lados que podem ser mantidos ao resolver um conflito da sincronização.
+const (
+	LegacySyncKeepLegacy = "legacy"
+	LegacySyncKeepApi    = "api"
+)
+
+// LegacySyncSource amplia a leitura do legado com as consultas da sincronização, que além das
+// linhas novas relê as que ainda podem ser alteradas no sistema antigo.
+type LegacySyncSource interface {
+	LegacySource
+	// RecentOrders devolve os pedidos com retirada a partir de pickupSince, em lotes pela chave.
+	RecentOrders(ctx context.Context, pickupSince time.Time, afterId int, limit int) ([]LegacyOrderRecord, error)
+	// Os *ByIds releem linhas específicas, como as que falharam na última sincronização.
+	ProductsByIds(ctx context.Context, ids []int) ([]LegacyProductRecord, error)
+	CustomersByIds(ctx context.Context, ids []int) ([]LegacyCustomerRecord, error)
+	AddressesByIds(ctx context.Context, ids []int) ([]LegacyAddressRecord, error)
+	OrdersByIds(ctx context.Context, ids []int) ([]LegacyOrderRecord, error)
+}
+
+// LegacySyncRow é uma linha convertida do legado e a impressão digital do seu conteúdo, usada
+// para gravar só o que mudou desde a última sincronização. Error vem preenchido, e Row vazio,
+// quando a linha não pôde ser convertida.
+type LegacySyncRow[T any] struct {
+	OldId       int
+	Fingerprint string
+	Row         T
+	Error       string
+}
+
+type LegacySyncBatch[T any] struct {
+	Stage string
+	Rows  []LegacySyncRow[T]
+	// Force grava mesmo as linhas editadas pela API, resolvendo o conflito a favor do legado.
+	Force bool
+}
+
+type LegacySyncBatchResult struct {
+	Synced    int
+	Failed    []LegacyImportError
+	Conflicts []LegacySyncConflict
+}
+
+// LegacySyncConflict é um pedido ou cliente alterado no legado depois de ter sido editado pela API.
+type LegacySyncConflict struct {
+	Id         string     `json:"id"`
+	Stage      string     `json:"stage"`
+	OldId      int        `json:"oldId"`
+	RecordId   string     `json:"recordId"`
+	DetectedAt time.Time  `json:"detectedAt"`
+	ResolvedAt *time.Time `json:"resolvedAt"`
+	Resolution *string    `json:"resolution"`
+}
+
+// LegacySyncRepository grava a sincronização com o legado. Cada lote é uma transação e cada
+// linha um savepoint, como na importação; a versão gravada de cada linha fica registrada para
+// as próximas execuções.
+type LegacySyncRepository interface {
+	// Watermarks devolve, por etapa, a maior chave do legado já processada pela importação ou
+	// pela sincronização, com ou sem sucesso.
+	Watermarks(ctx context.Context) (map[string]int, error)
+	// FailedOldIds devolve, por etapa, as linhas cuja última gravação falhou. Como a marca d'água
+	// já passou por elas, são relidas pela chave a cada execução.
+	FailedOldIds(ctx context.Context) (map[string][]int, error)
+	// Fingerprints devolve a impressão digital da última versão gravada de cada old_id.
+	Fingerprints(ctx context.Context, stage string, oldIds []int) (map[int]string, error)
+	SyncProducts(ctx context.Context, batch LegacySyncBatch[LegacyProduct]) (*LegacySyncBatchResult, error)
+	// SyncCustomers e SyncOrders não gravam linhas editadas pela API desde a última sincronização:
+	// registram um conflito e mantêm a versão da API até ele ser resolvido. Só conflitos novos
+	// voltam no resultado; os que já estavam abertos continuam aguardando.
+	SyncCustomers(ctx context.Context, batch LegacySyncBatch[LegacyCustomer]) (*LegacySyncBatchResult, error)
+	SyncAddresses(ctx context.Context, batch LegacySyncBatch[LegacyAddress]) (*LegacySyncBatchResult, error)
+	SyncOrders(ctx context.Context, batch LegacySyncBatch[LegacyOrder]) (*LegacySyncBatchResult, error)
+	Conflicts(ctx context.Context) ([]LegacySyncConflict, error)
+	// KeepApiVersion resolve o conflito aberto da linha mantendo a versão da API; a versão do
+	// legado passa a contar como sincronizada.
+	KeepApiVersion(ctx context.Context, stage string, oldId int) (*LegacySyncConflict, error)
+	DryRun(ctx context.Context) (LegacySyncRepository, func(), error)
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Entidades com exclusão lógica que podem ser expurgadas.
+const (
+	PurgeOrders     = "orders"
+	PurgeProducts   = "products"
+	PurgeCustomers  = "customers"
+	PurgeCategories = "categories"
+)
+
+var PurgeEntities = []string{PurgeOrders, PurgeProducts, PurgeCustomers, PurgeCategories}
+
+func IsValidPurgeEntity(entity string) bool {
+	for _, valid := range PurgeEntities {
+		if entity == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// OrderNumberSeed descreve a sequência dos números de pedido antes e depois do reajuste.
+type OrderNumberSeed struct {
+	MaxNumber  int  `json:"maxNumber"`
+	NextNumber int  `json:"nextNumber"`
+	Applied    bool `json:"applied"`
+}
+
+// PurgeResult resume um expurgo. Skipped são as linhas excluídas há tempo suficiente que ainda
+// são referenciadas (ex.: produto presente em pedidos) e por isso ficam no banco.
+type PurgeResult struct {
+	Entity        string    `json:"entity"`
+	DeletedBefore time.Time `json:"deletedBefore"`
+	Purged        int       `json:"purged"`
+	Skipped       int       `json:"skipped"`
+	Applied       bool      `json:"applied"`
+}
+
+// MaintenanceRepository reúne as operações administrativas feitas pelo zionctl. Com dryRun as
+// alterações são feitas em uma transação desfeita ao final, então o resultado mostra
+// exatamente o que seria aplicado.
+type MaintenanceRepository interface {
+	ReseedOrderNumbers(ctx context.Context, minNext int, dryRun bool) (*OrderNumberSeed, error)
+	PurgeDeleted(ctx context.Context, entity string, deletedBefore time.Time, dryRun bool) (*PurgeResult, error)
+}
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// BusinessStats são os números do negócio expostos como métricas. Contagens por status e por
+// canal vêm em mapas para virarem labels.
+type BusinessStats struct {
+	OrdersCreatedToday  int
+	OrdersPendingPickup map[string]int
+	NotificationsFailed map[string]int
+}
+
+// MetricsRepository calcula os números do negócio a partir do banco; since é o início do dia
+// na loja e until o início do dia seguinte.
+type MetricsRepository interface {
+	BusinessStats(ctx context.Context, since time.Time, until time.Time) (*BusinessStats, error)
+}
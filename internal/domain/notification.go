@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"text/template"
+	"time"
+)
+
+const (
+	NotificationOrderConfirmation = "order_confirmation"
+	NotificationOrderReady        = "order_ready"
+	NotificationOrderCancelled    = "order_cancelled"
+	NotificationPickupReminder    = "pickup_reminder"
+)
+
+const (
+	NotificationStatusPending = "pending"
+	NotificationStatusSent    = "sent"
+	NotificationStatusFailed  = "failed"
+)
+
+type Notification struct {
+	Id                string     `json:"id"`
+	CustomerId        string     `json:"customerId"`
+	OrderId           *string    `json:"orderId"`
+	Template          string     `json:"template"`
+	Channel           string     `json:"channel"`
+	Recipient         string     `json:"recipient"`
+	Subject           *string    `json:"subject"`
+	Body              string     `json:"body"`
+	Status            string     `json:"status"`
+	ProviderMessageId *string    `json:"providerMessageId"`
+	Error             *string    `json:"error"`
+	JobId             *string    `json:"jobId"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	SentAt            *time.Time `json:"sentAt"`
+}
+
+type NotificationRepository interface {
+	Create(context.Context, Notification) (*Notification, error)
+	UpdateStatus(ctx context.Context, id string, status string, providerMessageId *string, errorMessage *string) error
+	FindByOrderId(ctx context.Context, orderId string) ([]Notification, error)
+	ExistsForOrder(ctx context.Context, orderId string, template string) (bool, error)
+	// FindByJobId devolve as tentativas registradas pelas execuções anteriores de um job do outbox.
+	FindByJobId(ctx context.Context, jobId string) ([]Notification, error)
+	// FindUndelivered devolve a última tentativa de cada par pedido/template criado desde since
+	// que só tem tentativas com falha. Com orderId vazio considera todos os pedidos.
+	FindUndelivered(ctx context.Context, since time.Time, orderId string) ([]Notification, error)
+}
+
+type notificationTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+// StoreLocation é o fuso horário da loja, usado para datas exibidas ao cliente e rotinas agendadas.
+var StoreLocation = loadStoreLocation()
+
+var notificationTemplates = map[string]notificationTemplate{
+	NotificationOrderConfirmation: newNotificationTemplate(
+		"Pedido {{.Number}} confirmado",
+		"Olá, {{.Customer.Name}}! Recebemos o seu pedido nº {{.Number}} para retirada em {{date .PickupDate}}.{{if .PickupCode}} Na retirada, apresente o código {{.PickupCode}}.{{end}} Obrigado pela preferência!",
+	),
+	NotificationOrderReady: newNotificationTemplate(
+		"Pedido {{.Number}} pronto para retirada",
+		"Olá, {{.Customer.Name}}! O seu pedido nº {{.Number}} está pronto para retirada. Estamos te esperando!",
+	),
+	NotificationOrderCancelled: newNotificationTemplate(
+		"Pedido {{.Number}} cancelado",
+		"Olá, {{.Customer.Name}}. O seu pedido nº {{.Number}} foi cancelado. Em caso de dúvidas, entre em contato conosco.",
+	),
+	NotificationPickupReminder: newNotificationTemplate(
+		"Lembrete: retirada do pedido {{.Number}} amanhã",
+		"Olá, {{.Customer.Name}}! Passando para lembrar que o seu pedido nº {{.Number}} estará disponível para retirada amanhã, {{date .PickupDate}}.{{if .PickupCode}} Código de retirada: {{.PickupCode}}.{{end}} Até lá!",
+	),
+}
+
+// RenderOrderNotification monta o assunto e o texto da mensagem do template para o pedido.
+func RenderOrderNotification(name string, order Order) (string, string, error) {
+	tmpl, ok := notificationTemplates[name]
+	if !ok {
+		return "", "", fmt.Errorf("notification template %s not found", name)
+	}
+
+	var subject, body bytes.Buffer
+	if err := tmpl.subject.Execute(&subject, order); err != nil {
+		return "", "", fmt.Errorf("error rendering subject of template %s: %w", name, err)
+	}
+	if err := tmpl.body.Execute(&body, order); err != nil {
+		return "", "", fmt.Errorf("error rendering template %s: %w", name, err)
+	}
+
+	return subject.String(), body.String(), nil
+}
+
+func newNotificationTemplate(subject, body string) notificationTemplate {
+	funcs := template.FuncMap{
+		"date": func(t time.Time) string { return t.In(StoreLocation).Format("02/01/2006") },
+	}
+
+	return notificationTemplate{
+		subject: template.Must(template.New("subject").Funcs(funcs).Parse(subject)),
+		body:    template.Must(template.New("body").Funcs(funcs).Parse(body)),
+	}
+}
+
+func loadStoreLocation() *time.Location {
+	loc, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		return time.FixedZone("BRT", -3*60*60)
+	}
+	return loc
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+const OrderEventsChannel = "order_events"
+
+const (
+	OrderEventCreated       = "created"
+	OrderEventUpdated       = "updated"
+	OrderEventStatusChanged = "status_changed"
+	OrderEventDeleted       = "deleted"
+	// OrderEventResync avisa que eventos podem ter sido perdidos e o cliente deve recarregar a lista.
+	OrderEventResync = "resync"
+)
+
+type OrderEvent struct {
+	Type           string     `json:"type"`
+	OrderId        string     `json:"orderId,omitempty"`
+	OrderNumber    string     `json:"orderNumber,omitempty"`
+	Status         string     `json:"status,omitempty"`
+	PreviousStatus *string    `json:"previousStatus,omitempty"`
+	PickupDate     *time.Time `json:"pickupDate,omitempty"`
+}
+
+type OrderEventFilter struct {
+	PickupDateStart *time.Time
+	PickupDateEnd   *time.Time
+	Statuses        []string
+}
+
+// Matches indica se o evento interessa à conexão. Uma mudança de status é entregue quando o
+// status anterior ou o novo está no filtro, para que o quadro consiga remover pedidos que saíram dele.
+func (f OrderEventFilter) Matches(event OrderEvent) bool {
+	if event.Type == OrderEventResync {
+		return true
+	}
+
+	if event.PickupDate != nil {
+		if f.PickupDateStart != nil && event.PickupDate.Before(*f.PickupDateStart) {
+			return false
+		}
+		if f.PickupDateEnd != nil && event.PickupDate.After(*f.PickupDateEnd) {
+			return false
+		}
+	}
+
+	if len(f.Statuses) == 0 {
+		return true
+	}
+	if slices.Contains(f.Statuses, event.Status) {
+		return true
+	}
+	return event.PreviousStatus != nil && slices.Contains(f.Statuses, *event.PreviousStatus)
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOrderEventFilter_Matches(t *testing.T) {
+	pickupDate := time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC)
+	start := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 10, 20, 23, 59, 59, 0, time.UTC)
+	pending := OrderStatusPending
+
+	t.Run("should match every event when filter is empty", func(t *testing.T) {
+		event := OrderEvent{Type: OrderEventCreated, Status: OrderStatusPending, PickupDate: &pickupDate}
+		if !(OrderEventFilter{}).Matches(event) {
+			t.Errorf("expected event to match empty filter")
+		}
+	})
+
+	t.Run("should not match events outside the pickup date range", func(t *testing.T) {
+		nextDay := pickupDate.AddDate(0, 0, 1)
+		filter := OrderEventFilter{PickupDateStart: &start, PickupDateEnd: &end}
+		if filter.Matches(OrderEvent{Type: OrderEventUpdated, PickupDate: &nextDay}) {
+			t.Errorf("expected event of %v not to match range %v - %v", nextDay, start, end)
+		}
+		if !filter.Matches(OrderEvent{Type: OrderEventUpdated, PickupDate: &pickupDate}) {
+			t.Errorf("expected event of %v to match range %v - %v", pickupDate, start, end)
+		}
+	})
+
+	t.Run("should match status changes leaving the filtered status", func(t *testing.T) {
+		filter := OrderEventFilter{Statuses: []string{OrderStatusPending}}
+		event := OrderEvent{Type: OrderEventStatusChanged, Status: OrderStatusReady, PreviousStatus: &pending, PickupDate: &pickupDate}
+		if !filter.Matches(event) {
+			t.Errorf("expected status change from pending to match pending filter")
+		}
+		if filter.Matches(OrderEvent{Type: OrderEventUpdated, Status: OrderStatusReady, PickupDate: &pickupDate}) {
+			t.Errorf("expected ready order not to match pending filter")
+		}
+	})
+
+	t.Run("should always match resync events", func(t *testing.T) {
+		filter := OrderEventFilter{PickupDateStart: &start, Statuses: []string{OrderStatusReady}}
+		if !filter.Matches(OrderEvent{Type: OrderEventResync}) {
+			t.Errorf("expected resync event to match any filter")
+		}
+	})
+}
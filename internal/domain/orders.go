@@ -1,35 +1,91 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	OrderStatusPending   = "pending"
+	OrderStatusReady     = "ready"
+	OrderStatusPickedUp  = "picked_up"
+	OrderStatusCancelled = "cancelled"
+)
+
+var OrderStatuses = []string{OrderStatusPending, OrderStatusReady, OrderStatusPickedUp, OrderStatusCancelled}
+
+func IsValidOrderStatus(status string) bool {
+	return slices.Contains(OrderStatuses, status)
+}
 
 type Order struct {
-	Id           string         `json:"id"`
-	Number       string         `json:"number"`
-	PickupDate   time.Time      `json:"pickupDate"`
-	Customer     Customer       `json:"customer"`
-	Address      *Address       `json:"address"`
-	Employee     string         `json:"employee"`
-	OrderLocal   *string        `json:"orderLocal"`
-	Observations *string        `json:"observations"`
-	IsPickedUp   *bool          `json:"isPickedUp"`
-	Products     []OrderProduct `json:"products"`
-	CreatedAt    time.Time      `json:"createdAt"`
-	UpdatedAt    *time.Time     `json:"updatedAt"`
+	Id             string          `json:"id"`
+	Number         string          `json:"number"`
+	PickupCode     string          `json:"pickupCode"`
+	PickupDate     time.Time       `json:"pickupDate"`
+	Customer       Customer        `json:"customer"`
+	Address        *Address        `json:"address"`
+	Employee       string          `json:"employee"`
+	OrderLocal     *string         `json:"orderLocal"`
+	Observations   *string         `json:"observations"`
+	IsPickedUp     *bool           `json:"isPickedUp"`
+	Status         string          `json:"status"`
+	Products       []OrderProduct  `json:"products"`
+	CustomerAlerts []CustomerAlert `json:"customerAlerts,omitempty"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	UpdatedAt      *time.Time      `json:"updatedAt"`
 }
 
 func (o *Order) SetAddress(address *Address) {
 	o.Address = address
 }
 
+// SetPickedUp marca ou desmarca a retirada mantendo o status coerente, como UpdateStatus faz no
+// sentido contrário: retirado leva o pedido a picked_up, e desmarcar um pedido retirado o
+// devolve a ready.
+func (o *Order) SetPickedUp(isPickedUp bool) {
+	o.IsPickedUp = &isPickedUp
+	switch {
+	case isPickedUp:
+		o.Status = OrderStatusPickedUp
+	case o.Status == OrderStatusPickedUp:
+		o.Status = OrderStatusReady
+	}
+}
+
 type OrderProduct struct {
-	Id          string            `json:"id"`
-	OrderId     string            `json:"orderId"`
-	ProductId   string            `json:"productId"`
-	Quantity    int               `json:"quantity"`
-	UnityType   string            `json:"unityType"`
-	Price       int               `json:"price"`
-	Name        string            `json:"name"`
-	SubProducts []OrderSubProduct `json:"subProducts"`
+	Id               string            `json:"id"`
+	OrderId          string            `json:"orderId"`
+	ProductId        string            `json:"productId"`
+	Quantity         int               `json:"quantity"`
+	UnityType        string            `json:"unityType"`
+	Price            int               `json:"price"`
+	Name             string            `json:"name"`
+	Observations     *string           `json:"observations"`
+	ProductionStatus string            `json:"productionStatus"`
+	SubProducts      []OrderSubProduct `json:"subProducts"`
+}
+
+// UnityTypeKilogram é vendido por peso: a quantidade da linha é em gramas e o preço é por quilo.
+const UnityTypeKilogram = "KG"
+
+// Total é o valor da linha em centavos.
+func (p OrderProduct) Total() int {
+	if strings.TrimSpace(p.UnityType) == UnityTypeKilogram {
+		return p.Price * p.Quantity / 1000
+	}
+	return p.Price * p.Quantity
+}
+
+// Total é o valor do pedido em centavos.
+func (o Order) Total() int {
+	total := 0
+	for _, product := range o.Products {
+		total += product.Total()
+	}
+	return total
 }
 
 type OrderSubProduct struct {
@@ -46,9 +102,14 @@ type FindAllOrderFilters struct {
 }
 
 type OrderRepository interface {
-	FindAll(Pagination, FindAllOrderFilters) ([]Order, Pagination, error)
-	FindById(id string) (*Order, error)
-	Update(Order) error
-	Delete(id string) error
-	Create(order Order) (*Order, error)
+	FindAll(context.Context, Pagination, FindAllOrderFilters) ([]Order, Pagination, error)
+	FindById(ctx context.Context, id string) (*Order, error)
+	FindByPickupCode(ctx context.Context, code string) (*Order, error)
+	// As escritas gravam os jobs na mesma transação do pedido (outbox).
+	Update(ctx context.Context, order Order, jobs []NewBackgroundJob) error
+	// UpdateStatus só grava os jobs quando o status muda; com o pedido já no status pedido não
+	// faz nada, para que duas chamadas concorrentes não publiquem a mesma mudança duas vezes.
+	UpdateStatus(ctx context.Context, id string, status string, jobs []NewBackgroundJob) error
+	Delete(ctx context.Context, id string, jobs []NewBackgroundJob) error
+	Create(ctx context.Context, order Order, jobs []NewBackgroundJob) (*Order, error)
 }
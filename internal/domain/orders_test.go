@@ -0,0 +1,26 @@
+package domain
+
+import "testing"
+
+func TestOrder_SetPickedUp(t *testing.T) {
+	for _, tc := range []struct {
+		status     string
+		isPickedUp bool
+		expected   string
+	}{
+		{OrderStatusPending, true, OrderStatusPickedUp},
+		{OrderStatusReady, true, OrderStatusPickedUp},
+		{OrderStatusPickedUp, false, OrderStatusReady},
+		{OrderStatusPending, false, OrderStatusPending},
+		{OrderStatusCancelled, false, OrderStatusCancelled},
+	} {
+		t.Run("should move "+tc.status+" to "+tc.expected, func(t *testing.T) {
+			order := Order{Status: tc.status}
+			order.SetPickedUp(tc.isPickedUp)
+
+			if order.Status != tc.expected || order.IsPickedUp == nil || *order.IsPickedUp != tc.isPickedUp {
+				t.Errorf("expected %s and picked up %v, but got %s and %v", tc.expected, tc.isPickedUp, order.Status, order.IsPickedUp)
+			}
+		})
+	}
+}
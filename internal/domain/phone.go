@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+const brazilCountryCode = "55"
+
+// NormalizePhone converte um telefone digitado em qualquer formato para E.164.
+// Números sem código de país são tratados como brasileiros e precisam conter o DDD.
+func NormalizePhone(raw string) (string, error) {
+	trimmed := strings.TrimSpace(raw)
+	digits := phoneDigits(trimmed)
+	if digits == "" {
+		return "", NewInvalidPhoneError(raw)
+	}
+
+	// Números internacionais explícitos que não são brasileiros são mantidos como vieram
+	if strings.HasPrefix(trimmed, "+") && !strings.HasPrefix(digits, brazilCountryCode) {
+		if len(digits) < 8 || len(digits) > 15 {
+			return "", NewInvalidPhoneError(raw)
+		}
+		return "+" + digits, nil
+	}
+
+	digits = strings.TrimLeft(digits, "0")
+
+	national := digits
+	if (len(digits) == 12 || len(digits) == 13) && strings.HasPrefix(digits, brazilCountryCode) {
+		national = digits[len(brazilCountryCode):]
+	}
+
+	if !isValidBrazilianNationalNumber(national) {
+		return "", NewInvalidPhoneError(raw)
+	}
+
+	return "+" + brazilCountryCode + national, nil
+}
+
+// NormalizeOptionalPhone normaliza telefones opcionais, tratando vazio como ausente.
+func NormalizeOptionalPhone(raw *string) (*string, error) {
+	if raw == nil || strings.TrimSpace(*raw) == "" {
+		return nil, nil
+	}
+
+	normalized, err := NormalizePhone(*raw)
+	if err != nil {
+		return nil, err
+	}
+	return &normalized, nil
+}
+
+// FormatPhone devolve o telefone no formato de exibição brasileiro, ex: (11) 99999-0000.
+// Valores que não estão em E.164 brasileiro são devolvidos sem alteração.
+func FormatPhone(phone string) string {
+	national, ok := strings.CutPrefix(phone, "+"+brazilCountryCode)
+	if !ok || !isValidBrazilianNationalNumber(national) {
+		return phone
+	}
+
+	ddd, number := national[:2], national[2:]
+	split := len(number) - 4
+	return fmt.Sprintf("(%s) %s-%s", ddd, number[:split], number[split:])
+}
+
+// WhatsAppNumber devolve o telefone no formato aceito pela API do WhatsApp (somente dígitos).
+func WhatsAppNumber(phone string) string {
+	return phoneDigits(phone)
+}
+
+func isValidBrazilianNationalNumber(national string) bool {
+	switch len(national) {
+	case 10:
+		// Fixo: DDD + 8 dígitos
+	case 11:
+		// Celular: DDD + 9 + 8 dígitos
+		if national[2] != '9' {
+			return false
+		}
+	default:
+		return false
+	}
+
+	return national[0] != '0' && national[1] != '0'
+}
+
+func phoneDigits(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
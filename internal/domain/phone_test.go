@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	cases := map[string]string{
+		"(11) 99999-0000":     "+5511999990000",
+		"11999990000":         "+5511999990000",
+		"011 99999-0000":      "+5511999990000",
+		"+55 11 99999-0000":   "+5511999990000",
+		"5511999990000":       "+5511999990000",
+		"(19) 3333-4444":      "+551933334444",
+		"+5511999990000":      "+5511999990000",
+		"+1 (415) 555-2671":   "+14155552671",
+		" 21 98888-7777 ":     "+5521988887777",
+		"+55 (21) 3222-1111 ": "+552132221111",
+	}
+
+	for input, expected := range cases {
+		normalized, err := NormalizePhone(input)
+		if err != nil {
+			t.Errorf("expected no error for %q, but got %v", input, err)
+			continue
+		}
+		if normalized != expected {
+			t.Errorf("expected %q to normalize to %s, but got %s", input, expected, normalized)
+		}
+	}
+}
+
+func TestNormalizePhone_Invalid(t *testing.T) {
+	for _, input := range []string{"", "abc", "99999-0000", "(11) 89999-0000", "123"} {
+		_, err := NormalizePhone(input)
+
+		var invalidPhoneErr *InvalidPhoneError
+		if !errors.As(err, &invalidPhoneErr) {
+			t.Errorf("expected InvalidPhoneError for %q, but got %v", input, err)
+		}
+	}
+}
+
+func TestFormatPhone(t *testing.T) {
+	cases := map[string]string{
+		"+5511999990000": "(11) 99999-0000",
+		"+551933334444":  "(19) 3333-4444",
+		"+14155552671":   "+14155552671",
+		"11999990000":    "11999990000",
+	}
+
+	for input, expected := range cases {
+		if formatted := FormatPhone(input); formatted != expected {
+			t.Errorf("expected %s to be formatted as %s, but got %s", input, expected, formatted)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	PickupCodeFormatQR      = "qr"
+	PickupCodeFormatCode128 = "code128"
+)
+
+var PickupCodeFormats = []string{PickupCodeFormatQR, PickupCodeFormatCode128}
+
+// NormalizePickupCode deixa o código como é gravado: maiúsculo e sem espaços ou hífens,
+// aceitando tanto a leitura do scanner quanto a digitação manual no balcão.
+func NormalizePickupCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || unicode.IsSpace(r) {
+			return -1
+		}
+		return unicode.ToUpper(r)
+	}, code)
+}
+
+// OrderCanBePickedUp indica se o pedido ainda pode ser entregue ao cliente.
+func OrderCanBePickedUp(status string) bool {
+	return status == OrderStatusPending || status == OrderStatusReady
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizePickupCode(t *testing.T) {
+	t.Run("should uppercase and strip separators typed at the counter", func(t *testing.T) {
+		if code := NormalizePickupCode(" ab3k-7x 9q "); code != "AB3K7X9Q" {
+			t.Errorf("expected AB3K7X9Q, but got %s", code)
+		}
+	})
+}
+
+func TestRenderOrderNotification_PickupCode(t *testing.T) {
+	order := Order{
+		Number:     "42",
+		PickupCode: "AB3K7X9Q",
+		PickupDate: time.Date(2026, 10, 20, 15, 0, 0, 0, time.UTC),
+		Customer:   Customer{Name: "João"},
+	}
+
+	t.Run("should include the pickup code on the confirmation message", func(t *testing.T) {
+		_, body, err := RenderOrderNotification(NotificationOrderConfirmation, order)
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if !strings.Contains(body, "código AB3K7X9Q") {
+			t.Errorf("expected pickup code in message, but got %q", body)
+		}
+	})
+
+	t.Run("should omit the code sentence when the order has no code", func(t *testing.T) {
+		order.PickupCode = ""
+		_, body, err := RenderOrderNotification(NotificationOrderConfirmation, order)
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if strings.Contains(body, "código") {
+			t.Errorf("expected no pickup code sentence, but got %q", body)
+		}
+	})
+}
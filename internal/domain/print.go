@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+const (
+	PrinterCounter = "counter"
+	PrinterKitchen = "kitchen"
+)
+
+var Printers = []string{PrinterCounter, PrinterKitchen}
+
+func IsValidPrinter(printer string) bool {
+	return slices.Contains(Printers, printer)
+}
+
+const (
+	PrintJobOrderReceipt   = "order_receipt"
+	PrintJobKitchenTicket  = "kitchen_ticket"
+	PrintJobProductionList = "production_list"
+)
+
+var printJobDefaultPrinters = map[string]string{
+	PrintJobOrderReceipt:   PrinterCounter,
+	PrintJobKitchenTicket:  PrinterKitchen,
+	PrintJobProductionList: PrinterKitchen,
+}
+
+// DefaultPrinterFor indica a impressora usada quando a impressão não escolhe uma: o comprovante
+// sai no balcão e a produção na cozinha.
+func DefaultPrinterFor(kind string) string {
+	return printJobDefaultPrinters[kind]
+}
+
+func IsValidPrintJobKind(kind string) bool {
+	_, ok := printJobDefaultPrinters[kind]
+	return ok
+}
+
+const (
+	PrintJobPending  = "pending"
+	PrintJobPrinting = "printing"
+	PrintJobPrinted  = "printed"
+	PrintJobFailed   = "failed"
+)
+
+// PrintJobMaxAttempts limita as reservas de um job. Um job reservado tantas vezes sem
+// confirmação, como um documento que trava a impressora, falha em vez de voltar à fila.
+const PrintJobMaxAttempts = 3
+
+// PrintJob é um documento já renderizado em ESC/POS aguardando o agente da impressora.
+// O payload só é carregado na reserva pelo agente e ao consultar um job específico.
+type PrintJob struct {
+	Id          string     `json:"id"`
+	Printer     string     `json:"printer"`
+	Kind        string     `json:"kind"`
+	OrderId     *string    `json:"orderId"`
+	Description string     `json:"description"`
+	Payload     []byte     `json:"payload,omitempty"`
+	Status      string     `json:"status"`
+	Attempts    int        `json:"attempts"`
+	ReprintOf   *string    `json:"reprintOf"`
+	RequestedBy string     `json:"requestedBy"`
+	ClaimedBy   *string    `json:"claimedBy"`
+	Error       *string    `json:"error"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	PrintedAt   *time.Time `json:"printedAt"`
+}
+
+type PrintJobFilters struct {
+	Printer string
+	Status  string
+	OrderId string
+}
+
+type PrintJobRepository interface {
+	Create(context.Context, PrintJob) (*PrintJob, error)
+	FindById(ctx context.Context, id string) (*PrintJob, error)
+	FindAll(context.Context, PrintJobFilters, Pagination) ([]PrintJob, Pagination, error)
+	// Claim reserva os próximos jobs da impressora para o agente pelo tempo do lease. Jobs
+	// reservados e não confirmados voltam para a fila quando o lease expira, até
+	// PrintJobMaxAttempts reservas; depois disso são marcados como falhos.
+	Claim(ctx context.Context, printer string, agentId string, limit int, lease time.Duration) ([]PrintJob, error)
+	// MarkPrinted e MarkFailed só alteram o job reservado por agentId que ainda está em impressão
+	MarkPrinted(ctx context.Context, id string, agentId string) error
+	MarkFailed(ctx context.Context, id string, agentId string, errorMessage string) error
+}
@@ -1,6 +1,10 @@
 package domain
 
+import "context"
+
 type NewProduct struct {
+	// Id pode vir do caso de uso para que os jobs do outbox já o referenciem; vazio, o banco gera.
+	Id              string  `json:"-"`
 	Name            string  `json:"name"`
 	Value           uint32  `json:"value"`
 	UnityType       string  `json:"unityType"`
@@ -26,9 +30,10 @@ type FindAllProductFilters struct {
 }
 
 type ProductRepository interface {
-	FindAll(FindAllProductFilters) ([]Product, error)
-	FindById(id string) (*Product, error)
-	Update(Product) error
-	Delete(id string) error
-	Create(product NewProduct) (*Product, error)
+	FindAll(context.Context, FindAllProductFilters) ([]Product, error)
+	FindById(ctx context.Context, id string) (*Product, error)
+	// Update, Delete e Create gravam os jobs na mesma transação da escrita do produto (outbox).
+	Update(ctx context.Context, product Product, jobs []NewBackgroundJob) error
+	Delete(ctx context.Context, id string, jobs []NewBackgroundJob) error
+	Create(ctx context.Context, product NewProduct, jobs []NewBackgroundJob) (*Product, error)
 }
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type SearchOrder struct {
+	Id         string    `json:"id"`
+	Number     string    `json:"number"`
+	PickupDate time.Time `json:"pickupDate"`
+	IsPickedUp *bool     `json:"isPickedUp"`
+	Customer   Customer  `json:"customer"`
+}
+
+type SearchResult struct {
+	Customers []Customer    `json:"customers"`
+	Products  []Product     `json:"products"`
+	Orders    []SearchOrder `json:"orders"`
+}
+
+type SearchRepository interface {
+	SearchCustomers(ctx context.Context, term string, limit int) ([]Customer, error)
+	SearchProducts(ctx context.Context, term string, limit int) ([]Product, error)
+	SearchOrders(ctx context.Context, term string, limit int) ([]SearchOrder, error)
+}
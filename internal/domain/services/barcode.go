@@ -0,0 +1,6 @@
+package services
+
+// BarcodeRenderer gera a imagem PNG do código no formato pedido (domain.PickupCodeFormats).
+type BarcodeRenderer interface {
+	RenderPNG(content string, format string, size int) ([]byte, error)
+}
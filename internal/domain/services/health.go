@@ -0,0 +1,10 @@
+package services
+
+import "context"
+
+// HealthCheck verifica uma dependência da API. Os detalhes são exibidos no /readyz.
+type HealthCheck interface {
+	Name() string
+	Critical() bool
+	Check(ctx context.Context) (map[string]any, error)
+}
@@ -0,0 +1,26 @@
+package services
+
+import "context"
+
+const (
+	NotificationChannelWhatsApp = "whatsapp"
+	NotificationChannelSMS      = "sms"
+	NotificationChannelEmail    = "email"
+	NotificationChannelLog      = "log"
+)
+
+type NotificationMessage struct {
+	Phone   string
+	Email   *string
+	Subject string
+	Body    string
+}
+
+// NotificationSender é implementado por cada canal de envio (WhatsApp, SMS, e-mail...).
+type NotificationSender interface {
+	Channel() string
+	// Recipient devolve o destinatário da mensagem neste canal, ou vazio se o cliente não puder recebê-la.
+	Recipient(message NotificationMessage) string
+	// Send envia a mensagem e devolve o id atribuído pelo provedor, quando houver.
+	Send(ctx context.Context, recipient string, message NotificationMessage) (string, error)
+}
@@ -0,0 +1,9 @@
+package services
+
+import "github.com/deividr/zion-api/internal/domain"
+
+type OrderEventSubscriber interface {
+	// Subscribe devolve um canal com os eventos que passam pelo filtro e uma função para
+	// cancelar a inscrição. O canal é fechado quando o assinante não acompanha o ritmo dos eventos.
+	Subscribe(filter domain.OrderEventFilter) (<-chan domain.OrderEvent, func())
+}
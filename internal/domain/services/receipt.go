@@ -0,0 +1,14 @@
+package services
+
+import (
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+// ReceiptRenderer gera os documentos da impressora térmica já no formato da impressora.
+type ReceiptRenderer interface {
+	RenderOrderReceipt(order domain.Order) ([]byte, error)
+	RenderKitchenTicket(order domain.Order) ([]byte, error)
+	RenderProductionList(date time.Time, batches []domain.KitchenBatch) ([]byte, error)
+}
@@ -0,0 +1,23 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+// JobFunc executa um job agendado e devolve um resumo do que foi feito.
+type JobFunc func(ctx context.Context) (string, error)
+
+type ScheduledJob struct {
+	Name     string
+	Schedule string
+	NextRun  *time.Time
+}
+
+type JobScheduler interface {
+	Jobs() []ScheduledJob
+	// Trigger inicia a execução imediata do job e devolve o registro da execução em andamento.
+	Trigger(name string, triggeredBy string) (*domain.JobRun, error)
+}
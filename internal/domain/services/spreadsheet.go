@@ -0,0 +1,9 @@
+package services
+
+import "github.com/deividr/zion-api/internal/domain"
+
+// SpreadsheetReader lê as linhas da primeira planilha de um arquivo CSV ou XLSX. Arquivos que
+// não puderem ser lidos devolvem domain.InvalidSpreadsheetError.
+type SpreadsheetReader interface {
+	Read(data []byte) ([]domain.SpreadsheetRow, error)
+}
@@ -1,10 +1,12 @@
 package services
 
+import "context"
+
 type PresignedURLResponse struct {
 	SignedURL string `json:"signedUrl"`
 	PublicURL string `json:"publicUrl"`
 }
 
 type UploadRepository interface {
-	GetPresignedURL(objectKey string) (*PresignedURLResponse, error)
+	GetPresignedURL(ctx context.Context, objectKey string) (*PresignedURLResponse, error)
 }
@@ -0,0 +1,12 @@
+package services
+
+import (
+	"context"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+// WebhookSender faz uma tentativa de entrega do payload no endpoint da assinatura.
+type WebhookSender interface {
+	Send(ctx context.Context, subscription domain.WebhookSubscription, delivery domain.WebhookDelivery) domain.WebhookDeliveryAttempt
+}
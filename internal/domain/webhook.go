@@ -0,0 +1,214 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	WebhookOrderCreated       = "order.created"
+	WebhookOrderUpdated       = "order.updated"
+	WebhookOrderStatusChanged = "order.status_changed"
+	WebhookOrderDeleted       = "order.deleted"
+	WebhookCustomerCreated    = "customer.created"
+	WebhookCustomerUpdated    = "customer.updated"
+	WebhookCustomerDeleted    = "customer.deleted"
+	WebhookProductCreated     = "product.created"
+	WebhookProductUpdated     = "product.updated"
+	WebhookProductDeleted     = "product.deleted"
+	// WebhookAllEvents inscreve a assinatura em todos os eventos.
+	WebhookAllEvents = "*"
+)
+
+var WebhookEventTypes = []string{
+	WebhookOrderCreated,
+	WebhookOrderUpdated,
+	WebhookOrderStatusChanged,
+	WebhookOrderDeleted,
+	WebhookCustomerCreated,
+	WebhookCustomerUpdated,
+	WebhookCustomerDeleted,
+	WebhookProductCreated,
+	WebhookProductUpdated,
+	WebhookProductDeleted,
+}
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+const (
+	WebhookMaxAttempts    = 8
+	webhookBaseRetryDelay = 30 * time.Second
+	webhookMaxRetryDelay  = 6 * time.Hour
+	// WebhookDeliveryTimeout limita cada envio; o lease das entregas reservadas é calculado a partir dele.
+	WebhookDeliveryTimeout = 10 * time.Second
+)
+
+type NewWebhookSubscription struct {
+	Url         string   `json:"url"`
+	Secret      string   `json:"secret"`
+	EventTypes  []string `json:"eventTypes"`
+	Description *string  `json:"description"`
+	IsActive    *bool    `json:"isActive"`
+}
+
+type WebhookSubscription struct {
+	Id          string     `json:"id"`
+	Url         string     `json:"url"`
+	Secret      string     `json:"secret,omitempty"`
+	EventTypes  []string   `json:"eventTypes"`
+	Description *string    `json:"description"`
+	IsActive    bool       `json:"isActive"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   *time.Time `json:"updatedAt"`
+}
+
+// Accepts indica se a assinatura deve receber o tipo de evento.
+func (s WebhookSubscription) Accepts(eventType string) bool {
+	return s.IsActive && (slices.Contains(s.EventTypes, WebhookAllEvents) || slices.Contains(s.EventTypes, eventType))
+}
+
+// WebhookEvent é o corpo enviado aos assinantes.
+type WebhookEvent struct {
+	Id         string    `json:"id"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurredAt"`
+	Data       any       `json:"data"`
+}
+
+type WebhookDelivery struct {
+	Id             string          `json:"id"`
+	SubscriptionId string          `json:"subscriptionId"`
+	EventId        string          `json:"eventId"`
+	EventType      string          `json:"eventType"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"nextAttemptAt"`
+	LastStatusCode *int            `json:"lastStatusCode"`
+	LastError      *string         `json:"lastError"`
+	ReplayOf       *string         `json:"replayOf"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	DeliveredAt    *time.Time      `json:"deliveredAt"`
+}
+
+// WebhookDeliveryAttempt é o resultado de uma tentativa de entrega.
+type WebhookDeliveryAttempt struct {
+	StatusCode *int
+	Error      *string
+}
+
+// Succeeded considera entregue qualquer resposta 2xx.
+func (a WebhookDeliveryAttempt) Succeeded() bool {
+	return a.Error == nil && a.StatusCode != nil && *a.StatusCode >= 200 && *a.StatusCode < 300
+}
+
+// WebhookRetryDelay calcula a espera antes da próxima tentativa com backoff exponencial:
+// 30s, 1min, 2min, 4min... limitado a 6h.
+func WebhookRetryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := webhookBaseRetryDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= webhookMaxRetryDelay {
+			return webhookMaxRetryDelay
+		}
+	}
+	return delay
+}
+
+// ValidateWebhookSubscription normaliza e valida os dados da assinatura.
+func ValidateWebhookSubscription(subscription NewWebhookSubscription) (NewWebhookSubscription, error) {
+	subscription.Url = strings.TrimSpace(subscription.Url)
+	parsed, err := url.Parse(subscription.Url)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return subscription, NewInvalidWebhookError("url must be an absolute http or https URL")
+	}
+	if !IsPublicWebhookHost(parsed.Hostname()) {
+		return subscription, NewInvalidWebhookError("url must not point to a local or private address")
+	}
+
+	if len(subscription.EventTypes) == 0 {
+		return subscription, NewInvalidWebhookError("at least one event type is required")
+	}
+
+	eventTypes := []string{}
+	for _, eventType := range subscription.EventTypes {
+		eventType = strings.TrimSpace(eventType)
+		if slices.Contains(eventTypes, eventType) {
+			continue
+		}
+		if eventType != WebhookAllEvents && !slices.Contains(WebhookEventTypes, eventType) {
+			return subscription, NewInvalidWebhookError("unknown event type " + eventType)
+		}
+		eventTypes = append(eventTypes, eventType)
+	}
+	subscription.EventTypes = eventTypes
+
+	return subscription, nil
+}
+
+// IsPublicWebhookHost recusa hosts que apontam para a própria rede: localhost e IPs de loopback,
+// privados, link-local (inclui o metadata 169.254.169.254) ou não especificados. Nomes de domínio
+// passam aqui e o IP resolvido é verificado de novo na conexão com IsPublicWebhookAddr.
+func IsPublicWebhookHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return true
+	}
+	return IsPublicWebhookAddr(addr)
+}
+
+// IsPublicWebhookAddr indica se o IP pode receber webhooks.
+func IsPublicWebhookAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified() &&
+		!sharedAddressSpace.Contains(addr)
+}
+
+// sharedAddressSpace é a faixa de CGNAT (RFC 6598), também interna à rede do provedor.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+type WebhookSubscriptionRepository interface {
+	FindAll(ctx context.Context) ([]WebhookSubscription, error)
+	FindActiveByEvent(ctx context.Context, eventType string) ([]WebhookSubscription, error)
+	FindById(ctx context.Context, id string) (*WebhookSubscription, error)
+	Create(context.Context, NewWebhookSubscription) (*WebhookSubscription, error)
+	Update(context.Context, WebhookSubscription) error
+	Delete(ctx context.Context, id string) error
+}
+
+type WebhookDeliveryRepository interface {
+	Create(context.Context, WebhookDelivery) (*WebhookDelivery, error)
+	// CreateForEvent grava as entregas de um evento em um único comando, ignorando as assinaturas
+	// que já têm entrega desse evento.
+	CreateForEvent(ctx context.Context, deliveries []WebhookDelivery) error
+	FindById(ctx context.Context, id string) (*WebhookDelivery, error)
+	FindBySubscription(ctx context.Context, subscriptionId string, pagination Pagination) ([]WebhookDelivery, Pagination, error)
+	// ClaimDue reserva até limit entregas pendentes vencidas para esta instância,
+	// adiando o próximo horário para que outras instâncias não as peguem ao mesmo tempo.
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]WebhookDelivery, error)
+	RecordAttempt(ctx context.Context, id string, status string, attempt WebhookDeliveryAttempt, retryIn time.Duration) error
+}
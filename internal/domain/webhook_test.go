@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWebhookRetryDelay(t *testing.T) {
+	t.Run("should double the delay on each attempt", func(t *testing.T) {
+		expected := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute}
+		for i, delay := range expected {
+			if got := WebhookRetryDelay(i + 1); got != delay {
+				t.Errorf("expected delay %s for attempt %d, but got %s", delay, i+1, got)
+			}
+		}
+	})
+
+	t.Run("should cap the delay", func(t *testing.T) {
+		if got := WebhookRetryDelay(50); got != 6*time.Hour {
+			t.Errorf("expected delay capped at 6h, but got %s", got)
+		}
+	})
+}
+
+func TestValidateWebhookSubscription(t *testing.T) {
+	t.Run("should reject relative or non http urls", func(t *testing.T) {
+		for _, url := range []string{"", "/hooks", "ftp://example.com/hook"} {
+			_, err := ValidateWebhookSubscription(NewWebhookSubscription{Url: url, EventTypes: []string{WebhookOrderCreated}})
+			var invalidErr *InvalidWebhookError
+			if !errors.As(err, &invalidErr) {
+				t.Errorf("expected invalid webhook error for url %q, but got %v", url, err)
+			}
+		}
+	})
+
+	t.Run("should reject urls pointing to local or private addresses", func(t *testing.T) {
+		urls := []string{
+			"http://localhost:8080/hook",
+			"http://127.0.0.1/hook",
+			"http://[::1]/hook",
+			"http://10.0.0.5/hook",
+			"http://192.168.1.10/hook",
+			"http://172.16.0.1/hook",
+			"http://169.254.169.254/latest/meta-data",
+			"http://[::ffff:127.0.0.1]/hook",
+			"http://0.0.0.0/hook",
+		}
+		for _, url := range urls {
+			_, err := ValidateWebhookSubscription(NewWebhookSubscription{Url: url, EventTypes: []string{WebhookOrderCreated}})
+			var invalidErr *InvalidWebhookError
+			if !errors.As(err, &invalidErr) {
+				t.Errorf("expected invalid webhook error for url %q, but got %v", url, err)
+			}
+		}
+
+		if _, err := ValidateWebhookSubscription(NewWebhookSubscription{Url: "https://203.0.113.10/hook", EventTypes: []string{WebhookOrderCreated}}); err != nil {
+			t.Errorf("expected public address to be accepted, but got %v", err)
+		}
+	})
+
+	t.Run("should reject unknown event types", func(t *testing.T) {
+		_, err := ValidateWebhookSubscription(NewWebhookSubscription{Url: "https://example.com", EventTypes: []string{"order.eaten"}})
+		if err == nil {
+			t.Errorf("expected error for unknown event type")
+		}
+	})
+
+	t.Run("should remove repeated event types", func(t *testing.T) {
+		subscription, err := ValidateWebhookSubscription(NewWebhookSubscription{
+			Url:        " https://example.com/hook ",
+			EventTypes: []string{WebhookOrderCreated, WebhookOrderCreated, WebhookAllEvents},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if subscription.Url != "https://example.com/hook" || len(subscription.EventTypes) != 2 {
+			t.Errorf("expected trimmed url and 2 event types, but got %+v", subscription)
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package barcode
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/qr"
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+const (
+	DefaultSize = 256
+	MaxSize     = 1024
+
+	// code128Ratio é a proporção largura/altura usada para o código de barras.
+	code128Ratio = 3
+)
+
+type PNGRenderer struct{}
+
+func NewPNGRenderer() *PNGRenderer {
+	return &PNGRenderer{}
+}
+
+// RenderPNG gera o QR code (size x size) ou o Code128 (size de largura) em PNG.
+func (r *PNGRenderer) RenderPNG(content string, format string, size int) ([]byte, error) {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	if size > MaxSize {
+		size = MaxSize
+	}
+
+	var (
+		code          barcode.Barcode
+		err           error
+		width, height int
+	)
+	switch format {
+	case domain.PickupCodeFormatQR:
+		code, err = qr.Encode(content, qr.M, qr.Auto)
+		width, height = size, size
+	case domain.PickupCodeFormatCode128:
+		code, err = code128.Encode(content)
+		width, height = size, size/code128Ratio
+	default:
+		return nil, domain.NewInvalidPickupCodeFormatError(format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error encoding %s barcode: %w", format, err)
+	}
+
+	// A imagem não pode ficar menor que a quantidade de módulos do código
+	if bounds := code.Bounds(); width < bounds.Dx() {
+		width = bounds.Dx()
+		if format == domain.PickupCodeFormatQR {
+			height = width
+		}
+	}
+
+	scaled, err := barcode.Scale(code, width, height)
+	if err != nil {
+		return nil, fmt.Errorf("error scaling %s barcode: %w", format, err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, scaled); err != nil {
+		return nil, fmt.Errorf("error encoding barcode png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,52 @@
+package barcode
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"testing"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+func TestPNGRenderer_RenderPNG(t *testing.T) {
+	renderer := NewPNGRenderer()
+
+	t.Run("should render a square QR code", func(t *testing.T) {
+		data, err := renderer.RenderPNG("AB3K7X9Q", domain.PickupCodeFormatQR, 200)
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("expected a valid png, but got %v", err)
+		}
+		if bounds := img.Bounds(); bounds.Dx() != 200 || bounds.Dy() != 200 {
+			t.Errorf("expected 200x200 image, but got %dx%d", bounds.Dx(), bounds.Dy())
+		}
+	})
+
+	t.Run("should render a wide Code128 barcode", func(t *testing.T) {
+		data, err := renderer.RenderPNG("AB3K7X9Q", domain.PickupCodeFormatCode128, 0)
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("expected a valid png, but got %v", err)
+		}
+		if bounds := img.Bounds(); bounds.Dx() <= bounds.Dy() {
+			t.Errorf("expected a landscape image, but got %dx%d", bounds.Dx(), bounds.Dy())
+		}
+	})
+
+	t.Run("should reject unknown formats", func(t *testing.T) {
+		_, err := renderer.RenderPNG("AB3K7X9Q", "ean13", 0)
+		var formatErr *domain.InvalidPickupCodeFormatError
+		if !errors.As(err, &formatErr) {
+			t.Errorf("expected InvalidPickupCodeFormatError, but got %v", err)
+		}
+	})
+}
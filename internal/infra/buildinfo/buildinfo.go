@@ -0,0 +1,48 @@
+// Package buildinfo expõe a versão do binário. Os valores são injetados no build:
+//
+//	go build -ldflags "-X github.com/deividr/zion-api/internal/infra/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/deividr/zion-api/internal/infra/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+// Get devolve as informações do build. Sem ldflags, usa os dados de VCS que o Go grava no
+// binário quando compilado dentro do repositório.
+func Get() domain.BuildInfo {
+	info := domain.BuildInfo{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range build.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.BuildTime == "":
+				info.BuildTime = setting.Value
+			}
+		}
+	}
+
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = "unknown"
+	}
+	return info
+}
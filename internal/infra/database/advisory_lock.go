@@ -0,0 +1,39 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TryAdvisoryLock tenta obter um advisory lock de sessão identificado por key sem bloquear.
+// O lock fica preso à conexão adquirida do pool, que só é devolvida quando unlock é chamado.
+// Serve para garantir que apenas uma instância da API execute determinada tarefa por vez.
+func TryAdvisoryLock(ctx context.Context, pool *pgxpool.Pool, key string) (func(), bool, error) {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("error acquiring connection for advisory lock: %w", err)
+	}
+
+	var acquired bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock(hashtext($1))", key).Scan(&acquired); err != nil {
+		conn.Release()
+		return nil, false, fmt.Errorf("error acquiring advisory lock %s: %w", key, err)
+	}
+
+	if !acquired {
+		conn.Release()
+		return func() {}, false, nil
+	}
+
+	unlock := func() {
+		if _, err := conn.Exec(context.Background(), "SELECT pg_advisory_unlock(hashtext($1))", key); err != nil {
+			// Sem o unlock a sessão continuaria segurando o lock, então a conexão é descartada.
+			conn.Conn().Close(context.Background())
+		}
+		conn.Release()
+	}
+
+	return unlock, true, nil
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PingCheck verifica se o pool consegue falar com o Postgres.
+type PingCheck struct {
+	pool *pgxpool.Pool
+}
+
+func NewPingCheck(pool *pgxpool.Pool) *PingCheck {
+	return &PingCheck{pool: pool}
+}
+
+func (c *PingCheck) Name() string   { return "postgres" }
+func (c *PingCheck) Critical() bool { return true }
+
+func (c *PingCheck) Check(ctx context.Context) (map[string]any, error) {
+	stat := c.pool.Stat()
+	details := map[string]any{
+		"totalConns":    stat.TotalConns(),
+		"idleConns":     stat.IdleConns(),
+		"acquiredConns": stat.AcquiredConns(),
+		"maxConns":      stat.MaxConns(),
+	}
+
+	if err := c.pool.Ping(ctx); err != nil {
+		return details, fmt.Errorf("ping failed: %w", err)
+	}
+	return details, nil
+}
+
+// MigrationCheck compara a versão aplicada pelo golang-migrate com a migration mais recente
+// embutida no binário. Um banco à frente do binário (deploy em andamento) não é falha.
+type MigrationCheck struct {
+	pool     *pgxpool.Pool
+	expected uint64
+}
+
+func NewMigrationCheck(pool *pgxpool.Pool, expected uint64) *MigrationCheck {
+	return &MigrationCheck{pool: pool, expected: expected}
+}
+
+func (c *MigrationCheck) Name() string   { return "migrations" }
+func (c *MigrationCheck) Critical() bool { return true }
+
+func (c *MigrationCheck) Check(ctx context.Context) (map[string]any, error) {
+	details := map[string]any{"expected": c.expected}
+
+	var version uint64
+	var dirty bool
+	err := c.pool.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return details, fmt.Errorf("no migration applied")
+		}
+		return details, fmt.Errorf("error reading schema_migrations: %w", err)
+	}
+
+	details["current"] = version
+	details["dirty"] = dirty
+
+	if dirty {
+		return details, fmt.Errorf("migration %d is dirty", version)
+	}
+	if version < c.expected {
+		return details, fmt.Errorf("schema is at %d, expected %d", version, c.expected)
+	}
+	return details, nil
+}
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_category_products_parent_id;
+
+ALTER TABLE category_products DROP COLUMN is_deleted;
+ALTER TABLE category_products DROP COLUMN display_order;
+ALTER TABLE category_products DROP COLUMN parent_id;
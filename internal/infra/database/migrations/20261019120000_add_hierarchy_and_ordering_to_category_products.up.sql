@@ -0,0 +1,5 @@
+ALTER TABLE category_products ADD COLUMN parent_id uuid REFERENCES category_products (id);
+ALTER TABLE category_products ADD COLUMN display_order integer NOT NULL DEFAULT 0;
+ALTER TABLE category_products ADD COLUMN is_deleted boolean NOT NULL DEFAULT false;
+
+CREATE INDEX idx_category_products_parent_id ON category_products (parent_id);
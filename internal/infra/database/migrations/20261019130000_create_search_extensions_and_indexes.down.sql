@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_products_name_trgm;
+DROP INDEX IF EXISTS idx_customers_email_trgm;
+DROP INDEX IF EXISTS idx_customers_phone2_digits_trgm;
+DROP INDEX IF EXISTS idx_customers_phone_digits_trgm;
+DROP INDEX IF EXISTS idx_customers_name_trgm;
+
+DROP FUNCTION IF EXISTS only_digits(text);
+DROP FUNCTION IF EXISTS immutable_unaccent(text);
+
+DROP EXTENSION IF EXISTS pg_trgm;
+DROP EXTENSION IF EXISTS unaccent;
@@ -0,0 +1,17 @@
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- unaccent() is STABLE, so it cannot be used in index expressions directly
+CREATE OR REPLACE FUNCTION immutable_unaccent(text) RETURNS text AS $$
+    SELECT public.unaccent('public.unaccent', $1)
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+CREATE OR REPLACE FUNCTION only_digits(text) RETURNS text AS $$
+    SELECT regexp_replace($1, '\D', '', 'g')
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+CREATE INDEX idx_customers_name_trgm ON customers USING gin (immutable_unaccent(name) gin_trgm_ops);
+CREATE INDEX idx_customers_phone_digits_trgm ON customers USING gin (only_digits(phone) gin_trgm_ops);
+CREATE INDEX idx_customers_phone2_digits_trgm ON customers USING gin (only_digits(phone2) gin_trgm_ops);
+CREATE INDEX idx_customers_email_trgm ON customers USING gin (immutable_unaccent(email) gin_trgm_ops);
+CREATE INDEX idx_products_name_trgm ON products USING gin (immutable_unaccent(name) gin_trgm_ops);
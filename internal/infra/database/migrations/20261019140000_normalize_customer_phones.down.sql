@@ -0,0 +1,3 @@
+-- A normalização dos telefones não é revertida; apenas os objetos auxiliares são removidos.
+DROP TABLE IF EXISTS customer_phone_collisions;
+DROP FUNCTION IF EXISTS normalize_br_phone(text);
@@ -0,0 +1,120 @@
+-- Mesmas regras de domain.NormalizePhone: números brasileiros com DDD viram E.164.
+-- Retorna NULL quando o valor não pode ser normalizado.
+CREATE OR REPLACE FUNCTION normalize_br_phone(raw text) RETURNS text AS $$
+DECLARE
+    digits text := ltrim(only_digits(raw), '0');
+BEGIN
+    IF raw IS NULL OR digits = '' THEN
+        RETURN NULL;
+    END IF;
+
+    IF left(btrim(raw), 1) = '+' AND left(digits, 2) <> '55' THEN
+        IF length(digits) BETWEEN 8 AND 15 THEN
+            RETURN '+' || digits;
+        END IF;
+        RETURN NULL;
+    END IF;
+
+    IF length(digits) IN (12, 13) AND left(digits, 2) = '55' THEN
+        digits := substr(digits, 3);
+    END IF;
+
+    IF length(digits) = 11 AND substr(digits, 3, 1) <> '9' THEN
+        RETURN NULL;
+    END IF;
+
+    IF length(digits) NOT IN (10, 11) OR substr(digits, 1, 1) = '0' OR substr(digits, 2, 1) = '0' THEN
+        RETURN NULL;
+    END IF;
+
+    RETURN '+55' || digits;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE PARALLEL SAFE;
+
+-- Clientes cujo telefone normalizado já pertence a outro cliente mantêm o valor
+-- original e ficam registrados aqui para revisão (e posterior merge).
+CREATE TABLE customer_phone_collisions (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    customer_id uuid NOT NULL REFERENCES customers (id),
+    column_name text NOT NULL,
+    original_phone text NOT NULL,
+    normalized_phone text NOT NULL,
+    conflicting_customer_id uuid NOT NULL REFERENCES customers (id),
+    created_at timestamp DEFAULT now() NOT NULL
+);
+
+WITH ranked AS (
+    SELECT id,
+           phone,
+           COALESCE(normalize_br_phone(phone), phone) AS normalized,
+           row_number() OVER w AS rn,
+           first_value(id) OVER w AS kept_id
+    FROM customers
+    WINDOW w AS (
+        PARTITION BY COALESCE(normalize_br_phone(phone), phone)
+        ORDER BY phone = COALESCE(normalize_br_phone(phone), phone) DESC, is_deleted, created_at, id
+    )
+)
+INSERT INTO customer_phone_collisions (customer_id, column_name, original_phone, normalized_phone, conflicting_customer_id)
+SELECT id, 'phone', phone, normalized, kept_id
+FROM ranked
+WHERE rn > 1;
+
+WITH ranked AS (
+    SELECT id,
+           COALESCE(normalize_br_phone(phone), phone) AS normalized,
+           row_number() OVER (
+               PARTITION BY COALESCE(normalize_br_phone(phone), phone)
+               ORDER BY phone = COALESCE(normalize_br_phone(phone), phone) DESC, is_deleted, created_at, id
+           ) AS rn
+    FROM customers
+)
+UPDATE customers c
+SET phone = r.normalized
+FROM ranked r
+WHERE c.id = r.id AND r.rn = 1 AND c.phone <> r.normalized;
+
+UPDATE customers SET phone2 = NULL WHERE btrim(phone2) = '';
+
+WITH ranked AS (
+    SELECT id,
+           phone2,
+           COALESCE(normalize_br_phone(phone2), phone2) AS normalized,
+           row_number() OVER w AS rn,
+           first_value(id) OVER w AS kept_id
+    FROM customers
+    WHERE phone2 IS NOT NULL
+    WINDOW w AS (
+        PARTITION BY COALESCE(normalize_br_phone(phone2), phone2)
+        ORDER BY phone2 = COALESCE(normalize_br_phone(phone2), phone2) DESC, is_deleted, created_at, id
+    )
+)
+INSERT INTO customer_phone_collisions (customer_id, column_name, original_phone, normalized_phone, conflicting_customer_id)
+SELECT id, 'phone2', phone2, normalized, kept_id
+FROM ranked
+WHERE rn > 1;
+
+WITH ranked AS (
+    SELECT id,
+           COALESCE(normalize_br_phone(phone2), phone2) AS normalized,
+           row_number() OVER (
+               PARTITION BY COALESCE(normalize_br_phone(phone2), phone2)
+               ORDER BY phone2 = COALESCE(normalize_br_phone(phone2), phone2) DESC, is_deleted, created_at, id
+           ) AS rn
+    FROM customers
+    WHERE phone2 IS NOT NULL
+)
+UPDATE customers c
+SET phone2 = r.normalized
+FROM ranked r
+WHERE c.id = r.id AND r.rn = 1 AND c.phone2 <> r.normalized;
+
+DO $$
+DECLARE
+    collisions integer;
+    invalid integer;
+BEGIN
+    SELECT count(*) INTO collisions FROM customer_phone_collisions;
+    SELECT count(*) INTO invalid FROM customers WHERE normalize_br_phone(phone) IS NULL;
+    RAISE NOTICE 'phone normalization: % collisions recorded in customer_phone_collisions, % phones could not be normalized', collisions, invalid;
+END $$;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_address_customers_customer_id;
+DROP TABLE IF EXISTS customer_merges;
+ALTER TABLE customers DROP COLUMN merged_into;
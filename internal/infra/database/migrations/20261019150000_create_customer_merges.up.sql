@@ -0,0 +1,15 @@
+ALTER TABLE customers ADD COLUMN merged_into uuid REFERENCES customers (id);
+
+CREATE TABLE customer_merges (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    survivor_id uuid NOT NULL REFERENCES customers (id),
+    merged_customer_ids uuid[] NOT NULL,
+    merged_customers jsonb NOT NULL,
+    moved_orders integer NOT NULL DEFAULT 0,
+    moved_addresses integer NOT NULL DEFAULT 0,
+    merged_by text NOT NULL,
+    created_at timestamp DEFAULT now() NOT NULL
+);
+
+CREATE INDEX idx_customer_merges_survivor_id ON customer_merges (survivor_id);
+CREATE INDEX idx_address_customers_customer_id ON address_customers (customer_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS customer_notes;
+ALTER TABLE customers DROP COLUMN dietary_preferences;
+ALTER TABLE customers DROP COLUMN tags;
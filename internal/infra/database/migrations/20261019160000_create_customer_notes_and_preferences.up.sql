@@ -0,0 +1,13 @@
+ALTER TABLE customers ADD COLUMN tags text[] NOT NULL DEFAULT '{}';
+ALTER TABLE customers ADD COLUMN dietary_preferences text[] NOT NULL DEFAULT '{}';
+
+CREATE TABLE customer_notes (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    customer_id uuid NOT NULL REFERENCES customers (id),
+    content text NOT NULL,
+    author text NOT NULL,
+    is_alert boolean NOT NULL DEFAULT false,
+    created_at timestamp DEFAULT now() NOT NULL
+);
+
+CREATE INDEX idx_customer_notes_customer_id ON customer_notes (customer_id);
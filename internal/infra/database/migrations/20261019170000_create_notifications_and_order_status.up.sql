@@ -0,0 +1,23 @@
+ALTER TABLE orders ADD COLUMN status text NOT NULL DEFAULT 'pending';
+UPDATE orders SET status = 'picked_up' WHERE is_picked_up = true;
+
+CREATE TABLE notifications (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    customer_id uuid NOT NULL REFERENCES customers (id),
+    order_id uuid REFERENCES orders (id),
+    template text NOT NULL,
+    channel text NOT NULL,
+    recipient text NOT NULL,
+    subject text,
+    body text NOT NULL,
+    status text NOT NULL DEFAULT 'pending',
+    provider_message_id text,
+    error text,
+    created_at timestamp DEFAULT now() NOT NULL,
+    sent_at timestamp,
+    updated_at timestamp
+);
+
+CREATE INDEX idx_notifications_order_id ON notifications (order_id);
+CREATE INDEX idx_notifications_customer_id ON notifications (customer_id);
+CREATE INDEX idx_notifications_provider_message_id ON notifications (provider_message_id);
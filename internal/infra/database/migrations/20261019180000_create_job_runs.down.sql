@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_notifications_order_id_template;
+DROP TABLE IF EXISTS job_runs;
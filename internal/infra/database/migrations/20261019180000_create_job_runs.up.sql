@@ -0,0 +1,13 @@
+CREATE TABLE job_runs (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_name text NOT NULL,
+    status text NOT NULL,
+    triggered_by text NOT NULL,
+    result text,
+    error text,
+    started_at timestamp DEFAULT now() NOT NULL,
+    finished_at timestamp
+);
+
+CREATE INDEX idx_job_runs_job_name_started_at ON job_runs (job_name, started_at DESC);
+CREATE INDEX idx_notifications_order_id_template ON notifications (order_id, template);
@@ -0,0 +1,46 @@
+-- Publica no canal order_events cada mudança em pedidos, para que todas as instâncias da API
+-- repassem o evento aos quadros conectados via SSE. O payload é determinístico para que o
+-- Postgres descarte notificações repetidas dentro da mesma transação.
+CREATE OR REPLACE FUNCTION notify_order_event() RETURNS trigger AS $$
+DECLARE
+    event_type text;
+    rec record;
+    previous_status text;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        event_type := 'created';
+        rec := NEW;
+    ELSIF TG_OP = 'DELETE' THEN
+        event_type := 'deleted';
+        rec := OLD;
+    ELSE
+        rec := NEW;
+        previous_status := OLD.status;
+
+        IF NEW.is_deleted AND NOT OLD.is_deleted THEN
+            event_type := 'deleted';
+        ELSIF NEW.is_deleted THEN
+            RETURN NULL;
+        ELSIF NEW.status IS DISTINCT FROM OLD.status THEN
+            event_type := 'status_changed';
+        ELSE
+            event_type := 'updated';
+        END IF;
+    END IF;
+
+    PERFORM pg_notify('order_events', json_build_object(
+        'type', event_type,
+        'orderId', rec.id,
+        'orderNumber', rec.order_number::text,
+        'status', rec.status,
+        'previousStatus', previous_status,
+        'pickupDate', to_char(rec.pickup_date, 'YYYY-MM-DD"T"HH24:MI:SS"Z"')
+    )::text);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER orders_notify_event
+AFTER INSERT OR UPDATE OR DELETE ON orders
+FOR EACH ROW EXECUTE FUNCTION notify_order_event();
@@ -0,0 +1,18 @@
+CREATE TABLE background_jobs (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind text NOT NULL,
+    payload jsonb NOT NULL DEFAULT '{}',
+    status text NOT NULL DEFAULT 'pending',
+    attempts int NOT NULL DEFAULT 0,
+    max_attempts int NOT NULL DEFAULT 10,
+    run_at timestamp DEFAULT now() NOT NULL,
+    locked_by text,
+    locked_at timestamp,
+    last_error text,
+    created_at timestamp DEFAULT now() NOT NULL,
+    finished_at timestamp
+);
+
+-- Fila: jobs pendentes e jobs em execução cujo lease expirou (worker morreu no meio do job)
+CREATE INDEX idx_background_jobs_claim ON background_jobs (run_at) WHERE status IN ('pending', 'running');
+CREATE INDEX idx_background_jobs_status_kind ON background_jobs (status, kind);
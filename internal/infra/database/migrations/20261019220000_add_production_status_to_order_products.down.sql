@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_order_products_order_id;
+
+ALTER TABLE order_products
+    DROP COLUMN IF EXISTS done_by,
+    DROP COLUMN IF EXISTS done_at,
+    DROP COLUMN IF EXISTS production_status,
+    DROP COLUMN IF EXISTS observations;
@@ -0,0 +1,13 @@
+ALTER TABLE order_products
+    ADD COLUMN observations text,
+    ADD COLUMN production_status text NOT NULL DEFAULT 'pending',
+    ADD COLUMN done_at timestamp,
+    ADD COLUMN done_by text;
+
+-- Pedidos já retirados não voltam para a cozinha
+UPDATE order_products op
+SET production_status = 'done', done_at = o.updated_at
+FROM orders o
+WHERE o.id = op.order_id AND o.status IN ('ready', 'picked_up');
+
+CREATE INDEX idx_order_products_order_id ON order_products (order_id);
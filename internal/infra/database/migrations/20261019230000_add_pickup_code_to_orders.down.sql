@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_orders_pickup_code;
+ALTER TABLE orders DROP COLUMN IF EXISTS pickup_code;
+DROP FUNCTION IF EXISTS generate_pickup_code();
@@ -0,0 +1,15 @@
+-- Código curto impresso/enviado ao cliente para localizar o pedido na retirada.
+-- O alfabeto evita caracteres ambíguos (0/O, 1/I/L).
+CREATE FUNCTION generate_pickup_code() RETURNS text AS $$
+    SELECT string_agg(substr('23456789ABCDEFGHJKMNPQRSTUVWXYZ', 1 + floor(random() * 31)::int, 1), '')
+    FROM generate_series(1, 8);
+$$ LANGUAGE sql VOLATILE;
+
+ALTER TABLE orders ADD COLUMN pickup_code text;
+
+UPDATE orders SET pickup_code = generate_pickup_code();
+
+ALTER TABLE orders ALTER COLUMN pickup_code SET DEFAULT generate_pickup_code();
+ALTER TABLE orders ALTER COLUMN pickup_code SET NOT NULL;
+
+CREATE UNIQUE INDEX idx_orders_pickup_code ON orders (pickup_code);
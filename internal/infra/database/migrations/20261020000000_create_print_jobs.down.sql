@@ -0,0 +1 @@
+DROP TABLE IF EXISTS print_jobs;
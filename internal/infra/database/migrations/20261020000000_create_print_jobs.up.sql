@@ -0,0 +1,21 @@
+CREATE TABLE print_jobs (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    printer text NOT NULL,
+    kind text NOT NULL,
+    order_id uuid REFERENCES orders (id),
+    description text NOT NULL,
+    payload bytea NOT NULL,
+    status text NOT NULL DEFAULT 'pending',
+    attempts int NOT NULL DEFAULT 0,
+    reprint_of uuid REFERENCES print_jobs (id),
+    requested_by text NOT NULL,
+    claimed_by text,
+    claimed_until timestamp,
+    error text,
+    created_at timestamp DEFAULT now() NOT NULL,
+    printed_at timestamp
+);
+
+-- Fila por impressora: jobs pendentes e jobs reservados cujo prazo expirou (agente caiu no meio da impressão)
+CREATE INDEX idx_print_jobs_claim ON print_jobs (printer, created_at) WHERE status IN ('pending', 'printing');
+CREATE INDEX idx_print_jobs_order_id ON print_jobs (order_id);
@@ -0,0 +1,3 @@
+ALTER TABLE orders ALTER COLUMN order_number DROP DEFAULT;
+
+DROP SEQUENCE IF EXISTS orders_order_number_seq;
@@ -0,0 +1,7 @@
+-- Até aqui todo pedido criado pela API recebia o número 500. Os números importados do sistema
+-- legado são mantidos e a sequência continua a partir do maior deles.
+CREATE SEQUENCE orders_order_number_seq OWNED BY orders.order_number;
+
+SELECT setval('orders_order_number_seq', COALESCE((SELECT max(order_number) FROM orders), 0) + 1, false);
+
+ALTER TABLE orders ALTER COLUMN order_number SET DEFAULT nextval('orders_order_number_seq');
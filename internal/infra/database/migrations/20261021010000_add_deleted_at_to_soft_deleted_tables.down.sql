@@ -0,0 +1,11 @@
+DROP TRIGGER IF EXISTS products_set_deleted_at ON products;
+DROP TRIGGER IF EXISTS customers_set_deleted_at ON customers;
+DROP TRIGGER IF EXISTS orders_set_deleted_at ON orders;
+DROP TRIGGER IF EXISTS category_products_set_deleted_at ON category_products;
+
+ALTER TABLE products DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE customers DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE category_products DROP COLUMN IF EXISTS deleted_at;
+
+DROP FUNCTION IF EXISTS set_deleted_at();
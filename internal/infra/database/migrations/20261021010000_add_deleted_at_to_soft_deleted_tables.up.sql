@@ -0,0 +1,32 @@
+-- deleted_at registra quando a linha foi marcada como excluída, para que o expurgo respeite um
+-- prazo de retenção. É mantido por trigger, sem depender de cada repositório.
+CREATE OR REPLACE FUNCTION set_deleted_at() RETURNS trigger AS $$
+BEGIN
+    IF NEW.is_deleted AND NOT OLD.is_deleted THEN
+        NEW.deleted_at := now();
+    ELSIF NOT NEW.is_deleted THEN
+        NEW.deleted_at := NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE products ADD COLUMN deleted_at timestamp;
+ALTER TABLE customers ADD COLUMN deleted_at timestamp;
+ALTER TABLE orders ADD COLUMN deleted_at timestamp;
+ALTER TABLE category_products ADD COLUMN deleted_at timestamp;
+
+-- Linhas já excluídas usam a última alteração conhecida
+UPDATE products SET deleted_at = COALESCE(updated_at, created_at) WHERE is_deleted;
+UPDATE customers SET deleted_at = COALESCE(updated_at, created_at) WHERE is_deleted;
+UPDATE orders SET deleted_at = COALESCE(updated_at, created_at) WHERE is_deleted;
+UPDATE category_products SET deleted_at = now() WHERE is_deleted;
+
+CREATE TRIGGER products_set_deleted_at BEFORE UPDATE OF is_deleted ON products
+    FOR EACH ROW EXECUTE FUNCTION set_deleted_at();
+CREATE TRIGGER customers_set_deleted_at BEFORE UPDATE OF is_deleted ON customers
+    FOR EACH ROW EXECUTE FUNCTION set_deleted_at();
+CREATE TRIGGER orders_set_deleted_at BEFORE UPDATE OF is_deleted ON orders
+    FOR EACH ROW EXECUTE FUNCTION set_deleted_at();
+CREATE TRIGGER category_products_set_deleted_at BEFORE UPDATE OF is_deleted ON category_products
+    FOR EACH ROW EXECUTE FUNCTION set_deleted_at();
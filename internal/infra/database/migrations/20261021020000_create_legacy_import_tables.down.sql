@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS legacy_import_errors;
+DROP TABLE IF EXISTS legacy_import_checkpoints;
+
+DROP INDEX IF EXISTS idx_orders_old_id;
+DROP INDEX IF EXISTS idx_addresses_old_id;
+DROP INDEX IF EXISTS idx_customers_old_id;
+DROP INDEX IF EXISTS idx_products_old_id;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS old_id;
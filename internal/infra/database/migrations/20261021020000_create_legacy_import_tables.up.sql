@@ -0,0 +1,29 @@
+-- Pedidos passam a guardar a chave do legado (cd_pedido), como produtos, clientes e endereços,
+-- para que a importação possa ser repetida atualizando em vez de duplicar.
+ALTER TABLE orders ADD COLUMN old_id int;
+
+-- Não são únicos porque as cargas antigas podem ter duplicado linhas; a importação recusa
+-- old_ids repetidos e os lista no relatório de erros.
+CREATE INDEX idx_products_old_id ON products (old_id);
+CREATE INDEX idx_customers_old_id ON customers (old_id);
+CREATE INDEX idx_addresses_old_id ON addresses (old_id);
+CREATE INDEX idx_orders_old_id ON orders (old_id);
+
+-- Progresso de cada etapa da importação: last_old_id é a maior chave do legado já processada.
+CREATE TABLE legacy_import_checkpoints (
+    stage text PRIMARY KEY,
+    last_old_id integer NOT NULL DEFAULT 0,
+    imported integer NOT NULL DEFAULT 0,
+    skipped integer NOT NULL DEFAULT 0,
+    completed_at timestamp,
+    updated_at timestamp DEFAULT now() NOT NULL
+);
+
+-- Linhas do legado que não foram importadas e o motivo, acumuladas entre execuções retomadas.
+CREATE TABLE legacy_import_errors (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    stage text NOT NULL,
+    old_id text NOT NULL,
+    reason text NOT NULL,
+    created_at timestamp DEFAULT now() NOT NULL
+);
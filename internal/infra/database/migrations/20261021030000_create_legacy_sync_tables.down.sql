@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS legacy_sync_conflicts;
+DROP TABLE IF EXISTS legacy_sync_rows;
+
+DROP TRIGGER IF EXISTS orders_set_updated_at ON orders;
+DROP FUNCTION IF EXISTS set_updated_at();
@@ -0,0 +1,39 @@
+-- updated_at dos pedidos passa a ser mantido por trigger, para que qualquer edição feita pela
+-- API (itens, status, exclusão) seja visível à sincronização com o legado.
+CREATE OR REPLACE FUNCTION set_updated_at() RETURNS trigger AS $$
+BEGIN
+    NEW.updated_at := now();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER orders_set_updated_at BEFORE UPDATE ON orders
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- Última versão de cada linha do legado gravada pela sincronização. fingerprint identifica o
+-- conteúdo aplicado e synced_at quando foi gravado; um pedido com updated_at posterior foi
+-- editado pela API depois disso. last_error guarda a falha da última tentativa.
+CREATE TABLE legacy_sync_rows (
+    stage text NOT NULL,
+    old_id integer NOT NULL,
+    fingerprint text,
+    synced_at timestamp,
+    last_error text,
+    updated_at timestamp DEFAULT now() NOT NULL,
+    PRIMARY KEY (stage, old_id)
+);
+
+-- Pedidos alterados no legado e também editados pela API. A versão do legado não é aplicada
+-- até alguém escolher qual lado mantém.
+CREATE TABLE legacy_sync_conflicts (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    stage text NOT NULL,
+    old_id integer NOT NULL,
+    record_id uuid NOT NULL,
+    fingerprint text NOT NULL,
+    detected_at timestamp DEFAULT now() NOT NULL,
+    resolved_at timestamp,
+    resolution text
+);
+
+CREATE UNIQUE INDEX idx_legacy_sync_conflicts_open ON legacy_sync_conflicts (stage, old_id) WHERE resolved_at IS NULL;
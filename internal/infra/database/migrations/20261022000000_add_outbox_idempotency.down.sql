@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_webhook_deliveries_event_subscription;
+
+DROP INDEX IF EXISTS idx_notifications_job_id;
+ALTER TABLE notifications DROP COLUMN IF EXISTS job_id;
@@ -0,0 +1,7 @@
+-- Tentativas de notificação feitas por um job do outbox, para que uma nova execução do job
+-- não reenvie a mensagem por um canal que já enviou.
+ALTER TABLE notifications ADD COLUMN job_id uuid;
+CREATE INDEX idx_notifications_job_id ON notifications (job_id) WHERE job_id IS NOT NULL;
+
+-- O id do evento vem do job do outbox: repetir o job não duplica as entregas.
+CREATE UNIQUE INDEX idx_webhook_deliveries_event_subscription ON webhook_deliveries (event_id, subscription_id) WHERE replay_of IS NULL;
@@ -0,0 +1 @@
+DROP TRIGGER IF EXISTS customers_set_updated_at ON customers;
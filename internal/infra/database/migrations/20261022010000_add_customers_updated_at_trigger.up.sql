@@ -0,0 +1,4 @@
+-- Clientes editados pela API também entram em conflito na sincronização com o legado, então o
+-- updated_at deles passa a ser mantido por trigger como o dos pedidos.
+CREATE TRIGGER customers_set_updated_at BEFORE UPDATE ON customers
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
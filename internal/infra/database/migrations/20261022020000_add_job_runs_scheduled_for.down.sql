@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_job_runs_job_name_scheduled_for;
+
+ALTER TABLE job_runs DROP COLUMN IF EXISTS scheduled_for;
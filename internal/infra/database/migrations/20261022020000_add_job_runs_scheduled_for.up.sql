@@ -0,0 +1,4 @@
+ALTER TABLE job_runs ADD COLUMN scheduled_for timestamptz;
+
+-- Cada horário do cron é reivindicado por uma única instância; execuções manuais ficam com NULL
+CREATE UNIQUE INDEX idx_job_runs_job_name_scheduled_for ON job_runs (job_name, scheduled_for);
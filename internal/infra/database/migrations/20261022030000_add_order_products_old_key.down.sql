@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_order_products_order_id_old_key;
+
+ALTER TABLE order_products DROP COLUMN IF EXISTS old_key;
@@ -0,0 +1,4 @@
+ALTER TABLE order_products ADD COLUMN old_key text;
+
+-- Chave do item no legado, que não tem id próprio: produto, molho e ocorrência dentro do pedido
+CREATE UNIQUE INDEX idx_order_products_order_id_old_key ON order_products (order_id, old_key);
@@ -0,0 +1,41 @@
+// Package migrations embute os arquivos SQL no binário, para que a versão esperada do schema
+// seja conhecida em produção, onde a pasta de migrations não é copiada.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+//go:embed *.sql
+var FS embed.FS
+
+// LatestVersion devolve a versão da migration mais recente (o prefixo numérico do arquivo),
+// no mesmo formato registrado pelo golang-migrate na tabela schema_migrations.
+func LatestVersion() (uint64, error) {
+	files, err := fs.Glob(FS, "*.up.sql")
+	if err != nil {
+		return 0, fmt.Errorf("error listing migrations: %w", err)
+	}
+
+	var latest uint64
+	for _, file := range files {
+		prefix, _, ok := strings.Cut(file, "_")
+		if !ok {
+			return 0, fmt.Errorf("migration %s has no version prefix", file)
+		}
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("migration %s has an invalid version: %w", file, err)
+		}
+		latest = max(latest, version)
+	}
+
+	if latest == 0 {
+		return 0, fmt.Errorf("no migrations found")
+	}
+	return latest, nil
+}
@@ -0,0 +1,65 @@
+package migrations
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+func TestLatestVersion(t *testing.T) {
+	t.Run("should return the newest embedded migration version", func(t *testing.T) {
+		version, err := LatestVersion()
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if version < 20261020000000 {
+			t.Errorf("expected at least version 20261020000000, but got %d", version)
+		}
+	})
+}
+
+func TestFS(t *testing.T) {
+	t.Run("should be readable by golang-migrate up to the latest version", func(t *testing.T) {
+		source, err := iofs.New(FS, ".")
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		defer source.Close()
+
+		version, err := source.First()
+		if err != nil {
+			t.Fatalf("expected a first migration, but got %v", err)
+		}
+		for {
+			next, err := source.Next(version)
+			if errors.Is(err, fs.ErrNotExist) {
+				break
+			}
+			if err != nil {
+				t.Fatalf("expected no error after %d, but got %v", version, err)
+			}
+			version = next
+		}
+
+		latest, _ := LatestVersion()
+		if uint64(version) != latest {
+			t.Errorf("expected to walk up to %d, but stopped at %d", latest, version)
+		}
+	})
+
+	t.Run("should have a down file for every up file", func(t *testing.T) {
+		files, err := fs.Glob(FS, "*.up.sql")
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		for _, file := range files {
+			down := strings.TrimSuffix(file, ".up.sql") + ".down.sql"
+			if _, err := fs.Stat(FS, down); err != nil {
+				t.Errorf("expected %s to exist", down)
+			}
+		}
+	})
+}
@@ -0,0 +1,175 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/deividr/zion-api/internal/infra/database/migrations"
+	"github.com/deividr/zion-api/internal/infra/logger"
+	"github.com/golang-migrate/migrate/v4"
+	pgxmigrate "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// migrationLockKey serializa as migrations entre instâncias que sobem ao mesmo tempo.
+const migrationLockKey = "zion-migrate"
+
+// MigrationStatus é uma migration embutida no binário e se ela já foi aplicada no banco.
+type MigrationStatus struct {
+	Version uint64
+	Name    string
+	Applied bool
+}
+
+// Migrator aplica as migrations embutidas em migrations.FS com o golang-migrate, mantendo a
+// mesma tabela schema_migrations usada pelo CLI migrate.
+type Migrator struct {
+	migrate *migrate.Migrate
+}
+
+// NewMigrator abre uma conexão própria, fora do pool da API, para que as migrations não fiquem
+// sujeitas ao statement_timeout das requisições.
+func NewMigrator(databaseURL string) (*Migrator, error) {
+	connConfig, err := pgx.ParseConfig(databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse connection string: %w", err)
+	}
+	db := stdlib.OpenDB(*connConfig)
+
+	driver, err := pgxmigrate.WithInstance(db, &pgxmigrate.Config{})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("unable to connect to database for migrations: %w", err)
+	}
+
+	source, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("unable to read embedded migrations: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, "pgx5", driver)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("unable to setup migrations: %w", err)
+	}
+	m.Log = migrateLogger{logger: logger.New()}
+
+	return &Migrator{migrate: m}, nil
+}
+
+// Up aplica todas as migrations pendentes.
+func (m *Migrator) Up() error {
+	if err := m.migrate.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("error applying migrations: %w", err)
+	}
+	return nil
+}
+
+// Down desfaz as últimas steps migrations.
+func (m *Migrator) Down(steps int) error {
+	if steps < 1 {
+		return fmt.Errorf("steps must be at least 1, got %d", steps)
+	}
+	if err := m.migrate.Steps(-steps); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("error reverting migrations: %w", err)
+	}
+	return nil
+}
+
+// Version devolve a versão aplicada no banco, zero quando nenhuma migration foi aplicada.
+func (m *Migrator) Version() (uint64, bool, error) {
+	version, dirty, err := m.migrate.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("error reading schema version: %w", err)
+	}
+	return uint64(version), dirty, nil
+}
+
+// Status lista as migrations embutidas em ordem, indicando quais já estão no banco.
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	current, _, err := m.Version()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := fs.Glob(migrations.FS, "*.up.sql")
+	if err != nil {
+		return nil, fmt.Errorf("error listing migrations: %w", err)
+	}
+
+	statuses := make([]MigrationStatus, 0, len(files))
+	for _, file := range files {
+		prefix, name, _ := strings.Cut(strings.TrimSuffix(file, ".up.sql"), "_")
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s has an invalid version: %w", file, err)
+		}
+		statuses = append(statuses, MigrationStatus{Version: version, Name: name, Applied: version <= current})
+	}
+
+	sort.Slice(statuses, func(i, k int) bool { return statuses[i].Version < statuses[k].Version })
+	return statuses, nil
+}
+
+// Close fecha a conexão aberta por NewMigrator.
+func (m *Migrator) Close() error {
+	sourceErr, databaseErr := m.migrate.Close()
+	return errors.Join(sourceErr, databaseErr)
+}
+
+// MigrateWithLock aplica as migrations pendentes segurando um advisory lock, para que apenas
+// uma das instâncias que sobem juntas rode as migrations; as outras esperam o lock e
+// encontram o schema já atualizado.
+func MigrateWithLock(ctx context.Context, pool *pgxpool.Pool, databaseURL string) error {
+	for {
+		unlock, acquired, err := TryAdvisoryLock(ctx, pool, migrationLockKey)
+		if err != nil {
+			return err
+		}
+		if acquired {
+			defer unlock()
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for another instance to finish migrations: %w", ctx.Err())
+		case <-time.After(time.Second):
+		}
+	}
+
+	migrator, err := NewMigrator(databaseURL)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	return migrator.Up()
+}
+
+// migrateLogger envia o progresso do golang-migrate para o logger da API.
+type migrateLogger struct {
+	logger *logger.Logger
+}
+
+func (l migrateLogger) Printf(format string, v ...any) {
+	l.logger.Info(strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+// Verbose habilita o log de cada migration aplicada.
+func (l migrateLogger) Verbose() bool {
+	return true
+}
@@ -3,28 +3,61 @@ package database
 import (
 	"context"
 	"fmt"
-	"os"
+	"strconv"
+	"time"
 
+	"github.com/deividr/zion-api/internal/config"
+	"github.com/deividr/zion-api/internal/infra/metrics"
+	"github.com/exaring/otelpgx"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgconn/ctxwatch"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func GetConnection() *pgxpool.Pool {
-	config, err := pgxpool.ParseConfig(os.Getenv("DATABASE_URL"))
+const (
+	// cancelDeadlineDelay é quanto o pgx espera pelo cancelamento no servidor antes de
+	// desistir da conexão.
+	cancelDeadlineDelay    = 2 * time.Second
+	statementTimeoutMargin = time.Second
+	// wordSimilarityThreshold é o mínimo de word_similarity para o operador <% do pg_trgm usado
+	// na busca aproximada. O padrão da extensão, 0.6, recusa erros de digitação comuns.
+	wordSimilarityThreshold = "0.3"
+)
+
+func GetConnection(cfg config.Database) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(cfg.URL)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Unable to parse connection string: %v\n", err)
-		os.Exit(1)
+		return nil, fmt.Errorf("unable to parse connection string: %w", err)
 	}
 
 	// Configurações do pool de conexões
-	config.MaxConns = 10
-	config.MaxConnLifetime = 0
-	config.MaxConnIdleTime = 0
+	poolConfig.MaxConns = cfg.MaxConns
+	poolConfig.MaxConnLifetime = 0
+	poolConfig.MaxConnIdleTime = 0
+	// Cada consulta vira um span filho do span do contexto recebido pelo repositório
+	poolConfig.ConnConfig.Tracer = otelpgx.NewTracer(otelpgx.WithTrimSQLInSpanName())
+	// Por padrão o pgx apenas fecha a conexão quando o contexto é cancelado e a consulta segue
+	// rodando no servidor; com o cancel request o Postgres interrompe a consulta de fato.
+	poolConfig.ConnConfig.BuildContextWatcherHandler = func(conn *pgconn.PgConn) ctxwatch.Handler {
+		return &pgconn.CancelRequestContextWatcherHandler{Conn: conn, DeadlineDelay: cancelDeadlineDelay}
+	}
+	poolConfig.ConnConfig.RuntimeParams["pg_trgm.word_similarity_threshold"] = wordSimilarityThreshold
+	// Rede de segurança no servidor caso o cancel request se perca; um pouco acima do limite
+	// dos repositórios para que normalmente o cancelamento parta da aplicação.
+	if cfg.QueryTimeout > 0 {
+		statementTimeout := cfg.QueryTimeout + statementTimeoutMargin
+		poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(statementTimeout.Milliseconds(), 10)
+	}
 
-	dbpool, err := pgxpool.NewWithConfig(context.Background(), config)
+	dbpool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Unable to create connection pool: %v\n", err)
-		os.Exit(1)
+		return nil, fmt.Errorf("unable to create connection pool: %w", err)
+	}
+
+	if err := metrics.Register(metrics.NewPoolCollector(dbpool)); err != nil {
+		dbpool.Close()
+		return nil, fmt.Errorf("unable to register pool metrics: %w", err)
 	}
 
-	return dbpool
+	return dbpool, nil
 }
@@ -0,0 +1,30 @@
+package database
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type queryTimeoutKey struct{}
+
+// TrackQueryTimeouts devolve um contexto em que os repositórios registram as operações
+// interrompidas pelo próprio limite de tempo. Assim quem criou o contexto (o middleware da
+// requisição) distingue o banco lento de um erro qualquer, mesmo que o erro original tenha
+// se perdido nas camadas acima.
+func TrackQueryTimeouts(ctx context.Context) context.Context {
+	return context.WithValue(ctx, queryTimeoutKey{}, &atomic.Bool{})
+}
+
+// MarkQueryTimeout registra no contexto que uma operação estourou o limite de tempo. Sem
+// TrackQueryTimeouts (jobs, scripts) não faz nada.
+func MarkQueryTimeout(ctx context.Context) {
+	if timedOut, ok := ctx.Value(queryTimeoutKey{}).(*atomic.Bool); ok {
+		timedOut.Store(true)
+	}
+}
+
+// QueryTimedOut informa se alguma operação feita com o contexto estourou o limite de tempo.
+func QueryTimedOut(ctx context.Context) bool {
+	timedOut, ok := ctx.Value(queryTimeoutKey{}).(*atomic.Bool)
+	return ok && timedOut.Load()
+}
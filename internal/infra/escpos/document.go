@@ -0,0 +1,189 @@
+package escpos
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+const (
+	esc = 0x1B
+	gs  = 0x1D
+	lf  = 0x0A
+
+	// codePagePC850 é a tabela multilíngue que cobre os acentos do português.
+	codePagePC850 = 2
+)
+
+const (
+	AlignLeft   = 0
+	AlignCenter = 1
+	AlignRight  = 2
+)
+
+// Document monta a sequência de bytes ESC/POS de um documento. O texto é recebido em UTF-8 e
+// convertido para a code page 850; caracteres sem correspondência saem como "?".
+type Document struct {
+	buf     bytes.Buffer
+	columns int
+}
+
+// NewDocument inicializa a impressora e seleciona a code page. columns é a quantidade de
+// caracteres por linha na fonte padrão (48 no papel de 80mm).
+func NewDocument(columns int) *Document {
+	d := &Document{columns: columns}
+	d.buf.Write([]byte{esc, '@'})
+	d.buf.Write([]byte{esc, 't', codePagePC850})
+	return d
+}
+
+func (d *Document) Align(align byte) {
+	d.buf.Write([]byte{esc, 'a', align})
+}
+
+func (d *Document) Bold(on bool) {
+	d.buf.Write([]byte{esc, 'E', boolByte(on)})
+}
+
+// Size amplia os caracteres (1 a 8 vezes) na largura e na altura.
+func (d *Document) Size(width, height int) {
+	d.buf.Write([]byte{gs, '!', byte((clamp(width, 1, 8)-1)<<4 | (clamp(height, 1, 8) - 1))})
+}
+
+func (d *Document) Text(text string) {
+	for _, r := range text {
+		b, ok := charmap.CodePage850.EncodeRune(r)
+		if !ok {
+			b = '?'
+		}
+		d.buf.WriteByte(b)
+	}
+}
+
+func (d *Document) Line(text string) {
+	d.Text(text)
+	d.buf.WriteByte(lf)
+}
+
+// Wrap quebra o texto em linhas da largura do papel, sem cortar palavras quando possível.
+func (d *Document) Wrap(text string, indent string) {
+	for _, line := range wrap(text, d.columns-utf8.RuneCountInString(indent)) {
+		d.Line(indent + line)
+	}
+}
+
+// Row alinha o texto da esquerda e o da direita na mesma linha, truncando a esquerda se faltar espaço.
+func (d *Document) Row(left string, right string) {
+	d.Line(row(left, right, d.columns))
+}
+
+func (d *Document) Separator() {
+	d.Line(strings.Repeat("-", d.columns))
+}
+
+func (d *Document) Feed(lines int) {
+	d.buf.Write([]byte{esc, 'd', byte(clamp(lines, 0, 255))})
+}
+
+// QRCode imprime o QR code (modelo 2, correção M) com módulos de size pontos.
+func (d *Document) QRCode(data string, size int) {
+	length := len(data) + 3
+	d.buf.Write([]byte{gs, '(', 'k', 4, 0, '1', 'A', '2', 0})
+	d.buf.Write([]byte{gs, '(', 'k', 3, 0, '1', 'C', byte(clamp(size, 1, 16))})
+	d.buf.Write([]byte{gs, '(', 'k', 3, 0, '1', 'E', '1'})
+	d.buf.Write([]byte{gs, '(', 'k', byte(length % 256), byte(length / 256), '1', 'P', '0'})
+	d.buf.WriteString(data)
+	d.buf.Write([]byte{gs, '(', 'k', 3, 0, '1', 'Q', '0'})
+}
+
+// Code128 imprime o código de barras (conjunto B) com o texto legível abaixo.
+func (d *Document) Code128(data string, height int) {
+	d.buf.Write([]byte{gs, 'h', byte(clamp(height, 1, 255))})
+	d.buf.Write([]byte{gs, 'w', 2})
+	d.buf.Write([]byte{gs, 'H', 2})
+	d.buf.Write([]byte{gs, 'k', 73, byte(len(data) + 2), '{', 'B'})
+	d.buf.WriteString(data)
+}
+
+// Cut avança o papel até a guilhotina e faz o corte parcial.
+func (d *Document) Cut() {
+	d.buf.Write([]byte{gs, 'V', 66, 3})
+}
+
+func (d *Document) Bytes() []byte {
+	return d.buf.Bytes()
+}
+
+func row(left string, right string, columns int) string {
+	space := columns - utf8.RuneCountInString(right) - 1
+	if space < 0 {
+		space = 0
+	}
+	left = truncate(left, space)
+	padding := columns - utf8.RuneCountInString(left) - utf8.RuneCountInString(right)
+	if padding < 1 {
+		padding = 1
+	}
+	return left + strings.Repeat(" ", padding) + right
+}
+
+func wrap(text string, width int) []string {
+	if width < 1 {
+		width = 1
+	}
+
+	lines := []string{}
+	current := ""
+	for _, word := range strings.Fields(text) {
+		for utf8.RuneCountInString(word) > width {
+			if current != "" {
+				lines = append(lines, current)
+				current = ""
+			}
+			runes := []rune(word)
+			lines = append(lines, string(runes[:width]))
+			word = string(runes[width:])
+		}
+
+		switch {
+		case current == "":
+			current = word
+		case utf8.RuneCountInString(current)+1+utf8.RuneCountInString(word) <= width:
+			current += " " + word
+		default:
+			lines = append(lines, current)
+			current = word
+		}
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width])
+}
+
+func boolByte(on bool) byte {
+	if on {
+		return 1
+	}
+	return 0
+}
+
+func clamp(value, min, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
@@ -0,0 +1,93 @@
+package escpos
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDocument(t *testing.T) {
+	t.Run("should initialize the printer and select code page 850", func(t *testing.T) {
+		d := NewDocument(Columns80mm)
+
+		expected := []byte{0x1B, '@', 0x1B, 't', 2}
+		if !bytes.Equal(d.Bytes(), expected) {
+			t.Errorf("expected %x, but got %x", expected, d.Bytes())
+		}
+	})
+
+	t.Run("should encode accented text in code page 850", func(t *testing.T) {
+		d := NewDocument(Columns80mm)
+		d.Line("Nº ção €")
+
+		// º = 0xA7, ç = 0x87, ã = 0xC6; o euro não existe na CP850 e sai como "?"
+		expected := []byte{'N', 0xA7, ' ', 0x87, 0xC6, 'o', ' ', '?', '\n'}
+		if got := d.Bytes()[5:]; !bytes.Equal(got, expected) {
+			t.Errorf("expected %x, but got %x", expected, got)
+		}
+	})
+
+	t.Run("should emit style, feed and cut commands", func(t *testing.T) {
+		d := NewDocument(Columns80mm)
+		d.Align(AlignCenter)
+		d.Bold(true)
+		d.Size(2, 2)
+		d.Feed(3)
+		d.Cut()
+
+		expected := []byte{
+			0x1B, 'a', 1,
+			0x1B, 'E', 1,
+			0x1D, '!', 0x11,
+			0x1B, 'd', 3,
+			0x1D, 'V', 66, 3,
+		}
+		if got := d.Bytes()[5:]; !bytes.Equal(got, expected) {
+			t.Errorf("expected %x, but got %x", expected, got)
+		}
+	})
+
+	t.Run("should store and print a QR code", func(t *testing.T) {
+		d := NewDocument(Columns80mm)
+		d.QRCode("AB3K7X9Q", 6)
+
+		expected := []byte{
+			0x1D, '(', 'k', 4, 0, '1', 'A', '2', 0,
+			0x1D, '(', 'k', 3, 0, '1', 'C', 6,
+			0x1D, '(', 'k', 3, 0, '1', 'E', '1',
+			0x1D, '(', 'k', 11, 0, '1', 'P', '0', 'A', 'B', '3', 'K', '7', 'X', '9', 'Q',
+			0x1D, '(', 'k', 3, 0, '1', 'Q', '0',
+		}
+		if got := d.Bytes()[5:]; !bytes.Equal(got, expected) {
+			t.Errorf("expected %x, but got %x", expected, got)
+		}
+	})
+
+	t.Run("should print Code128 using code set B", func(t *testing.T) {
+		d := NewDocument(Columns80mm)
+		d.Code128("AB12", 80)
+
+		expected := []byte{
+			0x1D, 'h', 80,
+			0x1D, 'w', 2,
+			0x1D, 'H', 2,
+			0x1D, 'k', 73, 6, '{', 'B', 'A', 'B', '1', '2',
+		}
+		if got := d.Bytes()[5:]; !bytes.Equal(got, expected) {
+			t.Errorf("expected %x, but got %x", expected, got)
+		}
+	})
+
+	t.Run("should align rows and wrap long text to the paper width", func(t *testing.T) {
+		d := NewDocument(20)
+		d.Row("Lasanha à bolonhesa", "R$ 10,00")
+		d.Wrap("Sem cebola e com bastante queijo", "  ")
+
+		var expected bytes.Buffer
+		expected.WriteString("Lasanha " + string([]byte{0x85}) + " b R$ 10,00\n")
+		expected.WriteString("  Sem cebola e com\n")
+		expected.WriteString("  bastante queijo\n")
+		if got := d.Bytes()[5:]; !bytes.Equal(got, expected.Bytes()) {
+			t.Errorf("expected %q, but got %q", expected.Bytes(), got)
+		}
+	})
+}
@@ -0,0 +1,235 @@
+package escpos
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+// Columns80mm é a largura em caracteres da fonte padrão no papel de 80mm.
+const Columns80mm = 48
+
+type Renderer struct {
+	columns   int
+	storeName string
+}
+
+func NewRenderer(columns int, storeName string) *Renderer {
+	if columns <= 0 {
+		columns = Columns80mm
+	}
+	return &Renderer{columns: columns, storeName: storeName}
+}
+
+// RenderOrderReceipt gera o comprovante do balcão: itens com valores, total e o código de retirada.
+func (r *Renderer) RenderOrderReceipt(order domain.Order) ([]byte, error) {
+	d := NewDocument(r.columns)
+
+	d.Align(AlignCenter)
+	if r.storeName != "" {
+		d.Bold(true)
+		d.Line(r.storeName)
+		d.Bold(false)
+	}
+	d.Size(2, 2)
+	d.Line("PEDIDO Nº " + order.Number)
+	d.Size(1, 1)
+	d.Line("Retirada: " + formatDateTime(order.PickupDate))
+
+	d.Align(AlignLeft)
+	d.Separator()
+	d.Line("Cliente: " + order.Customer.Name)
+	if order.Customer.Phone != "" {
+		d.Line("Telefone: " + order.Customer.Phone)
+	}
+	if address := formatAddress(order.Address); address != "" {
+		d.Wrap("Endereço: "+address, "")
+	}
+	d.Separator()
+
+	for _, product := range order.Products {
+		d.Row(formatQuantity(product.Quantity, product.UnityType)+" "+product.Name, formatMoney(product.Total()))
+		for _, subProduct := range product.SubProducts {
+			d.Wrap("+ "+subProduct.Name, "   ")
+		}
+		if product.Observations != nil && *product.Observations != "" {
+			d.Wrap("Obs: "+*product.Observations, "   ")
+		}
+	}
+
+	d.Separator()
+	d.Bold(true)
+	d.Row("TOTAL", formatMoney(order.Total()))
+	d.Bold(false)
+
+	if order.Observations != nil && *order.Observations != "" {
+		d.Separator()
+		d.Wrap("Obs: "+*order.Observations, "")
+	}
+
+	if order.PickupCode != "" {
+		d.Feed(1)
+		d.Align(AlignCenter)
+		d.QRCode(order.PickupCode, 6)
+		d.Line("")
+		d.Bold(true)
+		d.Line("Código de retirada: " + order.PickupCode)
+		d.Bold(false)
+		d.Align(AlignLeft)
+	}
+
+	d.Feed(2)
+	d.Cut()
+	return d.Bytes(), nil
+}
+
+// RenderKitchenTicket gera o ticket de produção do pedido, sem valores e com os alertas do cliente em destaque.
+func (r *Renderer) RenderKitchenTicket(order domain.Order) ([]byte, error) {
+	d := NewDocument(r.columns)
+
+	d.Align(AlignCenter)
+	d.Size(2, 2)
+	d.Line("Nº " + order.Number)
+	d.Size(1, 1)
+	d.Line("Retirada: " + formatDateTime(order.PickupDate))
+	d.Line(order.Customer.Name)
+
+	d.Align(AlignLeft)
+	if len(order.CustomerAlerts) > 0 {
+		d.Separator()
+		d.Bold(true)
+		for _, alert := range order.CustomerAlerts {
+			d.Wrap("! "+alert.Message, "")
+		}
+		d.Bold(false)
+	}
+	d.Separator()
+
+	for _, product := range order.Products {
+		d.Size(1, 2)
+		d.Wrap(formatQuantity(product.Quantity, product.UnityType)+" "+product.Name, "")
+		d.Size(1, 1)
+		for _, subProduct := range product.SubProducts {
+			d.Wrap("+ "+subProduct.Name, "   ")
+		}
+		if product.Observations != nil && *product.Observations != "" {
+			d.Bold(true)
+			d.Wrap("Obs: "+*product.Observations, "   ")
+			d.Bold(false)
+		}
+	}
+
+	if order.Observations != nil && *order.Observations != "" {
+		d.Separator()
+		d.Bold(true)
+		d.Wrap("Obs: "+*order.Observations, "")
+		d.Bold(false)
+	}
+
+	if order.PickupCode != "" {
+		d.Separator()
+		d.Align(AlignCenter)
+		d.Line("Código: " + order.PickupCode)
+		d.Align(AlignLeft)
+	}
+
+	d.Feed(2)
+	d.Cut()
+	return d.Bytes(), nil
+}
+
+// RenderProductionList gera a lista de produção do dia com os lotes e os pedidos de cada lote.
+func (r *Renderer) RenderProductionList(date time.Time, batches []domain.KitchenBatch) ([]byte, error) {
+	d := NewDocument(r.columns)
+
+	d.Align(AlignCenter)
+	d.Size(2, 2)
+	d.Line("PRODUÇÃO")
+	d.Size(1, 1)
+	d.Line(date.In(domain.StoreLocation).Format("02/01/2006"))
+	d.Align(AlignLeft)
+	d.Separator()
+
+	if len(batches) == 0 {
+		d.Line("Nenhum item pendente.")
+	}
+
+	for i, batch := range batches {
+		if i > 0 {
+			d.Line("")
+		}
+		d.Bold(true)
+		d.Row(batch.Name, formatQuantity(batch.TotalQuantity, batch.UnityType))
+		d.Bold(false)
+		if len(batch.SubProducts) > 0 {
+			d.Wrap("+ "+strings.Join(batch.SubProducts, ", "), "   ")
+		}
+		if batch.Observations != nil && *batch.Observations != "" {
+			d.Wrap("Obs: "+*batch.Observations, "   ")
+		}
+		for _, ticket := range batch.Tickets {
+			d.Row(
+				fmt.Sprintf("   #%s %s %s", ticket.OrderNumber, ticket.PickupDate.In(domain.StoreLocation).Format("15:04"), ticket.CustomerName),
+				formatQuantity(ticket.Quantity, batch.UnityType),
+			)
+		}
+	}
+
+	d.Feed(2)
+	d.Cut()
+	return d.Bytes(), nil
+}
+
+func formatDateTime(t time.Time) string {
+	return t.In(domain.StoreLocation).Format("02/01/2006 15:04")
+}
+
+// formatQuantity exibe produtos por peso em gramas ou quilos ("500 g", "1,5 kg") e os demais na unidade.
+func formatQuantity(quantity int, unityType string) string {
+	unityType = strings.TrimSpace(unityType)
+	if unityType != domain.UnityTypeKilogram {
+		return fmt.Sprintf("%d %s", quantity, unityType)
+	}
+	if quantity < 1000 {
+		return fmt.Sprintf("%d g", quantity)
+	}
+	kilos := strconv.FormatFloat(float64(quantity)/1000, 'f', -1, 64)
+	return strings.Replace(kilos, ".", ",", 1) + " kg"
+}
+
+// formatMoney formata centavos como "R$ 1.234,56".
+func formatMoney(cents int) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	reais := strconv.Itoa(cents / 100)
+	var grouped strings.Builder
+	for i, digit := range reais {
+		if i > 0 && (len(reais)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	return fmt.Sprintf("%sR$ %s,%02d", sign, grouped.String(), cents%100)
+}
+
+func formatAddress(address *domain.Address) string {
+	if address == nil {
+		return ""
+	}
+
+	parts := []string{}
+	for _, part := range []*string{address.Street, address.Number, address.Neighborhood, address.City} {
+		if part != nil && strings.TrimSpace(*part) != "" {
+			parts = append(parts, strings.TrimSpace(*part))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,122 @@
+package escpos
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+func TestRenderer(t *testing.T) {
+	observations := "Sem cebola"
+	pickupDate := time.Date(2026, 10, 20, 18, 30, 0, 0, time.UTC)
+	order := domain.Order{
+		Number:     "42",
+		PickupCode: "AB3K7X9Q",
+		PickupDate: pickupDate,
+		Customer:   domain.Customer{Name: "Maria", Phone: "11999990000"},
+		Products: []domain.OrderProduct{
+			{Name: "Lasanha", Quantity: 2, UnityType: "UN", Price: 4500},
+			{Name: "Nhoque", Quantity: 500, UnityType: "KG", Price: 6000, Observations: &observations,
+				SubProducts: []domain.OrderSubProduct{{Name: "Molho sugo"}}},
+		},
+		CustomerAlerts: []domain.CustomerAlert{{Type: "dietary", Message: "Alergia a castanhas"}},
+	}
+	renderer := NewRenderer(Columns80mm, "Zion Massas")
+
+	t.Run("should render the counter receipt with totals and the pickup code", func(t *testing.T) {
+		data, err := renderer.RenderOrderReceipt(order)
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+
+		assertSequence(t, data,
+			[]byte{0x1B, '@', 0x1B, 't', 2},
+			[]byte("Zion Massas\n"),
+			[]byte{0x1D, '!', 0x11},
+			[]byte("PEDIDO N\xa7 42\n"),
+			[]byte("Retirada: 20/10/2026 15:30\n"),
+			[]byte(row("2 UN Lasanha", "R$ 90,00", Columns80mm)+"\n"),
+			[]byte(row("500 g Nhoque", "R$ 30,00", Columns80mm)+"\n"),
+			[]byte("   + Molho sugo\n"),
+			[]byte("   Obs: Sem cebola\n"),
+			[]byte(row("TOTAL", "R$ 120,00", Columns80mm)+"\n"),
+			[]byte{0x1D, '(', 'k', 11, 0, '1', 'P', '0', 'A', 'B', '3', 'K', '7', 'X', '9', 'Q'},
+			[]byte("C\xa2digo de retirada: AB3K7X9Q\n"),
+		)
+		if !bytes.HasSuffix(data, []byte{0x1D, 'V', 66, 3}) {
+			t.Errorf("expected receipt to end with a cut, but got %x", data[len(data)-4:])
+		}
+	})
+
+	t.Run("should render the kitchen ticket without prices and with customer alerts", func(t *testing.T) {
+		data, err := renderer.RenderKitchenTicket(order)
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+
+		assertSequence(t, data,
+			[]byte("N\xa7 42\n"),
+			[]byte("! Alergia a castanhas\n"),
+			[]byte{0x1D, '!', 0x01},
+			[]byte("2 UN Lasanha\n"),
+			[]byte("500 g Nhoque\n"),
+			[]byte{0x1B, 'E', 1},
+			[]byte("   Obs: Sem cebola\n"),
+			[]byte("C\xa2digo: AB3K7X9Q\n"),
+		)
+		if bytes.Contains(data, []byte("R$")) {
+			t.Errorf("expected no prices on the kitchen ticket")
+		}
+	})
+
+	t.Run("should render the production list grouped by batch", func(t *testing.T) {
+		batches := []domain.KitchenBatch{{
+			Name:          "Nhoque",
+			UnityType:     "KG",
+			TotalQuantity: 1500,
+			SubProducts:   []string{"Molho sugo"},
+			Tickets: []domain.KitchenBatchTicket{
+				{OrderNumber: "42", CustomerName: "Maria", PickupDate: pickupDate, Quantity: 500},
+				{OrderNumber: "43", CustomerName: "Ana", PickupDate: pickupDate, Quantity: 1000},
+			},
+		}}
+
+		data, err := renderer.RenderProductionList(pickupDate, batches)
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+
+		assertSequence(t, data,
+			[]byte("PRODU\x80\xc7O\n"),
+			[]byte("20/10/2026\n"),
+			[]byte(row("Nhoque", "1,5 kg", Columns80mm)+"\n"),
+			[]byte("   + Molho sugo\n"),
+			[]byte(row("   #42 15:30 Maria", "500 g", Columns80mm)+"\n"),
+			[]byte(row("   #43 15:30 Ana", "1 kg", Columns80mm)+"\n"),
+		)
+	})
+}
+
+func TestFormatMoney(t *testing.T) {
+	cases := map[int]string{0: "R$ 0,00", 5: "R$ 0,05", 123456: "R$ 1.234,56", 100000000: "R$ 1.000.000,00"}
+	for cents, expected := range cases {
+		if got := formatMoney(cents); got != expected {
+			t.Errorf("expected %s for %d, but got %s", expected, cents, got)
+		}
+	}
+}
+
+// assertSequence verifica que os trechos aparecem no documento nessa ordem.
+func assertSequence(t *testing.T, data []byte, parts ...[]byte) {
+	t.Helper()
+	offset := 0
+	for _, part := range parts {
+		i := bytes.Index(data[offset:], part)
+		if i < 0 {
+			t.Fatalf("expected %q after offset %d, but it was not found in %q", part, offset, data)
+		}
+		offset += i + len(part)
+	}
+}
@@ -0,0 +1,151 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/infra/logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	subscriberBuffer  = 64
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 30 * time.Second
+)
+
+type subscriber struct {
+	events chan domain.OrderEvent
+	filter domain.OrderEventFilter
+}
+
+// PgOrderEventHub escuta o canal order_events do Postgres em uma conexão dedicada e
+// distribui os eventos para os assinantes conectados nesta instância da API.
+type PgOrderEventHub struct {
+	config *pgx.ConnConfig
+	logger *logger.Logger
+
+	mu          sync.Mutex
+	subscribers map[int]*subscriber
+	nextId      int
+}
+
+func NewPgOrderEventHub(pool *pgxpool.Pool) *PgOrderEventHub {
+	return &PgOrderEventHub{
+		config:      pool.Config().ConnConfig.Copy(),
+		logger:      logger.New(),
+		subscribers: map[int]*subscriber{},
+	}
+}
+
+// Run mantém o LISTEN ativo até ctx ser cancelado, reconectando com backoff quando a conexão cai.
+// Após uma reconexão os assinantes recebem um evento resync, já que notificações podem ter sido perdidas.
+func (h *PgOrderEventHub) Run(ctx context.Context) {
+	delay := minReconnectDelay
+	connected := false
+
+	for {
+		err := h.listen(ctx, func() {
+			if connected {
+				h.broadcast(domain.OrderEvent{Type: domain.OrderEventResync})
+			}
+			connected = true
+			delay = minReconnectDelay
+		})
+		if ctx.Err() != nil {
+			h.closeAll()
+			return
+		}
+
+		h.logger.Error(fmt.Sprintf("Order events listener disconnected, retrying in %s", delay), err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			h.closeAll()
+			return
+		}
+		delay = min(delay*2, maxReconnectDelay)
+	}
+}
+
+func (h *PgOrderEventHub) Subscribe(filter domain.OrderEventFilter) (<-chan domain.OrderEvent, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	id := h.nextId
+	h.nextId++
+	sub := &subscriber{events: make(chan domain.OrderEvent, subscriberBuffer), filter: filter}
+	h.subscribers[id] = sub
+
+	unsubscribe := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subscribers[id]; ok {
+			delete(h.subscribers, id)
+			close(sub.events)
+		}
+	}
+
+	return sub.events, unsubscribe
+}
+
+func (h *PgOrderEventHub) listen(ctx context.Context, onListening func()) error {
+	conn, err := pgx.ConnectConfig(ctx, h.config)
+	if err != nil {
+		return fmt.Errorf("error connecting order events listener: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+domain.OrderEventsChannel); err != nil {
+		return fmt.Errorf("error listening to %s: %w", domain.OrderEventsChannel, err)
+	}
+	onListening()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("error waiting for order events: %w", err)
+		}
+
+		var event domain.OrderEvent
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			h.logger.Error("Error decoding order event", err)
+			continue
+		}
+		h.broadcast(event)
+	}
+}
+
+// broadcast entrega o evento sem bloquear. Assinantes com o buffer cheio são desconectados
+// para que o cliente reconecte e recarregue o quadro em vez de ficar com dados defasados.
+func (h *PgOrderEventHub) broadcast(event domain.OrderEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for id, sub := range h.subscribers {
+		if !sub.filter.Matches(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			delete(h.subscribers, id)
+			close(sub.events)
+		}
+	}
+}
+
+func (h *PgOrderEventHub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for id, sub := range h.subscribers {
+		delete(h.subscribers, id)
+		close(sub.events)
+	}
+}
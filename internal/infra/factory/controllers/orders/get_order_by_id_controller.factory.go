@@ -8,6 +8,6 @@ import (
 )
 
 func GetOrderByIdControllerFactory(db *pgxpool.Pool) *ordersController.GetOrderByIdController {
-	useCase := orders.NewGetOrderByIdUseCase(postgres.NewPgOrderRepository(db))
+	useCase := orders.NewGetOrderByIdUseCase(postgres.NewPgOrderRepository(db), postgres.NewPgCustomerNoteRepository(db))
 	return ordersController.NewGetOrderByIdController(useCase)
 }
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/deividr/zion-api/internal/config"
+	"github.com/deividr/zion-api/internal/domain/services"
+	"github.com/deividr/zion-api/internal/infra/database"
+	"github.com/deividr/zion-api/internal/infra/database/migrations"
+	"github.com/deividr/zion-api/internal/infra/storage"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// NewReadinessChecks monta as verificações do /readyz: Postgres, versão das migrations e storage.
+func NewReadinessChecks(pool *pgxpool.Pool, cfg config.Storage) ([]services.HealthCheck, error) {
+	expected, err := migrations.LatestVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded migrations: %w", err)
+	}
+
+	tigris, err := storage.NewTigris(cfg.Bucket, cfg.Endpoint, cfg.PublicURLBase, cfg.Region, cfg.AccessKeyId, cfg.SecretAccessKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return []services.HealthCheck{
+		database.NewPingCheck(pool),
+		database.NewMigrationCheck(pool, expected),
+		storage.NewHealthCheck(tigris),
+	}, nil
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"github.com/deividr/zion-api/internal/config"
+	"github.com/deividr/zion-api/internal/domain/services"
+	"github.com/deividr/zion-api/internal/infra/notification"
+)
+
+// NewNotificationSenders monta os canais de notificação na ordem de prioridade definida em
+// NOTIFICATION_CHANNELS (ex: "whatsapp,sms,email"). Sem configuração, as mensagens são apenas logadas.
+func NewNotificationSenders(cfg config.Notification) []services.NotificationSender {
+	var senders []services.NotificationSender
+	for _, channel := range cfg.Channels {
+		switch channel {
+		case services.NotificationChannelWhatsApp:
+			senders = append(senders, notification.NewWhatsApp(
+				cfg.WhatsApp.APIURL,
+				cfg.WhatsApp.PhoneNumberId,
+				cfg.WhatsApp.AccessToken,
+			))
+		case services.NotificationChannelSMS:
+			senders = append(senders, notification.NewSMS(
+				cfg.SMS.APIURL,
+				cfg.SMS.APIKey,
+				cfg.SMS.Sender,
+			))
+		case services.NotificationChannelEmail:
+			senders = append(senders, notification.NewEmail(
+				cfg.SMTP.Host,
+				cfg.SMTP.Port,
+				cfg.SMTP.Username,
+				cfg.SMTP.Password,
+				cfg.SMTP.From,
+			))
+		case services.NotificationChannelLog:
+			senders = append(senders, notification.NewLog())
+		}
+	}
+
+	if len(senders) == 0 {
+		senders = append(senders, notification.NewLog())
+	}
+
+	return senders
+}
@@ -8,5 +8,6 @@ import (
 
 func GetOrderByIdUsecaseFactory(db *pgxpool.Pool) *orders.GetOrderByIdUseCase {
 	orderRepository := postgres.NewPgOrderRepository(db)
-	return orders.NewGetOrderByIdUseCase(orderRepository)
+	customerNoteRepository := postgres.NewPgCustomerNoteRepository(db)
+	return orders.NewGetOrderByIdUseCase(orderRepository, customerNoteRepository)
 }
@@ -0,0 +1,315 @@
+// Package legacy lê o banco MySQL do sistema antigo para a importação e a reconciliação.
+package legacy
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/deividr/zion-api/internal/config"
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/go-sql-driver/mysql"
+)
+
+// legacyLocation é o fuso em que o sistema antigo gravava as datas, sem informação de fuso.
+const legacyLocation = "America/Sao_Paulo"
+
+type MySQLSource struct {
+	db *sql.DB
+}
+
+func NewMySQLSource(cfg config.Legacy) (*MySQLSource, error) {
+	mysqlConfig := mysql.NewConfig()
+	mysqlConfig.Net = "tcp"
+	mysqlConfig.Addr = cfg.Host + ":" + cfg.Port
+	mysqlConfig.User = cfg.User
+	mysqlConfig.Passwd = cfg.Password
+	mysqlConfig.DBName = cfg.Database
+	return openMySQLSource(mysqlConfig)
+}
+
+// openMySQLSource conecta com as datas lidas no fuso do legado, também para os testes que
+// recebem a conexão pronta em um DSN.
+func openMySQLSource(mysqlConfig *mysql.Config) (*MySQLSource, error) {
+	location, err := time.LoadLocation(legacyLocation)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load legacy timezone: %w", err)
+	}
+
+	mysqlConfig.ParseTime = true
+	mysqlConfig.Loc = location
+
+	db, err := sql.Open("mysql", mysqlConfig.FormatDSN())
+	if err != nil {
+		return nil, fmt.Errorf("unable to open legacy database: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("unable to connect to legacy database: %w", err)
+	}
+	return &MySQLSource{db: db}, nil
+}
+
+func (s *MySQLSource) Close() error {
+	return s.db.Close()
+}
+
+func (s *MySQLSource) Products(ctx context.Context, afterId int, limit int) ([]domain.LegacyProductRecord, error) {
+	return s.queryProducts(ctx, "cd_produto > ? ORDER BY cd_produto LIMIT ?", afterId, limit)
+}
+
+func (s *MySQLSource) ProductsByIds(ctx context.Context, ids []int) ([]domain.LegacyProductRecord, error) {
+	if len(ids) == 0 {
+		return []domain.LegacyProductRecord{}, nil
+	}
+	return s.queryProducts(ctx, "cd_produto IN ("+placeholders(len(ids))+") ORDER BY cd_produto", intArgs(ids)...)
+}
+
+func (s *MySQLSource) queryProducts(ctx context.Context, condition string, args ...any) ([]domain.LegacyProductRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT cd_produto, COALESCE(nm_produto, ''), COALESCE(st_unidade, ''), COALESCE(vl_produto, 0)
+		FROM produto
+		WHERE `+condition, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying legacy products: %w", err)
+	}
+	defer rows.Close()
+
+	products := []domain.LegacyProductRecord{}
+	for rows.Next() {
+		var product domain.LegacyProductRecord
+		if err := rows.Scan(&product.OldId, &product.Name, &product.UnityType, &product.Value); err != nil {
+			return nil, fmt.Errorf("error scanning legacy product: %w", err)
+		}
+		products = append(products, product)
+	}
+	return products, rows.Err()
+}
+
+func (s *MySQLSource) Customers(ctx context.Context, afterId int, limit int) ([]domain.LegacyCustomerRecord, error) {
+	return s.queryCustomers(ctx, "cd_cliente > ? ORDER BY cd_cliente LIMIT ?", afterId, limit)
+}
+
+func (s *MySQLSource) CustomersByIds(ctx context.Context, ids []int) ([]domain.LegacyCustomerRecord, error) {
+	if len(ids) == 0 {
+		return []domain.LegacyCustomerRecord{}, nil
+	}
+	return s.queryCustomers(ctx, "cd_cliente IN ("+placeholders(len(ids))+") ORDER BY cd_cliente", intArgs(ids)...)
+}
+
+func (s *MySQLSource) queryCustomers(ctx context.Context, condition string, args ...any) ([]domain.LegacyCustomerRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT cd_cliente, COALESCE(nm_cliente, ''), COALESCE(nr_telefone1, ''), nr_telefone2, ds_email, dt_criacao
+		FROM cliente
+		WHERE `+condition, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying legacy customers: %w", err)
+	}
+	defer rows.Close()
+
+	customers := []domain.LegacyCustomerRecord{}
+	for rows.Next() {
+		var customer domain.LegacyCustomerRecord
+		var phone2, email sql.NullString
+		var createdAt sql.NullTime
+		if err := rows.Scan(&customer.OldId, &customer.Name, &customer.Phone, &phone2, &email, &createdAt); err != nil {
+			return nil, fmt.Errorf("error scanning legacy customer: %w", err)
+		}
+		customer.Phone2 = nullString(phone2)
+		customer.Email = nullString(email)
+		customer.CreatedAt = nullTime(createdAt)
+		customers = append(customers, customer)
+	}
+	return customers, rows.Err()
+}
+
+func (s *MySQLSource) Addresses(ctx context.Context, afterId int, limit int) ([]domain.LegacyAddressRecord, error) {
+	return s.queryAddresses(ctx, "cd_endereco > ? ORDER BY cd_endereco LIMIT ?", afterId, limit)
+}
+
+func (s *MySQLSource) AddressesByIds(ctx context.Context, ids []int) ([]domain.LegacyAddressRecord, error) {
+	if len(ids) == 0 {
+		return []domain.LegacyAddressRecord{}, nil
+	}
+	return s.queryAddresses(ctx, "cd_endereco IN ("+placeholders(len(ids))+") ORDER BY cd_endereco", intArgs(ids)...)
+}
+
+func (s *MySQLSource) queryAddresses(ctx context.Context, condition string, args ...any) ([]domain.LegacyAddressRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT cd_endereco, cd_cliente, COALESCE(cd_cep, ''), COALESCE(ds_logradouro, ''), COALESCE(nr_logradouro, ''),
+		       COALESCE(ds_bairro, ''), COALESCE(ds_cidade, ''), COALESCE(ds_uf, ''), COALESCE(ds_complemento, ''), ds_distancia
+		FROM endereco
+		WHERE `+condition, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying legacy addresses: %w", err)
+	}
+	defer rows.Close()
+
+	addresses := []domain.LegacyAddressRecord{}
+	for rows.Next() {
+		var address domain.LegacyAddressRecord
+		var customerId sql.NullInt64
+		var distance sql.NullString
+		if err := rows.Scan(
+			&address.OldId,
+			&customerId,
+			&address.Cep,
+			&address.Street,
+			&address.Number,
+			&address.Neighborhood,
+			&address.City,
+			&address.State,
+			&address.AditionalDetails,
+			&distance,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning legacy address: %w", err)
+		}
+		if customerId.Valid {
+			id := int(customerId.Int64)
+			address.OldCustomerId = &id
+		}
+		address.Distance = nullString(distance)
+		addresses = append(addresses, address)
+	}
+	return addresses, rows.Err()
+}
+
+func (s *MySQLSource) Orders(ctx context.Context, afterId int, limit int) ([]domain.LegacyOrderRecord, error) {
+	return s.queryOrders(ctx, "cd_pedido > ? ORDER BY cd_pedido LIMIT ?", afterId, limit)
+}
+
+// RecentOrders compara só a data de retirada, que o legado grava sem horário.
+func (s *MySQLSource) RecentOrders(ctx context.Context, pickupSince time.Time, afterId int, limit int) ([]domain.LegacyOrderRecord, error) {
+	return s.queryOrders(ctx, "dt_retirada >= ? AND cd_pedido > ? ORDER BY cd_pedido LIMIT ?", pickupSince, afterId, limit)
+}
+
+func (s *MySQLSource) OrdersByIds(ctx context.Context, ids []int) ([]domain.LegacyOrderRecord, error) {
+	if len(ids) == 0 {
+		return []domain.LegacyOrderRecord{}, nil
+	}
+	return s.queryOrders(ctx, "cd_pedido IN ("+placeholders(len(ids))+") ORDER BY cd_pedido", intArgs(ids)...)
+}
+
+// queryOrders devolve os pedidos que atendem condition com os itens, lidos em uma segunda consulta.
+func (s *MySQLSource) queryOrders(ctx context.Context, condition string, args ...any) ([]domain.LegacyOrderRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT cd_pedido, nr_pedido, dt_retirada, dt_pedido, cd_cliente, nr_geladeira, ds_observacao, COALESCE(st_retirado, false)
+		FROM pedido
+		WHERE `+condition, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying legacy orders: %w", err)
+	}
+	defer rows.Close()
+
+	orders := []domain.LegacyOrderRecord{}
+	positions := map[int]int{}
+	for rows.Next() {
+		var order domain.LegacyOrderRecord
+		var pickupDate, createdAt sql.NullTime
+		var orderLocal, observations sql.NullString
+		if err := rows.Scan(
+			&order.OldId,
+			&order.Number,
+			&pickupDate,
+			&createdAt,
+			&order.OldCustomerId,
+			&orderLocal,
+			&observations,
+			&order.IsPickedUp,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning legacy order: %w", err)
+		}
+		order.PickupDate = nullTime(pickupDate)
+		order.CreatedAt = nullTime(createdAt)
+		order.OrderLocal = nullString(orderLocal)
+		order.Observations = nullString(observations)
+		order.Items = []domain.LegacyOrderItemRecord{}
+		positions[order.OldId] = len(orders)
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading legacy orders: %w", err)
+	}
+	if len(orders) == 0 {
+		return orders, nil
+	}
+
+	orderIds := make([]int, len(orders))
+	for i, order := range orders {
+		orderIds[i] = order.OldId
+	}
+	items, err := s.orderItems(ctx, orderIds)
+	if err != nil {
+		return nil, err
+	}
+	for orderId, orderItems := range items {
+		if position, ok := positions[orderId]; ok {
+			orders[position].Items = orderItems
+		}
+	}
+	return orders, nil
+}
+
+// orderItems lê os itens dos pedidos, agrupados pelo pedido. A ordem é fixa para que a
+// sincronização compare o mesmo pedido sempre com o mesmo conteúdo.
+func (s *MySQLSource) orderItems(ctx context.Context, orderIds []int) (map[int][]domain.LegacyOrderItemRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT ip.cd_pedido, ip.cd_produto, COALESCE(p.nm_produto, ''), COALESCE(p.st_unidade, ''), ip.cd_molho, COALESCE(ip.vl_quantidade, 0),
+		       COALESCE(p.vl_produto, 0)
+		FROM item_pedido ip
+		LEFT JOIN produto p ON p.cd_produto = ip.cd_produto
+		WHERE ip.cd_pedido IN (`+placeholders(len(orderIds))+`)
+		ORDER BY ip.cd_pedido, ip.cd_produto, ip.cd_molho, ip.vl_quantidade
+	`, intArgs(orderIds)...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying legacy order items: %w", err)
+	}
+	defer rows.Close()
+
+	items := map[int][]domain.LegacyOrderItemRecord{}
+	for rows.Next() {
+		var orderId int
+		var item domain.LegacyOrderItemRecord
+		var subProductId sql.NullString
+		if err := rows.Scan(&orderId, &item.OldProductId, &item.ProductName, &item.UnityType, &subProductId, &item.Quantity, &item.Value); err != nil {
+			return nil, fmt.Errorf("error scanning legacy order item: %w", err)
+		}
+		// O legado grava "" ou 0 quando o item não tem molho
+		if id, err := strconv.Atoi(strings.TrimSpace(subProductId.String)); err == nil && id > 0 {
+			item.OldSubProductId = &id
+		}
+		items[orderId] = append(items[orderId], item)
+	}
+	return items, rows.Err()
+}
+
+// placeholders monta a lista "?, ?, ?" de uma cláusula IN com n valores.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+func intArgs(values []int) []any {
+	args := make([]any, len(values))
+	for i, value := range values {
+		args[i] = value
+	}
+	return args
+}
+
+func nullString(value sql.NullString) *string {
+	if !value.Valid {
+		return nil
+	}
+	return &value.String
+}
+
+// nullTime também trata como ausente a data zero que o MySQL aceita ("0000-00-00").
+func nullTime(value sql.NullTime) *time.Time {
+	if !value.Valid || value.Time.IsZero() {
+		return nil
+	}
+	return &value.Time
+}
@@ -0,0 +1,125 @@
+package legacy
+
+import (
+	"context"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// fixtureSource conecta no MySQL de TEST_LEGACY_MYSQL_DSN, com as tabelas do legado criadas se
+// ainda não existirem. O teste é pulado quando a variável não está definida; as linhas gravadas
+// usam ids altos e são apagadas no fim, sem tocar nas outras.
+func fixtureSource(t *testing.T) *MySQLSource {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_LEGACY_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("TEST_LEGACY_MYSQL_DSN is not set")
+	}
+
+	mysqlConfig, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		t.Fatalf("expected a valid DSN, but got %v", err)
+	}
+	source, err := openMySQLSource(mysqlConfig)
+	if err != nil {
+		t.Fatalf("expected no error connecting to the fixture database, but got %v", err)
+	}
+	t.Cleanup(func() { _ = source.Close() })
+
+	fixtureExec(t, source,
+		`CREATE TABLE IF NOT EXISTS produto (
+			cd_produto INT PRIMARY KEY, nm_produto VARCHAR(100), st_unidade VARCHAR(3), vl_produto DECIMAL(10, 2)
+		)`,
+		`CREATE TABLE IF NOT EXISTS pedido (
+			cd_pedido INT PRIMARY KEY, nr_pedido INT, dt_retirada DATE, dt_pedido DATETIME, cd_cliente INT,
+			nr_geladeira VARCHAR(20), ds_observacao TEXT, st_retirado TINYINT(1)
+		)`,
+		`CREATE TABLE IF NOT EXISTS item_pedido (
+			cd_pedido INT, cd_produto INT, cd_molho VARCHAR(10), vl_quantidade DECIMAL(10, 3)
+		)`,
+	)
+	return source
+}
+
+// fixtureExec executa os comandos de preparação dos dados, falhando o teste no primeiro erro.
+func fixtureExec(t *testing.T, source *MySQLSource, statements ...string) {
+	t.Helper()
+
+	for _, statement := range statements {
+		if _, err := source.db.Exec(statement); err != nil {
+			t.Fatalf("expected no error running %q, but got %v", statement, err)
+		}
+	}
+}
+
+func TestMySQLSource_OrdersByIds(t *testing.T) {
+	source := fixtureSource(t)
+
+	cleanup := func() {
+		fixtureExec(t, source,
+			"DELETE FROM item_pedido WHERE cd_pedido = 990001",
+			"DELETE FROM pedido WHERE cd_pedido = 990001",
+			"DELETE FROM produto WHERE cd_produto IN (990001, 990002, 990007)",
+		)
+	}
+	cleanup()
+	t.Cleanup(cleanup)
+
+	fixtureExec(t, source,
+		`INSERT INTO produto (cd_produto, nm_produto, st_unidade, vl_produto) VALUES
+			(990001, 'Nhoque Fixture', 'KG', 58.00),
+			(990002, 'Pão Fixture', 'un ', 1.50),
+			(990007, 'Molho Fixture', 'UN', 0)`,
+		`INSERT INTO pedido (cd_pedido, nr_pedido, dt_retirada, dt_pedido, cd_cliente, nr_geladeira, ds_observacao, st_retirado)
+			VALUES (990001, 42, '2026-10-20', '2026-10-19 18:30:00', 990001, NULL, 'sem sal', 1)`,
+		// O legado grava "" ou "0" quando o item não tem molho
+		`INSERT INTO item_pedido (cd_pedido, cd_produto, cd_molho, vl_quantidade) VALUES
+			(990001, 990001, '7', 1.5),
+			(990001, 990001, '0', 0.35),
+			(990001, 990002, '', 2.5)`,
+	)
+
+	orders, err := source.OrdersByIds(context.Background(), []int{990001})
+	if err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+	if len(orders) != 1 {
+		t.Fatalf("expected one order, but got %+v", orders)
+	}
+	order := orders[0]
+
+	t.Run("should read the order in the legacy timezone", func(t *testing.T) {
+		if order.Number != 42 || order.OldCustomerId != 990001 || !order.IsPickedUp || order.OrderLocal != nil {
+			t.Errorf("unexpected order %+v", order)
+		}
+		if order.CreatedAt == nil || order.CreatedAt.Location().String() != legacyLocation || order.CreatedAt.Hour() != 18 {
+			t.Errorf("expected the creation date in %s, but got %v", legacyLocation, order.CreatedAt)
+		}
+	})
+
+	t.Run("should read the items in a fixed order with the product unit", func(t *testing.T) {
+		if len(order.Items) != 3 {
+			t.Fatalf("expected three items, but got %+v", order.Items)
+		}
+		quantities := []float64{order.Items[0].Quantity, order.Items[1].Quantity, order.Items[2].Quantity}
+		if !slices.Equal(quantities, []float64{0.35, 1.5, 2.5}) {
+			t.Errorf("expected the items ordered by product, sauce and quantity, but got %v", quantities)
+		}
+		if item := order.Items[2]; item.UnityType != "un " || item.Value != 1.5 || item.ProductName != "Pão Fixture" {
+			t.Errorf("expected the unit as stored in the legacy, but got %+v", item)
+		}
+	})
+
+	t.Run("should ignore the empty sauces of the legacy", func(t *testing.T) {
+		if order.Items[0].OldSubProductId != nil || order.Items[2].OldSubProductId != nil {
+			t.Errorf("expected no sauce, but got %v and %v", order.Items[0].OldSubProductId, order.Items[2].OldSubProductId)
+		}
+		if sauce := order.Items[1].OldSubProductId; sauce == nil || *sauce != 7 {
+			t.Errorf("expected sauce 7, but got %v", sauce)
+		}
+	})
+}
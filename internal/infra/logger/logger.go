@@ -1,10 +1,15 @@
 package logger
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"os"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Level string
@@ -16,57 +21,132 @@ const (
 	DEBUG Level = "DEBUG"
 )
 
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+)
+
+// Logger sem log definido (criado por New) usa sempre o base atual, para que Configure valha
+// também para os loggers criados antes dele.
 type Logger struct {
-	log zerolog.Logger
+	log *zerolog.Logger
 }
 
-func New() *Logger {
-	// Configura o zerolog para usar formato mais legível de tempo
+// base é o logger configurado na inicialização. Até Configure ser chamado (scripts e testes)
+// vale a saída colorida de console em nível Info.
+var base atomic.Pointer[zerolog.Logger]
+
+func init() {
 	zerolog.TimeFieldFormat = time.RFC3339
 
-	// Cria um logger com saída formatada e colorida para console
-	output := zerolog.ConsoleWriter{
-		Out:        os.Stdout,
-		TimeFormat: "2006-01-02 15:04:05",
+	logger := build(consoleWriter(os.Stdout), zerolog.InfoLevel)
+	base.Store(&logger)
+}
+
+// Configure define o formato ("json" ou "console") e o nível mínimo de todos os loggers,
+// inclusive os já criados com New.
+func Configure(format string, level string) error {
+	return configure(os.Stdout, format, level)
+}
+
+// ConfigureOutput é o Configure com outro destino, para comandos em que a saída padrão é o
+// próprio resultado (ex.: zionctl --json).
+func ConfigureOutput(out io.Writer, format string, level string) error {
+	return configure(out, format, level)
+}
+
+func configure(out io.Writer, format string, level string) error {
+	parsed, err := zerolog.ParseLevel(level)
+	if err != nil || level == "" {
+		return fmt.Errorf("invalid log level %q", level)
 	}
 
-	// Configura o nível mínimo de log e adiciona timestamp por padrão
-	logger := zerolog.New(output).
-		Level(zerolog.InfoLevel).
-		With().
-		Timestamp().
-		Caller().
-		Logger()
+	output := out
+	switch format {
+	case FormatJSON:
+	case FormatConsole:
+		output = consoleWriter(out)
+	default:
+		return fmt.Errorf("invalid log format %q", format)
+	}
+
+	logger := build(output, parsed)
+	base.Store(&logger)
+	return nil
+}
+
+func New() *Logger {
+	return &Logger{}
+}
+
+// FromContext devolve um logger com os campos da requisição (request_id, user_id, trace_id).
+func FromContext(ctx context.Context) *Logger {
+	return New().Ctx(ctx)
+}
+
+// Ctx acrescenta ao logger os campos guardados no contexto por WithField e o trace em andamento.
+func (l *Logger) Ctx(ctx context.Context) *Logger {
+	builder := l.zerolog().With()
 
-	return &Logger{
-		log: logger,
+	if fields, ok := ctx.Value(fieldsKey{}).([]field); ok {
+		for _, f := range fields {
+			builder = builder.Str(f.key, f.value)
+		}
 	}
+
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		builder = builder.
+			Str("trace_id", spanContext.TraceID().String()).
+			Str("span_id", spanContext.SpanID().String())
+	}
+
+	logger := builder.Logger()
+	return &Logger{log: &logger}
+}
+
+type fieldsKey struct{}
+
+type field struct {
+	key   string
+	value string
+}
+
+// WithField devolve um contexto cujos logs (via FromContext ou Ctx) incluem o campo.
+func WithField(ctx context.Context, key string, value string) context.Context {
+	fields, _ := ctx.Value(fieldsKey{}).([]field)
+	next := make([]field, 0, len(fields)+1)
+	for _, f := range fields {
+		if f.key != key {
+			next = append(next, f)
+		}
+	}
+	return context.WithValue(ctx, fieldsKey{}, append(next, field{key: key, value: value}))
 }
 
 func (l *Logger) Error(message string, err error) {
-	l.log.Error().
+	l.event(zerolog.ErrorLevel).
 		Err(err).
 		Msg(message)
 }
 
 func (l *Logger) Info(message string) {
-	l.log.Info().
+	l.event(zerolog.InfoLevel).
 		Msg(message)
 }
 
 func (l *Logger) Warn(message string) {
-	l.log.Warn().
+	l.event(zerolog.WarnLevel).
 		Msg(message)
 }
 
 func (l *Logger) Debug(message string) {
-	l.log.Debug().
+	l.event(zerolog.DebugLevel).
 		Msg(message)
 }
 
 // Métodos auxiliares para adicionar contexto aos logs
 func (l *Logger) WithFields(fields map[string]interface{}) *zerolog.Event {
-	event := l.log.Info()
+	event := l.event(zerolog.InfoLevel)
 	for k, v := range fields {
 		event.Interface(k, v)
 	}
@@ -75,9 +155,41 @@ func (l *Logger) WithFields(fields map[string]interface{}) *zerolog.Event {
 
 // Exemplo de uso com contexto estruturado
 func (l *Logger) InfoWithContext(message string, fields map[string]interface{}) {
-	event := l.log.Info()
+	event := l.event(zerolog.InfoLevel)
 	for k, v := range fields {
 		event.Interface(k, v)
 	}
 	event.Msg(message)
 }
+
+// Event abre um evento no nível informado, para logs com campos tipados como o access log.
+func (l *Logger) Event(level zerolog.Level) *zerolog.Event {
+	return l.event(level)
+}
+
+// event registra como caller quem chamou o método público deste pacote.
+func (l *Logger) event(level zerolog.Level) *zerolog.Event {
+	return l.zerolog().WithLevel(level).Caller(2)
+}
+
+func (l *Logger) zerolog() *zerolog.Logger {
+	if l.log == nil {
+		return base.Load()
+	}
+	return l.log
+}
+
+func build(output io.Writer, level zerolog.Level) zerolog.Logger {
+	return zerolog.New(output).
+		Level(level).
+		With().
+		Timestamp().
+		Logger()
+}
+
+func consoleWriter(out io.Writer) zerolog.ConsoleWriter {
+	return zerolog.ConsoleWriter{
+		Out:        out,
+		TimeFormat: "2006-01-02 15:04:05",
+	}
+}
@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+)
+
+func captureJSON(t *testing.T, level string) *bytes.Buffer {
+	t.Helper()
+	var out bytes.Buffer
+	if err := configure(&out, FormatJSON, level); err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+	t.Cleanup(func() { configure(os.Stdout, FormatConsole, "info") })
+	return &out
+}
+
+func decode(t *testing.T, out *bytes.Buffer) map[string]any {
+	t.Helper()
+	var entry map[string]any
+	if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+		t.Fatalf("expected a JSON line, but got %q: %v", out.String(), err)
+	}
+	return entry
+}
+
+func TestLogger(t *testing.T) {
+	t.Run("should write JSON with the fields stored in the context", func(t *testing.T) {
+		out := captureJSON(t, "info")
+
+		ctx := WithField(context.Background(), "request_id", "req-1")
+		ctx = WithField(ctx, "user_id", "user-1")
+		FromContext(ctx).Error("Failed to create order", errors.New("boom"))
+
+		entry := decode(t, out)
+		if entry["request_id"] != "req-1" || entry["user_id"] != "user-1" {
+			t.Errorf("expected request and user ids, but got %v", entry)
+		}
+		if entry["level"] != "error" || entry["error"] != "boom" || entry["message"] != "Failed to create order" {
+			t.Errorf("unexpected entry %v", entry)
+		}
+	})
+
+	t.Run("should apply the configuration to loggers created before it", func(t *testing.T) {
+		log := New()
+		out := captureJSON(t, "warn")
+
+		log.Info("ignored")
+		if out.Len() != 0 {
+			t.Fatalf("expected info to be filtered at warn level, but got %q", out.String())
+		}
+
+		log.Warn("kept")
+		if entry := decode(t, out); entry["message"] != "kept" {
+			t.Errorf("expected warn message, but got %v", entry)
+		}
+	})
+
+	t.Run("should replace a field set twice", func(t *testing.T) {
+		out := captureJSON(t, "info")
+
+		ctx := WithField(context.Background(), "user_id", "user-1")
+		ctx = WithField(ctx, "user_id", "user-2")
+		FromContext(ctx).Info("hello")
+
+		if entry := decode(t, out); entry["user_id"] != "user-2" {
+			t.Errorf("expected user-2, but got %v", entry["user_id"])
+		}
+	})
+
+	t.Run("should reject unknown formats and levels", func(t *testing.T) {
+		if err := Configure("xml", "info"); err == nil {
+			t.Error("expected an error for format xml")
+		}
+		if err := Configure(FormatJSON, "verbose"); err == nil {
+			t.Error("expected an error for level verbose")
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/infra/logger"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// collectTimeout limita as consultas feitas durante uma coleta, que não recebe contexto.
+	collectTimeout = 5 * time.Second
+	// businessStatsTTL é por quanto tempo os números do negócio são reaproveitados entre coletas.
+	// São contagens sobre pedidos e notificações, caras demais para cada scrape.
+	businessStatsTTL = 30 * time.Second
+)
+
+// BusinessCollector consulta o banco nas coletas. Os números vêm do banco, e não de contadores
+// em memória, para que todas as instâncias exponham o mesmo valor e nada se perca em um restart.
+type BusinessCollector struct {
+	metrics domain.MetricsRepository
+	jobs    domain.BackgroundJobRepository
+	now     func() time.Time
+	logger  *logger.Logger
+
+	// O último resultado de BusinessStats, válido por businessStatsTTL dentro do mesmo dia
+	mu          sync.Mutex
+	stats       *domain.BusinessStats
+	statsSince  time.Time
+	statsExpiry time.Time
+
+	ordersCreatedToday  *prometheus.Desc
+	ordersPendingPickup *prometheus.Desc
+	notificationsFailed *prometheus.Desc
+	queueJobs           *prometheus.Desc
+	queueOldestPending  *prometheus.Desc
+}
+
+func NewBusinessCollector(metrics domain.MetricsRepository, jobs domain.BackgroundJobRepository) *BusinessCollector {
+	return &BusinessCollector{
+		metrics: metrics,
+		jobs:    jobs,
+		now:     time.Now,
+		logger:  logger.New(),
+		ordersCreatedToday: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "orders", "created_today"),
+			"Orders created since midnight (store time).", nil, nil,
+		),
+		ordersPendingPickup: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "orders", "pending_pickup"),
+			"Orders due until the end of today, including late ones, not picked up yet.", []string{"status"}, nil,
+		),
+		notificationsFailed: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "notifications", "failed"),
+			"Notifications currently in the failed state.", []string{"channel"}, nil,
+		),
+		queueJobs: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "queue", "jobs"),
+			"Background jobs by kind and state.", []string{"kind", "state"}, nil,
+		),
+		queueOldestPending: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "queue", "oldest_pending_seconds"),
+			"Age of the oldest pending job of each kind.", []string{"kind"}, nil,
+		),
+	}
+}
+
+func (c *BusinessCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.ordersCreatedToday
+	ch <- c.ordersPendingPickup
+	ch <- c.notificationsFailed
+	ch <- c.queueJobs
+	ch <- c.queueOldestPending
+}
+
+func (c *BusinessCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	now := c.now()
+	local := now.In(domain.StoreLocation)
+	since := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, domain.StoreLocation)
+
+	stats, err := c.businessStats(ctx, now, since)
+	if err != nil {
+		c.logger.Error("Error collecting business metrics", err)
+		ch <- prometheus.NewInvalidMetric(c.ordersCreatedToday, err)
+	} else {
+		ch <- prometheus.MustNewConstMetric(c.ordersCreatedToday, prometheus.GaugeValue, float64(stats.OrdersCreatedToday))
+		// Os status sem pedidos também são expostos, para a série não sumir do gráfico
+		for _, status := range []string{domain.OrderStatusPending, domain.OrderStatusReady} {
+			ch <- prometheus.MustNewConstMetric(c.ordersPendingPickup, prometheus.GaugeValue, float64(stats.OrdersPendingPickup[status]), status)
+		}
+		for channel, count := range stats.NotificationsFailed {
+			ch <- prometheus.MustNewConstMetric(c.notificationsFailed, prometheus.GaugeValue, float64(count), channel)
+		}
+	}
+
+	queue, err := c.jobs.Stats(ctx)
+	if err != nil {
+		c.logger.Error("Error collecting queue metrics", err)
+		ch <- prometheus.NewInvalidMetric(c.queueJobs, err)
+		return
+	}
+	for _, stat := range queue {
+		ch <- prometheus.MustNewConstMetric(c.queueJobs, prometheus.GaugeValue, float64(stat.Pending), stat.Kind, domain.BackgroundJobPending)
+		ch <- prometheus.MustNewConstMetric(c.queueJobs, prometheus.GaugeValue, float64(stat.Running), stat.Kind, domain.BackgroundJobRunning)
+		ch <- prometheus.MustNewConstMetric(c.queueJobs, prometheus.GaugeValue, float64(stat.Dead), stat.Kind, domain.BackgroundJobDead)
+
+		age := 0.0
+		if stat.OldestPendingAt != nil {
+			age = max(now.Sub(*stat.OldestPendingAt).Seconds(), 0)
+		}
+		ch <- prometheus.MustNewConstMetric(c.queueOldestPending, prometheus.GaugeValue, age, stat.Kind)
+	}
+}
+
+// businessStats devolve os números do negócio da última consulta enquanto ela vale, para que
+// scrapes frequentes ou de várias origens não repitam as contagens. Erros não são guardados.
+func (c *BusinessCollector) businessStats(ctx context.Context, now time.Time, since time.Time) (*domain.BusinessStats, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stats != nil && c.statsSince.Equal(since) && now.Before(c.statsExpiry) {
+		return c.stats, nil
+	}
+
+	stats, err := c.metrics.BusinessStats(ctx, since, since.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	c.stats, c.statsSince, c.statsExpiry = stats, since, now.Add(businessStatsTTL)
+	return stats, nil
+}
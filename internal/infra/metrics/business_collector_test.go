@@ -0,0 +1,129 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type mockMetricsRepository struct {
+	stats *domain.BusinessStats
+	err   error
+	since time.Time
+	calls int
+}
+
+func (m *mockMetricsRepository) BusinessStats(ctx context.Context, since time.Time, until time.Time) (*domain.BusinessStats, error) {
+	m.calls++
+	m.since = since
+	return m.stats, m.err
+}
+
+type mockQueueRepository struct {
+	domain.BackgroundJobRepository
+	stats []domain.QueueStats
+}
+
+func (m *mockQueueRepository) Stats(ctx context.Context) ([]domain.QueueStats, error) {
+	return m.stats, nil
+}
+
+func TestBusinessCollector(t *testing.T) {
+	now := time.Date(2026, 10, 20, 2, 0, 0, 0, time.UTC) // 23h do dia 19 em São Paulo
+	oldest := now.Add(-90 * time.Second)
+
+	t.Run("should expose business and queue metrics", func(t *testing.T) {
+		repo := &mockMetricsRepository{stats: &domain.BusinessStats{
+			OrdersCreatedToday:  12,
+			OrdersPendingPickup: map[string]int{domain.OrderStatusReady: 3},
+			NotificationsFailed: map[string]int{"whatsapp": 2},
+		}}
+		queue := &mockQueueRepository{stats: []domain.QueueStats{
+			{Kind: domain.BackgroundJobOrderNotification, Pending: 5, Running: 1, OldestPendingAt: &oldest},
+		}}
+		collector := NewBusinessCollector(repo, queue)
+		collector.now = func() time.Time { return now }
+
+		expected := `
+# HELP zion_orders_created_today Orders created since midnight (store time).
+# TYPE zion_orders_created_today gauge
+zion_orders_created_today 12
+# HELP zion_orders_pending_pickup Orders due until the end of today, including late ones, not picked up yet.
+# TYPE zion_orders_pending_pickup gauge
+zion_orders_pending_pickup{status="pending"} 0
+zion_orders_pending_pickup{status="ready"} 3
+# HELP zion_notifications_failed Notifications currently in the failed state.
+# TYPE zion_notifications_failed gauge
+zion_notifications_failed{channel="whatsapp"} 2
+# HELP zion_queue_oldest_pending_seconds Age of the oldest pending job of each kind.
+# TYPE zion_queue_oldest_pending_seconds gauge
+zion_queue_oldest_pending_seconds{kind="order.notification"} 90
+`
+		err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+			"zion_orders_created_today", "zion_orders_pending_pickup", "zion_notifications_failed", "zion_queue_oldest_pending_seconds")
+		if err != nil {
+			t.Error(err)
+		}
+
+		if repo.since.In(domain.StoreLocation).Day() != 19 || repo.since.In(domain.StoreLocation).Hour() != 0 {
+			t.Errorf("expected the day to start at midnight store time, but got %v", repo.since.In(domain.StoreLocation))
+		}
+	})
+
+	t.Run("should reuse the business stats until they expire", func(t *testing.T) {
+		repo := &mockMetricsRepository{stats: &domain.BusinessStats{OrdersCreatedToday: 1}}
+		collector := NewBusinessCollector(repo, &mockQueueRepository{})
+		current := now
+		collector.now = func() time.Time { return current }
+
+		testutil.CollectAndCount(collector)
+		current = current.Add(businessStatsTTL - time.Second)
+		testutil.CollectAndCount(collector)
+		if repo.calls != 1 {
+			t.Errorf("expected one query within the cache window, but got %d", repo.calls)
+		}
+
+		current = current.Add(time.Second)
+		testutil.CollectAndCount(collector)
+		if repo.calls != 2 {
+			t.Errorf("expected the stats to be queried again after expiring, but got %d queries", repo.calls)
+		}
+	})
+
+	t.Run("should query again when the day changes", func(t *testing.T) {
+		repo := &mockMetricsRepository{stats: &domain.BusinessStats{OrdersCreatedToday: 1}}
+		collector := NewBusinessCollector(repo, &mockQueueRepository{})
+		current := time.Date(2026, 10, 20, 2, 59, 50, 0, time.UTC) // 23h59m50s em São Paulo
+		collector.now = func() time.Time { return current }
+
+		testutil.CollectAndCount(collector)
+		current = current.Add(20 * time.Second) // meia-noite passou, mas o cache ainda vale
+		testutil.CollectAndCount(collector)
+		if repo.calls != 2 || repo.since.In(domain.StoreLocation).Day() != 20 {
+			t.Errorf("expected the new day to be queried, but got %d queries since %v", repo.calls, repo.since)
+		}
+	})
+
+	t.Run("should keep queue metrics when business stats fail", func(t *testing.T) {
+		repo := &mockMetricsRepository{err: errors.New("connection refused")}
+		queue := &mockQueueRepository{stats: []domain.QueueStats{{Kind: domain.BackgroundJobOrderNotification, Pending: 5}}}
+		collector := NewBusinessCollector(repo, queue)
+
+		registry := prometheus.NewPedanticRegistry()
+		registry.MustRegister(collector)
+
+		families, err := registry.Gather()
+		if err == nil {
+			t.Error("expected the collect error to be reported")
+		}
+		if len(families) != 2 || families[0].GetName() != "zion_queue_jobs" {
+			t.Errorf("expected only queue metrics, but got %v", families)
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain/services"
+	"github.com/google/uuid"
+)
+
+// Email envia as mensagens por SMTP com autenticação PLAIN.
+type Email struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+}
+
+func NewEmail(host, port, username, password, from string) *Email {
+	return &Email{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+func (e *Email) Channel() string {
+	return services.NotificationChannelEmail
+}
+
+func (e *Email) Recipient(message services.NotificationMessage) string {
+	if message.Email == nil {
+		return ""
+	}
+	return strings.TrimSpace(*message.Email)
+}
+
+// Send não é interrompido pelo ctx depois de iniciado, pois net/smtp não aceita contexto.
+func (e *Email) Send(ctx context.Context, recipient string, message services.NotificationMessage) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	messageId := fmt.Sprintf("<%s@%s>", uuid.NewString(), e.host)
+
+	headers := []string{
+		"From: " + e.from,
+		"To: " + recipient,
+		"Subject: " + mime.QEncoding.Encode("utf-8", message.Subject),
+		"Message-ID: " + messageId,
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Transfer-Encoding: 8bit",
+	}
+	msg := strings.Join(headers, "\r\n") + "\r\n\r\n" + message.Body
+
+	var auth smtp.Auth
+	if e.username != "" {
+		auth = smtp.PlainAuth("", e.username, e.password, e.host)
+	}
+
+	if err := smtp.SendMail(net.JoinHostPort(e.host, e.port), auth, e.from, []string{recipient}, []byte(msg)); err != nil {
+		return "", fmt.Errorf("error sending email: %w", err)
+	}
+
+	return messageId, nil
+}
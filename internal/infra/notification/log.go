@@ -0,0 +1,32 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/deividr/zion-api/internal/domain/services"
+	"github.com/deividr/zion-api/internal/infra/logger"
+	"github.com/google/uuid"
+)
+
+// Log não envia nada, apenas registra a mensagem. Usado em desenvolvimento e testes.
+type Log struct {
+	logger *logger.Logger
+}
+
+func NewLog() *Log {
+	return &Log{logger: logger.New()}
+}
+
+func (l *Log) Channel() string {
+	return services.NotificationChannelLog
+}
+
+func (l *Log) Recipient(message services.NotificationMessage) string {
+	return message.Phone
+}
+
+func (l *Log) Send(ctx context.Context, recipient string, message services.NotificationMessage) (string, error) {
+	l.logger.Info(fmt.Sprintf("notification to %s: %s", recipient, message.Body))
+	return uuid.NewString(), nil
+}
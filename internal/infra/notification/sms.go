@@ -0,0 +1,82 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain/services"
+)
+
+// SMS envia mensagens por um gateway HTTP genérico que recebe {to, from, message}
+// autenticado por chave de API e devolve {id}.
+type SMS struct {
+	client *http.Client
+	apiURL string
+	apiKey string
+	sender string
+}
+
+func NewSMS(apiURL, apiKey, sender string) *SMS {
+	return &SMS{
+		client: &http.Client{Timeout: 10 * time.Second},
+		apiURL: apiURL,
+		apiKey: apiKey,
+		sender: sender,
+	}
+}
+
+func (s *SMS) Channel() string {
+	return services.NotificationChannelSMS
+}
+
+func (s *SMS) Recipient(message services.NotificationMessage) string {
+	return message.Phone
+}
+
+func (s *SMS) Send(ctx context.Context, recipient string, message services.NotificationMessage) (string, error) {
+	payload, err := json.Marshal(map[string]string{
+		"to":      recipient,
+		"from":    s.sender,
+		"message": message.Body,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error encoding sms message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.apiURL, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("error building sms request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error sending sms: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("error reading sms response: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return "", fmt.Errorf("sms gateway returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Id string `json:"id"`
+	}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &result); err != nil {
+			return "", fmt.Errorf("error decoding sms response: %w", err)
+		}
+	}
+	return result.Id, nil
+}
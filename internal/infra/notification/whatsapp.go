@@ -0,0 +1,107 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/domain/services"
+)
+
+// WhatsApp envia mensagens de texto pela API HTTP no formato do WhatsApp Business (Cloud API).
+type WhatsApp struct {
+	client        *http.Client
+	baseURL       string
+	phoneNumberId string
+	accessToken   string
+}
+
+func NewWhatsApp(baseURL, phoneNumberId, accessToken string) *WhatsApp {
+	return &WhatsApp{
+		client:        &http.Client{Timeout: 10 * time.Second},
+		baseURL:       strings.TrimSuffix(baseURL, "/"),
+		phoneNumberId: phoneNumberId,
+		accessToken:   accessToken,
+	}
+}
+
+func (w *WhatsApp) Channel() string {
+	return services.NotificationChannelWhatsApp
+}
+
+func (w *WhatsApp) Recipient(message services.NotificationMessage) string {
+	return domain.WhatsAppNumber(message.Phone)
+}
+
+type whatsAppRequest struct {
+	MessagingProduct string           `json:"messaging_product"`
+	To               string           `json:"to"`
+	Type             string           `json:"type"`
+	Text             whatsAppTextBody `json:"text"`
+}
+
+type whatsAppTextBody struct {
+	Body string `json:"body"`
+}
+
+type whatsAppResponse struct {
+	Messages []struct {
+		Id string `json:"id"`
+	} `json:"messages"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (w *WhatsApp) Send(ctx context.Context, recipient string, message services.NotificationMessage) (string, error) {
+	payload, err := json.Marshal(whatsAppRequest{
+		MessagingProduct: "whatsapp",
+		To:               recipient,
+		Type:             "text",
+		Text:             whatsAppTextBody{Body: message.Body},
+	})
+	if err != nil {
+		return "", fmt.Errorf("error encoding whatsapp message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/%s/messages", w.baseURL, w.phoneNumberId), bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("error building whatsapp request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+w.accessToken)
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error sending whatsapp message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("error reading whatsapp response: %w", err)
+	}
+
+	var result whatsAppResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("error decoding whatsapp response (status %d): %w", resp.StatusCode, err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		if result.Error != nil {
+			return "", fmt.Errorf("whatsapp api returned status %d: %s", resp.StatusCode, result.Error.Message)
+		}
+		return "", fmt.Errorf("whatsapp api returned status %d", resp.StatusCode)
+	}
+
+	if len(result.Messages) == 0 {
+		return "", nil
+	}
+	return result.Messages[0].Id, nil
+}
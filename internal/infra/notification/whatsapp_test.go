@@ -0,0 +1,65 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/deividr/zion-api/internal/domain/services"
+)
+
+func TestWhatsApp_Send(t *testing.T) {
+	t.Run("should post the text message and return the provider id", func(t *testing.T) {
+		var received whatsAppRequest
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/123/messages" {
+				t.Errorf("unexpected path %s", r.URL.Path)
+			}
+			if r.Header.Get("Authorization") != "Bearer token" {
+				t.Errorf("unexpected authorization header %s", r.Header.Get("Authorization"))
+			}
+			if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+				t.Fatalf("error decoding request: %v", err)
+			}
+			w.Write([]byte(`{"messages":[{"id":"wamid.1"}]}`))
+		}))
+		defer server.Close()
+
+		sender := NewWhatsApp(server.URL, "123", "token")
+		message := services.NotificationMessage{Phone: "+5511999990000", Body: "Seu pedido está pronto"}
+
+		recipient := sender.Recipient(message)
+		if recipient != "5511999990000" {
+			t.Errorf("expected recipient 5511999990000, but got %s", recipient)
+		}
+
+		id, err := sender.Send(context.Background(), recipient, message)
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if id != "wamid.1" {
+			t.Errorf("expected id wamid.1, but got %s", id)
+		}
+		if received.To != "5511999990000" || received.Text.Body != "Seu pedido está pronto" || received.MessagingProduct != "whatsapp" {
+			t.Errorf("unexpected request body %+v", received)
+		}
+	})
+
+	t.Run("should return the api error message", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"message":"invalid recipient"}}`))
+		}))
+		defer server.Close()
+
+		_, err := NewWhatsApp(server.URL, "123", "token").Send(context.Background(), "5511", services.NotificationMessage{Body: "oi"})
+		if err == nil {
+			t.Fatal("expected an error, but got nil")
+		}
+		if err.Error() != "whatsapp api returned status 400: invalid recipient" {
+			t.Errorf("unexpected error %v", err)
+		}
+	})
+}
@@ -0,0 +1,211 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/infra/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultPollInterval = time.Second
+	// jobTimeout limita cada execução; o lease é maior para que o job não seja entregue a
+	// outro worker enquanto ainda está rodando.
+	jobTimeout = 2 * time.Minute
+	jobLease   = 5 * time.Minute
+)
+
+var tracer = otel.Tracer("github.com/deividr/zion-api/internal/infra/queue")
+
+// Handler executa um job. Recebe o job inteiro para que possa usar o id como chave de
+// idempotência entre as tentativas.
+type Handler func(ctx context.Context, job domain.BackgroundJob) error
+
+// WorkerPool consome a fila background_jobs com concurrency workers. Jobs que falham são
+// reagendados com backoff até max_attempts e então movidos para a dead-letter.
+type WorkerPool struct {
+	repo         domain.BackgroundJobRepository
+	concurrency  int
+	pollInterval time.Duration
+	workerId     string
+	logger       *logger.Logger
+
+	handlers   map[string]Handler
+	kinds      []string
+	stopping   chan struct{}
+	jobsCtx    context.Context
+	cancelJobs context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+func NewWorkerPool(repo domain.BackgroundJobRepository, concurrency int) *WorkerPool {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	hostname, _ := os.Hostname()
+	jobsCtx, cancelJobs := context.WithCancel(context.Background())
+
+	return &WorkerPool{
+		repo:         repo,
+		concurrency:  concurrency,
+		pollInterval: defaultPollInterval,
+		workerId:     fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		logger:       logger.New(),
+		handlers:     map[string]Handler{},
+		stopping:     make(chan struct{}),
+		jobsCtx:      jobsCtx,
+		cancelJobs:   cancelJobs,
+	}
+}
+
+func (p *WorkerPool) Register(kind string, handler Handler) {
+	p.handlers[kind] = handler
+	p.kinds = append(p.kinds, kind)
+}
+
+func (p *WorkerPool) Start() {
+	for i := 0; i < p.concurrency; i++ {
+		p.wg.Add(1)
+		go p.work()
+	}
+}
+
+// Shutdown para de buscar novos jobs e espera os que estão em execução terminarem. Se ctx
+// expirar antes, os jobs em andamento são cancelados; como continuam marcados como running,
+// voltam para a fila quando o lease expirar.
+func (p *WorkerPool) Shutdown(ctx context.Context) error {
+	close(p.stopping)
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancelJobs()
+		return nil
+	case <-ctx.Done():
+		p.cancelJobs()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (p *WorkerPool) work() {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case <-p.stopping:
+			return
+		default:
+		}
+
+		// A busca roda a cada poll, então fica fora de trace; cada job claimado abre o seu em process
+		jobs, err := p.repo.Claim(p.jobsCtx, p.workerId, p.kinds, 1, jobLease)
+		if err != nil {
+			p.logger.Error("Error claiming background jobs", err)
+		}
+
+		if len(jobs) == 0 {
+			select {
+			case <-p.stopping:
+				return
+			case <-time.After(p.pollInterval):
+			}
+			continue
+		}
+
+		for _, job := range jobs {
+			p.process(job)
+		}
+	}
+}
+
+func (p *WorkerPool) process(job domain.BackgroundJob) {
+	ctx, cancel := context.WithTimeout(p.jobsCtx, jobTimeout)
+	defer cancel()
+
+	// Cada execução é a raiz de um trace, já que o job roda fora da requisição que o criou
+	ctx, span := tracer.Start(ctx, "job "+job.Kind,
+		trace.WithNewRoot(),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("job.id", job.Id),
+			attribute.String("job.kind", job.Kind),
+			attribute.Int("job.attempt", job.Attempts),
+		),
+	)
+	defer span.End()
+
+	// Os logs feitos pelo handler saem com o id do job, como os de uma requisição saem com request_id
+	ctx = logger.WithField(ctx, "job_id", job.Id)
+	log := p.logger.Ctx(ctx)
+
+	// O resultado é gravado fora do contexto do job, que pode ter expirado justamente
+	// pelo timeout que causou a falha.
+	err := p.run(ctx, job)
+	if err == nil {
+		p.record(ctx, job, p.repo.Complete(context.WithoutCancel(ctx), job))
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+
+	var permanentErr *domain.PermanentJobError
+	dead := job.Attempts >= job.MaxAttempts || errors.As(err, &permanentErr)
+	switch {
+	case permanentErr != nil:
+		log.Error(fmt.Sprintf("Background job %s (%s) moved to dead-letter, the error is not retryable", job.Id, job.Kind), err)
+	case dead:
+		log.Error(fmt.Sprintf("Background job %s (%s) moved to dead-letter after %d attempts", job.Id, job.Kind, job.Attempts), err)
+	default:
+		log.Warn(fmt.Sprintf("Background job %s (%s) failed on attempt %d: %v", job.Id, job.Kind, job.Attempts, err))
+	}
+
+	p.record(ctx, job, p.repo.Fail(context.WithoutCancel(ctx), job, err.Error(), domain.BackgroundJobRetryDelay(job.Attempts), dead))
+}
+
+// record registra o erro ao gravar o resultado do job. Perder o lease não é falha do worker:
+// o job já está com outra execução, que vai gravar o próprio resultado.
+func (p *WorkerPool) record(ctx context.Context, job domain.BackgroundJob, err error) {
+	if err == nil {
+		return
+	}
+
+	var leaseLostErr *domain.BackgroundJobLeaseLostError
+	if errors.As(err, &leaseLostErr) {
+		p.logger.Ctx(ctx).Warn(fmt.Sprintf("Result of background job %s discarded: %v", job.Id, err))
+		return
+	}
+	p.logger.Ctx(ctx).Error(fmt.Sprintf("Error recording result of background job %s", job.Id), err)
+}
+
+// run executa o handler convertendo panics em erro, para que o job seja reagendado.
+func (p *WorkerPool) run(ctx context.Context, job domain.BackgroundJob) (err error) {
+	handler, ok := p.handlers[job.Kind]
+	if !ok {
+		return fmt.Errorf("no handler registered for job kind %s", job.Kind)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	return handler(ctx, job)
+}
@@ -0,0 +1,184 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+type failure struct {
+	retryIn time.Duration
+	dead    bool
+}
+
+type mockBackgroundJobRepository struct {
+	domain.BackgroundJobRepository
+	mu        sync.Mutex
+	queue     []domain.BackgroundJob
+	completed []string
+	failed    map[string]failure
+}
+
+func (m *mockBackgroundJobRepository) Claim(ctx context.Context, workerId string, kinds []string, limit int, lease time.Duration) ([]domain.BackgroundJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.queue) == 0 {
+		return nil, nil
+	}
+	job := m.queue[0]
+	m.queue = m.queue[1:]
+	job.Attempts++
+	return []domain.BackgroundJob{job}, nil
+}
+
+func (m *mockBackgroundJobRepository) Complete(ctx context.Context, job domain.BackgroundJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.completed = append(m.completed, job.Id)
+	return nil
+}
+
+func (m *mockBackgroundJobRepository) Fail(ctx context.Context, job domain.BackgroundJob, errorMessage string, retryIn time.Duration, dead bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failed[job.Id] = failure{retryIn: retryIn, dead: dead}
+	return nil
+}
+
+func (m *mockBackgroundJobRepository) processed() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.completed) + len(m.failed)
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met before timeout")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWorkerPool(t *testing.T) {
+	t.Run("should complete successful jobs and reschedule or dead-letter failures", func(t *testing.T) {
+		repo := &mockBackgroundJobRepository{
+			failed: map[string]failure{},
+			queue: []domain.BackgroundJob{
+				{Id: "ok", Kind: "test", Payload: json.RawMessage(`{"fail":false}`), MaxAttempts: 3},
+				{Id: "retry", Kind: "test", Payload: json.RawMessage(`{"fail":true}`), MaxAttempts: 3},
+				{Id: "dead", Kind: "test", Payload: json.RawMessage(`{"fail":true}`), Attempts: 2, MaxAttempts: 3},
+				{Id: "panic", Kind: "panic", Payload: json.RawMessage(`{}`), MaxAttempts: 3},
+				{Id: "permanent", Kind: "permanent", Payload: json.RawMessage(`{}`), MaxAttempts: 3},
+			},
+		}
+
+		pool := NewWorkerPool(repo, 2)
+		pool.pollInterval = 10 * time.Millisecond
+		pool.Register("test", func(ctx context.Context, job domain.BackgroundJob) error {
+			var input struct{ Fail bool }
+			_ = json.Unmarshal(job.Payload, &input)
+			if input.Fail {
+				return errors.New("boom")
+			}
+			return nil
+		})
+		pool.Register("panic", func(ctx context.Context, job domain.BackgroundJob) error {
+			panic("unexpected")
+		})
+		pool.Register("permanent", func(ctx context.Context, job domain.BackgroundJob) error {
+			return domain.NewPermanentJobError(errors.New("invalid payload"))
+		})
+
+		pool.Start()
+		waitFor(t, func() bool { return repo.processed() == 5 })
+		if err := pool.Shutdown(context.Background()); err != nil {
+			t.Fatalf("expected clean shutdown, but got %v", err)
+		}
+
+		if len(repo.completed) != 1 || repo.completed[0] != "ok" {
+			t.Errorf("expected only job ok to complete, but got %v", repo.completed)
+		}
+		if f := repo.failed["retry"]; f.dead || f.retryIn != domain.BackgroundJobRetryDelay(1) {
+			t.Errorf("expected job retry to be rescheduled in %s, but got %+v", domain.BackgroundJobRetryDelay(1), f)
+		}
+		if f := repo.failed["dead"]; !f.dead {
+			t.Errorf("expected job dead to move to dead-letter, but got %+v", f)
+		}
+		if _, ok := repo.failed["panic"]; !ok {
+			t.Errorf("expected panicking job to be recorded as failure")
+		}
+		if f := repo.failed["permanent"]; !f.dead {
+			t.Errorf("expected permanent failure to move to dead-letter on the first attempt, but got %+v", f)
+		}
+	})
+
+	t.Run("should wait for running jobs when shutting down", func(t *testing.T) {
+		repo := &mockBackgroundJobRepository{
+			failed: map[string]failure{},
+			queue:  []domain.BackgroundJob{{Id: "slow", Kind: "slow", MaxAttempts: 3}},
+		}
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		pool := NewWorkerPool(repo, 1)
+		pool.pollInterval = 10 * time.Millisecond
+		pool.Register("slow", func(ctx context.Context, job domain.BackgroundJob) error {
+			close(started)
+			<-release
+			return nil
+		})
+
+		pool.Start()
+		<-started
+
+		shutdownErr := make(chan error)
+		go func() { shutdownErr <- pool.Shutdown(context.Background()) }()
+
+		select {
+		case <-shutdownErr:
+			t.Fatalf("expected shutdown to wait for the running job")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(release)
+		if err := <-shutdownErr; err != nil {
+			t.Fatalf("expected clean shutdown, but got %v", err)
+		}
+		if len(repo.completed) != 1 {
+			t.Errorf("expected running job to complete before shutdown, but got %v", repo.completed)
+		}
+	})
+
+	t.Run("should cancel running jobs when the drain times out", func(t *testing.T) {
+		repo := &mockBackgroundJobRepository{
+			failed: map[string]failure{},
+			queue:  []domain.BackgroundJob{{Id: "stuck", Kind: "stuck", MaxAttempts: 3}},
+		}
+
+		started := make(chan struct{})
+		pool := NewWorkerPool(repo, 1)
+		pool.Register("stuck", func(ctx context.Context, job domain.BackgroundJob) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		pool.Start()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if err := pool.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline exceeded, but got %v", err)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/deividr/zion-api/internal/infra/database"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// fixturePool conecta no Postgres de TEST_DATABASE_URL com as migrations aplicadas. Os testes
+// que precisam de dados são pulados quando a variável não está definida; cada teste grava e
+// apaga as próprias linhas.
+func fixturePool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	migrator, err := database.NewMigrator(url)
+	if err != nil {
+		t.Fatalf("expected no error opening the migrator, but got %v", err)
+	}
+	defer migrator.Close()
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("expected no error applying migrations, but got %v", err)
+	}
+
+	pool, err := pgxpool.New(context.Background(), url)
+	if err != nil {
+		t.Fatalf("expected no error connecting to the fixture database, but got %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+// fixtureExec executa os comandos de preparação dos dados, falhando o teste no primeiro erro.
+func fixtureExec(t *testing.T, pool *pgxpool.Pool, statements ...string) {
+	t.Helper()
+
+	for _, statement := range statements {
+		if _, err := pool.Exec(context.Background(), statement); err != nil {
+			t.Fatalf("expected no error running %q, but got %v", statement, err)
+		}
+	}
+}
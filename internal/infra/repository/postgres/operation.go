@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/deividr/zion-api/internal/infra/database"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/deividr/zion-api/internal/infra/repository/postgres")
+
+// queryTimeout limita cada método dos repositórios. Começa desligado para o zionctl
+// e é definido pela API na inicialização com SetQueryTimeout.
+var queryTimeout atomic.Int64
+
+// SetQueryTimeout define o limite de tempo de cada operação dos repositórios; zero desliga.
+func SetQueryTimeout(timeout time.Duration) {
+	queryTimeout.Store(int64(timeout))
+}
+
+// operation é o span do método do repositório junto com o prazo aplicado às suas consultas.
+type operation struct {
+	trace.Span
+	ctx    context.Context
+	parent context.Context
+	cancel context.CancelFunc
+}
+
+// End encerra o span e libera o prazo. Quando o prazo da própria operação estourou (e não o da
+// requisição ou do job), marca o contexto para que a requisição seja respondida com 503.
+func (o *operation) End(options ...trace.SpanEndOption) {
+	if errors.Is(o.ctx.Err(), context.DeadlineExceeded) && o.parent.Err() == nil {
+		database.MarkQueryTimeout(o.parent)
+		o.Span.SetStatus(codes.Error, "query timeout")
+	}
+	o.cancel()
+	o.Span.End(options...)
+}
+
+// startSpan agrupa as consultas de um método do repositório e aplica a elas o queryTimeout.
+// Assim como o tracer do pgx, só registra spans dentro de um trace já iniciado.
+func startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	parent := ctx
+	span := trace.SpanFromContext(ctx)
+	if span.IsRecording() {
+		ctx, span = tracer.Start(ctx, name)
+	}
+
+	cancel := context.CancelFunc(func() {})
+	if timeout := time.Duration(queryTimeout.Load()); timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+
+	return ctx, &operation{Span: span, ctx: ctx, parent: parent, cancel: cancel}
+}
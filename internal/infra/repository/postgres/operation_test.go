@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/deividr/zion-api/internal/infra/database"
+)
+
+func TestStartSpan(t *testing.T) {
+	SetQueryTimeout(10 * time.Millisecond)
+	t.Cleanup(func() { SetQueryTimeout(0) })
+
+	t.Run("should mark the request when the operation times out", func(t *testing.T) {
+		parent := database.TrackQueryTimeouts(context.Background())
+
+		ctx, span := startSpan(parent, "PgOrderRepository.FindAll")
+		<-ctx.Done()
+		span.End()
+
+		if !database.QueryTimedOut(parent) {
+			t.Error("expected the query timeout to be recorded")
+		}
+	})
+
+	t.Run("should not mark the request when the caller was canceled first", func(t *testing.T) {
+		parent, cancel := context.WithCancel(database.TrackQueryTimeouts(context.Background()))
+		cancel()
+
+		_, span := startSpan(parent, "PgOrderRepository.FindAll")
+		time.Sleep(20 * time.Millisecond)
+		span.End()
+
+		if database.QueryTimedOut(parent) {
+			t.Error("expected no query timeout for a canceled request")
+		}
+	})
+
+	t.Run("should not apply a deadline when disabled", func(t *testing.T) {
+		SetQueryTimeout(0)
+
+		ctx, span := startSpan(context.Background(), "PgOrderRepository.FindAll")
+		defer span.End()
+
+		if _, ok := ctx.Deadline(); ok {
+			t.Error("expected no deadline")
+		}
+	})
+}
@@ -23,7 +23,10 @@ func NewPgAddressRepository(db *pgxpool.Pool) *PgAddressRepository {
 	}
 }
 
-func (r *PgAddressRepository) FindAll(pagination domain.Pagination) ([]domain.Address, domain.Pagination, error) {
+func (r *PgAddressRepository) FindAll(ctx context.Context, pagination domain.Pagination) ([]domain.Address, domain.Pagination, error) {
+	ctx, span := startSpan(ctx, "PgAddressRepository.FindAll")
+	defer span.End()
+
 	offset := pagination.Limit * (pagination.Page - 1)
 
 	baseQuery := r.qb
@@ -35,7 +38,7 @@ func (r *PgAddressRepository) FindAll(pagination domain.Pagination) ([]domain.Ad
 
 	var totalCount int
 
-	err = r.db.QueryRow(context.Background(), totalCountQuery, totalCountArgs...).Scan(&totalCount)
+	err = r.db.QueryRow(ctx, totalCountQuery, totalCountArgs...).Scan(&totalCount)
 	if err != nil {
 		return nil, domain.Pagination{}, fmt.Errorf("error on search total addresses: %v", err)
 	}
@@ -60,7 +63,7 @@ func (r *PgAddressRepository) FindAll(pagination domain.Pagination) ([]domain.Ad
 		return nil, domain.Pagination{}, fmt.Errorf("error on query build: %v", err)
 	}
 
-	rows, err := r.db.Query(context.Background(), query, args...)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, domain.Pagination{}, fmt.Errorf("error on search addresses: %v", err)
 	}
@@ -93,9 +96,12 @@ func (r *PgAddressRepository) FindAll(pagination domain.Pagination) ([]domain.Ad
 	return addresses, pagination, nil
 }
 
-func (r *PgAddressRepository) FindById(id string) (*domain.Address, error) {
+func (r *PgAddressRepository) FindById(ctx context.Context, id string) (*domain.Address, error) {
+	ctx, span := startSpan(ctx, "PgAddressRepository.FindById")
+	defer span.End()
+
 	var address domain.Address
-	err := r.db.QueryRow(context.Background(), `
+	err := r.db.QueryRow(ctx, `
 		SELECT
 			id,
 			old_id,
@@ -128,7 +134,10 @@ func (r *PgAddressRepository) FindById(id string) (*domain.Address, error) {
 	return &address, nil
 }
 
-func (r *PgAddressRepository) FindBy(filters map[string]interface{}) ([]domain.Address, error) {
+func (r *PgAddressRepository) FindBy(ctx context.Context, filters map[string]interface{}) ([]domain.Address, error) {
+	ctx, span := startSpan(ctx, "PgAddressRepository.FindBy")
+	defer span.End()
+
 	baseQuery := r.qb.Select(
 		"id",
 		"old_id",
@@ -151,7 +160,7 @@ func (r *PgAddressRepository) FindBy(filters map[string]interface{}) ([]domain.A
 		return nil, fmt.Errorf("error building query: %w", err)
 	}
 
-	rows, err := r.db.Query(context.Background(), query, args...)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("error searching addresses: %w", err)
 	}
@@ -182,7 +191,10 @@ func (r *PgAddressRepository) FindBy(filters map[string]interface{}) ([]domain.A
 	return addresses, nil
 }
 
-func (r *PgAddressRepository) FindByCustomerId(customerId string) ([]domain.Address, error) {
+func (r *PgAddressRepository) FindByCustomerId(ctx context.Context, customerId string) ([]domain.Address, error) {
+	ctx, span := startSpan(ctx, "PgAddressRepository.FindByCustomerId")
+	defer span.End()
+
 	query := `
 		SELECT
 			a.id,
@@ -202,7 +214,7 @@ func (r *PgAddressRepository) FindByCustomerId(customerId string) ([]domain.Addr
 		ORDER BY ac.is_default DESC
 	`
 
-	rows, err := r.db.Query(context.Background(), query, customerId)
+	rows, err := r.db.Query(ctx, query, customerId)
 	if err != nil {
 		return nil, fmt.Errorf("error searching addresses by customer id: %w", err)
 	}
@@ -234,9 +246,12 @@ func (r *PgAddressRepository) FindByCustomerId(customerId string) ([]domain.Addr
 	return addresses, nil
 }
 
-func (r *PgAddressRepository) Update(address domain.Address) error {
+func (r *PgAddressRepository) Update(ctx context.Context, address domain.Address) error {
+	ctx, span := startSpan(ctx, "PgAddressRepository.Update")
+	defer span.End()
+
 	// Get the current address to compare CEP and number
-	currentAddress, err := r.FindById(address.Id)
+	currentAddress, err := r.FindById(ctx, address.Id)
 	if err != nil {
 		return fmt.Errorf("error fetching current address: %v", err)
 	}
@@ -267,7 +282,7 @@ func (r *PgAddressRepository) Update(address domain.Address) error {
 		return fmt.Errorf("error building query to update address: %v", err)
 	}
 
-	result, err := r.db.Query(context.Background(), updateBuilder, args...)
+	result, err := r.db.Query(ctx, updateBuilder, args...)
 	if err != nil {
 		return fmt.Errorf("error updating address: %v", err)
 	}
@@ -276,9 +291,12 @@ func (r *PgAddressRepository) Update(address domain.Address) error {
 	return nil
 }
 
-func (r *PgAddressRepository) UpdateDefaultAddress(customerId string, addressId string) error {
+func (r *PgAddressRepository) UpdateDefaultAddress(ctx context.Context, customerId string, addressId string) error {
+	ctx, span := startSpan(ctx, "PgAddressRepository.UpdateDefaultAddress")
+	defer span.End()
+
 	// First, remove default flag from all customer addresses
-	_, err := r.db.Exec(context.Background(),
+	_, err := r.db.Exec(ctx,
 		"UPDATE address_customers SET is_default = false WHERE customer_id = $1",
 		customerId,
 	)
@@ -287,7 +305,7 @@ func (r *PgAddressRepository) UpdateDefaultAddress(customerId string, addressId
 	}
 
 	// Set the new default address
-	_, err = r.db.Exec(context.Background(),
+	_, err = r.db.Exec(ctx,
 		"UPDATE address_customers SET is_default = true WHERE customer_id = $1 AND address_id = $2",
 		customerId,
 		addressId,
@@ -299,9 +317,12 @@ func (r *PgAddressRepository) UpdateDefaultAddress(customerId string, addressId
 	return nil
 }
 
-func (r *PgAddressRepository) Delete(customerId string, addressId string) error {
+func (r *PgAddressRepository) Delete(ctx context.Context, customerId string, addressId string) error {
+	ctx, span := startSpan(ctx, "PgAddressRepository.Delete")
+	defer span.End()
+
 	// Remove only the relationship in address_customers table, keeping the address in addresses table
-	result, err := r.db.Exec(context.Background(),
+	result, err := r.db.Exec(ctx,
 		"DELETE FROM address_customers WHERE address_id = $1 AND customer_id = $2",
 		addressId, customerId)
 	if err != nil {
@@ -316,7 +337,10 @@ func (r *PgAddressRepository) Delete(customerId string, addressId string) error
 	return nil
 }
 
-func (r *PgAddressRepository) Create(customerId string, newAddress domain.NewAddress) (*domain.Address, error) {
+func (r *PgAddressRepository) Create(ctx context.Context, customerId string, newAddress domain.NewAddress) (*domain.Address, error) {
+	ctx, span := startSpan(ctx, "PgAddressRepository.Create")
+	defer span.End()
+
 	var addressId string
 
 	// Check if an address with the same CEP and number already exists
@@ -327,7 +351,7 @@ func (r *PgAddressRepository) Create(customerId string, newAddress domain.NewAdd
 		LIMIT 1
 	`
 
-	err := r.db.QueryRow(context.Background(), checkQuery, newAddress.Cep, newAddress.Number).Scan(&addressId)
+	err := r.db.QueryRow(ctx, checkQuery, newAddress.Cep, newAddress.Number).Scan(&addressId)
 	// If no existing address found, create a new one
 	if err != nil {
 		insertBuilder, args, errQB := r.qb.Insert("addresses").
@@ -348,7 +372,7 @@ func (r *PgAddressRepository) Create(customerId string, newAddress domain.NewAdd
 			return nil, fmt.Errorf("error building query to create address: %v", errQB)
 		}
 
-		errQuery := r.db.QueryRow(context.Background(), insertBuilder, args...).Scan(&addressId)
+		errQuery := r.db.QueryRow(ctx, insertBuilder, args...).Scan(&addressId)
 		if errQuery != nil {
 			return nil, fmt.Errorf("error creating address: %v", errQuery)
 		}
@@ -356,7 +380,7 @@ func (r *PgAddressRepository) Create(customerId string, newAddress domain.NewAdd
 
 	// If the address is marked as default, remove default flag from other addresses
 	if newAddress.IsDefault != nil && *newAddress.IsDefault {
-		_, err := r.db.Exec(context.Background(),
+		_, err := r.db.Exec(ctx,
 			"UPDATE address_customers SET is_default = false WHERE customer_id = $1",
 			customerId,
 		)
@@ -366,7 +390,7 @@ func (r *PgAddressRepository) Create(customerId string, newAddress domain.NewAdd
 	}
 
 	// Create the relationship between address and customer
-	_, err = r.db.Exec(context.Background(),
+	_, err = r.db.Exec(ctx,
 		"INSERT INTO address_customers (address_id, customer_id, is_default) VALUES ($1, $2, $3)",
 		addressId,
 		customerId,
@@ -381,7 +405,7 @@ func (r *PgAddressRepository) Create(customerId string, newAddress domain.NewAdd
 	}
 
 	// Fetch the created/existing address to return
-	createdAddress, err := r.FindById(addressId)
+	createdAddress, err := r.FindById(ctx, addressId)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching created address: %v", err)
 	}
@@ -0,0 +1,352 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PgBackgroundJobRepository struct {
+	db *pgxpool.Pool
+	qb squirrel.StatementBuilderType
+}
+
+func NewPgBackgroundJobRepository(db *pgxpool.Pool) *PgBackgroundJobRepository {
+	return &PgBackgroundJobRepository{
+		db: db,
+		qb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+var backgroundJobColumns = []string{
+	"id",
+	"kind",
+	"payload",
+	"status",
+	"attempts",
+	"max_attempts",
+	"run_at",
+	"locked_by",
+	"locked_at",
+	"last_error",
+	"created_at",
+	"finished_at",
+}
+
+// querier é satisfeito tanto pelo pool quanto por uma transação, permitindo gravar jobs
+// junto com outras escritas (outbox).
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+func (r *PgBackgroundJobRepository) Enqueue(ctx context.Context, job domain.NewBackgroundJob) (*domain.BackgroundJob, error) {
+	ctx, span := startSpan(ctx, "PgBackgroundJobRepository.Enqueue")
+	defer span.End()
+
+	query, args, err := backgroundJobInsert(r.qb, job)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := scanBackgroundJob(r.db.QueryRow(ctx, query+" RETURNING "+strings.Join(backgroundJobColumns, ", "), args...))
+	if err != nil {
+		return nil, fmt.Errorf("error enqueueing background job: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *PgBackgroundJobRepository) Claim(ctx context.Context, workerId string, kinds []string, limit int, lease time.Duration) ([]domain.BackgroundJob, error) {
+	ctx, span := startSpan(ctx, "PgBackgroundJobRepository.Claim")
+	defer span.End()
+
+	rows, err := r.db.Query(ctx, `
+		UPDATE background_jobs
+		SET status = 'running',
+			attempts = attempts + 1,
+			locked_by = $1,
+			locked_at = now(),
+			run_at = now() + make_interval(secs => $4)
+		WHERE id IN (
+			SELECT id
+			FROM background_jobs
+			WHERE status IN ('pending', 'running')
+				AND run_at <= now()
+				AND kind = ANY($2)
+			ORDER BY run_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+strings.Join(backgroundJobColumns, ", "),
+		workerId, kinds, limit, lease.Seconds(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error claiming background jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []domain.BackgroundJob{}
+	for rows.Next() {
+		job, err := scanBackgroundJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning background job: %w", err)
+		}
+		jobs = append(jobs, *job)
+	}
+
+	return jobs, rows.Err()
+}
+
+func (r *PgBackgroundJobRepository) Complete(ctx context.Context, job domain.BackgroundJob) error {
+	ctx, span := startSpan(ctx, "PgBackgroundJobRepository.Complete")
+	defer span.End()
+
+	query, args, err := r.qb.Update("background_jobs").
+		Set("status", domain.BackgroundJobDone).
+		Set("finished_at", squirrel.Expr("now()")).
+		Set("locked_by", nil).
+		Set("locked_at", nil).
+		Where(claimedBackgroundJob(job)).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("error building query to complete background job: %w", err)
+	}
+
+	result, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("error completing background job: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.NewBackgroundJobLeaseLostError(job.Id)
+	}
+	return nil
+}
+
+func (r *PgBackgroundJobRepository) Fail(ctx context.Context, job domain.BackgroundJob, errorMessage string, retryIn time.Duration, dead bool) error {
+	ctx, span := startSpan(ctx, "PgBackgroundJobRepository.Fail")
+	defer span.End()
+
+	updateBuilder := r.qb.Update("background_jobs").
+		Set("last_error", errorMessage).
+		Set("locked_by", nil).
+		Set("locked_at", nil).
+		Where(claimedBackgroundJob(job))
+
+	if dead {
+		updateBuilder = updateBuilder.
+			Set("status", domain.BackgroundJobDead).
+			Set("finished_at", squirrel.Expr("now()"))
+	} else {
+		updateBuilder = updateBuilder.
+			Set("status", domain.BackgroundJobPending).
+			Set("run_at", squirrel.Expr("now() + make_interval(secs => ?)", retryIn.Seconds()))
+	}
+
+	query, args, err := updateBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("error building query to fail background job: %w", err)
+	}
+
+	result, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("error failing background job: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.NewBackgroundJobLeaseLostError(job.Id)
+	}
+	return nil
+}
+
+// claimedBackgroundJob restringe a escrita à reserva feita pelo Claim: attempts muda a cada
+// reserva, então identifica a execução mesmo quando o mesmo worker reserva o job de novo.
+func claimedBackgroundJob(job domain.BackgroundJob) squirrel.Eq {
+	return squirrel.Eq{
+		"id":        job.Id,
+		"status":    domain.BackgroundJobRunning,
+		"locked_by": job.LockedBy,
+		"attempts":  job.Attempts,
+	}
+}
+
+func (r *PgBackgroundJobRepository) Stats(ctx context.Context) ([]domain.QueueStats, error) {
+	ctx, span := startSpan(ctx, "PgBackgroundJobRepository.Stats")
+	defer span.End()
+
+	rows, err := r.db.Query(ctx, `
+		SELECT
+			kind,
+			count(*) FILTER (WHERE status = 'pending'),
+			count(*) FILTER (WHERE status = 'running'),
+			count(*) FILTER (WHERE status = 'dead'),
+			min(created_at) FILTER (WHERE status = 'pending')
+		FROM background_jobs
+		WHERE status <> 'done'
+		GROUP BY kind
+		ORDER BY kind
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching queue stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := []domain.QueueStats{}
+	for rows.Next() {
+		var stat domain.QueueStats
+		if err := rows.Scan(&stat.Kind, &stat.Pending, &stat.Running, &stat.Dead, &stat.OldestPendingAt); err != nil {
+			return nil, fmt.Errorf("error scanning queue stats: %w", err)
+		}
+		stats = append(stats, stat)
+	}
+
+	return stats, rows.Err()
+}
+
+func (r *PgBackgroundJobRepository) FindDead(ctx context.Context, pagination domain.Pagination) ([]domain.BackgroundJob, domain.Pagination, error) {
+	ctx, span := startSpan(ctx, "PgBackgroundJobRepository.FindDead")
+	defer span.End()
+
+	offset := pagination.Limit * (pagination.Page - 1)
+
+	if err := r.db.QueryRow(ctx,
+		"SELECT count(*) FROM background_jobs WHERE status = 'dead'",
+	).Scan(&pagination.Total); err != nil {
+		return nil, domain.Pagination{}, fmt.Errorf("error counting dead background jobs: %w", err)
+	}
+
+	query, args, err := r.qb.Select(backgroundJobColumns...).
+		From("background_jobs").
+		Where(squirrel.Eq{"status": domain.BackgroundJobDead}).
+		OrderBy("finished_at DESC").
+		Limit(uint64(pagination.Limit)).
+		Offset(uint64(offset)).
+		ToSql()
+	if err != nil {
+		return nil, domain.Pagination{}, fmt.Errorf("error building dead background jobs query: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, domain.Pagination{}, fmt.Errorf("error fetching dead background jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []domain.BackgroundJob{}
+	for rows.Next() {
+		job, err := scanBackgroundJob(rows)
+		if err != nil {
+			return nil, domain.Pagination{}, fmt.Errorf("error scanning background job: %w", err)
+		}
+		jobs = append(jobs, *job)
+	}
+
+	return jobs, pagination, rows.Err()
+}
+
+func (r *PgBackgroundJobRepository) Retry(ctx context.Context, id string) error {
+	ctx, span := startSpan(ctx, "PgBackgroundJobRepository.Retry")
+	defer span.End()
+
+	result, err := r.db.Exec(ctx, `
+		UPDATE background_jobs
+		SET status = 'pending', attempts = 0, run_at = now(), finished_at = NULL
+		WHERE id = $1 AND status = 'dead'
+	`, id)
+	if err != nil {
+		return fmt.Errorf("error retrying background job: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.NewBackgroundJobNotFoundError(id)
+	}
+	return nil
+}
+
+// enqueueBackgroundJobs grava os jobs com o querier informado, normalmente a transação
+// da escrita que os originou.
+func enqueueBackgroundJobs(ctx context.Context, db querier, qb squirrel.StatementBuilderType, jobs []domain.NewBackgroundJob) error {
+	for _, job := range jobs {
+		query, args, err := backgroundJobInsert(qb, job)
+		if err != nil {
+			return err
+		}
+		if _, err := db.Exec(ctx, query, args...); err != nil {
+			return fmt.Errorf("error enqueueing %s job: %w", job.Kind, err)
+		}
+	}
+	return nil
+}
+
+// queueBackgroundJobs enfileira os jobs em um batch, para que sejam gravados no mesmo round trip
+// das outras escritas; execQueuedBackgroundJobs lê os resultados na mesma ordem.
+func queueBackgroundJobs(batch *pgx.Batch, qb squirrel.StatementBuilderType, jobs []domain.NewBackgroundJob) error {
+	for _, job := range jobs {
+		query, args, err := backgroundJobInsert(qb, job)
+		if err != nil {
+			return err
+		}
+		batch.Queue(query, args...)
+	}
+	return nil
+}
+
+func execQueuedBackgroundJobs(results pgx.BatchResults, jobs []domain.NewBackgroundJob) error {
+	for _, job := range jobs {
+		if _, err := results.Exec(); err != nil {
+			return fmt.Errorf("error enqueueing %s job: %w", job.Kind, err)
+		}
+	}
+	return nil
+}
+
+func backgroundJobInsert(qb squirrel.StatementBuilderType, job domain.NewBackgroundJob) (string, []any, error) {
+	payload, err := json.Marshal(job.Payload)
+	if err != nil {
+		return "", nil, fmt.Errorf("error encoding %s job payload: %w", job.Kind, err)
+	}
+
+	maxAttempts := job.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = domain.BackgroundJobDefaultMaxAttempts
+	}
+
+	query, args, err := qb.Insert("background_jobs").
+		Columns("kind", "payload", "max_attempts").
+		Values(job.Kind, payload, maxAttempts).
+		ToSql()
+	if err != nil {
+		return "", nil, fmt.Errorf("error building query to enqueue job: %w", err)
+	}
+
+	return query, args, nil
+}
+
+func scanBackgroundJob(row pgx.Row) (*domain.BackgroundJob, error) {
+	var job domain.BackgroundJob
+	var payload []byte
+	if err := row.Scan(
+		&job.Id,
+		&job.Kind,
+		&payload,
+		&job.Status,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&job.RunAt,
+		&job.LockedBy,
+		&job.LockedAt,
+		&job.LastError,
+		&job.CreatedAt,
+		&job.FinishedAt,
+	); err != nil {
+		return nil, err
+	}
+	job.Payload = payload
+	return &job, nil
+}
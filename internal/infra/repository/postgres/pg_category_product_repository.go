@@ -2,10 +2,12 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/deividr/zion-api/internal/domain"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -21,24 +23,28 @@ func NewPgCategoryProductRepository(db *pgxpool.Pool) *PgCategoryProductReposito
 	}
 }
 
-func (r *PgCategoryProductRepository) FindAll() ([]domain.CategoryProduct, error) {
-	totalCountQuery, totalCountArgs, err := r.qb.Select("count(*)").From("category_products").ToSql()
-	if err != nil {
-		return nil, fmt.Errorf("erro ao construir query de total: %v", err)
-	}
+func (r *PgCategoryProductRepository) FindAll(ctx context.Context) ([]domain.CategoryProduct, error) {
+	ctx, span := startSpan(ctx, "PgCategoryProductRepository.FindAll")
+	defer span.End()
 
-	var totalCount int
-	err = r.db.QueryRow(context.Background(), totalCountQuery, totalCountArgs...).Scan(&totalCount)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar total de categorias: %v", err)
-	}
+	productCountQuery := `(
+		SELECT count(*)
+		FROM products p
+		WHERE p.category_id = cp.id AND p.is_deleted = false
+	) AS product_count`
 
-	query, args, err := r.qb.Select("id", "name", "description").From("category_products").ToSql()
+	query, args, err := r.qb.
+		Select("cp.id", "cp.name", "cp.description", "cp.parent_id", "cp.display_order").
+		Column(productCountQuery).
+		From("category_products cp").
+		Where(squirrel.Eq{"cp.is_deleted": false}).
+		OrderBy("cp.display_order", "cp.name").
+		ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("erro ao construir query: %v", err)
 	}
 
-	rows, err := r.db.Query(context.Background(), query, args...)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao buscar categorias: %v", err)
 	}
@@ -48,7 +54,14 @@ func (r *PgCategoryProductRepository) FindAll() ([]domain.CategoryProduct, error
 
 	for rows.Next() {
 		var category domain.CategoryProduct
-		err := rows.Scan(&category.Id, &category.Name, &category.Description)
+		err := rows.Scan(
+			&category.Id,
+			&category.Name,
+			&category.Description,
+			&category.ParentId,
+			&category.DisplayOrder,
+			&category.ProductCount,
+		)
 		if err != nil {
 			return nil, fmt.Errorf("erro ao ler categoria: %v", err)
 		}
@@ -58,25 +71,50 @@ func (r *PgCategoryProductRepository) FindAll() ([]domain.CategoryProduct, error
 	return categories, nil
 }
 
-func (r *PgCategoryProductRepository) FindById(id string) (*domain.CategoryProduct, error) {
+func (r *PgCategoryProductRepository) FindById(ctx context.Context, id string) (*domain.CategoryProduct, error) {
+	ctx, span := startSpan(ctx, "PgCategoryProductRepository.FindById")
+	defer span.End()
+
 	var category domain.CategoryProduct
-	err := r.db.QueryRow(context.Background(), `SELECT id, name, description FROM category_products WHERE id = $1`, id).Scan(
+	err := r.db.QueryRow(ctx, `
+		SELECT cp.id, cp.name, cp.description, cp.parent_id, cp.display_order,
+			   (SELECT count(*) FROM products p WHERE p.category_id = cp.id AND p.is_deleted = false)
+		FROM category_products cp
+		WHERE cp.id = $1 AND cp.is_deleted = false
+	`, id).Scan(
 		&category.Id,
 		&category.Name,
 		&category.Description,
+		&category.ParentId,
+		&category.DisplayOrder,
+		&category.ProductCount,
 	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.NewCategoryNotFoundError(id)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("categoria não encontrada: %v", err)
+		return nil, fmt.Errorf("erro ao buscar categoria: %v", err)
 	}
 	return &category, nil
 }
 
-func (r *PgCategoryProductRepository) Update(category domain.CategoryProduct) error {
-	updateBuilder, args, err := r.qb.Update("category_products").Set("name", category.Name).Set("description", category.Description).Where(squirrel.Eq{"id": category.Id}).ToSql()
+func (r *PgCategoryProductRepository) Update(ctx context.Context, category domain.CategoryProduct) error {
+	ctx, span := startSpan(ctx, "PgCategoryProductRepository.Update")
+	defer span.End()
+
+	updateBuilder, args, err := r.qb.
+		Update("category_products").
+		Set("name", category.Name).
+		Set("description", category.Description).
+		Set("parent_id", category.ParentId).
+		Set("display_order", category.DisplayOrder).
+		Where(squirrel.Eq{"id": category.Id}).
+		Where(squirrel.Eq{"is_deleted": false}).
+		ToSql()
 	if err != nil {
 		return fmt.Errorf("erro ao construir query para atualizar a categoria: %v", err)
 	}
-	result, err := r.db.Query(context.Background(), updateBuilder, args...)
+	result, err := r.db.Query(ctx, updateBuilder, args...)
 	if err != nil {
 		return fmt.Errorf("erro ao atualizar categoria: %v", err)
 	}
@@ -84,29 +122,93 @@ func (r *PgCategoryProductRepository) Update(category domain.CategoryProduct) er
 	return nil
 }
 
-func (r *PgCategoryProductRepository) Delete(id string) error {
-	result, err := r.db.Query(context.Background(), "DELETE FROM category_products WHERE id = $1", id)
+// Delete faz o soft delete da categoria. Se reassignTo for informado, os produtos
+// são movidos para essa categoria; caso contrário a exclusão é recusada quando
+// ainda existem produtos vinculados. Subcategorias sobem um nível na hierarquia.
+func (r *PgCategoryProductRepository) Delete(ctx context.Context, id string, reassignTo *string) error {
+	ctx, span := startSpan(ctx, "PgCategoryProductRepository.Delete")
+	defer span.End()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var parentId *string
+	err = tx.QueryRow(ctx,
+		"SELECT parent_id FROM category_products WHERE id = $1 AND is_deleted = false FOR UPDATE",
+		id,
+	).Scan(&parentId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.NewCategoryNotFoundError(id)
+	}
 	if err != nil {
+		return fmt.Errorf("erro ao buscar categoria: %v", err)
+	}
+
+	if reassignTo != nil {
+		// Só os produtos ativos mudam de categoria; os excluídos ficam como estavam
+		if _, err := tx.Exec(ctx,
+			"UPDATE products SET category_id = $1 WHERE category_id = $2 AND is_deleted = false",
+			*reassignTo, id,
+		); err != nil {
+			return fmt.Errorf("erro ao mover produtos da categoria: %v", err)
+		}
+	} else {
+		var productCount int
+		err := tx.QueryRow(ctx,
+			"SELECT count(*) FROM products WHERE category_id = $1 AND is_deleted = false",
+			id,
+		).Scan(&productCount)
+		if err != nil {
+			return fmt.Errorf("erro ao contar produtos da categoria: %v", err)
+		}
+		if productCount > 0 {
+			return domain.NewCategoryHasProductsError(id, productCount)
+		}
+	}
+
+	if _, err := tx.Exec(ctx,
+		"UPDATE category_products SET parent_id = $1 WHERE parent_id = $2",
+		parentId, id,
+	); err != nil {
+		return fmt.Errorf("erro ao mover subcategorias: %v", err)
+	}
+
+	if _, err := tx.Exec(ctx,
+		"UPDATE category_products SET is_deleted = true WHERE id = $1",
+		id,
+	); err != nil {
 		return fmt.Errorf("erro ao deletar categoria: %v", err)
 	}
-	defer result.Close()
-	return nil
+
+	return tx.Commit(ctx)
 }
 
-func (r *PgCategoryProductRepository) Create(category domain.CategoryProduct) (*domain.CategoryProduct, error) {
-	insertBuilder, args, errQB := r.qb.Insert("category_products").Columns("name", "description").Values(&category.Name, &category.Description).Suffix("RETURNING id").ToSql()
+func (r *PgCategoryProductRepository) Create(ctx context.Context, category domain.CategoryProduct) (*domain.CategoryProduct, error) {
+	ctx, span := startSpan(ctx, "PgCategoryProductRepository.Create")
+	defer span.End()
+
+	insertBuilder, args, errQB := r.qb.Insert("category_products").
+		Columns("name", "description", "parent_id", "display_order").
+		Values(&category.Name, &category.Description, &category.ParentId, &category.DisplayOrder).
+		Suffix("RETURNING id").
+		ToSql()
 	if errQB != nil {
 		return nil, fmt.Errorf("erro ao construir query para criar a categoria: %v", errQB)
 	}
 	var id string
-	errQuery := r.db.QueryRow(context.Background(), insertBuilder, args...).Scan(&id)
+	errQuery := r.db.QueryRow(ctx, insertBuilder, args...).Scan(&id)
 	if errQuery != nil {
 		return nil, fmt.Errorf("erro ao criar categoria: %v", errQuery)
 	}
 	createdCategory := &domain.CategoryProduct{
-		Id:          id,
-		Name:        category.Name,
-		Description: category.Description,
+		Id:           id,
+		Name:         category.Name,
+		Description:  category.Description,
+		ParentId:     category.ParentId,
+		DisplayOrder: category.DisplayOrder,
 	}
 	return createdCategory, nil
 }
@@ -0,0 +1,352 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// nameSimilarityThreshold é a similaridade mínima entre nomes para que dois clientes
+// sejam considerados possíveis duplicados apenas pelo nome.
+const nameSimilarityThreshold = 0.8
+
+type PgCustomerMergeRepository struct {
+	db *pgxpool.Pool
+	qb squirrel.StatementBuilderType
+}
+
+func NewPgCustomerMergeRepository(db *pgxpool.Pool) *PgCustomerMergeRepository {
+	return &PgCustomerMergeRepository{
+		db: db,
+		qb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+// FindDuplicates monta os pares a partir dos índices: telefones pelos índices únicos de phone e
+// phone2, nomes pelo índice de trigramas de idx_customers_name_trgm e endereços pela chave de
+// address_customers. O limite de similaridade vale só para a transação, para que o operador %
+// já descarte pelo índice os nomes distantes.
+func (r *PgCustomerMergeRepository) FindDuplicates(ctx context.Context, limit int) ([]domain.DuplicateCandidate, error) {
+	ctx, span := startSpan(ctx, "PgCustomerMergeRepository.FindDuplicates")
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx,
+		"SELECT set_config('pg_trgm.similarity_threshold', $1, true)",
+		strconv.FormatFloat(nameSimilarityThreshold, 'f', -1, 64),
+	); err != nil {
+		return nil, fmt.Errorf("error setting name similarity threshold: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, duplicatesQuery, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error searching duplicate customers: %w", err)
+	}
+	defer rows.Close()
+
+	candidates := []domain.DuplicateCandidate{}
+	for rows.Next() {
+		var candidate domain.DuplicateCandidate
+		if err := rows.Scan(
+			&candidate.Customer.Id,
+			&candidate.Customer.Name,
+			&candidate.Customer.Phone,
+			&candidate.Customer.Phone2,
+			&candidate.Customer.Email,
+			&candidate.Customer.CreatedAt,
+			&candidate.Duplicate.Id,
+			&candidate.Duplicate.Name,
+			&candidate.Duplicate.Phone,
+			&candidate.Duplicate.Phone2,
+			&candidate.Duplicate.Email,
+			&candidate.Duplicate.CreatedAt,
+			&candidate.Reasons,
+			&candidate.NameSimilarity,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning duplicate customer: %w", err)
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, rows.Err()
+}
+
+// duplicatesQuery lista os pares sem passar por uma CTE com todos os clientes, que seria
+// materializada e obrigaria a comparar cada cliente com todos os outros. Telefones são únicos por
+// coluna, então entre os já normalizados um par só acontece entre phone e phone2. Os clientes
+// cujo telefone normalizado já era de outro cliente ficaram com o valor original na migration de
+// normalização e vêm de customer_phone_collisions.
+const duplicatesQuery = `
+	WITH pairs AS (
+		SELECT least(a.id, b.id) AS customer_id, greatest(a.id, b.id) AS duplicate_id, 'same_phone' AS reason
+		FROM customers a
+		JOIN customers b ON b.phone2 = a.phone AND b.id <> a.id
+		WHERE a.is_deleted = false AND b.is_deleted = false
+		UNION ALL
+		SELECT least(a.id, b.id), greatest(a.id, b.id), 'same_phone'
+		FROM customer_phone_collisions pc
+		JOIN customers a ON a.id = pc.customer_id AND a.is_deleted = false
+		JOIN customers b ON b.id = pc.conflicting_customer_id AND b.is_deleted = false
+		WHERE a.id <> b.id
+		UNION ALL
+		SELECT a.id, b.id, 'similar_name'
+		FROM customers a
+		JOIN customers b ON immutable_unaccent(b.name) % immutable_unaccent(a.name) AND a.id < b.id
+		WHERE a.is_deleted = false AND b.is_deleted = false
+		UNION ALL
+		SELECT ac1.customer_id, ac2.customer_id, 'shared_address'
+		FROM address_customers ac1
+		JOIN address_customers ac2 ON ac2.address_id = ac1.address_id AND ac1.customer_id < ac2.customer_id
+		JOIN customers a ON a.id = ac1.customer_id AND a.is_deleted = false
+		JOIN customers b ON b.id = ac2.customer_id AND b.is_deleted = false
+	)
+	SELECT a.id, a.name, a.phone, a.phone2, a.email, a.created_at,
+		   b.id, b.name, b.phone, b.phone2, b.email, b.created_at,
+		   array_agg(DISTINCT p.reason ORDER BY p.reason),
+		   similarity(immutable_unaccent(a.name), immutable_unaccent(b.name))::float8 AS name_similarity
+	FROM pairs p
+	JOIN customers a ON a.id = p.customer_id
+	JOIN customers b ON b.id = p.duplicate_id
+	GROUP BY a.id, b.id
+	ORDER BY count(DISTINCT p.reason) DESC, name_similarity DESC
+	LIMIT $1
+`
+
+func (r *PgCustomerMergeRepository) FindMerges(ctx context.Context, pagination domain.Pagination) ([]domain.CustomerMerge, domain.Pagination, error) {
+	ctx, span := startSpan(ctx, "PgCustomerMergeRepository.FindMerges")
+	defer span.End()
+
+	offset := pagination.Limit * (pagination.Page - 1)
+
+	var totalCount int
+	if err := r.db.QueryRow(ctx, "SELECT count(*) FROM customer_merges").Scan(&totalCount); err != nil {
+		return nil, domain.Pagination{}, fmt.Errorf("error fetching total number of merges: %w", err)
+	}
+
+	query, args, err := r.qb.
+		Select(
+			"id",
+			"survivor_id",
+			"merged_customer_ids::text[]",
+			"merged_customers",
+			"moved_orders",
+			"moved_addresses",
+			"merged_by",
+			"created_at",
+		).
+		From("customer_merges").
+		OrderBy("created_at DESC").
+		Limit(uint64(pagination.Limit)).
+		Offset(uint64(offset)).
+		ToSql()
+	if err != nil {
+		return nil, domain.Pagination{}, fmt.Errorf("error building merges query: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, domain.Pagination{}, fmt.Errorf("error fetching merges: %w", err)
+	}
+	defer rows.Close()
+
+	merges := []domain.CustomerMerge{}
+	for rows.Next() {
+		var merge domain.CustomerMerge
+		var mergedCustomersJSON []byte
+		if err := rows.Scan(
+			&merge.Id,
+			&merge.SurvivorId,
+			&merge.MergedCustomerIds,
+			&mergedCustomersJSON,
+			&merge.MovedOrders,
+			&merge.MovedAddresses,
+			&merge.MergedBy,
+			&merge.CreatedAt,
+		); err != nil {
+			return nil, domain.Pagination{}, fmt.Errorf("error scanning merge: %w", err)
+		}
+		if err := json.Unmarshal(mergedCustomersJSON, &merge.MergedCustomers); err != nil {
+			return nil, domain.Pagination{}, fmt.Errorf("error unmarshaling merged customers: %w", err)
+		}
+		merges = append(merges, merge)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domain.Pagination{}, fmt.Errorf("error iterating merge rows: %w", err)
+	}
+
+	pagination.Total = totalCount
+	return merges, pagination, nil
+}
+
+// Merge move pedidos e endereços dos clientes duplicados para o cliente sobrevivente,
+// faz o soft delete dos duplicados e registra o que foi unificado, tudo na mesma transação.
+func (r *PgCustomerMergeRepository) Merge(ctx context.Context, survivorId string, mergedIds []string, mergedBy string) (*domain.CustomerMerge, error) {
+	ctx, span := startSpan(ctx, "PgCustomerMergeRepository.Merge")
+	defer span.End()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var survivor domain.Customer
+	err = tx.QueryRow(ctx, `
+		SELECT id, name, phone, phone2, email
+		FROM customers
+		WHERE id = $1 AND is_deleted = false
+		FOR UPDATE
+	`, survivorId).Scan(&survivor.Id, &survivor.Name, &survivor.Phone, &survivor.Phone2, &survivor.Email)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.NewCustomerNotFoundError(survivorId)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching survivor customer: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT id, name, phone, phone2, email, created_at
+		FROM customers
+		WHERE id = ANY($1::uuid[]) AND is_deleted = false
+		FOR UPDATE
+	`, mergedIds)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching merged customers: %w", err)
+	}
+
+	mergedCustomers := []domain.Customer{}
+	for rows.Next() {
+		var customer domain.Customer
+		if err := rows.Scan(&customer.Id, &customer.Name, &customer.Phone, &customer.Phone2, &customer.Email, &customer.CreatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning merged customer: %w", err)
+		}
+		mergedCustomers = append(mergedCustomers, customer)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating merged customers: %w", err)
+	}
+
+	for _, id := range mergedIds {
+		if !slices.ContainsFunc(mergedCustomers, func(c domain.Customer) bool { return c.Id == id }) {
+			return nil, domain.NewCustomerNotFoundError(id)
+		}
+	}
+
+	ordersResult, err := tx.Exec(ctx,
+		"UPDATE orders SET customer_id = $1, updated_at = now() WHERE customer_id = ANY($2::uuid[])",
+		survivorId, mergedIds,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error moving orders: %w", err)
+	}
+
+	addressesResult, err := tx.Exec(ctx, `
+		INSERT INTO address_customers (address_id, customer_id, is_default)
+		SELECT DISTINCT address_id, $1::uuid, false
+		FROM address_customers
+		WHERE customer_id = ANY($2::uuid[])
+		ON CONFLICT (address_id, customer_id) DO NOTHING
+	`, survivorId, mergedIds)
+	if err != nil {
+		return nil, fmt.Errorf("error moving addresses: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx,
+		"DELETE FROM address_customers WHERE customer_id = ANY($1::uuid[])",
+		mergedIds,
+	); err != nil {
+		return nil, fmt.Errorf("error removing merged addresses relationship: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx,
+		"UPDATE customer_notes SET customer_id = $1 WHERE customer_id = ANY($2::uuid[])",
+		survivorId, mergedIds,
+	); err != nil {
+		return nil, fmt.Errorf("error moving customer notes: %w", err)
+	}
+
+	// Une as tags e preferências alimentares de todos os clientes unificados
+	if _, err := tx.Exec(ctx, `
+		UPDATE customers s
+		SET tags = ARRAY(
+				SELECT DISTINCT t
+				FROM customers c, unnest(c.tags) t
+				WHERE c.id = s.id OR c.id = ANY($2::uuid[])
+			),
+			dietary_preferences = ARRAY(
+				SELECT DISTINCT d
+				FROM customers c, unnest(c.dietary_preferences) d
+				WHERE c.id = s.id OR c.id = ANY($2::uuid[])
+			)
+		WHERE s.id = $1
+	`, survivorId, mergedIds); err != nil {
+		return nil, fmt.Errorf("error merging customer preferences: %w", err)
+	}
+
+	// Aproveita o e-mail de um duplicado quando o sobrevivente não tiver
+	if survivor.Email == nil || *survivor.Email == "" {
+		for _, customer := range mergedCustomers {
+			if customer.Email != nil && *customer.Email != "" {
+				if _, err := tx.Exec(ctx,
+					"UPDATE customers SET email = $1 WHERE id = $2",
+					*customer.Email, survivorId,
+				); err != nil {
+					return nil, fmt.Errorf("error updating survivor email: %w", err)
+				}
+				break
+			}
+		}
+	}
+
+	if _, err := tx.Exec(ctx,
+		"UPDATE customers SET is_deleted = true, merged_into = $1, updated_at = now() WHERE id = ANY($2::uuid[])",
+		survivorId, mergedIds,
+	); err != nil {
+		return nil, fmt.Errorf("error deleting merged customers: %w", err)
+	}
+
+	mergedCustomersJSON, err := json.Marshal(mergedCustomers)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling merged customers: %w", err)
+	}
+
+	merge := domain.CustomerMerge{
+		SurvivorId:        survivorId,
+		MergedCustomerIds: mergedIds,
+		MergedCustomers:   mergedCustomers,
+		MovedOrders:       int(ordersResult.RowsAffected()),
+		MovedAddresses:    int(addressesResult.RowsAffected()),
+		MergedBy:          mergedBy,
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO customer_merges (survivor_id, merged_customer_ids, merged_customers, moved_orders, moved_addresses, merged_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, survivorId, mergedIds, mergedCustomersJSON, merge.MovedOrders, merge.MovedAddresses, mergedBy).Scan(&merge.Id, &merge.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("error recording merge: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return &merge, nil
+}
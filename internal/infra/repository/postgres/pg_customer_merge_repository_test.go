@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestPgCustomerMergeRepository_FindDuplicates(t *testing.T) {
+	pool := fixturePool(t)
+
+	const (
+		normalized   = "00000000-0000-4000-8000-000000000a01"
+		collided     = "00000000-0000-4000-8000-000000000a02"
+		withPhone2   = "00000000-0000-4000-8000-000000000a03"
+		sharedPhone2 = "00000000-0000-4000-8000-000000000a04"
+		accented     = "00000000-0000-4000-8000-000000000a05"
+		unaccented   = "00000000-0000-4000-8000-000000000a06"
+		unrelated    = "00000000-0000-4000-8000-000000000a07"
+		deleted      = "00000000-0000-4000-8000-000000000a08"
+	)
+	ids := `'{` + normalized + `,` + collided + `,` + withPhone2 + `,` + sharedPhone2 + `,` + accented + `,` + unaccented + `,` + unrelated + `,` + deleted + `}'::uuid[]`
+	cleanup := func() {
+		fixtureExec(t, pool,
+			"DELETE FROM customer_phone_collisions WHERE customer_id = ANY("+ids+") OR conflicting_customer_id = ANY("+ids+")",
+			"DELETE FROM customers WHERE id = ANY("+ids+")",
+		)
+	}
+	cleanup()
+	t.Cleanup(cleanup)
+
+	fixtureExec(t, pool,
+		`INSERT INTO customers (id, name, phone, phone2, is_deleted) VALUES
+			('`+normalized+`', 'Amanda Quintela Fixture', '+5511990000001', NULL, false),
+			('`+collided+`', 'Bernardo Ximenes Fixture', '(11) 99000-0001', NULL, false),
+			('`+withPhone2+`', 'Cecília Ulhoa Fixture', '+5511990000002', NULL, false),
+			('`+sharedPhone2+`', 'Diogo Yamamoto Fixture', '+5511990000003', '+5511990000002', false),
+			('`+accented+`', 'Eduarda Conceição Vasconcelos', '+5511990000004', NULL, false),
+			('`+unaccented+`', 'Eduarda Conceicao Vasconcelos', '+5511990000005', NULL, false),
+			('`+unrelated+`', 'Gustavo Zanetti Fixture', '+5511990000006', NULL, false),
+			('`+deleted+`', 'Gustavo Zanetti Fixture', '+5511990000007', '+5511990000006', true)`,
+		// A migration de normalização deixou o telefone original e registrou o conflito
+		`INSERT INTO customer_phone_collisions (customer_id, column_name, original_phone, normalized_phone, conflicting_customer_id)
+			VALUES ('`+collided+`', 'phone', '(11) 99000-0001', '+5511990000001', '`+normalized+`')`,
+	)
+
+	candidates, err := NewPgCustomerMergeRepository(pool).FindDuplicates(context.Background(), 1000)
+	if err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+
+	// reasons indexa os pares encontrados entre os clientes do teste, na ordem de id
+	reasons := map[[2]string][]string{}
+	fixtureIds := []string{normalized, collided, withPhone2, sharedPhone2, accented, unaccented, unrelated, deleted}
+	for _, candidate := range candidates {
+		if slices.Contains(fixtureIds, candidate.Customer.Id) || slices.Contains(fixtureIds, candidate.Duplicate.Id) {
+			reasons[[2]string{candidate.Customer.Id, candidate.Duplicate.Id}] = candidate.Reasons
+		}
+	}
+
+	expected := map[[2]string][]string{
+		{normalized, collided}:     {"same_phone"},
+		{withPhone2, sharedPhone2}: {"same_phone"},
+		{accented, unaccented}:     {"similar_name"},
+	}
+
+	t.Run("should pair customers by phone, collided phone and name", func(t *testing.T) {
+		for pair, want := range expected {
+			if got, ok := reasons[pair]; !ok || !slices.Equal(got, want) {
+				t.Errorf("expected %v to be paired by %v, but got %v", pair, want, got)
+			}
+		}
+	})
+
+	t.Run("should not pair deleted or unrelated customers", func(t *testing.T) {
+		for pair, got := range reasons {
+			if _, ok := expected[pair]; !ok {
+				t.Errorf("unexpected pair %v by %v", pair, got)
+			}
+		}
+	})
+}
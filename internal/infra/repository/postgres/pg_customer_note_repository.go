@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PgCustomerNoteRepository struct {
+	db *pgxpool.Pool
+	qb squirrel.StatementBuilderType
+}
+
+func NewPgCustomerNoteRepository(db *pgxpool.Pool) *PgCustomerNoteRepository {
+	return &PgCustomerNoteRepository{
+		db: db,
+		qb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+func (r *PgCustomerNoteRepository) FindByCustomerId(ctx context.Context, customerId string) ([]domain.CustomerNote, error) {
+	ctx, span := startSpan(ctx, "PgCustomerNoteRepository.FindByCustomerId")
+	defer span.End()
+
+	query, args, err := r.qb.
+		Select("id", "customer_id", "content", "author", "is_alert", "created_at").
+		From("customer_notes").
+		Where(squirrel.Eq{"customer_id": customerId}).
+		OrderBy("is_alert DESC", "created_at DESC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building customer notes query: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching customer notes: %w", err)
+	}
+	defer rows.Close()
+
+	notes := []domain.CustomerNote{}
+	for rows.Next() {
+		var note domain.CustomerNote
+		if err := rows.Scan(
+			&note.Id,
+			&note.CustomerId,
+			&note.Content,
+			&note.Author,
+			&note.IsAlert,
+			&note.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning customer note: %w", err)
+		}
+		notes = append(notes, note)
+	}
+
+	return notes, rows.Err()
+}
+
+func (r *PgCustomerNoteRepository) Create(ctx context.Context, customerId string, author string, newNote domain.NewCustomerNote) (*domain.CustomerNote, error) {
+	ctx, span := startSpan(ctx, "PgCustomerNoteRepository.Create")
+	defer span.End()
+
+	insertBuilder, args, err := r.qb.Insert("customer_notes").
+		Columns("customer_id", "content", "author", "is_alert").
+		Values(customerId, newNote.Content, author, newNote.IsAlert).
+		Suffix("RETURNING id, created_at").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building query to create customer note: %w", err)
+	}
+
+	note := domain.CustomerNote{
+		CustomerId: customerId,
+		Content:    newNote.Content,
+		Author:     author,
+		IsAlert:    newNote.IsAlert,
+	}
+	if err := r.db.QueryRow(ctx, insertBuilder, args...).Scan(&note.Id, &note.CreatedAt); err != nil {
+		return nil, fmt.Errorf("error creating customer note: %w", err)
+	}
+
+	return &note, nil
+}
+
+func (r *PgCustomerNoteRepository) Delete(ctx context.Context, customerId string, noteId string) error {
+	ctx, span := startSpan(ctx, "PgCustomerNoteRepository.Delete")
+	defer span.End()
+
+	result, err := r.db.Exec(ctx,
+		"DELETE FROM customer_notes WHERE id = $1 AND customer_id = $2",
+		noteId, customerId,
+	)
+	if err != nil {
+		return fmt.Errorf("error deleting customer note: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.NewCustomerNoteNotFoundError(noteId)
+	}
+
+	return nil
+}
@@ -2,10 +2,13 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/deividr/zion-api/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -21,7 +24,10 @@ func NewPgCustomerRepository(db *pgxpool.Pool) *PgCustomerRepository {
 	}
 }
 
-func (r *PgCustomerRepository) FindAll(pagination domain.Pagination, filters domain.FindAllCustomerFilters) ([]domain.Customer, domain.Pagination, error) {
+func (r *PgCustomerRepository) FindAll(ctx context.Context, pagination domain.Pagination, filters domain.FindAllCustomerFilters) ([]domain.Customer, domain.Pagination, error) {
+	ctx, span := startSpan(ctx, "PgCustomerRepository.FindAll")
+	defer span.End()
+
 	offset := pagination.Limit * (pagination.Page - 1)
 
 	baseQuery := r.qb.
@@ -30,18 +36,18 @@ func (r *PgCustomerRepository) FindAll(pagination domain.Pagination, filters dom
 	var filterConditions []squirrel.Sqlizer
 
 	if filters.Name != "" {
-		filterConditions = append(filterConditions, squirrel.ILike{"name": "%" + filters.Name + "%"})
+		filterConditions = append(filterConditions, textMatch("name", filters.Name))
 	}
 
-	if filters.Phone != "" {
+	if cond := phoneMatch("phone", filters.Phone); cond != nil {
 		filterConditions = append(filterConditions, squirrel.Or{
-			squirrel.ILike{"phone": "%" + filters.Phone + "%"},
-			squirrel.ILike{"phone2": "%" + filters.Phone + "%"},
+			cond,
+			phoneMatch("phone2", filters.Phone),
 		})
 	}
 
 	if filters.Email != "" {
-		filterConditions = append(filterConditions, squirrel.ILike{"email": "%" + filters.Email + "%"})
+		filterConditions = append(filterConditions, textMatch("email", filters.Email))
 	}
 
 	if len(filterConditions) > 0 {
@@ -63,14 +69,20 @@ func (r *PgCustomerRepository) FindAll(pagination domain.Pagination, filters dom
 
 	var totalCount int
 
-	err = r.db.QueryRow(context.Background(), totalCountQuery, totalCountArgs...).Scan(&totalCount)
+	err = r.db.QueryRow(ctx, totalCountQuery, totalCountArgs...).Scan(&totalCount)
 	if err != nil {
 		return nil, domain.Pagination{}, fmt.Errorf("erro ao buscar total de clientes: %v", err)
 	}
 
-	query, args, err := baseQuery.
-		Select("id", "name", "phone", "phone2", "email").
-		From("customers").
+	selectQuery := baseQuery.
+		Select("id", "name", "phone", "phone2", "email", "tags", "dietary_preferences").
+		From("customers")
+
+	if filters.Name != "" {
+		selectQuery = selectQuery.OrderByClause(rankExpr("name"), filters.Name)
+	}
+
+	query, args, err := selectQuery.
 		Limit(uint64(pagination.Limit)).
 		Offset(uint64(offset)).
 		ToSql()
@@ -78,7 +90,7 @@ func (r *PgCustomerRepository) FindAll(pagination domain.Pagination, filters dom
 		return nil, domain.Pagination{}, fmt.Errorf("erro ao construir query: %v", err)
 	}
 
-	rows, err := r.db.Query(context.Background(), query, args...)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, domain.Pagination{}, fmt.Errorf("erro ao buscar clientes: %v", err)
 	}
@@ -94,6 +106,8 @@ func (r *PgCustomerRepository) FindAll(pagination domain.Pagination, filters dom
 			&customer.Phone,
 			&customer.Phone2,
 			&customer.Email,
+			&customer.Tags,
+			&customer.DietaryPreferences,
 		)
 		if err != nil {
 			return nil, domain.Pagination{}, fmt.Errorf("erro ao ler cliente: %v", err)
@@ -106,10 +120,13 @@ func (r *PgCustomerRepository) FindAll(pagination domain.Pagination, filters dom
 	return customers, pagination, nil
 }
 
-func (r *PgCustomerRepository) FindById(id string) (*domain.Customer, error) {
+func (r *PgCustomerRepository) FindById(ctx context.Context, id string) (*domain.Customer, error) {
+	ctx, span := startSpan(ctx, "PgCustomerRepository.FindById")
+	defer span.End()
+
 	var customer domain.Customer
-	err := r.db.QueryRow(context.Background(), `
-		SELECT id, name, phone, phone2, email
+	err := r.db.QueryRow(ctx, `
+		SELECT id, name, phone, phone2, email, tags, dietary_preferences
 		FROM customers
 		WHERE id = $1 AND is_deleted = false
 	`, id).Scan(
@@ -118,15 +135,53 @@ func (r *PgCustomerRepository) FindById(id string) (*domain.Customer, error) {
 		&customer.Phone,
 		&customer.Phone2,
 		&customer.Email,
+		&customer.Tags,
+		&customer.DietaryPreferences,
 	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.NewCustomerNotFoundError(id)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("cliente não encontrado: %v", err)
+		return nil, fmt.Errorf("erro ao buscar cliente: %v", err)
 	}
 
 	return &customer, nil
 }
 
-func (r *PgCustomerRepository) Update(customer domain.Customer) error {
+func (r *PgCustomerRepository) FindByPhone(ctx context.Context, phone string) (*domain.Customer, error) {
+	ctx, span := startSpan(ctx, "PgCustomerRepository.FindByPhone")
+	defer span.End()
+
+	var customer domain.Customer
+	err := r.db.QueryRow(ctx, `
+		SELECT id, name, phone, phone2, email, tags, dietary_preferences
+		FROM customers
+		WHERE (phone = $1 OR phone2 = $1) AND is_deleted = false
+		ORDER BY phone = $1 DESC
+		LIMIT 1
+	`, phone).Scan(
+		&customer.Id,
+		&customer.Name,
+		&customer.Phone,
+		&customer.Phone2,
+		&customer.Email,
+		&customer.Tags,
+		&customer.DietaryPreferences,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.NewCustomerNotFoundError(phone)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar cliente por telefone: %v", err)
+	}
+
+	return &customer, nil
+}
+
+func (r *PgCustomerRepository) Update(ctx context.Context, customer domain.Customer, jobs []domain.NewBackgroundJob) error {
+	ctx, span := startSpan(ctx, "PgCustomerRepository.Update")
+	defer span.End()
+
 	updateBuilder, args, err := r.qb.
 		Update("customers").Set("name", customer.Name).
 		Set("phone", customer.Phone).
@@ -139,32 +194,101 @@ func (r *PgCustomerRepository) Update(customer domain.Customer) error {
 		return fmt.Errorf("erro ao construir query para atualizar o cliente: %v", err)
 	}
 
-	result, err := r.db.Query(context.Background(), updateBuilder, args...)
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %v", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, updateBuilder, args...); err != nil {
+		if isUniqueViolation(err) {
+			return duplicatePhoneError(err, customer.Phone, customer.Phone2)
+		}
 		return fmt.Errorf("erro ao atualizar cliente: %v", err)
 	}
-	defer result.Close()
 
+	if err := enqueueBackgroundJobs(ctx, tx, r.qb, jobs); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %v", err)
+	}
 	return nil
 }
 
-func (r *PgCustomerRepository) Delete(id string) error {
-	result, err := r.db.Query(context.Background(), "UPDATE customers SET is_deleted = true WHERE id = $1", id)
+func (r *PgCustomerRepository) UpdatePreferences(ctx context.Context, id string, preferences domain.CustomerPreferences) error {
+	ctx, span := startSpan(ctx, "PgCustomerRepository.UpdatePreferences")
+	defer span.End()
+
+	updateBuilder, args, err := r.qb.
+		Update("customers").
+		Set("tags", preferences.Tags).
+		Set("dietary_preferences", preferences.DietaryPreferences).
+		Set("updated_at", squirrel.Expr("now()")).
+		Where(squirrel.Eq{"id": id}).
+		Where(squirrel.Eq{"is_deleted": false}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("erro ao construir query para atualizar as preferências do cliente: %v", err)
+	}
+
+	result, err := r.db.Exec(ctx, updateBuilder, args...)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar preferências do cliente: %v", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("cliente não encontrado")
+	}
+
+	return nil
+}
+
+func (r *PgCustomerRepository) Delete(ctx context.Context, id string, jobs []domain.NewBackgroundJob) error {
+	ctx, span := startSpan(ctx, "PgCustomerRepository.Delete")
+	defer span.End()
+
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %v", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, "UPDATE customers SET is_deleted = true WHERE id = $1", id); err != nil {
 		return fmt.Errorf("erro ao deletar cliente: %v", err)
 	}
-	defer result.Close()
+
+	if err := enqueueBackgroundJobs(ctx, tx, r.qb, jobs); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %v", err)
+	}
 	return nil
 }
 
-func (r *PgCustomerRepository) Create(newCustomer domain.NewCustomer) (*domain.Customer, error) {
+func (r *PgCustomerRepository) Create(ctx context.Context, newCustomer domain.NewCustomer, jobs []domain.NewBackgroundJob) (*domain.Customer, error) {
+	ctx, span := startSpan(ctx, "PgCustomerRepository.Create")
+	defer span.End()
+
 	if newCustomer.Phone2 != nil && *newCustomer.Phone2 == "" {
 		newCustomer.Phone2 = nil
 	}
 
+	values := map[string]any{
+		"name":   newCustomer.Name,
+		"phone":  newCustomer.Phone,
+		"phone2": newCustomer.Phone2,
+		"email":  newCustomer.Email,
+	}
+	if newCustomer.Id != "" {
+		values["id"] = newCustomer.Id
+	}
+
 	insertBuilder, args, errQB := r.qb.Insert("customers").
-		Columns("name", "phone", "phone2", "email").
-		Values(&newCustomer.Name, &newCustomer.Phone, &newCustomer.Phone2, &newCustomer.Email).
+		SetMap(values).
 		Suffix("RETURNING id").
 		ToSql()
 
@@ -172,20 +296,179 @@ func (r *PgCustomerRepository) Create(newCustomer domain.NewCustomer) (*domain.C
 		return nil, fmt.Errorf("erro ao construir query para criar o cliente: %v", errQB)
 	}
 
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %v", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
 	var id string
-	errQuery := r.db.QueryRow(context.Background(), insertBuilder, args...).Scan(&id)
+	errQuery := tx.QueryRow(ctx, insertBuilder, args...).Scan(&id)
 
 	if errQuery != nil {
+		if isUniqueViolation(errQuery) {
+			return nil, duplicatePhoneError(errQuery, newCustomer.Phone, newCustomer.Phone2)
+		}
 		return nil, fmt.Errorf("erro ao criar cliente: %v", errQuery)
 	}
 
+	if err := enqueueBackgroundJobs(ctx, tx, r.qb, jobs); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("erro ao confirmar transação: %v", err)
+	}
+
 	createdCustomer := &domain.Customer{
-		Id:     id,
-		Name:   newCustomer.Name,
-		Phone:  newCustomer.Phone,
-		Phone2: newCustomer.Phone2,
-		Email:  newCustomer.Email,
+		Id:                 id,
+		Name:               newCustomer.Name,
+		Phone:              newCustomer.Phone,
+		Phone2:             newCustomer.Phone2,
+		Email:              newCustomer.Email,
+		Tags:               []string{},
+		DietaryPreferences: []string{},
 	}
 
 	return createdCustomer, nil
 }
+
+func (r *PgCustomerRepository) FindByPhones(ctx context.Context, phones []string) ([]domain.Customer, error) {
+	ctx, span := startSpan(ctx, "PgCustomerRepository.FindByPhones")
+	defer span.End()
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, name, phone, phone2, email, tags, dietary_preferences
+		FROM customers
+		WHERE (phone = ANY($1) OR phone2 = ANY($1)) AND is_deleted = false
+	`, phones)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar clientes por telefone: %v", err)
+	}
+	defer rows.Close()
+
+	customers := []domain.Customer{}
+	for rows.Next() {
+		var customer domain.Customer
+		err := rows.Scan(
+			&customer.Id,
+			&customer.Name,
+			&customer.Phone,
+			&customer.Phone2,
+			&customer.Email,
+			&customer.Tags,
+			&customer.DietaryPreferences,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler cliente: %v", err)
+		}
+		customers = append(customers, customer)
+	}
+	return customers, rows.Err()
+}
+
+// DeletedPhones existe porque phone e phone2 são únicos na tabela inteira, inclusive entre os
+// clientes excluídos.
+func (r *PgCustomerRepository) DeletedPhones(ctx context.Context, phones []string) ([]string, error) {
+	ctx, span := startSpan(ctx, "PgCustomerRepository.DeletedPhones")
+	defer span.End()
+
+	rows, err := r.db.Query(ctx, `
+		SELECT phone FROM customers WHERE is_deleted = true AND phone = ANY($1)
+		UNION
+		SELECT phone2 FROM customers WHERE is_deleted = true AND phone2 = ANY($1)
+	`, phones)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar telefones de clientes excluídos: %v", err)
+	}
+	defer rows.Close()
+
+	deleted := []string{}
+	for rows.Next() {
+		var phone string
+		if err := rows.Scan(&phone); err != nil {
+			return nil, fmt.Errorf("erro ao ler telefone: %v", err)
+		}
+		deleted = append(deleted, phone)
+	}
+	return deleted, rows.Err()
+}
+
+// Import grava a planilha inteira em uma transação: se uma linha falhar, nada é gravado.
+func (r *PgCustomerRepository) Import(ctx context.Context, creates []domain.NewCustomer, updates []domain.Customer, jobs []domain.NewBackgroundJob) error {
+	ctx, span := startSpan(ctx, "PgCustomerRepository.Import")
+	defer span.End()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %v", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Até domain.MaxImportRows linhas e um job por linha: as escritas vão em um único batch, e não
+	// em um round trip por instrução, para caber no timeout das consultas
+	batch := &pgx.Batch{}
+	for _, customer := range updates {
+		batch.Queue(`
+			UPDATE customers SET name = $2, phone = $3, phone2 = $4, email = $5, updated_at = now()
+			WHERE id = $1 AND is_deleted = false
+		`, customer.Id, customer.Name, customer.Phone, customer.Phone2, customer.Email)
+	}
+	for _, newCustomer := range creates {
+		batch.Queue(`
+			INSERT INTO customers (id, name, phone, phone2, email) VALUES ($1, $2, $3, $4, $5)
+		`, newCustomer.Id, newCustomer.Name, newCustomer.Phone, newCustomer.Phone2, newCustomer.Email)
+	}
+	if err := queueBackgroundJobs(batch, r.qb, jobs); err != nil {
+		return err
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	defer results.Close()
+
+	for _, customer := range updates {
+		result, err := results.Exec()
+		if err != nil {
+			if isUniqueViolation(err) {
+				return duplicatePhoneError(err, customer.Phone, customer.Phone2)
+			}
+			return fmt.Errorf("erro ao atualizar cliente %q: %v", customer.Name, err)
+		}
+		if result.RowsAffected() == 0 {
+			return domain.NewImportRowDeletedError(customer.Name)
+		}
+	}
+	for _, newCustomer := range creates {
+		if _, err := results.Exec(); err != nil {
+			if isUniqueViolation(err) {
+				return duplicatePhoneError(err, newCustomer.Phone, newCustomer.Phone2)
+			}
+			return fmt.Errorf("erro ao criar cliente %q: %v", newCustomer.Name, err)
+		}
+	}
+	if err := execQueuedBackgroundJobs(results, jobs); err != nil {
+		return err
+	}
+	if err := results.Close(); err != nil {
+		return fmt.Errorf("erro ao gravar importação: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %v", err)
+	}
+	return nil
+}
+
+// duplicatePhoneError informa o telefone que colidiu, conforme a constraint violada.
+func duplicatePhoneError(err error, phone string, phone2 *string) *domain.DuplicatePhoneError {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.ConstraintName == "customers_phone2_key" && phone2 != nil {
+		return domain.NewDuplicatePhoneError(*phone2)
+	}
+	return domain.NewDuplicatePhoneError(phone)
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PgJobRunRepository struct {
+	db *pgxpool.Pool
+	qb squirrel.StatementBuilderType
+}
+
+func NewPgJobRunRepository(db *pgxpool.Pool) *PgJobRunRepository {
+	return &PgJobRunRepository{
+		db: db,
+		qb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+var jobRunColumns = []string{"id", "job_name", "status", "triggered_by", "result", "error", "started_at", "finished_at"}
+
+func (r *PgJobRunRepository) Start(ctx context.Context, jobName string, triggeredBy string) (*domain.JobRun, error) {
+	ctx, span := startSpan(ctx, "PgJobRunRepository.Start")
+	defer span.End()
+
+	query, args, err := r.qb.Insert("job_runs").
+		Columns("job_name", "status", "triggered_by").
+		Values(jobName, domain.JobRunStatusRunning, triggeredBy).
+		Suffix("RETURNING id, started_at").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building query to start job run: %w", err)
+	}
+
+	run := domain.JobRun{JobName: jobName, Status: domain.JobRunStatusRunning, TriggeredBy: triggeredBy}
+	if err := r.db.QueryRow(ctx, query, args...).Scan(&run.Id, &run.StartedAt); err != nil {
+		return nil, fmt.Errorf("error starting job run: %w", err)
+	}
+
+	return &run, nil
+}
+
+func (r *PgJobRunRepository) StartScheduled(ctx context.Context, jobName string, scheduledFor time.Time) (*domain.JobRun, error) {
+	ctx, span := startSpan(ctx, "PgJobRunRepository.StartScheduled")
+	defer span.End()
+
+	query, args, err := r.qb.Insert("job_runs").
+		Columns("job_name", "status", "triggered_by", "scheduled_for").
+		Values(jobName, domain.JobRunStatusRunning, domain.JobTriggerSchedule, scheduledFor).
+		Suffix("ON CONFLICT (job_name, scheduled_for) DO NOTHING RETURNING id, started_at").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building query to claim scheduled job run: %w", err)
+	}
+
+	run := domain.JobRun{JobName: jobName, Status: domain.JobRunStatusRunning, TriggeredBy: domain.JobTriggerSchedule}
+	err = r.db.QueryRow(ctx, query, args...).Scan(&run.Id, &run.StartedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error claiming scheduled job run: %w", err)
+	}
+
+	return &run, nil
+}
+
+func (r *PgJobRunRepository) Finish(ctx context.Context, id string, status string, result *string, errorMessage *string) error {
+	ctx, span := startSpan(ctx, "PgJobRunRepository.Finish")
+	defer span.End()
+
+	query, args, err := r.qb.Update("job_runs").
+		Set("status", status).
+		Set("result", result).
+		Set("error", errorMessage).
+		Set("finished_at", squirrel.Expr("now()")).
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("error building query to finish job run: %w", err)
+	}
+
+	if _, err := r.db.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("error finishing job run: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PgJobRunRepository) FindLastByJob(ctx context.Context, jobName string) (*domain.JobRun, error) {
+	ctx, span := startSpan(ctx, "PgJobRunRepository.FindLastByJob")
+	defer span.End()
+
+	query, args, err := r.qb.Select(jobRunColumns...).
+		From("job_runs").
+		Where(squirrel.Eq{"job_name": jobName}).
+		OrderBy("started_at DESC").
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building last job run query: %w", err)
+	}
+
+	run, err := scanJobRun(r.db.QueryRow(ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching last job run: %w", err)
+	}
+
+	return run, nil
+}
+
+func (r *PgJobRunRepository) FindByJob(ctx context.Context, jobName string, pagination domain.Pagination) ([]domain.JobRun, domain.Pagination, error) {
+	ctx, span := startSpan(ctx, "PgJobRunRepository.FindByJob")
+	defer span.End()
+
+	offset := pagination.Limit * (pagination.Page - 1)
+
+	countQuery, countArgs, err := r.qb.Select("count(*)").
+		From("job_runs").
+		Where(squirrel.Eq{"job_name": jobName}).
+		ToSql()
+	if err != nil {
+		return nil, domain.Pagination{}, fmt.Errorf("error building job runs count query: %w", err)
+	}
+
+	if err := r.db.QueryRow(ctx, countQuery, countArgs...).Scan(&pagination.Total); err != nil {
+		return nil, domain.Pagination{}, fmt.Errorf("error counting job runs: %w", err)
+	}
+
+	query, args, err := r.qb.Select(jobRunColumns...).
+		From("job_runs").
+		Where(squirrel.Eq{"job_name": jobName}).
+		OrderBy("started_at DESC").
+		Limit(uint64(pagination.Limit)).
+		Offset(uint64(offset)).
+		ToSql()
+	if err != nil {
+		return nil, domain.Pagination{}, fmt.Errorf("error building job runs query: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, domain.Pagination{}, fmt.Errorf("error fetching job runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []domain.JobRun{}
+	for rows.Next() {
+		run, err := scanJobRun(rows)
+		if err != nil {
+			return nil, domain.Pagination{}, fmt.Errorf("error scanning job run: %w", err)
+		}
+		runs = append(runs, *run)
+	}
+
+	return runs, pagination, rows.Err()
+}
+
+func scanJobRun(row pgx.Row) (*domain.JobRun, error) {
+	var run domain.JobRun
+	if err := row.Scan(
+		&run.Id,
+		&run.JobName,
+		&run.Status,
+		&run.TriggeredBy,
+		&run.Result,
+		&run.Error,
+		&run.StartedAt,
+		&run.FinishedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
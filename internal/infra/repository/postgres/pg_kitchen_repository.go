@@ -0,0 +1,238 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PgKitchenRepository struct {
+	db *pgxpool.Pool
+	qb squirrel.StatementBuilderType
+}
+
+func NewPgKitchenRepository(db *pgxpool.Pool) *PgKitchenRepository {
+	return &PgKitchenRepository{
+		db: db,
+		qb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+func (r *PgKitchenRepository) FindTickets(ctx context.Context, filters domain.KitchenFilters) ([]domain.KitchenTicket, error) {
+	ctx, span := startSpan(ctx, "PgKitchenRepository.FindTickets")
+	defer span.End()
+
+	queryBuilder := r.qb.
+		Select(
+			"op.id",
+			"o.id",
+			"o.order_number::text",
+			"o.status",
+			"o.pickup_date",
+			"c.name",
+			"op.product_id",
+			"p.name",
+			"op.quantity",
+			"op.unity_type",
+			`COALESCE((
+				SELECT JSON_AGG(JSON_BUILD_OBJECT(
+					'id', osp.id,
+					'orderProductId', osp.order_product_id,
+					'productId', osp.product_id,
+					'name', sp.name
+				) ORDER BY sp.name)
+				FROM order_sub_products osp
+				JOIN products sp ON sp.id = osp.product_id
+				WHERE osp.order_product_id = op.id
+			), '[]'::json)`,
+			"op.observations",
+			"o.observations",
+			"c.tags",
+			"c.dietary_preferences",
+			`COALESCE((
+				SELECT JSON_AGG(JSON_BUILD_OBJECT('content', n.content, 'isAlert', n.is_alert))
+				FROM customer_notes n
+				WHERE n.customer_id = c.id AND n.is_alert
+			), '[]'::json)`,
+			"op.production_status",
+			"op.done_at",
+			"op.done_by",
+		).
+		From("order_products op").
+		Join("orders o ON o.id = op.order_id").
+		Join("customers c ON c.id = o.customer_id").
+		Join("products p ON p.id = op.product_id").
+		Where(squirrel.Eq{"o.is_deleted": false}).
+		Where(squirrel.Eq{"o.status": []string{domain.OrderStatusPending, domain.OrderStatusReady}}).
+		Where(squirrel.Expr("o.pickup_date BETWEEN ? AND ?", filters.PickupDateStart, filters.PickupDateEnd)).
+		OrderBy("o.pickup_date", "o.order_number", "p.name")
+
+	if !filters.IncludeDone {
+		queryBuilder = queryBuilder.Where(squirrel.Eq{"op.production_status": domain.ProductionStatusPending})
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building kitchen tickets query: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching kitchen tickets: %w", err)
+	}
+	defer rows.Close()
+
+	tickets := []domain.KitchenTicket{}
+	for rows.Next() {
+		var ticket domain.KitchenTicket
+		var customer domain.Customer
+		var subProductsJSON, notesJSON []byte
+		if err := rows.Scan(
+			&ticket.Id,
+			&ticket.OrderId,
+			&ticket.OrderNumber,
+			&ticket.OrderStatus,
+			&ticket.PickupDate,
+			&ticket.CustomerName,
+			&ticket.ProductId,
+			&ticket.Name,
+			&ticket.Quantity,
+			&ticket.UnityType,
+			&subProductsJSON,
+			&ticket.Observations,
+			&ticket.OrderObservations,
+			&customer.Tags,
+			&customer.DietaryPreferences,
+			&notesJSON,
+			&ticket.ProductionStatus,
+			&ticket.DoneAt,
+			&ticket.DoneBy,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning kitchen ticket: %w", err)
+		}
+
+		if err := json.Unmarshal(subProductsJSON, &ticket.SubProducts); err != nil {
+			return nil, fmt.Errorf("error unmarshaling kitchen ticket sub-products: %w", err)
+		}
+
+		var notes []domain.CustomerNote
+		if err := json.Unmarshal(notesJSON, &notes); err != nil {
+			return nil, fmt.Errorf("error unmarshaling customer alert notes: %w", err)
+		}
+		ticket.CustomerAlerts = domain.BuildCustomerAlerts(customer, notes)
+
+		tickets = append(tickets, ticket)
+	}
+
+	return tickets, rows.Err()
+}
+
+func (r *PgKitchenRepository) SetTicketsStatus(ctx context.Context, ticketIds []string, status string, userId string, orderJobs func(orderId string, status string) []domain.NewBackgroundJob) ([]domain.KitchenOrderProgress, error) {
+	ctx, span := startSpan(ctx, "PgKitchenRepository.SetTicketsStatus")
+	defer span.End()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Trava os pedidos para que baixas simultâneas de linhas diferentes vejam a contagem correta.
+	// A ordem por id evita que dois lotes com pedidos em comum esperem um pelo outro.
+	rows, err := tx.Query(ctx, `
+		SELECT op.id, o.id, o.status
+		FROM orders o
+		JOIN order_products op ON op.order_id = o.id
+		WHERE op.id = ANY($1) AND o.is_deleted = false
+		ORDER BY o.id
+		FOR UPDATE OF o
+	`, ticketIds)
+	if err != nil {
+		return nil, fmt.Errorf("error locking kitchen ticket orders: %w", err)
+	}
+	orderIds := map[string]string{}
+	orderStatus := map[string]string{}
+	for rows.Next() {
+		var ticketId, orderId, status string
+		if err := rows.Scan(&ticketId, &orderId, &status); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning kitchen ticket order: %w", err)
+		}
+		orderIds[ticketId] = orderId
+		orderStatus[orderId] = status
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error locking kitchen ticket orders: %w", err)
+	}
+
+	var doneAt, doneBy any
+	if status == domain.ProductionStatusDone {
+		doneAt = squirrel.Expr("now()")
+		doneBy = userId
+	}
+
+	results := make([]domain.KitchenOrderProgress, 0, len(ticketIds))
+	for _, ticketId := range ticketIds {
+		orderId, ok := orderIds[ticketId]
+		if !ok {
+			return nil, domain.NewKitchenTicketNotFoundError(ticketId)
+		}
+		progress := domain.KitchenOrderProgress{TicketId: ticketId, OrderId: orderId}
+
+		query, args, err := r.qb.Update("order_products").
+			Set("production_status", status).
+			Set("done_at", doneAt).
+			Set("done_by", doneBy).
+			Where(squirrel.Eq{"id": ticketId}).
+			ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("error building query to update kitchen ticket: %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, query, args...); err != nil {
+			return nil, fmt.Errorf("error updating kitchen ticket: %w", err)
+		}
+
+		if err := tx.QueryRow(ctx,
+			"SELECT count(*) FROM order_products WHERE order_id = $1 AND production_status = 'pending'", orderId,
+		).Scan(&progress.PendingTickets); err != nil {
+			return nil, fmt.Errorf("error counting pending kitchen tickets: %w", err)
+		}
+
+		// O pedido é sempre atualizado para que o quadro em tempo real receba a mudança
+		next := domain.KitchenOrderStatus(orderStatus[orderId], progress.PendingTickets)
+		if next == "" {
+			if _, err := tx.Exec(ctx, "UPDATE orders SET updated_at = now() WHERE id = $1", orderId); err != nil {
+				return nil, fmt.Errorf("error touching order: %w", err)
+			}
+		} else {
+			if _, err := tx.Exec(ctx, `
+				UPDATE orders
+				SET status = $1,
+					is_picked_up = ($1 = 'picked_up'),
+					updated_at = now()
+				WHERE id = $2
+			`, next, orderId); err != nil {
+				return nil, fmt.Errorf("error updating order status: %w", err)
+			}
+			if err := enqueueBackgroundJobs(ctx, tx, r.qb, orderJobs(orderId, next)); err != nil {
+				return nil, err
+			}
+			orderStatus[orderId] = next
+		}
+
+		progress.OrderStatus = orderStatus[orderId]
+		results = append(results, progress)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,543 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// legacyImportDB é o pool ou, no dry-run, a transação que será desfeita. Nos dois casos Begin
+// abre o escopo de cada lote (uma transação ou um savepoint).
+type legacyImportDB interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+type PgLegacyImportRepository struct {
+	db legacyImportDB
+}
+
+func NewPgLegacyImportRepository(db *pgxpool.Pool) *PgLegacyImportRepository {
+	return &PgLegacyImportRepository{db: db}
+}
+
+func (r *PgLegacyImportRepository) DryRun(ctx context.Context) (domain.LegacyImportRepository, func(), error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error starting dry run transaction: %w", err)
+	}
+	rollback := func() { _ = tx.Rollback(context.Background()) }
+	return &PgLegacyImportRepository{db: tx}, rollback, nil
+}
+
+func (r *PgLegacyImportRepository) Checkpoints(ctx context.Context) (map[string]domain.LegacyImportCheckpoint, error) {
+	ctx, span := startSpan(ctx, "PgLegacyImportRepository.Checkpoints")
+	defer span.End()
+
+	rows, err := r.db.Query(ctx, `SELECT stage, last_old_id, imported, skipped, completed_at FROM legacy_import_checkpoints`)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching import checkpoints: %w", err)
+	}
+	checkpoints, err := pgx.CollectRows(rows, scanLegacyImportCheckpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error scanning import checkpoints: %w", err)
+	}
+
+	byStage := make(map[string]domain.LegacyImportCheckpoint, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		byStage[checkpoint.Stage] = checkpoint
+	}
+	return byStage, nil
+}
+
+// Reset apaga checkpoints e erros para que a próxima execução recomece do início. Os registros
+// já importados ficam, e serão atualizados pelo upsert.
+func (r *PgLegacyImportRepository) Reset(ctx context.Context) error {
+	ctx, span := startSpan(ctx, "PgLegacyImportRepository.Reset")
+	defer span.End()
+
+	if _, err := r.db.Exec(ctx, "DELETE FROM legacy_import_checkpoints"); err != nil {
+		return fmt.Errorf("error resetting import checkpoints: %w", err)
+	}
+	if _, err := r.db.Exec(ctx, "DELETE FROM legacy_import_errors"); err != nil {
+		return fmt.Errorf("error resetting import errors: %w", err)
+	}
+	return nil
+}
+
+func (r *PgLegacyImportRepository) CompleteStage(ctx context.Context, stage string) error {
+	ctx, span := startSpan(ctx, "PgLegacyImportRepository.CompleteStage")
+	defer span.End()
+
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO legacy_import_checkpoints (stage, completed_at) VALUES ($1, now())
+		ON CONFLICT (stage) DO UPDATE SET completed_at = now(), updated_at = now()
+	`, stage)
+	if err != nil {
+		return fmt.Errorf("error completing import stage %s: %w", stage, err)
+	}
+	return nil
+}
+
+func (r *PgLegacyImportRepository) Errors(ctx context.Context) ([]domain.LegacyImportError, error) {
+	ctx, span := startSpan(ctx, "PgLegacyImportRepository.Errors")
+	defer span.End()
+
+	rows, err := r.db.Query(ctx, `SELECT stage, old_id, reason FROM legacy_import_errors ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching import errors: %w", err)
+	}
+	importErrors, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.LegacyImportError, error) {
+		var importError domain.LegacyImportError
+		err := row.Scan(&importError.Stage, &importError.OldId, &importError.Reason)
+		return importError, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error scanning import errors: %w", err)
+	}
+	return importErrors, nil
+}
+
+// ImportCategories cria as categorias usadas pela classificação dos produtos. O legado não tem
+// tabela de categorias, então a chave é o nome.
+func (r *PgLegacyImportRepository) ImportCategories(ctx context.Context, batch domain.LegacyImportBatch[domain.LegacyCategory]) (*domain.LegacyImportCheckpoint, error) {
+	ctx, span := startSpan(ctx, "PgLegacyImportRepository.ImportCategories")
+	defer span.End()
+
+	key := func(category domain.LegacyCategory) string { return category.Name }
+	return importLegacyBatch(ctx, r.db, batch, key, func(ctx context.Context, tx pgx.Tx, category domain.LegacyCategory) error {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO category_products (name, description)
+			SELECT $1, $2
+			WHERE NOT EXISTS (SELECT 1 FROM category_products WHERE lower(name) = lower($1) AND NOT is_deleted)
+		`, category.Name, category.Description)
+		return err
+	})
+}
+
+// ImportProducts atualiza nome, preço e unidade dos produtos já importados. A categoria só é
+// definida na criação, para não desfazer a classificação feita depois no sistema novo.
+func (r *PgLegacyImportRepository) ImportProducts(ctx context.Context, batch domain.LegacyImportBatch[domain.LegacyProduct]) (*domain.LegacyImportCheckpoint, error) {
+	ctx, span := startSpan(ctx, "PgLegacyImportRepository.ImportProducts")
+	defer span.End()
+
+	key := func(product domain.LegacyProduct) string { return strconv.Itoa(product.OldId) }
+	return importLegacyBatch(ctx, r.db, batch, key, importLegacyProduct)
+}
+
+func importLegacyProduct(ctx context.Context, tx pgx.Tx, product domain.LegacyProduct) error {
+	id, err := findByOldId(ctx, tx, "products", product.OldId)
+	if err != nil {
+		return err
+	}
+
+	if id != "" {
+		_, err = tx.Exec(ctx, `
+			UPDATE products SET name = $2, value = $3, unity_type = $4, updated_at = now() WHERE id = $1
+		`, id, product.Name, product.Value, product.UnityType)
+		return err
+	}
+
+	var categoryId *string
+	err = tx.QueryRow(ctx, `
+		SELECT id FROM category_products WHERE lower(name) = lower($1) AND NOT is_deleted ORDER BY display_order LIMIT 1
+	`, product.Category).Scan(&categoryId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("category %q not found", product.Category)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO products (old_id, name, value, unity_type, category_id) VALUES ($1, $2, $3, $4, $5)
+	`, product.OldId, product.Name, product.Value, product.UnityType, categoryId)
+	return err
+}
+
+// ImportCustomers não altera clientes que foram unidos a outro no sistema novo.
+func (r *PgLegacyImportRepository) ImportCustomers(ctx context.Context, batch domain.LegacyImportBatch[domain.LegacyCustomer]) (*domain.LegacyImportCheckpoint, error) {
+	ctx, span := startSpan(ctx, "PgLegacyImportRepository.ImportCustomers")
+	defer span.End()
+
+	key := func(customer domain.LegacyCustomer) string { return strconv.Itoa(customer.OldId) }
+	return importLegacyBatch(ctx, r.db, batch, key, importLegacyCustomer)
+}
+
+func importLegacyCustomer(ctx context.Context, tx pgx.Tx, customer domain.LegacyCustomer) error {
+	id, err := findByOldId(ctx, tx, "customers", customer.OldId)
+	if err != nil {
+		return err
+	}
+
+	if id != "" {
+		_, err = tx.Exec(ctx, `
+			UPDATE customers SET name = $2, phone = $3, phone2 = $4, email = $5, updated_at = now()
+			WHERE id = $1 AND merged_into IS NULL
+		`, id, customer.Name, customer.Phone, customer.Phone2, customer.Email)
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO customers (old_id, name, phone, phone2, email, created_at)
+		VALUES ($1, $2, $3, $4, $5, COALESCE($6, now()))
+	`, customer.OldId, customer.Name, customer.Phone, customer.Phone2, customer.Email, customer.CreatedAt)
+	return err
+}
+
+// ImportAddresses grava o endereço e o vincula ao cliente, como endereço padrão quando o
+// cliente ainda não tem um.
+func (r *PgLegacyImportRepository) ImportAddresses(ctx context.Context, batch domain.LegacyImportBatch[domain.LegacyAddress]) (*domain.LegacyImportCheckpoint, error) {
+	ctx, span := startSpan(ctx, "PgLegacyImportRepository.ImportAddresses")
+	defer span.End()
+
+	key := func(address domain.LegacyAddress) string { return strconv.Itoa(address.OldId) }
+	return importLegacyBatch(ctx, r.db, batch, key, importLegacyAddress)
+}
+
+func importLegacyAddress(ctx context.Context, tx pgx.Tx, address domain.LegacyAddress) error {
+	customerId, err := findLegacyCustomer(ctx, tx, address.OldCustomerId)
+	if err != nil {
+		return err
+	}
+
+	id, err := findByOldId(ctx, tx, "addresses", address.OldId)
+	if err != nil {
+		return err
+	}
+
+	if id != "" {
+		_, err = tx.Exec(ctx, `
+			UPDATE addresses
+			SET cep = $2, street = $3, number = $4, neighborhood = $5, city = $6, state = $7, aditional_details = $8, distance = $9, updated_at = now()
+			WHERE id = $1
+		`, id, address.Cep, address.Street, address.Number, address.Neighborhood, address.City, address.State, address.AditionalDetails, address.Distance)
+	} else {
+		err = tx.QueryRow(ctx, `
+			INSERT INTO addresses (old_id, cep, street, number, neighborhood, city, state, aditional_details, distance)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			RETURNING id
+		`, address.OldId, address.Cep, address.Street, address.Number, address.Neighborhood, address.City, address.State, address.AditionalDetails, address.Distance).Scan(&id)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO address_customers (address_id, customer_id, is_default)
+		VALUES ($1, $2, NOT EXISTS (SELECT 1 FROM address_customers WHERE customer_id = $2 AND is_default))
+		ON CONFLICT (address_id, customer_id) DO NOTHING
+	`, id, customerId)
+	return err
+}
+
+// ImportOrders grava o pedido e seus itens pela chave do legado. Pedidos carregados pelos
+// scripts antigos, sem old_id, são reconhecidos pelo número, cliente e data de retirada.
+func (r *PgLegacyImportRepository) ImportOrders(ctx context.Context, batch domain.LegacyImportBatch[domain.LegacyOrder]) (*domain.LegacyImportCheckpoint, error) {
+	ctx, span := startSpan(ctx, "PgLegacyImportRepository.ImportOrders")
+	defer span.End()
+
+	key := func(order domain.LegacyOrder) string { return strconv.Itoa(order.OldId) }
+	return importLegacyBatch(ctx, r.db, batch, key, importLegacyOrder)
+}
+
+func importLegacyOrder(ctx context.Context, tx pgx.Tx, order domain.LegacyOrder) error {
+	customerId, err := findLegacyCustomer(ctx, tx, order.OldCustomerId)
+	if err != nil {
+		return err
+	}
+
+	var addressId *string
+	if order.IsDelivery {
+		err := tx.QueryRow(ctx, `
+			SELECT address_id FROM address_customers WHERE customer_id = $1 ORDER BY is_default DESC LIMIT 1
+		`, customerId).Scan(&addressId)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+	}
+
+	id, err := findByOldId(ctx, tx, "orders", order.OldId)
+	if err != nil {
+		return err
+	}
+	if id == "" {
+		rows, err := tx.Query(ctx, `
+			SELECT id FROM orders WHERE old_id IS NULL AND order_number = $1 AND customer_id = $2 AND pickup_date = $3
+		`, order.Number, customerId, order.PickupDate)
+		if err != nil {
+			return err
+		}
+		ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return err
+		}
+		if len(ids) > 1 {
+			return fmt.Errorf("%d orders without old_id match number %d", len(ids), order.Number)
+		}
+		if len(ids) == 1 {
+			id = ids[0]
+		}
+	}
+
+	isPickedUp := order.Status == domain.OrderStatusPickedUp
+	if id != "" {
+		_, err = tx.Exec(ctx, `
+			UPDATE orders
+			SET old_id = $2, order_number = $3, pickup_date = $4, created_at = $5, customer_id = $6, order_local = $7,
+			    observations = $8, status = $9, is_picked_up = $10, address_id = $11, updated_at = now()
+			WHERE id = $1
+		`, id, order.OldId, order.Number, order.PickupDate, order.CreatedAt, customerId, order.OrderLocal, order.Observations, order.Status, isPickedUp, addressId)
+		if err != nil {
+			return err
+		}
+	} else {
+		err = tx.QueryRow(ctx, `
+			INSERT INTO orders (old_id, order_number, pickup_date, created_at, customer_id, employee_id, order_local, observations, status, is_picked_up, address_id)
+			VALUES ($1, $2, $3, $4, $5, '', $6, $7, $8, $9, $10)
+			RETURNING id
+		`, order.OldId, order.Number, order.PickupDate, order.CreatedAt, customerId, order.OrderLocal, order.Observations, order.Status, isPickedUp, addressId).Scan(&id)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Pedidos já retirados não voltam para a cozinha
+	productionStatus := domain.ProductionStatusPending
+	if isPickedUp {
+		productionStatus = domain.ProductionStatusDone
+	}
+
+	oldKeys := make([]string, 0, len(order.Items))
+	for _, item := range order.Items {
+		if err := importLegacyOrderItem(ctx, tx, id, item, productionStatus); err != nil {
+			return err
+		}
+		oldKeys = append(oldKeys, item.OldKey)
+	}
+
+	// Itens removidos no legado; os incluídos pela API, sem old_key, ficam
+	_, err = tx.Exec(ctx, `
+		DELETE FROM order_products WHERE order_id = $1 AND old_key IS NOT NULL AND NOT (old_key = ANY($2))
+	`, id, oldKeys)
+	return err
+}
+
+// importLegacyOrderItem atualiza o item pela chave do legado, ou o insere, sem mexer no andamento
+// da produção. Itens carregados pelos scripts antigos, sem old_key, são reconhecidos pelo produto.
+func importLegacyOrderItem(ctx context.Context, tx pgx.Tx, orderId string, item domain.LegacyOrderItem, productionStatus string) error {
+	var productId, unityType string
+	var price int
+	err := tx.QueryRow(ctx, `SELECT id, unity_type, value FROM products WHERE old_id = $1 ORDER BY is_deleted LIMIT 1`, item.OldProductId).
+		Scan(&productId, &unityType, &price)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("product %d was not imported", item.OldProductId)
+	}
+	if err != nil {
+		return err
+	}
+
+	var orderProductId string
+	err = tx.QueryRow(ctx, `
+		UPDATE order_products
+		SET old_key = $2, product_id = $3, quantity = $4, unity_type = $5, price = $6
+		WHERE id = (
+			SELECT id FROM order_products
+			WHERE order_id = $1 AND (old_key = $2 OR (old_key IS NULL AND product_id = $3))
+			ORDER BY old_key NULLS LAST, id
+			LIMIT 1
+		)
+		RETURNING id
+	`, orderId, item.OldKey, productId, item.Quantity, unityType, price).Scan(&orderProductId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = tx.QueryRow(ctx, `
+			INSERT INTO order_products (order_id, old_key, product_id, quantity, unity_type, price, production_status)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id
+		`, orderId, item.OldKey, productId, item.Quantity, unityType, price, productionStatus).Scan(&orderProductId)
+	}
+	if err != nil {
+		return err
+	}
+
+	// O molho do legado passa a ser o único sub-produto do item
+	var subProductId *string
+	if item.OldSubProductId != nil {
+		var id string
+		err := tx.QueryRow(ctx, `SELECT id FROM products WHERE old_id = $1 ORDER BY is_deleted LIMIT 1`, *item.OldSubProductId).Scan(&id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("sauce product %d was not imported", *item.OldSubProductId)
+		}
+		if err != nil {
+			return err
+		}
+		subProductId = &id
+	}
+
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM order_sub_products WHERE order_product_id = $1 AND product_id IS DISTINCT FROM $2
+	`, orderProductId, subProductId); err != nil {
+		return err
+	}
+	if subProductId == nil {
+		return nil
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO order_sub_products (order_product_id, product_id)
+		SELECT $1, $2
+		WHERE NOT EXISTS (SELECT 1 FROM order_sub_products WHERE order_product_id = $1 AND product_id = $2)
+	`, orderProductId, *subProductId)
+	return err
+}
+
+// importLegacyBatch grava o lote em uma transação, cada linha em um savepoint, e salva os erros,
+// o checkpoint e a versão de cada linha para a sincronização na mesma transação: se o processo
+// cair no meio, o lote inteiro é refeito.
+func importLegacyBatch[T any](
+	ctx context.Context,
+	db legacyImportDB,
+	batch domain.LegacyImportBatch[T],
+	key func(T) string,
+	importRow func(ctx context.Context, tx pgx.Tx, row T) error,
+) (*domain.LegacyImportCheckpoint, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting import batch: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	failures := append([]domain.LegacyImportError{}, batch.Skipped...)
+	imported := 0
+	for i, row := range batch.Rows {
+		savepoint, err := tx.Begin(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error starting savepoint: %w", err)
+		}
+
+		if err := importRow(ctx, savepoint, row); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if err := savepoint.Rollback(ctx); err != nil {
+				return nil, fmt.Errorf("error rolling back savepoint: %w", err)
+			}
+			failures = append(failures, domain.LegacyImportError{Stage: batch.Stage, OldId: key(row), Reason: legacyImportReason(err)})
+			continue
+		}
+
+		if err := savepoint.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("error releasing savepoint: %w", err)
+		}
+		imported++
+
+		// As etapas com impressão digital têm chave numérica
+		if i < len(batch.Fingerprints) {
+			oldId, _ := strconv.Atoi(key(row))
+			_, err := tx.Exec(ctx, `
+				INSERT INTO legacy_sync_rows (stage, old_id, fingerprint, synced_at) VALUES ($1, $2, $3, now())
+				ON CONFLICT (stage, old_id) DO UPDATE
+				SET fingerprint = EXCLUDED.fingerprint, synced_at = EXCLUDED.synced_at, last_error = NULL, updated_at = now()
+			`, batch.Stage, oldId, batch.Fingerprints[i])
+			if err != nil {
+				return nil, fmt.Errorf("error recording imported version: %w", err)
+			}
+		}
+	}
+
+	for _, failure := range failures {
+		_, err := tx.Exec(ctx, `INSERT INTO legacy_import_errors (stage, old_id, reason) VALUES ($1, $2, $3)`, failure.Stage, failure.OldId, failure.Reason)
+		if err != nil {
+			return nil, fmt.Errorf("error recording import error: %w", err)
+		}
+	}
+
+	rows, err := tx.Query(ctx, `
+		INSERT INTO legacy_import_checkpoints (stage, last_old_id, imported, skipped)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (stage) DO UPDATE
+		SET last_old_id = GREATEST(legacy_import_checkpoints.last_old_id, EXCLUDED.last_old_id),
+		    imported = legacy_import_checkpoints.imported + EXCLUDED.imported,
+		    skipped = legacy_import_checkpoints.skipped + EXCLUDED.skipped,
+		    updated_at = now()
+		RETURNING stage, last_old_id, imported, skipped, completed_at
+	`, batch.Stage, batch.LastOldId, imported, len(failures))
+	if err != nil {
+		return nil, fmt.Errorf("error saving import checkpoint: %w", err)
+	}
+	checkpoint, err := pgx.CollectExactlyOneRow(rows, scanLegacyImportCheckpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error saving import checkpoint: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing import batch: %w", err)
+	}
+	return &checkpoint, nil
+}
+
+// findByOldId devolve o id da linha de table com o old_id informado, ou "" quando não existe.
+// Linhas duplicadas pelas cargas antigas são um erro, porque não há como saber qual atualizar.
+func findByOldId(ctx context.Context, tx pgx.Tx, table string, oldId int) (string, error) {
+	rows, err := tx.Query(ctx, fmt.Sprintf("SELECT id FROM %s WHERE old_id = $1", table), oldId)
+	if err != nil {
+		return "", err
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return "", err
+	}
+
+	switch len(ids) {
+	case 0:
+		return "", nil
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("%d rows in %s share old_id %d", len(ids), table, oldId)
+	}
+}
+
+// findLegacyCustomer devolve o cliente importado com o old_id, ou o cliente em que ele foi
+// unido no sistema novo.
+func findLegacyCustomer(ctx context.Context, tx pgx.Tx, oldCustomerId int) (string, error) {
+	rows, err := tx.Query(ctx, "SELECT COALESCE(merged_into, id) FROM customers WHERE old_id = $1", oldCustomerId)
+	if err != nil {
+		return "", err
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return "", err
+	}
+
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("customer %d was not imported", oldCustomerId)
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("%d customers share old_id %d", len(ids), oldCustomerId)
+	}
+}
+
+// legacyImportReason descreve o erro para o relatório; em violações de unicidade o detalhe do
+// Postgres já diz qual valor colidiu (ex.: o telefone de outro cliente).
+func legacyImportReason(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Detail != "" {
+		return pgErr.Message + ": " + pgErr.Detail
+	}
+	return err.Error()
+}
+
+func scanLegacyImportCheckpoint(row pgx.CollectableRow) (domain.LegacyImportCheckpoint, error) {
+	var checkpoint domain.LegacyImportCheckpoint
+	err := row.Scan(&checkpoint.Stage, &checkpoint.LastOldId, &checkpoint.Imported, &checkpoint.Skipped, &checkpoint.CompletedAt)
+	return checkpoint, err
+}
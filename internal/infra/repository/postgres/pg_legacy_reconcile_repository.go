@@ -0,0 +1,243 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PgLegacyReconcileRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPgLegacyReconcileRepository(db *pgxpool.Pool) *PgLegacyReconcileRepository {
+	return &PgLegacyReconcileRepository{db: db}
+}
+
+// legacyCustomerOldIds lista os old_ids do cliente em customerColumn e dos clientes mesclados
+// com ele, para que um vínculo com o cliente mantido conte para os clientes do legado unidos nele.
+func legacyCustomerOldIds(customerColumn string) string {
+	return fmt.Sprintf(`COALESCE((
+		SELECT array_agg(DISTINCT c.old_id ORDER BY c.old_id)
+		FROM customers linked
+		JOIN customers c ON COALESCE(c.merged_into, c.id) = COALESCE(linked.merged_into, linked.id)
+		WHERE linked.id = %s AND c.old_id IS NOT NULL
+	), '{}'::int[])`, customerColumn)
+}
+
+func (r *PgLegacyReconcileRepository) Counts(ctx context.Context) (map[string]int, error) {
+	ctx, span := startSpan(ctx, "PgLegacyReconcileRepository.Counts")
+	defer span.End()
+
+	query, args := legacyCountsQuery()
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error counting imported rows: %w", err)
+	}
+	defer rows.Close()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var stage string
+		var count int
+		if err := rows.Scan(&stage, &count); err != nil {
+			return nil, fmt.Errorf("error scanning imported rows count: %w", err)
+		}
+		counts[stage] = count
+	}
+	return counts, rows.Err()
+}
+
+func (r *PgLegacyReconcileRepository) Products(ctx context.Context, oldIds []int) (map[int]domain.LegacyReconcileProduct, error) {
+	ctx, span := startSpan(ctx, "PgLegacyReconcileRepository.Products")
+	defer span.End()
+
+	query, args := legacyProductsQuery(oldIds)
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching imported products: %w", err)
+	}
+	products, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.LegacyReconcileProduct, error) {
+		var product domain.LegacyReconcileProduct
+		err := row.Scan(&product.OldId, &product.Value, &product.UnityType, &product.Copies)
+		return product, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error scanning imported products: %w", err)
+	}
+	return indexByOldId(products, func(p domain.LegacyReconcileProduct) int { return p.OldId }), nil
+}
+
+func (r *PgLegacyReconcileRepository) Customers(ctx context.Context, oldIds []int) (map[int]domain.LegacyReconcileCustomer, error) {
+	ctx, span := startSpan(ctx, "PgLegacyReconcileRepository.Customers")
+	defer span.End()
+
+	query, args := legacyCustomersQuery(oldIds)
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching imported customers: %w", err)
+	}
+	customers, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.LegacyReconcileCustomer, error) {
+		var customer domain.LegacyReconcileCustomer
+		err := row.Scan(&customer.OldId, &customer.Phone, &customer.Phone2, &customer.Copies)
+		return customer, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error scanning imported customers: %w", err)
+	}
+	return indexByOldId(customers, func(c domain.LegacyReconcileCustomer) int { return c.OldId }), nil
+}
+
+func (r *PgLegacyReconcileRepository) Addresses(ctx context.Context, oldIds []int) (map[int]domain.LegacyReconcileAddress, error) {
+	ctx, span := startSpan(ctx, "PgLegacyReconcileRepository.Addresses")
+	defer span.End()
+
+	query, args := legacyAddressesQuery(oldIds)
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching imported addresses: %w", err)
+	}
+	addresses, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.LegacyReconcileAddress, error) {
+		var address domain.LegacyReconcileAddress
+		err := row.Scan(&address.OldId, &address.Copies, &address.CustomerOldIds)
+		return address, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error scanning imported addresses: %w", err)
+	}
+	return indexByOldId(addresses, func(a domain.LegacyReconcileAddress) int { return a.OldId }), nil
+}
+
+// Orders devolve os pedidos com os itens, lidos em uma segunda consulta.
+func (r *PgLegacyReconcileRepository) Orders(ctx context.Context, oldIds []int) (map[int]domain.LegacyReconcileOrder, error) {
+	ctx, span := startSpan(ctx, "PgLegacyReconcileRepository.Orders")
+	defer span.End()
+
+	query, args := legacyOrdersQuery(oldIds)
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching imported orders: %w", err)
+	}
+	defer rows.Close()
+
+	orders := map[int]domain.LegacyReconcileOrder{}
+	orderIds := []string{}
+	oldIdsById := map[string]int{}
+	for rows.Next() {
+		var id string
+		var order domain.LegacyReconcileOrder
+		if err := rows.Scan(&id, &order.OldId, &order.Number, &order.Status, &order.IsDeleted, &order.Copies, &order.CustomerOldIds); err != nil {
+			return nil, fmt.Errorf("error scanning imported order: %w", err)
+		}
+		order.Items = []domain.LegacyReconcileOrderItem{}
+		orders[order.OldId] = order
+		orderIds = append(orderIds, id)
+		oldIdsById[id] = order.OldId
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading imported orders: %w", err)
+	}
+	if len(orderIds) == 0 {
+		return orders, nil
+	}
+
+	query, args = legacyOrderItemsQuery(orderIds)
+	itemRows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching imported order items: %w", err)
+	}
+	defer itemRows.Close()
+
+	for itemRows.Next() {
+		var orderId string
+		var item domain.LegacyReconcileOrderItem
+		if err := itemRows.Scan(&orderId, &item.OldProductId, &item.Quantity, &item.UnityType, &item.Price, &item.OldSubProductIds); err != nil {
+			return nil, fmt.Errorf("error scanning imported order item: %w", err)
+		}
+		order := orders[oldIdsById[orderId]]
+		order.Items = append(order.Items, item)
+		orders[order.OldId] = order
+	}
+	return orders, itemRows.Err()
+}
+
+// As consultas da reconciliação ficam separadas dos métodos para que o SQL possa ser testado
+// sem banco. Cada old_id pode ter mais de uma cópia; a linha comparada é a ativa e a contagem
+// de cópias vai junto.
+
+func legacyCountsQuery() (string, []any) {
+	return `
+		SELECT $1::text, count(DISTINCT old_id) FROM products
+		UNION ALL SELECT $2::text, count(DISTINCT old_id) FROM customers
+		UNION ALL SELECT $3::text, count(DISTINCT old_id) FROM addresses
+		UNION ALL SELECT $4::text, count(DISTINCT old_id) FROM orders
+	`, []any{domain.LegacyStageProducts, domain.LegacyStageCustomers, domain.LegacyStageAddresses, domain.LegacyStageOrders}
+}
+
+func legacyProductsQuery(oldIds []int) (string, []any) {
+	return `
+		SELECT DISTINCT ON (old_id) old_id, value, trim(unity_type), count(*) OVER (PARTITION BY old_id)
+		FROM products
+		WHERE old_id = ANY($1)
+		ORDER BY old_id, is_deleted
+	`, []any{oldIds}
+}
+
+func legacyCustomersQuery(oldIds []int) (string, []any) {
+	return `
+		SELECT DISTINCT ON (old_id) old_id, phone, phone2, count(*) OVER (PARTITION BY old_id)
+		FROM customers
+		WHERE old_id = ANY($1)
+		ORDER BY old_id, is_deleted
+	`, []any{oldIds}
+}
+
+func legacyAddressesQuery(oldIds []int) (string, []any) {
+	return fmt.Sprintf(`
+		SELECT DISTINCT ON (a.old_id) a.old_id, count(*) OVER (PARTITION BY a.old_id),
+		       COALESCE((
+		           SELECT array_agg(DISTINCT customer_old_ids.old_id ORDER BY customer_old_ids.old_id)
+		           FROM address_customers ac, unnest(%s) AS customer_old_ids(old_id)
+		           WHERE ac.address_id = a.id
+		       ), '{}'::int[])
+		FROM addresses a
+		WHERE a.old_id = ANY($1)
+		ORDER BY a.old_id, a.id
+	`, legacyCustomerOldIds("ac.customer_id")), []any{oldIds}
+}
+
+func legacyOrdersQuery(oldIds []int) (string, []any) {
+	return fmt.Sprintf(`
+		SELECT DISTINCT ON (o.old_id) o.id, o.old_id, o.order_number, o.status, o.is_deleted,
+		       count(*) OVER (PARTITION BY o.old_id), %s
+		FROM orders o
+		WHERE o.old_id = ANY($1)
+		ORDER BY o.old_id, o.is_deleted, o.created_at
+	`, legacyCustomerOldIds("o.customer_id")), []any{oldIds}
+}
+
+func legacyOrderItemsQuery(orderIds []string) (string, []any) {
+	return `
+		SELECT op.order_id, COALESCE(p.old_id, 0), op.quantity, trim(op.unity_type), op.price,
+		       COALESCE((
+		           SELECT array_agg(sp.old_id ORDER BY sp.old_id)
+		           FROM order_sub_products osp
+		           JOIN products sp ON sp.id = osp.product_id
+		           WHERE osp.order_product_id = op.id AND sp.old_id IS NOT NULL
+		       ), '{}'::int[])
+		FROM order_products op
+		JOIN products p ON p.id = op.product_id
+		WHERE op.order_id = ANY($1)
+	`, []any{orderIds}
+}
+
+func indexByOldId[T any](rows []T, oldId func(T) int) map[int]T {
+	byOldId := make(map[int]T, len(rows))
+	for _, row := range rows {
+		byOldId[oldId(row)] = row
+	}
+	return byOldId
+}
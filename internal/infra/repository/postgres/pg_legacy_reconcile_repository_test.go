@@ -0,0 +1,209 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+// highestPlaceholder devolve o maior $n da consulta, que precisa bater com a quantidade de argumentos.
+func highestPlaceholder(sql string) int {
+	highest := 0
+	for _, match := range regexp.MustCompile(`\$(\d+)`).FindAllStringSubmatch(sql, -1) {
+		n, _ := strconv.Atoi(match[1])
+		highest = max(highest, n)
+	}
+	return highest
+}
+
+// normalizeSQL junta os espaços da consulta para comparar trechos sem depender da indentação.
+func normalizeSQL(sql string) string {
+	return strings.Join(strings.Fields(sql), " ")
+}
+
+func TestLegacyReconcileQueries(t *testing.T) {
+	oldIds := []int{1, 2}
+
+	t.Run("should pass one argument per placeholder", func(t *testing.T) {
+		queries := map[string]func() (string, []any){
+			"counts":    legacyCountsQuery,
+			"products":  func() (string, []any) { return legacyProductsQuery(oldIds) },
+			"customers": func() (string, []any) { return legacyCustomersQuery(oldIds) },
+			"addresses": func() (string, []any) { return legacyAddressesQuery(oldIds) },
+			"orders":    func() (string, []any) { return legacyOrdersQuery(oldIds) },
+			"items":     func() (string, []any) { return legacyOrderItemsQuery([]string{"o1"}) },
+		}
+		for name, query := range queries {
+			sql, args := query()
+			if highest := highestPlaceholder(sql); highest != len(args) {
+				t.Errorf("expected the %s query to use %d placeholders, but it uses %d", name, len(args), highest)
+			}
+		}
+	})
+
+	t.Run("should count every stage by distinct old_id", func(t *testing.T) {
+		sql, args := legacyCountsQuery()
+
+		expected := []any{domain.LegacyStageProducts, domain.LegacyStageCustomers, domain.LegacyStageAddresses, domain.LegacyStageOrders}
+		if !slices.Equal(args, expected) {
+			t.Errorf("expected args %v, but got %v", expected, args)
+		}
+		if strings.Count(sql, "count(DISTINCT old_id)") != 4 {
+			t.Errorf("expected each stage to count distinct old_ids, but got %s", sql)
+		}
+	})
+
+	t.Run("should compare the active copy and report how many copies exist", func(t *testing.T) {
+		for name, sql := range map[string]string{
+			"products":  func() string { sql, _ := legacyProductsQuery(oldIds); return sql }(),
+			"customers": func() string { sql, _ := legacyCustomersQuery(oldIds); return sql }(),
+		} {
+			sql = normalizeSQL(sql)
+			if !strings.Contains(sql, "SELECT DISTINCT ON (old_id)") || !strings.Contains(sql, "ORDER BY old_id, is_deleted") {
+				t.Errorf("expected the %s query to prefer the row not deleted, but got %s", name, sql)
+			}
+			if !strings.Contains(sql, "count(*) OVER (PARTITION BY old_id)") {
+				t.Errorf("expected the %s query to count the copies, but got %s", name, sql)
+			}
+		}
+
+		sql, args := legacyOrdersQuery(oldIds)
+		if !strings.Contains(normalizeSQL(sql), "ORDER BY o.old_id, o.is_deleted, o.created_at") {
+			t.Errorf("expected the orders query to prefer the oldest active copy, but got %s", sql)
+		}
+		if ids, ok := args[0].([]int); !ok || !slices.Equal(ids, oldIds) {
+			t.Errorf("expected the old ids as the only argument, but got %v", args)
+		}
+	})
+
+	t.Run("should resolve customers through merges", func(t *testing.T) {
+		customers := normalizeSQL(legacyCustomerOldIds("o.customer_id"))
+		if !strings.Contains(customers, "COALESCE(c.merged_into, c.id) = COALESCE(linked.merged_into, linked.id)") || !strings.Contains(customers, "WHERE linked.id = o.customer_id") {
+			t.Errorf("unexpected merged customers sql %s", customers)
+		}
+
+		orders, _ := legacyOrdersQuery(oldIds)
+		if !strings.Contains(normalizeSQL(orders), customers) {
+			t.Errorf("expected the orders query to list the merged customers, but got %s", orders)
+		}
+		addresses, _ := legacyAddressesQuery(oldIds)
+		if !strings.Contains(normalizeSQL(addresses), normalizeSQL(legacyCustomerOldIds("ac.customer_id"))) {
+			t.Errorf("expected the addresses query to list the merged customers of each link, but got %s", addresses)
+		}
+	})
+
+	t.Run("should read the items with their legacy sub products", func(t *testing.T) {
+		sql, args := legacyOrderItemsQuery([]string{"o1", "o2"})
+		sql = normalizeSQL(sql)
+
+		if !strings.Contains(sql, "WHERE osp.order_product_id = op.id AND sp.old_id IS NOT NULL") {
+			t.Errorf("expected sub products without old_id to be ignored, but got %s", sql)
+		}
+		if !strings.Contains(sql, "WHERE op.order_id = ANY($1)") {
+			t.Errorf("expected the items to be filtered by order, but got %s", sql)
+		}
+		if ids, ok := args[0].([]string); !ok || !slices.Equal(ids, []string{"o1", "o2"}) {
+			t.Errorf("expected the order ids as the only argument, but got %v", args)
+		}
+	})
+}
+
+func TestPgLegacyReconcileRepository(t *testing.T) {
+	pool := fixturePool(t)
+
+	const (
+		product        = "00000000-0000-4000-8000-000000000b01"
+		deletedProduct = "00000000-0000-4000-8000-000000000b02"
+		sauce          = "00000000-0000-4000-8000-000000000b03"
+		kept           = "00000000-0000-4000-8000-000000000b11"
+		merged         = "00000000-0000-4000-8000-000000000b12"
+		address        = "00000000-0000-4000-8000-000000000b21"
+		order          = "00000000-0000-4000-8000-000000000b31"
+		deletedOrder   = "00000000-0000-4000-8000-000000000b32"
+		item           = "00000000-0000-4000-8000-000000000b41"
+	)
+	// Os old_ids ficam longe dos usados pela importação para não somar com dados já carregados
+	oldIds := []int{990001, 990002}
+	cleanup := func() {
+		fixtureExec(t, pool,
+			"DELETE FROM orders WHERE id IN ('"+order+"', '"+deletedOrder+"')",
+			"DELETE FROM address_customers WHERE address_id = '"+address+"'",
+			"DELETE FROM addresses WHERE id = '"+address+"'",
+			"DELETE FROM customers WHERE id IN ('"+kept+"', '"+merged+"')",
+			"DELETE FROM products WHERE id IN ('"+product+"', '"+deletedProduct+"', '"+sauce+"')",
+		)
+	}
+	cleanup()
+	t.Cleanup(cleanup)
+
+	fixtureExec(t, pool,
+		`INSERT INTO products (id, old_id, name, value, unity_type, is_deleted) VALUES
+			('`+product+`', 990001, 'Nhoque Fixture', 5800, 'KG', false),
+			('`+deletedProduct+`', 990001, 'Nhoque Fixture', 4000, 'UN', true),
+			('`+sauce+`', 990002, 'Molho Fixture', 0, 'UN', false)`,
+		`INSERT INTO customers (id, old_id, name, phone, is_deleted) VALUES
+			('`+kept+`', 990001, 'Helena Fixture', '+5511990000101', false),
+			('`+merged+`', 990002, 'Helena Fixture', '+5511990000102', true)`,
+		"UPDATE customers SET merged_into = '"+kept+"' WHERE id = '"+merged+"'",
+		`INSERT INTO addresses (id, old_id, cep, street, number, neighborhood, city, state, aditional_details, distance)
+			VALUES ('`+address+`', 990001, '01001000', 'Praça da Sé', '1', 'Sé', 'São Paulo', 'SP', '', 0)`,
+		"INSERT INTO address_customers (address_id, customer_id) VALUES ('"+address+"', '"+kept+"')",
+		// O pedido do cliente mesclado continua ligado a ele, e a cópia excluída é mais antiga
+		`INSERT INTO orders (id, old_id, order_number, pickup_date, customer_id, employee_id, status, is_deleted, created_at) VALUES
+			('`+deletedOrder+`', 990001, 990001, now(), '`+merged+`', 'legacy', 'pending', true, now() - interval '1 day'),
+			('`+order+`', 990001, 990001, now(), '`+merged+`', 'legacy', 'picked_up', false, now())`,
+		"INSERT INTO order_products (id, order_id, product_id, quantity, unity_type, price) VALUES ('"+item+"', '"+order+"', '"+product+"', 1500, 'KG', 5800)",
+		"INSERT INTO order_sub_products (order_product_id, product_id) VALUES ('"+item+"', '"+sauce+"')",
+	)
+
+	repo := NewPgLegacyReconcileRepository(pool)
+	ctx := context.Background()
+
+	t.Run("should compare the active product and count its copies", func(t *testing.T) {
+		products, err := repo.Products(ctx, oldIds)
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if got := products[990001]; got.Value != 5800 || got.UnityType != "KG" || got.Copies != 2 {
+			t.Errorf("expected the active product with 2 copies, but got %+v", got)
+		}
+		if _, ok := products[990002]; !ok || len(products) != 2 {
+			t.Errorf("expected only the fixture products, but got %+v", products)
+		}
+	})
+
+	t.Run("should list the merged customers of the address", func(t *testing.T) {
+		addresses, err := repo.Addresses(ctx, oldIds)
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if got := addresses[990001]; !slices.Equal(got.CustomerOldIds, []int{990001, 990002}) || got.Copies != 1 {
+			t.Errorf("expected the address of both merged customers, but got %+v", got)
+		}
+	})
+
+	t.Run("should compare the active order with its items and sub products", func(t *testing.T) {
+		orders, err := repo.Orders(ctx, oldIds)
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		got := orders[990001]
+		if got.Status != "picked_up" || got.IsDeleted || got.Copies != 2 || got.Number != 990001 {
+			t.Errorf("expected the active order with 2 copies, but got %+v", got)
+		}
+		if !slices.Equal(got.CustomerOldIds, []int{990001, 990002}) {
+			t.Errorf("expected the order to count for both merged customers, but got %v", got.CustomerOldIds)
+		}
+		if len(got.Items) != 1 {
+			t.Fatalf("expected one item, but got %+v", got.Items)
+		}
+		if item := got.Items[0]; item.OldProductId != 990001 || item.Quantity != 1500 || item.UnityType != "KG" || item.Price != 5800 || !slices.Equal(item.OldSubProductIds, []int{990002}) {
+			t.Errorf("unexpected item %+v", item)
+		}
+	})
+}
@@ -0,0 +1,380 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// errLegacySyncConflict interrompe a gravação de uma linha editada pela API; o conflito já foi
+// registrado no savepoint da linha.
+var errLegacySyncConflict = errors.New("row was edited in the API")
+
+// errLegacySyncConflictOpen interrompe a gravação de uma linha que já tem conflito aberto. O
+// conflito foi reportado quando detectado, então não volta no resultado como novo.
+var errLegacySyncConflictOpen = errors.New("row has an open sync conflict")
+
+type PgLegacySyncRepository struct {
+	db legacyImportDB
+}
+
+func NewPgLegacySyncRepository(db *pgxpool.Pool) *PgLegacySyncRepository {
+	return &PgLegacySyncRepository{db: db}
+}
+
+func (r *PgLegacySyncRepository) DryRun(ctx context.Context) (domain.LegacySyncRepository, func(), error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error starting dry run transaction: %w", err)
+	}
+	rollback := func() { _ = tx.Rollback(context.Background()) }
+	return &PgLegacySyncRepository{db: tx}, rollback, nil
+}
+
+func (r *PgLegacySyncRepository) Watermarks(ctx context.Context) (map[string]int, error) {
+	ctx, span := startSpan(ctx, "PgLegacySyncRepository.Watermarks")
+	defer span.End()
+
+	rows, err := r.db.Query(ctx, `
+		SELECT stage, max(last_old_id)
+		FROM (
+			SELECT stage, last_old_id FROM legacy_import_checkpoints
+			UNION ALL
+			SELECT stage, max(old_id) FROM legacy_sync_rows GROUP BY stage
+		) watermarks
+		GROUP BY stage
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching sync watermarks: %w", err)
+	}
+	defer rows.Close()
+
+	watermarks := map[string]int{}
+	for rows.Next() {
+		var stage string
+		var lastOldId int
+		if err := rows.Scan(&stage, &lastOldId); err != nil {
+			return nil, fmt.Errorf("error scanning sync watermark: %w", err)
+		}
+		watermarks[stage] = lastOldId
+	}
+	return watermarks, rows.Err()
+}
+
+func (r *PgLegacySyncRepository) FailedOldIds(ctx context.Context) (map[string][]int, error) {
+	ctx, span := startSpan(ctx, "PgLegacySyncRepository.FailedOldIds")
+	defer span.End()
+
+	rows, err := r.db.Query(ctx, `
+		SELECT stage, old_id FROM legacy_sync_rows WHERE last_error IS NOT NULL ORDER BY stage, old_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching failed sync rows: %w", err)
+	}
+	defer rows.Close()
+
+	failed := map[string][]int{}
+	for rows.Next() {
+		var stage string
+		var oldId int
+		if err := rows.Scan(&stage, &oldId); err != nil {
+			return nil, fmt.Errorf("error scanning failed sync row: %w", err)
+		}
+		failed[stage] = append(failed[stage], oldId)
+	}
+	return failed, rows.Err()
+}
+
+// Fingerprints ignora as linhas cuja última gravação falhou, para que sejam gravadas de novo
+// mesmo sem mudança no legado.
+func (r *PgLegacySyncRepository) Fingerprints(ctx context.Context, stage string, oldIds []int) (map[int]string, error) {
+	ctx, span := startSpan(ctx, "PgLegacySyncRepository.Fingerprints")
+	defer span.End()
+
+	rows, err := r.db.Query(ctx, `
+		SELECT old_id, fingerprint FROM legacy_sync_rows WHERE stage = $1 AND old_id = ANY($2) AND fingerprint IS NOT NULL AND last_error IS NULL
+	`, stage, oldIds)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching sync fingerprints: %w", err)
+	}
+	defer rows.Close()
+
+	fingerprints := map[int]string{}
+	for rows.Next() {
+		var oldId int
+		var fingerprint string
+		if err := rows.Scan(&oldId, &fingerprint); err != nil {
+			return nil, fmt.Errorf("error scanning sync fingerprint: %w", err)
+		}
+		fingerprints[oldId] = fingerprint
+	}
+	return fingerprints, rows.Err()
+}
+
+func (r *PgLegacySyncRepository) SyncProducts(ctx context.Context, batch domain.LegacySyncBatch[domain.LegacyProduct]) (*domain.LegacySyncBatchResult, error) {
+	ctx, span := startSpan(ctx, "PgLegacySyncRepository.SyncProducts")
+	defer span.End()
+
+	return syncLegacyBatch(ctx, r.db, batch, syncLegacyRow(importLegacyProduct))
+}
+
+func (r *PgLegacySyncRepository) SyncCustomers(ctx context.Context, batch domain.LegacySyncBatch[domain.LegacyCustomer]) (*domain.LegacySyncBatchResult, error) {
+	ctx, span := startSpan(ctx, "PgLegacySyncRepository.SyncCustomers")
+	defer span.End()
+
+	return syncLegacyBatch(ctx, r.db, batch, syncLegacyRowUnlessEdited("customers", batch, importLegacyCustomer))
+}
+
+func (r *PgLegacySyncRepository) SyncAddresses(ctx context.Context, batch domain.LegacySyncBatch[domain.LegacyAddress]) (*domain.LegacySyncBatchResult, error) {
+	ctx, span := startSpan(ctx, "PgLegacySyncRepository.SyncAddresses")
+	defer span.End()
+
+	return syncLegacyBatch(ctx, r.db, batch, syncLegacyRow(importLegacyAddress))
+}
+
+func (r *PgLegacySyncRepository) SyncOrders(ctx context.Context, batch domain.LegacySyncBatch[domain.LegacyOrder]) (*domain.LegacySyncBatchResult, error) {
+	ctx, span := startSpan(ctx, "PgLegacySyncRepository.SyncOrders")
+	defer span.End()
+
+	return syncLegacyBatch(ctx, r.db, batch, syncLegacyRowUnlessEdited("orders", batch, importLegacyOrder))
+}
+
+func (r *PgLegacySyncRepository) Conflicts(ctx context.Context) ([]domain.LegacySyncConflict, error) {
+	ctx, span := startSpan(ctx, "PgLegacySyncRepository.Conflicts")
+	defer span.End()
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, stage, old_id, record_id, detected_at, resolved_at, resolution
+		FROM legacy_sync_conflicts
+		WHERE resolved_at IS NULL
+		ORDER BY detected_at, old_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching sync conflicts: %w", err)
+	}
+	conflicts, err := pgx.CollectRows(rows, scanLegacySyncConflict)
+	if err != nil {
+		return nil, fmt.Errorf("error scanning sync conflicts: %w", err)
+	}
+	return conflicts, nil
+}
+
+func (r *PgLegacySyncRepository) KeepApiVersion(ctx context.Context, stage string, oldId int) (*domain.LegacySyncConflict, error) {
+	ctx, span := startSpan(ctx, "PgLegacySyncRepository.KeepApiVersion")
+	defer span.End()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, `
+		UPDATE legacy_sync_conflicts SET resolved_at = now(), resolution = $3
+		WHERE stage = $1 AND old_id = $2 AND resolved_at IS NULL
+		RETURNING id, stage, old_id, record_id, detected_at, resolved_at, resolution
+	`, stage, oldId, domain.LegacySyncKeepApi)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving sync conflict: %w", err)
+	}
+	conflict, err := pgx.CollectExactlyOneRow(rows, scanLegacySyncConflict)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.NewLegacySyncConflictNotFoundError(stage, oldId)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error resolving sync conflict: %w", err)
+	}
+
+	// A versão do legado que gerou o conflito passa a contar como gravada, e as edições da API
+	// até agora como aceitas
+	_, err = tx.Exec(ctx, `
+		INSERT INTO legacy_sync_rows (stage, old_id, fingerprint, synced_at)
+		SELECT stage, old_id, fingerprint, now() FROM legacy_sync_conflicts WHERE id = $1
+		ON CONFLICT (stage, old_id) DO UPDATE
+		SET fingerprint = EXCLUDED.fingerprint, synced_at = EXCLUDED.synced_at, last_error = NULL, updated_at = now()
+	`, conflict.Id)
+	if err != nil {
+		return nil, fmt.Errorf("error recording sync version: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return &conflict, nil
+}
+
+// syncLegacyBatch grava o lote em uma transação, cada linha em um savepoint, e registra em
+// legacy_sync_rows a versão gravada ou o erro. Um erro só substitui last_error: a versão
+// anterior continua valendo como a última gravada.
+func syncLegacyBatch[T any](
+	ctx context.Context,
+	db legacyImportDB,
+	batch domain.LegacySyncBatch[T],
+	syncRow func(ctx context.Context, tx pgx.Tx, row domain.LegacySyncRow[T]) error,
+) (*domain.LegacySyncBatchResult, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting sync batch: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	result := &domain.LegacySyncBatchResult{Failed: []domain.LegacyImportError{}, Conflicts: []domain.LegacySyncConflict{}}
+	for _, row := range batch.Rows {
+		reason := row.Error
+		if reason == "" {
+			savepoint, err := tx.Begin(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("error starting savepoint: %w", err)
+			}
+
+			syncErr := syncRow(ctx, savepoint, row)
+			conflicted := errors.Is(syncErr, errLegacySyncConflict) || errors.Is(syncErr, errLegacySyncConflictOpen)
+			if syncErr != nil && !conflicted {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				if err := savepoint.Rollback(ctx); err != nil {
+					return nil, fmt.Errorf("error rolling back savepoint: %w", err)
+				}
+				reason = legacyImportReason(syncErr)
+			} else if err := savepoint.Commit(ctx); err != nil {
+				return nil, fmt.Errorf("error releasing savepoint: %w", err)
+			}
+
+			if errors.Is(syncErr, errLegacySyncConflictOpen) {
+				continue
+			}
+			if errors.Is(syncErr, errLegacySyncConflict) {
+				conflict, err := findOpenLegacySyncConflict(ctx, tx, batch.Stage, row.OldId)
+				if err != nil {
+					return nil, err
+				}
+				result.Conflicts = append(result.Conflicts, *conflict)
+				continue
+			}
+		}
+
+		if reason != "" {
+			_, err := tx.Exec(ctx, `
+				INSERT INTO legacy_sync_rows (stage, old_id, last_error) VALUES ($1, $2, $3)
+				ON CONFLICT (stage, old_id) DO UPDATE SET last_error = EXCLUDED.last_error, updated_at = now()
+			`, batch.Stage, row.OldId, reason)
+			if err != nil {
+				return nil, fmt.Errorf("error recording sync error: %w", err)
+			}
+			result.Failed = append(result.Failed, domain.LegacyImportError{Stage: batch.Stage, OldId: strconv.Itoa(row.OldId), Reason: reason})
+			continue
+		}
+
+		_, err := tx.Exec(ctx, `
+			INSERT INTO legacy_sync_rows (stage, old_id, fingerprint, synced_at) VALUES ($1, $2, $3, now())
+			ON CONFLICT (stage, old_id) DO UPDATE
+			SET fingerprint = EXCLUDED.fingerprint, synced_at = EXCLUDED.synced_at, last_error = NULL, updated_at = now()
+		`, batch.Stage, row.OldId, row.Fingerprint)
+		if err != nil {
+			return nil, fmt.Errorf("error recording sync version: %w", err)
+		}
+		result.Synced++
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing sync batch: %w", err)
+	}
+	return result, nil
+}
+
+// syncLegacyRow adapta as funções da importação, que gravam só o conteúdo da linha.
+func syncLegacyRow[T any](importRow func(ctx context.Context, tx pgx.Tx, row T) error) func(ctx context.Context, tx pgx.Tx, row domain.LegacySyncRow[T]) error {
+	return func(ctx context.Context, tx pgx.Tx, row domain.LegacySyncRow[T]) error {
+		return importRow(ctx, tx, row.Row)
+	}
+}
+
+// syncLegacyRowUnlessEdited grava a linha só se o registro de table com o mesmo old_id não foi
+// editado pela API, ou seja, não tem updated_at posterior à última gravação da sincronização ou,
+// para linhas que ela ainda não gravou, ao fim da importação da etapa.
+func syncLegacyRowUnlessEdited[T any](
+	table string,
+	batch domain.LegacySyncBatch[T],
+	importRow func(ctx context.Context, tx pgx.Tx, row T) error,
+) func(ctx context.Context, tx pgx.Tx, row domain.LegacySyncRow[T]) error {
+	return func(ctx context.Context, tx pgx.Tx, row domain.LegacySyncRow[T]) error {
+		if batch.Force {
+			_, err := tx.Exec(ctx, `
+				UPDATE legacy_sync_conflicts SET resolved_at = now(), resolution = $3
+				WHERE stage = $1 AND old_id = $2 AND resolved_at IS NULL
+			`, batch.Stage, row.OldId, domain.LegacySyncKeepLegacy)
+			if err != nil {
+				return err
+			}
+			return importRow(ctx, tx, row.Row)
+		}
+
+		// Com um conflito já aberto só a versão do legado guardada nele é atualizada, para que
+		// manter a API descarte também as alterações posteriores
+		result, err := tx.Exec(ctx, `
+			UPDATE legacy_sync_conflicts SET fingerprint = $3
+			WHERE stage = $1 AND old_id = $2 AND resolved_at IS NULL
+		`, batch.Stage, row.OldId, row.Fingerprint)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() > 0 {
+			return errLegacySyncConflictOpen
+		}
+
+		var editedId *string
+		err = tx.QueryRow(ctx, `
+			SELECT t.id
+			FROM `+table+` t
+			WHERE t.old_id = $2
+			  AND t.updated_at > COALESCE(
+			      (SELECT synced_at FROM legacy_sync_rows WHERE stage = $1 AND old_id = $2),
+			      (SELECT completed_at FROM legacy_import_checkpoints WHERE stage = $1)
+			  )
+			LIMIT 1
+		`, batch.Stage, row.OldId).Scan(&editedId)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		if editedId == nil {
+			return importRow(ctx, tx, row.Row)
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO legacy_sync_conflicts (stage, old_id, record_id, fingerprint)
+			VALUES ($1, $2, $3, $4)
+		`, batch.Stage, row.OldId, *editedId, row.Fingerprint)
+		if err != nil {
+			return err
+		}
+		return errLegacySyncConflict
+	}
+}
+
+func findOpenLegacySyncConflict(ctx context.Context, tx pgx.Tx, stage string, oldId int) (*domain.LegacySyncConflict, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT id, stage, old_id, record_id, detected_at, resolved_at, resolution
+		FROM legacy_sync_conflicts
+		WHERE stage = $1 AND old_id = $2 AND resolved_at IS NULL
+	`, stage, oldId)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching sync conflict: %w", err)
+	}
+	conflict, err := pgx.CollectExactlyOneRow(rows, scanLegacySyncConflict)
+	if err != nil {
+		return nil, fmt.Errorf("error scanning sync conflict: %w", err)
+	}
+	return &conflict, nil
+}
+
+func scanLegacySyncConflict(row pgx.CollectableRow) (domain.LegacySyncConflict, error) {
+	var conflict domain.LegacySyncConflict
+	err := row.Scan(&conflict.Id, &conflict.Stage, &conflict.OldId, &conflict.RecordId, &conflict.DetectedAt, &conflict.ResolvedAt, &conflict.Resolution)
+	return conflict, err
+}
@@ -0,0 +1,188 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PgMaintenanceRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPgMaintenanceRepository(db *pgxpool.Pool) *PgMaintenanceRepository {
+	return &PgMaintenanceRepository{db: db}
+}
+
+// purgeTarget descreve como expurgar uma entidade: referenced é a condição (sobre o alias t)
+// que mantém a linha no banco e dependents apaga, com os ids em $1, as linhas que apontam para
+// ela e não fazem sentido sozinhas.
+type purgeTarget struct {
+	table      string
+	referenced string
+	dependents []string
+}
+
+var purgeTargets = map[string]purgeTarget{
+	domain.PurgeOrders: {
+		table: "orders",
+		dependents: []string{
+			"DELETE FROM notifications WHERE order_id = ANY($1)",
+			"DELETE FROM print_jobs WHERE order_id = ANY($1)",
+		},
+	},
+	domain.PurgeProducts: {
+		table: "products",
+		referenced: `EXISTS (SELECT 1 FROM order_products WHERE product_id = t.id)
+			OR EXISTS (SELECT 1 FROM order_sub_products WHERE product_id = t.id)`,
+	},
+	domain.PurgeCustomers: {
+		table: "customers",
+		referenced: `EXISTS (SELECT 1 FROM orders WHERE customer_id = t.id)
+			OR EXISTS (SELECT 1 FROM notifications WHERE customer_id = t.id)
+			OR EXISTS (SELECT 1 FROM customer_merges WHERE survivor_id = t.id)
+			OR EXISTS (SELECT 1 FROM customers c WHERE c.merged_into = t.id)`,
+		dependents: []string{
+			"DELETE FROM customer_notes WHERE customer_id = ANY($1)",
+			// Os endereços ficam sem dono quando todos os clientes ligados a eles são expurgados;
+			// saem junto, a menos que algum pedido ainda aponte para eles. O DELETE do CTE não é
+			// visível no DELETE externo, por isso os vínculos expurgados são ignorados à mão.
+			`WITH unlinked AS (
+				DELETE FROM address_customers WHERE customer_id = ANY($1) RETURNING address_id
+			)
+			DELETE FROM addresses a
+			WHERE a.id IN (SELECT address_id FROM unlinked)
+			  AND NOT EXISTS (SELECT 1 FROM address_customers ac WHERE ac.address_id = a.id AND NOT ac.customer_id = ANY($1))
+			  AND NOT EXISTS (SELECT 1 FROM orders o WHERE o.address_id = a.id)`,
+			"DELETE FROM customer_phone_collisions WHERE customer_id = ANY($1) OR conflicting_customer_id = ANY($1)",
+		},
+	},
+	domain.PurgeCategories: {
+		table: "category_products",
+		referenced: `EXISTS (SELECT 1 FROM products WHERE category_id = t.id)
+			OR EXISTS (SELECT 1 FROM category_products c WHERE c.parent_id = t.id)`,
+	},
+}
+
+// ReseedOrderNumbers ajusta a sequência para que o próximo pedido receba o maior número
+// existente mais um, ou minNext quando for maior. A tabela fica travada para inserções
+// enquanto o máximo é lido, para que nenhum pedido seja criado no meio do ajuste.
+func (r *PgMaintenanceRepository) ReseedOrderNumbers(ctx context.Context, minNext int, dryRun bool) (*domain.OrderNumberSeed, error) {
+	ctx, span := startSpan(ctx, "PgMaintenanceRepository.ReseedOrderNumbers")
+	defer span.End()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, "LOCK TABLE orders IN EXCLUSIVE MODE"); err != nil {
+		return nil, fmt.Errorf("error locking orders: %w", err)
+	}
+
+	seed := domain.OrderNumberSeed{}
+	if err := tx.QueryRow(ctx, "SELECT COALESCE(max(order_number), 0) FROM orders").Scan(&seed.MaxNumber); err != nil {
+		return nil, fmt.Errorf("error reading highest order number: %w", err)
+	}
+	seed.NextNumber = max(seed.MaxNumber+1, minNext)
+
+	// setval não é desfeito pelo rollback, então no dry-run não pode ser executado
+	if dryRun {
+		return &seed, nil
+	}
+
+	if _, err := tx.Exec(ctx, "SELECT setval('orders_order_number_seq', $1, false)", seed.NextNumber); err != nil {
+		return nil, fmt.Errorf("error reseeding order numbers: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	seed.Applied = true
+	return &seed, nil
+}
+
+// PurgeDeleted apaga definitivamente as linhas marcadas como excluídas antes de deletedBefore
+// que não são mais referenciadas.
+func (r *PgMaintenanceRepository) PurgeDeleted(ctx context.Context, entity string, deletedBefore time.Time, dryRun bool) (*domain.PurgeResult, error) {
+	ctx, span := startSpan(ctx, "PgMaintenanceRepository.PurgeDeleted")
+	defer span.End()
+
+	target, ok := purgeTargets[entity]
+	if !ok {
+		return nil, domain.NewInvalidPurgeEntityError(entity)
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	referenced := "false"
+	if target.referenced != "" {
+		referenced = target.referenced
+	}
+
+	rows, err := tx.Query(ctx, fmt.Sprintf(`
+		SELECT t.id, %s
+		FROM %s t
+		WHERE t.is_deleted AND t.deleted_at < $1
+		FOR UPDATE
+	`, referenced, target.table), deletedBefore)
+	if err != nil {
+		return nil, fmt.Errorf("error finding deleted %s: %w", entity, err)
+	}
+
+	result := domain.PurgeResult{Entity: entity, DeletedBefore: deletedBefore}
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		var isReferenced bool
+		if err := rows.Scan(&id, &isReferenced); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning deleted %s: %w", entity, err)
+		}
+		if isReferenced {
+			result.Skipped++
+			continue
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error finding deleted %s: %w", entity, err)
+	}
+
+	if len(ids) > 0 {
+		if err := purge(ctx, tx, target, ids); err != nil {
+			return nil, fmt.Errorf("error purging %s: %w", entity, err)
+		}
+	}
+	result.Purged = len(ids)
+
+	if dryRun {
+		return &result, nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	result.Applied = true
+	return &result, nil
+}
+
+func purge(ctx context.Context, tx pgx.Tx, target purgeTarget, ids []string) error {
+	for _, statement := range target.dependents {
+		if _, err := tx.Exec(ctx, statement, ids); err != nil {
+			return err
+		}
+	}
+	_, err := tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE id = ANY($1)", target.table), ids)
+	return err
+}
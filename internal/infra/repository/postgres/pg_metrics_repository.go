@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PgMetricsRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPgMetricsRepository(db *pgxpool.Pool) *PgMetricsRepository {
+	return &PgMetricsRepository{db: db}
+}
+
+func (r *PgMetricsRepository) BusinessStats(ctx context.Context, since time.Time, until time.Time) (*domain.BusinessStats, error) {
+	ctx, span := startSpan(ctx, "PgMetricsRepository.BusinessStats")
+	defer span.End()
+
+	stats := &domain.BusinessStats{
+		OrdersPendingPickup: map[string]int{},
+		NotificationsFailed: map[string]int{},
+	}
+
+	err := r.db.QueryRow(ctx, `
+		SELECT count(*)
+		FROM orders
+		WHERE is_deleted = false AND created_at >= $1 AND created_at < $2
+	`, since, until).Scan(&stats.OrdersCreatedToday)
+	if err != nil {
+		return nil, fmt.Errorf("error counting orders created today: %w", err)
+	}
+
+	// Pedidos com retirada até o fim do dia que ainda não foram entregues, incluindo atrasados
+	rows, err := r.db.Query(ctx, `
+		SELECT status, count(*)
+		FROM orders
+		WHERE is_deleted = false AND status IN ($1, $2) AND pickup_date < $3
+		GROUP BY status
+	`, domain.OrderStatusPending, domain.OrderStatusReady, until)
+	if err != nil {
+		return nil, fmt.Errorf("error counting orders pending pickup: %w", err)
+	}
+	if err := scanCounts(rows, stats.OrdersPendingPickup); err != nil {
+		return nil, fmt.Errorf("error scanning orders pending pickup: %w", err)
+	}
+
+	rows, err = r.db.Query(ctx, `
+		SELECT channel, count(*)
+		FROM notifications
+		WHERE status = $1
+		GROUP BY channel
+	`, domain.NotificationStatusFailed)
+	if err != nil {
+		return nil, fmt.Errorf("error counting failed notifications: %w", err)
+	}
+	if err := scanCounts(rows, stats.NotificationsFailed); err != nil {
+		return nil, fmt.Errorf("error scanning failed notifications: %w", err)
+	}
+
+	return stats, nil
+}
+
+func scanCounts(rows pgx.Rows, counts map[string]int) error {
+	defer rows.Close()
+	for rows.Next() {
+		var key string
+		var count int
+		if err := rows.Scan(&key, &count); err != nil {
+			return err
+		}
+		counts[key] = count
+	}
+	return rows.Err()
+}
@@ -0,0 +1,235 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var notificationColumns = []string{
+	"id",
+	"customer_id",
+	"order_id",
+	"template",
+	"channel",
+	"recipient",
+	"subject",
+	"body",
+	"status",
+	"provider_message_id",
+	"error",
+	"job_id",
+	"created_at",
+	"sent_at",
+}
+
+type PgNotificationRepository struct {
+	db *pgxpool.Pool
+	qb squirrel.StatementBuilderType
+}
+
+func NewPgNotificationRepository(db *pgxpool.Pool) *PgNotificationRepository {
+	return &PgNotificationRepository{
+		db: db,
+		qb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+func (r *PgNotificationRepository) Create(ctx context.Context, notification domain.Notification) (*domain.Notification, error) {
+	ctx, span := startSpan(ctx, "PgNotificationRepository.Create")
+	defer span.End()
+
+	insertBuilder, args, err := r.qb.Insert("notifications").
+		Columns("customer_id", "order_id", "template", "channel", "recipient", "subject", "body", "status", "job_id").
+		Values(
+			notification.CustomerId,
+			notification.OrderId,
+			notification.Template,
+			notification.Channel,
+			notification.Recipient,
+			notification.Subject,
+			notification.Body,
+			notification.Status,
+			notification.JobId,
+		).
+		Suffix("RETURNING id, created_at").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building query to create notification: %w", err)
+	}
+
+	if err := r.db.QueryRow(ctx, insertBuilder, args...).Scan(&notification.Id, &notification.CreatedAt); err != nil {
+		return nil, fmt.Errorf("error creating notification: %w", err)
+	}
+
+	return &notification, nil
+}
+
+func (r *PgNotificationRepository) UpdateStatus(ctx context.Context, id string, status string, providerMessageId *string, errorMessage *string) error {
+	ctx, span := startSpan(ctx, "PgNotificationRepository.UpdateStatus")
+	defer span.End()
+
+	updateBuilder := r.qb.
+		Update("notifications").
+		Set("status", status).
+		Set("provider_message_id", providerMessageId).
+		Set("error", errorMessage).
+		Set("updated_at", squirrel.Expr("now()")).
+		Where(squirrel.Eq{"id": id})
+
+	if status == domain.NotificationStatusSent {
+		updateBuilder = updateBuilder.Set("sent_at", squirrel.Expr("now()"))
+	}
+
+	query, args, err := updateBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("error building query to update notification: %w", err)
+	}
+
+	if _, err := r.db.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("error updating notification status: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PgNotificationRepository) FindByOrderId(ctx context.Context, orderId string) ([]domain.Notification, error) {
+	ctx, span := startSpan(ctx, "PgNotificationRepository.FindByOrderId")
+	defer span.End()
+
+	query, args, err := r.qb.
+		Select(notificationColumns...).
+		From("notifications").
+		Where(squirrel.Eq{"order_id": orderId}).
+		OrderBy("created_at DESC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building notifications query: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching notifications: %w", err)
+	}
+	defer rows.Close()
+
+	return scanNotifications(rows)
+}
+
+// ExistsForOrder indica se o pedido já tem alguma notificação do template que não falhou.
+func (r *PgNotificationRepository) ExistsForOrder(ctx context.Context, orderId string, template string) (bool, error) {
+	ctx, span := startSpan(ctx, "PgNotificationRepository.ExistsForOrder")
+	defer span.End()
+
+	query, args, err := r.qb.
+		Select("1").
+		From("notifications").
+		Where(squirrel.Eq{"order_id": orderId, "template": template}).
+		Where(squirrel.NotEq{"status": domain.NotificationStatusFailed}).
+		Prefix("SELECT EXISTS (").
+		Suffix(")").
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("error building notification exists query: %w", err)
+	}
+
+	var exists bool
+	if err := r.db.QueryRow(ctx, query, args...).Scan(&exists); err != nil {
+		return false, fmt.Errorf("error checking notification: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (r *PgNotificationRepository) FindByJobId(ctx context.Context, jobId string) ([]domain.Notification, error) {
+	ctx, span := startSpan(ctx, "PgNotificationRepository.FindByJobId")
+	defer span.End()
+
+	query, args, err := r.qb.
+		Select(notificationColumns...).
+		From("notifications").
+		Where(squirrel.Eq{"job_id": jobId}).
+		OrderBy("created_at").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building job notifications query: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching job notifications: %w", err)
+	}
+	defer rows.Close()
+
+	return scanNotifications(rows)
+}
+
+func (r *PgNotificationRepository) FindUndelivered(ctx context.Context, since time.Time, orderId string) ([]domain.Notification, error) {
+	ctx, span := startSpan(ctx, "PgNotificationRepository.FindUndelivered")
+	defer span.End()
+
+	selectBuilder := r.qb.
+		Select(notificationColumns...).
+		Options("DISTINCT ON (order_id, template)").
+		From("notifications n").
+		Where(squirrel.NotEq{"order_id": nil}).
+		Where(squirrel.GtOrEq{"created_at": since}).
+		Where(squirrel.Eq{"status": domain.NotificationStatusFailed}).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM notifications delivered
+			WHERE delivered.order_id = n.order_id
+				AND delivered.template = n.template
+				AND delivered.status <> ?
+		)`, domain.NotificationStatusFailed).
+		OrderBy("order_id", "template", "created_at DESC")
+
+	if orderId != "" {
+		selectBuilder = selectBuilder.Where(squirrel.Eq{"order_id": orderId})
+	}
+
+	query, args, err := selectBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building undelivered notifications query: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching undelivered notifications: %w", err)
+	}
+	defer rows.Close()
+
+	return scanNotifications(rows)
+}
+
+func scanNotifications(rows pgx.Rows) ([]domain.Notification, error) {
+	notifications := []domain.Notification{}
+	for rows.Next() {
+		var notification domain.Notification
+		if err := rows.Scan(
+			&notification.Id,
+			&notification.CustomerId,
+			&notification.OrderId,
+			&notification.Template,
+			&notification.Channel,
+			&notification.Recipient,
+			&notification.Subject,
+			&notification.Body,
+			&notification.Status,
+			&notification.ProviderMessageId,
+			&notification.Error,
+			&notification.JobId,
+			&notification.CreatedAt,
+			&notification.SentAt,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning notification: %w", err)
+		}
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, rows.Err()
+}
@@ -3,10 +3,13 @@ package postgres
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/infra/logger"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -23,7 +26,10 @@ func NewPgOrderRepository(db *pgxpool.Pool) *PgOrderRepository {
 	}
 }
 
-func (r *PgOrderRepository) FindAll(pagination domain.Pagination, filters domain.FindAllOrderFilters) ([]domain.Order, domain.Pagination, error) {
+func (r *PgOrderRepository) FindAll(ctx context.Context, pagination domain.Pagination, filters domain.FindAllOrderFilters) ([]domain.Order, domain.Pagination, error) {
+	ctx, span := startSpan(ctx, "PgOrderRepository.FindAll")
+	defer span.End()
+
 	offset := pagination.Limit * (pagination.Page - 1)
 
 	baseBuilder := r.qb.
@@ -32,13 +38,18 @@ func (r *PgOrderRepository) FindAll(pagination domain.Pagination, filters domain
 		Where(squirrel.Eq{"o.is_deleted": false}).
 		Where(squirrel.Expr("o.pickup_date BETWEEN ? AND ?", filters.PickupDateStart, filters.PickupDateEnd))
 
-	if filters.Search != nil {
+	if filters.Search != nil && *filters.Search != "" {
+		searchConditions := squirrel.Or{
+			textMatch("c.name", *filters.Search),
+			squirrel.Eq{"o.pickup_code": domain.NormalizePickupCode(*filters.Search)},
+		}
+		if cond := phoneMatch("c.phone", *filters.Search); cond != nil {
+			searchConditions = append(searchConditions, cond, phoneMatch("c.phone2", *filters.Search))
+		}
+
 		baseBuilder = baseBuilder.
 			Join("customers c ON c.id = o.customer_id").
-			Where(squirrel.Or{
-				squirrel.ILike{"c.name": fmt.Sprintf("%%%s%%", *filters.Search)},
-				squirrel.ILike{"c.phone": fmt.Sprintf("%%%s%%", *filters.Search)},
-			})
+			Where(searchConditions)
 	}
 
 	countBuilder := baseBuilder.Column("count(DISTINCT o.id)")
@@ -49,7 +60,7 @@ func (r *PgOrderRepository) FindAll(pagination domain.Pagination, filters domain
 	}
 
 	var totalCount int
-	err = r.db.QueryRow(context.Background(), totalCountQuery, totalCountArgs...).Scan(&totalCount)
+	err = r.db.QueryRow(ctx, totalCountQuery, totalCountArgs...).Scan(&totalCount)
 	if err != nil {
 		return nil, domain.Pagination{}, fmt.Errorf("error fetching total number of orders: %v", err)
 	}
@@ -72,6 +83,7 @@ func (r *PgOrderRepository) FindAll(pagination domain.Pagination, filters domain
 		Columns(
 			"o.id",
 			"o.order_number",
+			"o.pickup_code",
 			"o.pickup_date",
 			"o.created_at",
 			"o.updated_at",
@@ -79,6 +91,7 @@ func (r *PgOrderRepository) FindAll(pagination domain.Pagination, filters domain
 			"o.order_local",
 			"o.observations",
 			"o.is_picked_up",
+			"o.status",
 		).
 		Column(customerQuery).
 		OrderBy("o.pickup_date DESC").
@@ -90,7 +103,7 @@ func (r *PgOrderRepository) FindAll(pagination domain.Pagination, filters domain
 		return nil, domain.Pagination{}, fmt.Errorf("error building fetch orders query: %w", err)
 	}
 
-	rows, err := r.db.Query(context.Background(), query, args...)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, domain.Pagination{}, fmt.Errorf("error fetching orders: %v", err)
 	}
@@ -105,6 +118,7 @@ func (r *PgOrderRepository) FindAll(pagination domain.Pagination, filters domain
 		if err := rows.Scan(
 			&order.Id,
 			&order.Number,
+			&order.PickupCode,
 			&order.PickupDate,
 			&order.CreatedAt,
 			&order.UpdatedAt,
@@ -112,6 +126,7 @@ func (r *PgOrderRepository) FindAll(pagination domain.Pagination, filters domain
 			&order.OrderLocal,
 			&order.Observations,
 			&order.IsPickedUp,
+			&order.Status,
 			&customerJson,
 		); err != nil {
 			return nil, domain.Pagination{}, fmt.Errorf("error scanning order data: %w", err)
@@ -132,14 +147,18 @@ func (r *PgOrderRepository) FindAll(pagination domain.Pagination, filters domain
 	return orders, pagination, nil
 }
 
-func (r *PgOrderRepository) FindById(id string) (*domain.Order, error) {
+func (r *PgOrderRepository) FindById(ctx context.Context, id string) (*domain.Order, error) {
+	ctx, span := startSpan(ctx, "PgOrderRepository.FindById")
+	defer span.End()
+
 	var order domain.Order
 	var customerJSON, productsJSON string
 	var addressJSON *string
 
-	err := r.db.QueryRow(context.Background(), `
+	err := r.db.QueryRow(ctx, `
 		SELECT o.id,
 			   o.order_number,
+			   o.pickup_code,
 			   o.pickup_date,
 			   o.created_at,
 			   o.updated_at,
@@ -147,6 +166,7 @@ func (r *PgOrderRepository) FindById(id string) (*domain.Order, error) {
 			   o.order_local,
 			   o.observations,
 			   o.is_picked_up,
+			   o.status,
 			   CASE
 				   WHEN a.id IS NULL THEN NULL
 				   ELSE JSON_BUILD_OBJECT(
@@ -166,7 +186,9 @@ func (r *PgOrderRepository) FindById(id string) (*domain.Order, error) {
 				   'name', c.name,
 				   'phone', c.phone,
 				   'phone2', c.phone2,
-				   'email', c.email
+				   'email', c.email,
+				   'tags', c.tags,
+				   'dietaryPreferences', c.dietary_preferences
 			   ) AS customer,
 			   COALESCE((
 				   SELECT JSON_AGG(
@@ -178,6 +200,8 @@ func (r *PgOrderRepository) FindById(id string) (*domain.Order, error) {
 						   'unityType', op.unity_type,
 						   'price', op.price,
 						   'name', p.name,
+						   'observations', op.observations,
+						   'productionStatus', op.production_status,
 						   'subProducts', COALESCE((
 							   SELECT JSON_AGG(
 								   JSON_BUILD_OBJECT(
@@ -204,6 +228,7 @@ func (r *PgOrderRepository) FindById(id string) (*domain.Order, error) {
 	`, id).Scan(
 		&order.Id,
 		&order.Number,
+		&order.PickupCode,
 		&order.PickupDate,
 		&order.CreatedAt,
 		&order.UpdatedAt,
@@ -211,13 +236,17 @@ func (r *PgOrderRepository) FindById(id string) (*domain.Order, error) {
 		&order.OrderLocal,
 		&order.Observations,
 		&order.IsPickedUp,
+		&order.Status,
 		&addressJSON,
 		&customerJSON,
 		&productsJSON,
 	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.NewOrderNotFoundError(id)
+	}
 	if err != nil {
-		fmt.Println("Erro no scan do resultado", err)
-		return nil, fmt.Errorf("order not found: %v", err)
+		logger.FromContext(ctx).Error(fmt.Sprintf("Error scanning order %s", id), err)
+		return nil, fmt.Errorf("error fetching order: %v", err)
 	}
 
 	if addressJSON != nil {
@@ -237,17 +266,91 @@ func (r *PgOrderRepository) FindById(id string) (*domain.Order, error) {
 	return &order, nil
 }
 
-func (r *PgOrderRepository) insertOrderProducts(tx pgx.Tx, orderID string, products []domain.OrderProduct) error {
+// FindByPickupCode localiza o pedido pelo código lido no balcão.
+func (r *PgOrderRepository) FindByPickupCode(ctx context.Context, code string) (*domain.Order, error) {
+	ctx, span := startSpan(ctx, "PgOrderRepository.FindByPickupCode")
+	defer span.End()
+
+	var id string
+	err := r.db.QueryRow(ctx,
+		"SELECT id FROM orders WHERE pickup_code = $1 AND is_deleted = false", code,
+	).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NewPickupCodeNotFoundError(code)
+		}
+		return nil, fmt.Errorf("error fetching order by pickup code: %w", err)
+	}
+
+	return r.FindById(ctx, id)
+}
+
+type doneOrderProduct struct {
+	id        string
+	productId string
+	quantity  int
+	unityType string
+	doneAt    *time.Time
+	doneBy    *string
+}
+
+func (r *PgOrderRepository) findDoneOrderProducts(ctx context.Context, tx pgx.Tx, orderID string) ([]doneOrderProduct, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT id, product_id, quantity, unity_type, done_at, done_by
+		FROM order_products
+		WHERE order_id = $1 AND production_status = 'done'
+	`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching done order products: %w", err)
+	}
+	defer rows.Close()
+
+	lines := []doneOrderProduct{}
+	for rows.Next() {
+		var line doneOrderProduct
+		if err := rows.Scan(&line.id, &line.productId, &line.quantity, &line.unityType, &line.doneAt, &line.doneBy); err != nil {
+			return nil, fmt.Errorf("error scanning done order product: %w", err)
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, rows.Err()
+}
+
+// restoreDoneOrderProducts marca como prontas as linhas recriadas com o mesmo id das que a
+// cozinha já tinha finalizado, desde que continuem iguais (mesmo produto, quantidade e unidade).
+func (r *PgOrderRepository) restoreDoneOrderProducts(ctx context.Context, tx pgx.Tx, orderID string, lines []doneOrderProduct) error {
+	for _, line := range lines {
+		if _, err := tx.Exec(ctx, `
+			UPDATE order_products
+			SET production_status = 'done', done_at = $6, done_by = $7
+			WHERE id = $1 AND order_id = $2 AND product_id = $3 AND quantity = $4 AND unity_type = $5
+		`, line.id, orderID, line.productId, line.quantity, line.unityType, line.doneAt, line.doneBy); err != nil {
+			return fmt.Errorf("error restoring production status: %w", err)
+		}
+	}
+	return nil
+}
+
+// insertOrderProducts grava as linhas do pedido. As que trazem o id de uma linha em keepIds
+// são recriadas com o mesmo id; as demais recebem um id novo.
+func (r *PgOrderRepository) insertOrderProducts(ctx context.Context, tx pgx.Tx, orderID string, products []domain.OrderProduct, keepIds map[string]bool) error {
 	if len(products) == 0 {
 		return nil
 	}
 
 	// Insert new products and get their IDs
 	productsInsertBuilder := r.qb.Insert("order_products").
-		Columns("order_id", "product_id", "quantity", "unity_type", "price")
+		Columns("id", "order_id", "product_id", "quantity", "unity_type", "price", "observations")
 
+	kept := map[string]bool{}
 	for _, p := range products {
-		productsInsertBuilder = productsInsertBuilder.Values(orderID, p.ProductId, p.Quantity, p.UnityType, p.Price)
+		var id any = squirrel.Expr("DEFAULT")
+		if keepIds[p.Id] && !kept[p.Id] {
+			id = p.Id
+			kept[p.Id] = true
+		}
+		productsInsertBuilder = productsInsertBuilder.Values(id, orderID, p.ProductId, p.Quantity, p.UnityType, p.Price, p.Observations)
 	}
 
 	sql, args, err := productsInsertBuilder.Suffix("RETURNING id").ToSql()
@@ -255,7 +358,7 @@ func (r *PgOrderRepository) insertOrderProducts(tx pgx.Tx, orderID string, produ
 		return fmt.Errorf("error building insert products query: %w", err)
 	}
 
-	rows, err := tx.Query(context.Background(), sql, args...)
+	rows, err := tx.Query(ctx, sql, args...)
 	if err != nil {
 		return fmt.Errorf("error inserting products: %w", err)
 	}
@@ -291,7 +394,7 @@ func (r *PgOrderRepository) insertOrderProducts(tx pgx.Tx, orderID string, produ
 
 	if len(subProductRows) > 0 {
 		_, err = tx.CopyFrom(
-			context.Background(),
+			ctx,
 			pgx.Identifier{"order_sub_products"},
 			[]string{"order_product_id", "product_id"},
 			pgx.CopyFromRows(subProductRows),
@@ -304,12 +407,15 @@ func (r *PgOrderRepository) insertOrderProducts(tx pgx.Tx, orderID string, produ
 	return nil
 }
 
-func (r *PgOrderRepository) Update(order domain.Order) error {
-	tx, err := r.db.Begin(context.Background())
+func (r *PgOrderRepository) Update(ctx context.Context, order domain.Order, jobs []domain.NewBackgroundJob) error {
+	ctx, span := startSpan(ctx, "PgOrderRepository.Update")
+	defer span.End()
+
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("error starting transaction: %w", err)
 	}
-	defer func() { _ = tx.Rollback(context.Background()) }()
+	defer func() { _ = tx.Rollback(ctx) }()
 
 	// Update order details
 	var addressID *string
@@ -317,60 +423,167 @@ func (r *PgOrderRepository) Update(order domain.Order) error {
 		addressID = &order.Address.Id
 	}
 
-	updateBuilder, args, err := r.qb.
+	updateBuilder := r.qb.
 		Update("orders").
 		Set("pickup_date", order.PickupDate).
 		Set("order_local", order.OrderLocal).
 		Set("observations", order.Observations).
-		Set("is_picked_up", order.IsPickedUp).
 		Set("address_id", addressID).
 		Where(squirrel.Eq{"id": order.Id}).
-		Where(squirrel.Eq{"is_deleted": false}).ToSql()
+		Where(squirrel.Eq{"is_deleted": false})
+	// O status acompanha a marcação de retirada, como em Order.SetPickedUp
+	if order.IsPickedUp != nil {
+		updateBuilder = updateBuilder.
+			Set("is_picked_up", *order.IsPickedUp).
+			Set("status", squirrel.Expr("CASE WHEN ?::boolean THEN 'picked_up' WHEN status = 'picked_up' THEN 'ready' ELSE status END", *order.IsPickedUp))
+	}
+
+	query, args, err := updateBuilder.ToSql()
 	if err != nil {
 		return fmt.Errorf("error building query to update order: %w", err)
 	}
 
-	if _, err := tx.Exec(context.Background(), updateBuilder, args...); err != nil {
+	if _, err := tx.Exec(ctx, query, args...); err != nil {
 		return fmt.Errorf("error updating order: %w", err)
 	}
 
+	// Keep production progress of lines that are still in the order
+	doneLines, err := r.findDoneOrderProducts(ctx, tx, order.Id)
+	if err != nil {
+		return err
+	}
+	rows, err := tx.Query(ctx, "SELECT id FROM order_products WHERE order_id = $1", order.Id)
+	if err != nil {
+		return fmt.Errorf("error fetching order product ids: %w", err)
+	}
+	currentIds, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return fmt.Errorf("error scanning order product ids: %w", err)
+	}
+	keepIds := map[string]bool{}
+	for _, id := range currentIds {
+		keepIds[id] = true
+	}
+
 	// Delete old products
-	if _, err := tx.Exec(context.Background(), "DELETE FROM order_products WHERE order_id = $1", order.Id); err != nil {
+	if _, err := tx.Exec(ctx, "DELETE FROM order_products WHERE order_id = $1", order.Id); err != nil {
 		return fmt.Errorf("error deleting old order products: %w", err)
 	}
 
-	if err := r.insertOrderProducts(tx, order.Id, order.Products); err != nil {
+	if err := r.insertOrderProducts(ctx, tx, order.Id, order.Products, keepIds); err != nil {
 		return err
 	}
 
-	return tx.Commit(context.Background())
+	if err := r.restoreDoneOrderProducts(ctx, tx, order.Id, doneLines); err != nil {
+		return err
+	}
+
+	if err := enqueueBackgroundJobs(ctx, tx, r.qb, jobs); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
-func (r *PgOrderRepository) Delete(id string) error {
-	result, err := r.db.Query(context.Background(), "UPDATE orders SET is_deleted = true WHERE id = $1", id)
+func (r *PgOrderRepository) UpdateStatus(ctx context.Context, id string, status string, jobs []domain.NewBackgroundJob) error {
+	ctx, span := startSpan(ctx, "PgOrderRepository.UpdateStatus")
+	defer span.End()
+
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("error deleting order: %v", err)
+		return fmt.Errorf("error starting transaction: %w", err)
 	}
-	defer result.Close()
-	return nil
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	result, err := tx.Exec(ctx, `
+		UPDATE orders
+		SET status = $1,
+			is_picked_up = ($1 = 'picked_up'),
+			updated_at = now()
+		WHERE id = $2 AND is_deleted = false AND status <> $1
+	`, status, id)
+	if err != nil {
+		return fmt.Errorf("error updating order status: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		// Nenhuma linha mudou: ou o pedido não existe, ou já estava no status e não há o que publicar
+		var exists bool
+		if err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM orders WHERE id = $1 AND is_deleted = false)", id).Scan(&exists); err != nil {
+			return fmt.Errorf("error fetching order: %w", err)
+		}
+		if !exists {
+			return domain.NewOrderNotFoundError(id)
+		}
+		return nil
+	}
+
+	if err := enqueueBackgroundJobs(ctx, tx, r.qb, jobs); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
-func (r *PgOrderRepository) Create(order domain.Order) (*domain.Order, error) {
-	tx, err := r.db.Begin(context.Background())
+func (r *PgOrderRepository) Delete(ctx context.Context, id string, jobs []domain.NewBackgroundJob) error {
+	ctx, span := startSpan(ctx, "PgOrderRepository.Delete")
+	defer span.End()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Um pedido já excluído não gera outro order.deleted
+	result, err := tx.Exec(ctx, "UPDATE orders SET is_deleted = true WHERE id = $1 AND is_deleted = false", id)
+	if err != nil {
+		return fmt.Errorf("error deleting order: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.NewOrderNotFoundError(id)
+	}
+
+	if err := enqueueBackgroundJobs(ctx, tx, r.qb, jobs); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *PgOrderRepository) Create(ctx context.Context, order domain.Order, jobs []domain.NewBackgroundJob) (*domain.Order, error) {
+	ctx, span := startSpan(ctx, "PgOrderRepository.Create")
+	defer span.End()
+
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error starting transaction: %w", err)
 	}
-	defer func() { _ = tx.Rollback(context.Background()) }()
+	defer func() { _ = tx.Rollback(ctx) }()
 
 	var addressID *string
 	if order.Address != nil {
 		addressID = &order.Address.Id
 	}
 
+	values := map[string]any{
+		"pickup_date":  order.PickupDate,
+		"customer_id":  order.Customer.Id,
+		"employee_id":  order.Employee,
+		"order_local":  order.OrderLocal,
+		"observations": order.Observations,
+		"is_picked_up": order.IsPickedUp,
+		"address_id":   addressID,
+		"status":       order.Status,
+	}
+	// O id pode vir do caso de uso para que os jobs do outbox já o referenciem.
+	if order.Id != "" {
+		values["id"] = order.Id
+	}
+
 	insertBuilder, args, errQB := r.qb.Insert("orders").
-		Columns("order_number", "pickup_date", "customer_id", "employee_id", "order_local", "observations", "is_picked_up", "address_id").
-		Values(500, order.PickupDate, order.Customer.Id, order.Employee, order.OrderLocal, order.Observations, order.IsPickedUp, addressID).
-		Suffix("RETURNING id").
+		SetMap(values).
+		Suffix("RETURNING id, order_number::text, pickup_code").
 		ToSql()
 
 	if errQB != nil {
@@ -378,19 +591,22 @@ func (r *PgOrderRepository) Create(order domain.Order) (*domain.Order, error) {
 	}
 
 	var orderID string
-	if err := tx.QueryRow(context.Background(), insertBuilder, args...).Scan(&orderID); err != nil {
+	if err := tx.QueryRow(ctx, insertBuilder, args...).Scan(&orderID, &order.Number, &order.PickupCode); err != nil {
 		return nil, fmt.Errorf("error creating order: %w", err)
 	}
 
-	if err := r.insertOrderProducts(tx, orderID, order.Products); err != nil {
+	if err := r.insertOrderProducts(ctx, tx, orderID, order.Products, nil); err != nil {
+		return nil, err
+	}
+
+	if err := enqueueBackgroundJobs(ctx, tx, r.qb, jobs); err != nil {
 		return nil, err
 	}
 
-	if err := tx.Commit(context.Background()); err != nil {
+	if err := tx.Commit(ctx); err != nil {
 		return nil, fmt.Errorf("error committing transaction: %w", err)
 	}
 
 	order.Id = orderID
-	order.Number = "500" // TODO: get number from sequence
 	return &order, nil
 }
@@ -0,0 +1,282 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PgPrintJobRepository struct {
+	db *pgxpool.Pool
+	qb squirrel.StatementBuilderType
+}
+
+func NewPgPrintJobRepository(db *pgxpool.Pool) *PgPrintJobRepository {
+	return &PgPrintJobRepository{
+		db: db,
+		qb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+// printJobColumns não inclui o payload, que só é lido quando o documento vai ser impresso.
+var printJobColumns = []string{
+	"id",
+	"printer",
+	"kind",
+	"order_id",
+	"description",
+	"status",
+	"attempts",
+	"reprint_of",
+	"requested_by",
+	"claimed_by",
+	"error",
+	"created_at",
+	"printed_at",
+}
+
+var printJobPayloadColumns = append(slices.Clone(printJobColumns), "payload")
+
+func (r *PgPrintJobRepository) Create(ctx context.Context, job domain.PrintJob) (*domain.PrintJob, error) {
+	ctx, span := startSpan(ctx, "PgPrintJobRepository.Create")
+	defer span.End()
+
+	query, args, err := r.qb.Insert("print_jobs").
+		Columns("printer", "kind", "order_id", "description", "payload", "reprint_of", "requested_by").
+		Values(job.Printer, job.Kind, job.OrderId, job.Description, job.Payload, job.ReprintOf, job.RequestedBy).
+		Suffix("RETURNING " + strings.Join(printJobColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building query to create print job: %w", err)
+	}
+
+	created, err := scanPrintJob(r.db.QueryRow(ctx, query, args...), false)
+	if err != nil {
+		return nil, fmt.Errorf("error creating print job: %w", err)
+	}
+	created.Payload = job.Payload
+
+	return created, nil
+}
+
+func (r *PgPrintJobRepository) FindById(ctx context.Context, id string) (*domain.PrintJob, error) {
+	ctx, span := startSpan(ctx, "PgPrintJobRepository.FindById")
+	defer span.End()
+
+	query, args, err := r.qb.Select(printJobPayloadColumns...).
+		From("print_jobs").
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building print job query: %w", err)
+	}
+
+	job, err := scanPrintJob(r.db.QueryRow(ctx, query, args...), true)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NewPrintJobNotFoundError(id)
+		}
+		return nil, fmt.Errorf("error fetching print job: %w", err)
+	}
+
+	return job, nil
+}
+
+func (r *PgPrintJobRepository) FindAll(ctx context.Context, filters domain.PrintJobFilters, pagination domain.Pagination) ([]domain.PrintJob, domain.Pagination, error) {
+	ctx, span := startSpan(ctx, "PgPrintJobRepository.FindAll")
+	defer span.End()
+
+	offset := pagination.Limit * (pagination.Page - 1)
+
+	conditions := squirrel.And{}
+	if filters.Printer != "" {
+		conditions = append(conditions, squirrel.Eq{"printer": filters.Printer})
+	}
+	if filters.Status != "" {
+		conditions = append(conditions, squirrel.Eq{"status": filters.Status})
+	}
+	if filters.OrderId != "" {
+		conditions = append(conditions, squirrel.Eq{"order_id": filters.OrderId})
+	}
+
+	countQuery, countArgs, err := r.qb.Select("count(*)").From("print_jobs").Where(conditions).ToSql()
+	if err != nil {
+		return nil, domain.Pagination{}, fmt.Errorf("error building print jobs count query: %w", err)
+	}
+
+	if err := r.db.QueryRow(ctx, countQuery, countArgs...).Scan(&pagination.Total); err != nil {
+		return nil, domain.Pagination{}, fmt.Errorf("error counting print jobs: %w", err)
+	}
+
+	query, args, err := r.qb.Select(printJobColumns...).
+		From("print_jobs").
+		Where(conditions).
+		OrderBy("created_at DESC").
+		Limit(uint64(pagination.Limit)).
+		Offset(uint64(offset)).
+		ToSql()
+	if err != nil {
+		return nil, domain.Pagination{}, fmt.Errorf("error building print jobs query: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, domain.Pagination{}, fmt.Errorf("error fetching print jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []domain.PrintJob{}
+	for rows.Next() {
+		job, err := scanPrintJob(rows, false)
+		if err != nil {
+			return nil, domain.Pagination{}, fmt.Errorf("error scanning print job: %w", err)
+		}
+		jobs = append(jobs, *job)
+	}
+
+	return jobs, pagination, rows.Err()
+}
+
+func (r *PgPrintJobRepository) Claim(ctx context.Context, printer string, agentId string, limit int, lease time.Duration) ([]domain.PrintJob, error) {
+	ctx, span := startSpan(ctx, "PgPrintJobRepository.Claim")
+	defer span.End()
+
+	// Os jobs expirados que já esgotaram as tentativas falham na mesma instrução, e a reserva
+	// só pega os que ainda têm tentativas
+	rows, err := r.db.Query(ctx, `
+		WITH exhausted AS (
+			UPDATE print_jobs
+			SET status = 'failed',
+				claimed_until = NULL,
+				error = $6
+			WHERE printer = $1
+				AND status = 'printing'
+				AND claimed_until < now()
+				AND attempts >= $5
+		)
+		UPDATE print_jobs
+		SET status = 'printing',
+			attempts = attempts + 1,
+			claimed_by = $2,
+			claimed_until = now() + make_interval(secs => $4)
+		WHERE id IN (
+			SELECT id
+			FROM print_jobs
+			WHERE printer = $1
+				AND (status = 'pending' OR (status = 'printing' AND claimed_until < now() AND attempts < $5))
+			ORDER BY created_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+strings.Join(printJobPayloadColumns, ", "),
+		printer, agentId, limit, lease.Seconds(), domain.PrintJobMaxAttempts,
+		fmt.Sprintf("not acknowledged after %d attempts", domain.PrintJobMaxAttempts),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error claiming print jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []domain.PrintJob{}
+	for rows.Next() {
+		job, err := scanPrintJob(rows, true)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning print job: %w", err)
+		}
+		jobs = append(jobs, *job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating print jobs: %w", err)
+	}
+
+	// O UPDATE ... RETURNING não preserva a ordem da subconsulta
+	slices.SortFunc(jobs, func(a, b domain.PrintJob) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return jobs, nil
+}
+
+func (r *PgPrintJobRepository) MarkPrinted(ctx context.Context, id string, agentId string) error {
+	ctx, span := startSpan(ctx, "PgPrintJobRepository.MarkPrinted")
+	defer span.End()
+
+	result, err := r.db.Exec(ctx, `
+		UPDATE print_jobs
+		SET status = 'printed', printed_at = now(), claimed_until = NULL, error = NULL
+		WHERE id = $1 AND status = 'printing' AND claimed_by = $2
+	`, id, agentId)
+	if err != nil {
+		return fmt.Errorf("error marking print job as printed: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return r.notClaimed(ctx, id, agentId)
+	}
+	return nil
+}
+
+func (r *PgPrintJobRepository) MarkFailed(ctx context.Context, id string, agentId string, errorMessage string) error {
+	ctx, span := startSpan(ctx, "PgPrintJobRepository.MarkFailed")
+	defer span.End()
+
+	result, err := r.db.Exec(ctx, `
+		UPDATE print_jobs
+		SET status = 'failed', error = $3, claimed_until = NULL
+		WHERE id = $1 AND status = 'printing' AND claimed_by = $2
+	`, id, agentId, errorMessage)
+	if err != nil {
+		return fmt.Errorf("error marking print job as failed: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return r.notClaimed(ctx, id, agentId)
+	}
+	return nil
+}
+
+// notClaimed explica por que o ack não alterou nenhuma linha: o job não existe ou não está
+// sendo impresso pelo agente.
+func (r *PgPrintJobRepository) notClaimed(ctx context.Context, id string, agentId string) error {
+	var exists bool
+	if err := r.db.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM print_jobs WHERE id = $1)", id).Scan(&exists); err != nil {
+		return fmt.Errorf("error fetching print job: %w", err)
+	}
+	if !exists {
+		return domain.NewPrintJobNotFoundError(id)
+	}
+	return domain.NewPrintJobNotClaimedError(id, agentId)
+}
+
+func scanPrintJob(row pgx.Row, withPayload bool) (*domain.PrintJob, error) {
+	var job domain.PrintJob
+	dest := []any{
+		&job.Id,
+		&job.Printer,
+		&job.Kind,
+		&job.OrderId,
+		&job.Description,
+		&job.Status,
+		&job.Attempts,
+		&job.ReprintOf,
+		&job.RequestedBy,
+		&job.ClaimedBy,
+		&job.Error,
+		&job.CreatedAt,
+		&job.PrintedAt,
+	}
+	if withPayload {
+		dest = append(dest, &job.Payload)
+	}
+
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/Masterminds/squirrel"
 	"github.com/deividr/zion-api/internal/domain"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -21,18 +22,27 @@ func NewPgProductRepository(db *pgxpool.Pool) *PgProductRepository {
 	}
 }
 
-func (r *PgProductRepository) FindAll(filters domain.FindAllProductFilters) ([]domain.Product, error) {
-	query, args, err := r.qb.
+func (r *PgProductRepository) FindAll(ctx context.Context, filters domain.FindAllProductFilters) ([]domain.Product, error) {
+	ctx, span := startSpan(ctx, "PgProductRepository.FindAll")
+	defer span.End()
+
+	queryBuilder := r.qb.
 		Select("id", "name", "value", "unity_type", "category_id", "image_url", "is_variable_price").
 		From("products").
-		Where(squirrel.Eq{"is_deleted": false}).
-		Where(squirrel.ILike{"name": "%" + filters.Name + "%"}).
-		ToSql()
+		Where(squirrel.Eq{"is_deleted": false})
+
+	if filters.Name != "" {
+		queryBuilder = queryBuilder.
+			Where(textMatch("name", filters.Name)).
+			OrderByClause(rankExpr("name"), filters.Name)
+	}
+
+	query, args, err := queryBuilder.ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("erro ao construir query: %v", err)
 	}
 
-	rows, err := r.db.Query(context.Background(), query, args...)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao buscar produtos: %v", err)
 	}
@@ -60,9 +70,12 @@ func (r *PgProductRepository) FindAll(filters domain.FindAllProductFilters) ([]d
 	return products, nil
 }
 
-func (r *PgProductRepository) FindById(id string) (*domain.Product, error) {
+func (r *PgProductRepository) FindById(ctx context.Context, id string) (*domain.Product, error) {
+	ctx, span := startSpan(ctx, "PgProductRepository.FindById")
+	defer span.End()
+
 	var product domain.Product
-	err := r.db.QueryRow(context.Background(), `
+	err := r.db.QueryRow(ctx, `
 		SELECT id, name, value, unity_type, category_id, image_url, is_variable_price
 		FROM products
 		WHERE id = $1 AND is_deleted = false
@@ -82,7 +95,10 @@ func (r *PgProductRepository) FindById(id string) (*domain.Product, error) {
 	return &product, nil
 }
 
-func (r *PgProductRepository) Update(product domain.Product) error {
+func (r *PgProductRepository) Update(ctx context.Context, product domain.Product, jobs []domain.NewBackgroundJob) error {
+	ctx, span := startSpan(ctx, "PgProductRepository.Update")
+	defer span.End()
+
 	updateBuilder, args, err := r.qb.
 		Update("products").Set("name", product.Name).
 		Set("value", product.Value).
@@ -96,28 +112,68 @@ func (r *PgProductRepository) Update(product domain.Product) error {
 		return fmt.Errorf("erro ao construir query para atualizar o produto: %v", err)
 	}
 
-	result, err := r.db.Query(context.Background(), updateBuilder, args...)
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %v", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, updateBuilder, args...); err != nil {
 		return fmt.Errorf("erro ao atualizar produto: %v", err)
 	}
-	defer result.Close()
 
+	if err := enqueueBackgroundJobs(ctx, tx, r.qb, jobs); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %v", err)
+	}
 	return nil
 }
 
-func (r *PgProductRepository) Delete(id string) error {
-	result, err := r.db.Query(context.Background(), "UPDATE products SET is_deleted = true WHERE id = $1", id)
+func (r *PgProductRepository) Delete(ctx context.Context, id string, jobs []domain.NewBackgroundJob) error {
+	ctx, span := startSpan(ctx, "PgProductRepository.Delete")
+	defer span.End()
+
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %v", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, "UPDATE products SET is_deleted = true WHERE id = $1", id); err != nil {
 		return fmt.Errorf("erro ao deletar produto: %v", err)
 	}
-	defer result.Close()
+
+	if err := enqueueBackgroundJobs(ctx, tx, r.qb, jobs); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %v", err)
+	}
 	return nil
 }
 
-func (r *PgProductRepository) Create(newProduct domain.NewProduct) (*domain.Product, error) {
+func (r *PgProductRepository) Create(ctx context.Context, newProduct domain.NewProduct, jobs []domain.NewBackgroundJob) (*domain.Product, error) {
+	ctx, span := startSpan(ctx, "PgProductRepository.Create")
+	defer span.End()
+
+	values := map[string]any{
+		"name":              newProduct.Name,
+		"value":             newProduct.Value,
+		"unity_type":        newProduct.UnityType,
+		"category_id":       newProduct.CategoryId,
+		"image_url":         newProduct.ImageUrl,
+		"is_variable_price": newProduct.IsVariablePrice,
+	}
+	if newProduct.Id != "" {
+		values["id"] = newProduct.Id
+	}
+
 	insertBuilder, args, errQB := r.qb.Insert("products").
-		Columns("name", "value", "unity_type", "category_id", "image_url", "is_variable_price").
-		Values(&newProduct.Name, &newProduct.Value, &newProduct.UnityType, &newProduct.CategoryId, &newProduct.ImageUrl, &newProduct.IsVariablePrice).
+		SetMap(values).
 		Suffix("RETURNING id").
 		ToSql()
 
@@ -125,11 +181,25 @@ func (r *PgProductRepository) Create(newProduct domain.NewProduct) (*domain.Prod
 		return nil, fmt.Errorf("erro ao construir query para criar o produto: %v", errQB)
 	}
 
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %v", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
 	var id string
-	errQuery := r.db.QueryRow(context.Background(), insertBuilder, args...).Scan(&id)
+	errQuery := tx.QueryRow(ctx, insertBuilder, args...).Scan(&id)
 
 	if errQuery != nil {
-		return nil, fmt.Errorf("erro ao criar produto: %v", errQB)
+		return nil, fmt.Errorf("erro ao criar produto: %v", errQuery)
+	}
+
+	if err := enqueueBackgroundJobs(ctx, tx, r.qb, jobs); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("erro ao confirmar transação: %v", err)
 	}
 
 	createdProduct := &domain.Product{
@@ -144,3 +214,63 @@ func (r *PgProductRepository) Create(newProduct domain.NewProduct) (*domain.Prod
 
 	return createdProduct, nil
 }
+
+// Import grava a planilha inteira em uma transação: se uma linha falhar, nada é gravado.
+func (r *PgProductRepository) Import(ctx context.Context, creates []domain.NewProduct, updates []domain.Product, jobs []domain.NewBackgroundJob) error {
+	ctx, span := startSpan(ctx, "PgProductRepository.Import")
+	defer span.End()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %v", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// As escritas vão em um único batch, como na importação de clientes
+	batch := &pgx.Batch{}
+	for _, product := range updates {
+		batch.Queue(`
+			UPDATE products
+			SET name = $2, value = $3, unity_type = $4, category_id = $5, is_variable_price = $6, updated_at = now()
+			WHERE id = $1 AND is_deleted = false
+		`, product.Id, product.Name, product.Value, product.UnityType, product.CategoryId, product.IsVariablePrice)
+	}
+	for _, newProduct := range creates {
+		batch.Queue(`
+			INSERT INTO products (id, name, value, unity_type, category_id, is_variable_price)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, newProduct.Id, newProduct.Name, newProduct.Value, newProduct.UnityType, newProduct.CategoryId, newProduct.IsVariablePrice)
+	}
+	if err := queueBackgroundJobs(batch, r.qb, jobs); err != nil {
+		return err
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	defer results.Close()
+
+	for _, product := range updates {
+		result, err := results.Exec()
+		if err != nil {
+			return fmt.Errorf("erro ao atualizar produto %q: %v", product.Name, err)
+		}
+		if result.RowsAffected() == 0 {
+			return domain.NewImportRowDeletedError(product.Name)
+		}
+	}
+	for _, newProduct := range creates {
+		if _, err := results.Exec(); err != nil {
+			return fmt.Errorf("erro ao criar produto %q: %v", newProduct.Name, err)
+		}
+	}
+	if err := execQueuedBackgroundJobs(results, jobs); err != nil {
+		return err
+	}
+	if err := results.Close(); err != nil {
+		return fmt.Errorf("erro ao gravar importação: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %v", err)
+	}
+	return nil
+}
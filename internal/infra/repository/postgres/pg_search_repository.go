@@ -0,0 +1,175 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PgSearchRepository struct {
+	db *pgxpool.Pool
+	qb squirrel.StatementBuilderType
+}
+
+func NewPgSearchRepository(db *pgxpool.Pool) *PgSearchRepository {
+	return &PgSearchRepository{
+		db: db,
+		qb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+func (r *PgSearchRepository) SearchCustomers(ctx context.Context, term string, limit int) ([]domain.Customer, error) {
+	ctx, span := startSpan(ctx, "PgSearchRepository.SearchCustomers")
+	defer span.End()
+
+	conditions := squirrel.Or{
+		textMatch("name", term),
+		textMatch("email", term),
+	}
+	if cond := phoneMatch("phone", term); cond != nil {
+		conditions = append(conditions, cond, phoneMatch("phone2", term))
+	}
+
+	query, args, err := r.qb.
+		Select("id", "name", "phone", "phone2", "email", "created_at").
+		From("customers").
+		Where(squirrel.Eq{"is_deleted": false}).
+		Where(conditions).
+		OrderByClause(rankExpr("name"), term).
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building customer search query: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error searching customers: %w", err)
+	}
+	defer rows.Close()
+
+	customers := []domain.Customer{}
+	for rows.Next() {
+		var customer domain.Customer
+		if err := rows.Scan(
+			&customer.Id,
+			&customer.Name,
+			&customer.Phone,
+			&customer.Phone2,
+			&customer.Email,
+			&customer.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning customer search result: %w", err)
+		}
+		customers = append(customers, customer)
+	}
+
+	return customers, rows.Err()
+}
+
+func (r *PgSearchRepository) SearchProducts(ctx context.Context, term string, limit int) ([]domain.Product, error) {
+	ctx, span := startSpan(ctx, "PgSearchRepository.SearchProducts")
+	defer span.End()
+
+	query, args, err := r.qb.
+		Select("id", "name", "value", "unity_type", "category_id", "image_url", "is_variable_price").
+		From("products").
+		Where(squirrel.Eq{"is_deleted": false}).
+		Where(textMatch("name", term)).
+		OrderByClause(rankExpr("name"), term).
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building product search query: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error searching products: %w", err)
+	}
+	defer rows.Close()
+
+	products := []domain.Product{}
+	for rows.Next() {
+		var product domain.Product
+		if err := rows.Scan(
+			&product.Id,
+			&product.Name,
+			&product.Value,
+			&product.UnityType,
+			&product.CategoryId,
+			&product.ImageUrl,
+			&product.IsVariablePrice,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning product search result: %w", err)
+		}
+		products = append(products, product)
+	}
+
+	return products, rows.Err()
+}
+
+func (r *PgSearchRepository) SearchOrders(ctx context.Context, term string, limit int) ([]domain.SearchOrder, error) {
+	ctx, span := startSpan(ctx, "PgSearchRepository.SearchOrders")
+	defer span.End()
+
+	conditions := squirrel.Or{textMatch("c.name", term)}
+	if cond := phoneMatch("c.phone", term); cond != nil {
+		conditions = append(conditions, cond, phoneMatch("c.phone2", term))
+	}
+	if digits := onlyDigits(term); digits == term {
+		conditions = append(conditions, squirrel.Expr("o.order_number::text = ?", digits))
+	}
+
+	query, args, err := r.qb.
+		Select("o.id", "o.order_number", "o.pickup_date", "o.is_picked_up").
+		Column(`JSON_BUILD_OBJECT(
+			'id', c.id,
+			'name', c.name,
+			'phone', c.phone,
+			'phone2', c.phone2,
+			'email', c.email
+		) AS customer`).
+		From("orders o").
+		Join("customers c ON c.id = o.customer_id").
+		Where(squirrel.Eq{"o.is_deleted": false}).
+		Where(conditions).
+		OrderByClause(rankExpr("c.name"), term).
+		OrderBy("o.pickup_date DESC").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building order search query: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error searching orders: %w", err)
+	}
+	defer rows.Close()
+
+	orders := []domain.SearchOrder{}
+	for rows.Next() {
+		var order domain.SearchOrder
+		var customerJSON []byte
+		if err := rows.Scan(
+			&order.Id,
+			&order.Number,
+			&order.PickupDate,
+			&order.IsPickedUp,
+			&customerJSON,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning order search result: %w", err)
+		}
+		if err := json.Unmarshal(customerJSON, &order.Customer); err != nil {
+			return nil, fmt.Errorf("error unmarshaling order customer: %w", err)
+		}
+		orders = append(orders, order)
+	}
+
+	return orders, rows.Err()
+}
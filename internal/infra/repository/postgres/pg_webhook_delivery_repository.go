@@ -0,0 +1,240 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PgWebhookDeliveryRepository struct {
+	db *pgxpool.Pool
+	qb squirrel.StatementBuilderType
+}
+
+func NewPgWebhookDeliveryRepository(db *pgxpool.Pool) *PgWebhookDeliveryRepository {
+	return &PgWebhookDeliveryRepository{
+		db: db,
+		qb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+var webhookDeliveryColumns = []string{
+	"id",
+	"subscription_id",
+	"event_id",
+	"event_type",
+	"payload",
+	"status",
+	"attempts",
+	"next_attempt_at",
+	"last_status_code",
+	"last_error",
+	"replay_of",
+	"created_at",
+	"delivered_at",
+}
+
+func (r *PgWebhookDeliveryRepository) Create(ctx context.Context, delivery domain.WebhookDelivery) (*domain.WebhookDelivery, error) {
+	ctx, span := startSpan(ctx, "PgWebhookDeliveryRepository.Create")
+	defer span.End()
+
+	query, args, err := r.qb.Insert("webhook_deliveries").
+		Columns("subscription_id", "event_id", "event_type", "payload", "replay_of").
+		Values(delivery.SubscriptionId, delivery.EventId, delivery.EventType, []byte(delivery.Payload), delivery.ReplayOf).
+		Suffix("RETURNING " + strings.Join(webhookDeliveryColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building query to create webhook delivery: %w", err)
+	}
+
+	created, err := scanWebhookDelivery(r.db.QueryRow(ctx, query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("error creating webhook delivery: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *PgWebhookDeliveryRepository) CreateForEvent(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	ctx, span := startSpan(ctx, "PgWebhookDeliveryRepository.CreateForEvent")
+	defer span.End()
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	query, args, err := webhookDeliveriesInsert(r.qb, deliveries)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.db.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("error creating webhook deliveries: %w", err)
+	}
+
+	return nil
+}
+
+func webhookDeliveriesInsert(qb squirrel.StatementBuilderType, deliveries []domain.WebhookDelivery) (string, []any, error) {
+	insertBuilder := qb.Insert("webhook_deliveries").
+		Columns("subscription_id", "event_id", "event_type", "payload")
+	for _, delivery := range deliveries {
+		insertBuilder = insertBuilder.Values(delivery.SubscriptionId, delivery.EventId, delivery.EventType, []byte(delivery.Payload))
+	}
+
+	query, args, err := insertBuilder.
+		Suffix("ON CONFLICT (event_id, subscription_id) WHERE replay_of IS NULL DO NOTHING").
+		ToSql()
+	if err != nil {
+		return "", nil, fmt.Errorf("error building query to create webhook deliveries: %w", err)
+	}
+	return query, args, nil
+}
+
+func (r *PgWebhookDeliveryRepository) FindById(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	ctx, span := startSpan(ctx, "PgWebhookDeliveryRepository.FindById")
+	defer span.End()
+
+	query, args, err := r.qb.Select(webhookDeliveryColumns...).
+		From("webhook_deliveries").
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building webhook delivery query: %w", err)
+	}
+
+	delivery, err := scanWebhookDelivery(r.db.QueryRow(ctx, query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("webhook delivery not found: %w", err)
+	}
+
+	return delivery, nil
+}
+
+func (r *PgWebhookDeliveryRepository) FindBySubscription(ctx context.Context, subscriptionId string, pagination domain.Pagination) ([]domain.WebhookDelivery, domain.Pagination, error) {
+	ctx, span := startSpan(ctx, "PgWebhookDeliveryRepository.FindBySubscription")
+	defer span.End()
+
+	offset := pagination.Limit * (pagination.Page - 1)
+
+	if err := r.db.QueryRow(ctx,
+		"SELECT count(*) FROM webhook_deliveries WHERE subscription_id = $1", subscriptionId,
+	).Scan(&pagination.Total); err != nil {
+		return nil, domain.Pagination{}, fmt.Errorf("error counting webhook deliveries: %w", err)
+	}
+
+	query, args, err := r.qb.Select(webhookDeliveryColumns...).
+		From("webhook_deliveries").
+		Where(squirrel.Eq{"subscription_id": subscriptionId}).
+		OrderBy("created_at DESC").
+		Limit(uint64(pagination.Limit)).
+		Offset(uint64(offset)).
+		ToSql()
+	if err != nil {
+		return nil, domain.Pagination{}, fmt.Errorf("error building webhook deliveries query: %w", err)
+	}
+
+	deliveries, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, domain.Pagination{}, err
+	}
+
+	return deliveries, pagination, nil
+}
+
+func (r *PgWebhookDeliveryRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	ctx, span := startSpan(ctx, "PgWebhookDeliveryRepository.ClaimDue")
+	defer span.End()
+
+	return r.query(ctx, `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = now() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id
+			FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= now()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+strings.Join(webhookDeliveryColumns, ", "),
+		limit, lease.Seconds(),
+	)
+}
+
+func (r *PgWebhookDeliveryRepository) RecordAttempt(ctx context.Context, id string, status string, attempt domain.WebhookDeliveryAttempt, retryIn time.Duration) error {
+	ctx, span := startSpan(ctx, "PgWebhookDeliveryRepository.RecordAttempt")
+	defer span.End()
+
+	updateBuilder := r.qb.Update("webhook_deliveries").
+		Set("status", status).
+		Set("attempts", squirrel.Expr("attempts + 1")).
+		Set("last_status_code", attempt.StatusCode).
+		Set("last_error", attempt.Error).
+		Set("next_attempt_at", squirrel.Expr("now() + make_interval(secs => ?)", retryIn.Seconds())).
+		Where(squirrel.Eq{"id": id})
+
+	if status == domain.WebhookDeliverySucceeded {
+		updateBuilder = updateBuilder.Set("delivered_at", squirrel.Expr("now()"))
+	}
+
+	query, args, err := updateBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("error building query to record webhook attempt: %w", err)
+	}
+
+	if _, err := r.db.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("error recording webhook attempt: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PgWebhookDeliveryRepository) query(ctx context.Context, query string, args ...any) ([]domain.WebhookDelivery, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []domain.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func scanWebhookDelivery(row pgx.Row) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	var payload []byte
+	if err := row.Scan(
+		&delivery.Id,
+		&delivery.SubscriptionId,
+		&delivery.EventId,
+		&delivery.EventType,
+		&payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.NextAttemptAt,
+		&delivery.LastStatusCode,
+		&delivery.LastError,
+		&delivery.ReplayOf,
+		&delivery.CreatedAt,
+		&delivery.DeliveredAt,
+	); err != nil {
+		return nil, err
+	}
+	delivery.Payload = payload
+	return &delivery, nil
+}
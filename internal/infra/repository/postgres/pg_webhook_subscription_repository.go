@@ -0,0 +1,179 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PgWebhookSubscriptionRepository struct {
+	db *pgxpool.Pool
+	qb squirrel.StatementBuilderType
+}
+
+func NewPgWebhookSubscriptionRepository(db *pgxpool.Pool) *PgWebhookSubscriptionRepository {
+	return &PgWebhookSubscriptionRepository{
+		db: db,
+		qb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+var webhookSubscriptionColumns = []string{"id", "url", "secret", "event_types", "description", "is_active", "created_at", "updated_at"}
+
+func (r *PgWebhookSubscriptionRepository) FindAll(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	ctx, span := startSpan(ctx, "PgWebhookSubscriptionRepository.FindAll")
+	defer span.End()
+
+	return r.find(ctx, r.qb.Select(webhookSubscriptionColumns...).
+		From("webhook_subscriptions").
+		Where(squirrel.Eq{"is_deleted": false}).
+		OrderBy("created_at"))
+}
+
+func (r *PgWebhookSubscriptionRepository) FindActiveByEvent(ctx context.Context, eventType string) ([]domain.WebhookSubscription, error) {
+	ctx, span := startSpan(ctx, "PgWebhookSubscriptionRepository.FindActiveByEvent")
+	defer span.End()
+
+	return r.find(ctx, r.qb.Select(webhookSubscriptionColumns...).
+		From("webhook_subscriptions").
+		Where(squirrel.Eq{"is_deleted": false, "is_active": true}).
+		Where("(? = ANY(event_types) OR ? = ANY(event_types))", eventType, domain.WebhookAllEvents))
+}
+
+func (r *PgWebhookSubscriptionRepository) FindById(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	ctx, span := startSpan(ctx, "PgWebhookSubscriptionRepository.FindById")
+	defer span.End()
+
+	query, args, err := r.qb.Select(webhookSubscriptionColumns...).
+		From("webhook_subscriptions").
+		Where(squirrel.Eq{"id": id, "is_deleted": false}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building webhook subscription query: %w", err)
+	}
+
+	subscription, err := scanWebhookSubscription(r.db.QueryRow(ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.NewWebhookSubscriptionNotFoundError(id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching webhook subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+func (r *PgWebhookSubscriptionRepository) Create(ctx context.Context, subscription domain.NewWebhookSubscription) (*domain.WebhookSubscription, error) {
+	ctx, span := startSpan(ctx, "PgWebhookSubscriptionRepository.Create")
+	defer span.End()
+
+	isActive := true
+	if subscription.IsActive != nil {
+		isActive = *subscription.IsActive
+	}
+
+	query, args, err := r.qb.Insert("webhook_subscriptions").
+		Columns("url", "secret", "event_types", "description", "is_active").
+		Values(subscription.Url, subscription.Secret, subscription.EventTypes, subscription.Description, isActive).
+		Suffix("RETURNING " + strings.Join(webhookSubscriptionColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building query to create webhook subscription: %w", err)
+	}
+
+	created, err := scanWebhookSubscription(r.db.QueryRow(ctx, query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("error creating webhook subscription: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *PgWebhookSubscriptionRepository) Update(ctx context.Context, subscription domain.WebhookSubscription) error {
+	ctx, span := startSpan(ctx, "PgWebhookSubscriptionRepository.Update")
+	defer span.End()
+
+	query, args, err := r.qb.Update("webhook_subscriptions").
+		Set("url", subscription.Url).
+		Set("secret", subscription.Secret).
+		Set("event_types", subscription.EventTypes).
+		Set("description", subscription.Description).
+		Set("is_active", subscription.IsActive).
+		Set("updated_at", squirrel.Expr("now()")).
+		Where(squirrel.Eq{"id": subscription.Id, "is_deleted": false}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("error building query to update webhook subscription: %w", err)
+	}
+
+	result, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("error updating webhook subscription: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.NewWebhookSubscriptionNotFoundError(subscription.Id)
+	}
+
+	return nil
+}
+
+func (r *PgWebhookSubscriptionRepository) Delete(ctx context.Context, id string) error {
+	ctx, span := startSpan(ctx, "PgWebhookSubscriptionRepository.Delete")
+	defer span.End()
+
+	result, err := r.db.Exec(ctx, "UPDATE webhook_subscriptions SET is_deleted = true, is_active = false WHERE id = $1 AND NOT is_deleted", id)
+	if err != nil {
+		return fmt.Errorf("error deleting webhook subscription: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.NewWebhookSubscriptionNotFoundError(id)
+	}
+	return nil
+}
+
+func (r *PgWebhookSubscriptionRepository) find(ctx context.Context, builder squirrel.SelectBuilder) ([]domain.WebhookSubscription, error) {
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building webhook subscriptions query: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := []domain.WebhookSubscription{}
+	for rows.Next() {
+		subscription, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning webhook subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, *subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+func scanWebhookSubscription(row pgx.Row) (*domain.WebhookSubscription, error) {
+	var subscription domain.WebhookSubscription
+	if err := row.Scan(
+		&subscription.Id,
+		&subscription.Url,
+		&subscription.Secret,
+		&subscription.EventTypes,
+		&subscription.Description,
+		&subscription.IsActive,
+		&subscription.CreatedAt,
+		&subscription.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// textMatch compara o termo com a coluna ignorando acentos e caixa, aceitando
+// tanto trechos exatos quanto correspondências aproximadas. O operador <% usa o índice
+// trigram da coluna; o mínimo de similaridade é o pg_trgm.word_similarity_threshold que
+// database.GetConnection define em cada conexão.
+func textMatch(column, term string) squirrel.Sqlizer {
+	return squirrel.Or{
+		squirrel.Expr(fmt.Sprintf("immutable_unaccent(%s) ILIKE immutable_unaccent(?)", column), "%"+term+"%"),
+		squirrel.Expr(fmt.Sprintf("immutable_unaccent(?) <%% immutable_unaccent(%s)", column), term),
+	}
+}
+
+// phoneMatch compara apenas os dígitos do termo com os dígitos armazenados, de forma
+// que "(11) 99999-0000" encontre "11999990000". Retorna nil se o termo não tiver dígitos.
+func phoneMatch(column, term string) squirrel.Sqlizer {
+	digits := onlyDigits(term)
+	if digits == "" {
+		return nil
+	}
+
+	return squirrel.Expr(fmt.Sprintf("only_digits(%s) LIKE ?", column), "%"+digits+"%")
+}
+
+// rankExpr devolve a expressão usada para ordenar os resultados por relevância.
+func rankExpr(column string) string {
+	return fmt.Sprintf("word_similarity(immutable_unaccent(?), immutable_unaccent(%s)) DESC", column)
+}
+
+func onlyDigits(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"testing"
+)
+
+func TestPhoneMatch(t *testing.T) {
+	t.Run("should compare only the digits of a masked phone", func(t *testing.T) {
+		cond := phoneMatch("phone", "(11) 99999-0000")
+		if cond == nil {
+			t.Fatal("expected a condition, but got nil")
+		}
+
+		sql, args, err := cond.ToSql()
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+
+		if sql != "only_digits(phone) LIKE ?" {
+			t.Errorf("unexpected sql %s", sql)
+		}
+
+		if len(args) != 1 || args[0] != "%11999990000%" {
+			t.Errorf("expected args [%%11999990000%%], but got %v", args)
+		}
+	})
+
+	t.Run("should return nil when the term has no digits", func(t *testing.T) {
+		if cond := phoneMatch("phone", "João"); cond != nil {
+			t.Errorf("expected nil condition, but got %v", cond)
+		}
+	})
+}
+
+func TestTextMatch(t *testing.T) {
+	sql, args, err := textMatch("c.name", "Joao").ToSql()
+	if err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+
+	expected := "(immutable_unaccent(c.name) ILIKE immutable_unaccent(?) OR immutable_unaccent(?) <% immutable_unaccent(c.name))"
+	if sql != expected {
+		t.Errorf("expected sql %s, but got %s", expected, sql)
+	}
+
+	if len(args) != 2 || args[0] != "%Joao%" || args[1] != "Joao" {
+		t.Errorf("unexpected args %v", args)
+	}
+}
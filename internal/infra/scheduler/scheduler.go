@@ -0,0 +1,247 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/domain/services"
+	"github.com/deividr/zion-api/internal/infra/database"
+	"github.com/deividr/zion-api/internal/infra/logger"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/robfig/cron/v3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/deividr/zion-api/internal/infra/scheduler")
+
+type job struct {
+	name     string
+	schedule string
+	entryId  cron.EntryID
+	run      services.JobFunc
+}
+
+// Scheduler executa jobs em processo a partir de expressões cron no fuso da loja.
+// Cada horário do cron é reivindicado em job_runs antes de rodar, então quando várias
+// instâncias da API estão no ar apenas uma delas roda o job naquele horário, mesmo que
+// os relógios difiram e uma termine antes de a outra disparar. O advisory lock com o nome
+// do job impede ainda que uma execução manual e uma agendada rodem ao mesmo tempo.
+type Scheduler struct {
+	cron   *cron.Cron
+	pool   *pgxpool.Pool
+	runs   domain.JobRunRepository
+	logger *logger.Logger
+
+	mu   sync.RWMutex
+	jobs map[string]*job
+	ctx  context.Context
+	stop context.CancelFunc
+	wg   sync.WaitGroup
+}
+
+func New(pool *pgxpool.Pool, runs domain.JobRunRepository) *Scheduler {
+	ctx, stop := context.WithCancel(context.Background())
+
+	return &Scheduler{
+		cron:   cron.New(cron.WithLocation(domain.StoreLocation)),
+		pool:   pool,
+		runs:   runs,
+		logger: logger.New(),
+		jobs:   map[string]*job{},
+		ctx:    ctx,
+		stop:   stop,
+	}
+}
+
+func (s *Scheduler) Register(name string, schedule string, run services.JobFunc) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[name]; ok {
+		return fmt.Errorf("job %s already registered", name)
+	}
+
+	j := &job{name: name, schedule: schedule, run: run}
+	entryId, err := s.cron.AddFunc(schedule, func() { s.runScheduled(j) })
+	if err != nil {
+		return fmt.Errorf("invalid schedule %q for job %s: %w", schedule, name, err)
+	}
+	j.entryId = entryId
+	s.jobs[name] = j
+
+	return nil
+}
+
+func (s *Scheduler) Start() {
+	s.cron.Start()
+}
+
+// Stop interrompe o agendamento, cancela o contexto dos jobs em andamento e espera que terminem
+// ou que ctx expire.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	cronCtx := s.cron.Stop()
+	s.stop()
+
+	done := make(chan struct{})
+	go func() {
+		<-cronCtx.Done()
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) Jobs() []services.ScheduledJob {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	jobs := make([]services.ScheduledJob, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		scheduled := services.ScheduledJob{Name: j.name, Schedule: j.schedule}
+		if next := s.cron.Entry(j.entryId).Next; !next.IsZero() {
+			scheduled.NextRun = &next
+		}
+		jobs = append(jobs, scheduled)
+	}
+
+	sort.Slice(jobs, func(i, k int) bool { return jobs[i].Name < jobs[k].Name })
+	return jobs
+}
+
+func (s *Scheduler) Trigger(name string, triggeredBy string) (*domain.JobRun, error) {
+	s.mu.RLock()
+	j, ok := s.jobs[name]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, domain.NewJobNotFoundError(name)
+	}
+
+	ctx, span := s.startSpan(j, "manual")
+	unlock, acquired, err := database.TryAdvisoryLock(ctx, s.pool, lockKey(name))
+	if err != nil {
+		span.End()
+		return nil, err
+	}
+	if !acquired {
+		span.End()
+		return nil, domain.NewJobAlreadyRunningError(name)
+	}
+
+	run, err := s.runs.Start(ctx, name, triggeredBy)
+	if err != nil {
+		unlock()
+		span.End()
+		return nil, err
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer span.End()
+		defer unlock()
+		s.execute(ctx, span, j, run)
+	}()
+
+	return run, nil
+}
+
+func (s *Scheduler) runScheduled(j *job) {
+	s.wg.Add(1)
+	defer s.wg.Done()
+
+	ctx, span := s.startSpan(j, "schedule")
+	defer span.End()
+	log := s.logger.Ctx(ctx)
+
+	unlock, acquired, err := database.TryAdvisoryLock(ctx, s.pool, lockKey(j.name))
+	if err != nil {
+		log.Error(fmt.Sprintf("Error acquiring lock for job %s", j.name), err)
+		return
+	}
+	if !acquired {
+		log.Info(fmt.Sprintf("Job %s is running on another instance, skipping", j.name))
+		return
+	}
+	defer unlock()
+
+	// O cron atualiza Prev antes de atender a consulta, então ele é o horário deste disparo
+	scheduledFor := s.cron.Entry(j.entryId).Prev
+	run, err := s.runs.StartScheduled(ctx, j.name, scheduledFor)
+	if err != nil {
+		log.Error(fmt.Sprintf("Error recording run of job %s", j.name), err)
+		return
+	}
+	if run == nil {
+		log.Info(fmt.Sprintf("Job %s already ran on another instance for %s, skipping", j.name, scheduledFor.Format(time.RFC3339)))
+		return
+	}
+
+	s.execute(ctx, span, j, run)
+}
+
+// startSpan abre a raiz do trace de um disparo, que cobre o lock, o registro em job_runs e a
+// execução, já que o job roda fora de qualquer requisição.
+func (s *Scheduler) startSpan(j *job, trigger string) (context.Context, trace.Span) {
+	return tracer.Start(s.ctx, "scheduled_job "+j.name,
+		trace.WithNewRoot(),
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(attribute.String("job.name", j.name), attribute.String("job.trigger", trigger)),
+	)
+}
+
+func (s *Scheduler) execute(ctx context.Context, span trace.Span, j *job, run *domain.JobRun) {
+	started := time.Now()
+	status := domain.JobRunStatusSucceeded
+
+	span.SetAttributes(attribute.String("job.run_id", run.Id))
+	ctx = logger.WithField(ctx, "job_run_id", run.Id)
+	log := s.logger.Ctx(ctx)
+
+	var result, errorMessage *string
+	summary, err := safeRun(ctx, j.run)
+	if summary != "" {
+		result = &summary
+	}
+	if err != nil {
+		status = domain.JobRunStatusFailed
+		message := err.Error()
+		errorMessage = &message
+		span.RecordError(err)
+		span.SetStatus(codes.Error, message)
+		log.Error(fmt.Sprintf("Job %s failed", j.name), err)
+	} else {
+		log.Info(fmt.Sprintf("Job %s finished in %s: %s", j.name, time.Since(started).Round(time.Millisecond), summary))
+	}
+
+	// Registrado mesmo quando o scheduler está parando e s.ctx já foi cancelado
+	if err := s.runs.Finish(context.WithoutCancel(ctx), run.Id, status, result, errorMessage); err != nil {
+		log.Error(fmt.Sprintf("Error recording result of job %s", j.name), err)
+	}
+}
+
+// safeRun impede que um panic dentro do job derrube a API.
+func safeRun(ctx context.Context, run services.JobFunc) (summary string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return run(ctx)
+}
+
+func lockKey(name string) string {
+	return "zion-job:" + name
+}
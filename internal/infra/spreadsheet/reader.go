@@ -0,0 +1,333 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// zipSignature abre todo arquivo XLSX, que é um pacote zip de XMLs.
+var zipSignature = []byte("PK\x03\x04")
+
+// maxXLSXPartSize limita cada XML descompactado, já que um arquivo pequeno pode se expandir
+// muito ao ser descompactado.
+const maxXLSXPartSize = 64 << 20
+
+// maxColumns limita as colunas lidas de cada linha. As planilhas de importação têm poucas
+// colunas, e uma célula isolada em XFD faria cada linha ocupar 16384 posições.
+const maxColumns = 256
+
+// Reader lê planilhas em CSV ou XLSX sem depender da extensão do arquivo: o formato é
+// reconhecido pelo conteúdo.
+type Reader struct{}
+
+func NewReader() *Reader {
+	return &Reader{}
+}
+
+func (r *Reader) Read(data []byte) ([]domain.SpreadsheetRow, error) {
+	if bytes.HasPrefix(data, zipSignature) {
+		return readXLSX(data)
+	}
+	return readCSV(data)
+}
+
+// readCSV aceita vírgula, ponto e vírgula ou tab como separador. O Excel em português salva
+// CSV com ponto e vírgula e em Windows-1252, então um arquivo que não é UTF-8 é convertido.
+func readCSV(data []byte) ([]domain.SpreadsheetRow, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(data) {
+		decoded, err := charmap.Windows1252.NewDecoder().Bytes(data)
+		if err != nil {
+			return nil, domain.NewInvalidSpreadsheetError("the file is not a CSV or XLSX file")
+		}
+		data = decoded
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = csvSeparator(data)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	rows := newRowLimit()
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows.rows, nil
+		}
+		if err != nil {
+			return nil, domain.NewInvalidSpreadsheetError(err.Error())
+		}
+		// O número da linha no arquivo, já que o leitor pula as linhas em branco
+		line, _ := reader.FieldPos(0)
+		if err := rows.add(domain.SpreadsheetRow{Number: line, Cells: record}); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// rowLimit acumula as linhas lidas e interrompe a leitura quando as preenchidas passam do
+// cabeçalho mais domain.MaxImportRows, antes de o arquivo inteiro ir para a memória.
+type rowLimit struct {
+	rows   []domain.SpreadsheetRow
+	filled int
+}
+
+func newRowLimit() *rowLimit {
+	return &rowLimit{rows: []domain.SpreadsheetRow{}}
+}
+
+func (l *rowLimit) add(row domain.SpreadsheetRow) error {
+	for _, cell := range row.Cells {
+		if strings.TrimSpace(cell) != "" {
+			l.filled++
+			break
+		}
+	}
+	if l.filled > domain.MaxImportRows+1 {
+		return domain.NewInvalidSpreadsheetError(fmt.Sprintf("the spreadsheet has more than %d rows below the header", domain.MaxImportRows))
+	}
+	l.rows = append(l.rows, row)
+	return nil
+}
+
+// csvSeparator escolhe o separador mais frequente na primeira linha, o cabeçalho.
+func csvSeparator(data []byte) rune {
+	header, _, _ := bytes.Cut(data, []byte("\n"))
+	separator, count := ',', bytes.Count(header, []byte(","))
+	for _, candidate := range []rune{';', '\t'} {
+		if n := bytes.Count(header, []byte(string(candidate))); n > count {
+			separator, count = candidate, n
+		}
+	}
+	return separator
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		Id     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		RelationshipId string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+// xlsxText é um texto do XLSX: simples em <t> ou formatado em várias partes <r><t>.
+type xlsxText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	var text strings.Builder
+	text.WriteString(t.Text)
+	for _, run := range t.Runs {
+		text.WriteString(run.Text)
+	}
+	return text.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxRow struct {
+	Number int `xml:"r,attr"`
+	Cells  []struct {
+		Ref    string   `xml:"r,attr"`
+		Type   string   `xml:"t,attr"`
+		Value  string   `xml:"v"`
+		Inline xlsxText `xml:"is"`
+	} `xml:"c"`
+}
+
+// readXLSX lê a primeira planilha da pasta de trabalho. Células numéricas vêm como o Excel as
+// guarda, com ponto decimal e sem formatação. As linhas são lidas uma a uma, e a leitura para
+// assim que a planilha passa do limite de linhas.
+func readXLSX(data []byte) ([]domain.SpreadsheetRow, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, domain.NewInvalidSpreadsheetError("the XLSX file is corrupted")
+	}
+	files := map[string]*zip.File{}
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+	var sharedStrings xlsxSharedStrings
+	if _, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeXLSXPart(files, "xl/sharedStrings.xml", &sharedStrings); err != nil {
+			return nil, err
+		}
+	}
+
+	worksheet, err := openXLSXPart(files, sheetPath)
+	if err != nil {
+		return nil, err
+	}
+	defer worksheet.Close()
+
+	decoder := xml.NewDecoder(io.LimitReader(worksheet, maxXLSXPartSize))
+	rows := newRowLimit()
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return rows.rows, nil
+		}
+		if err != nil {
+			return nil, domain.NewInvalidSpreadsheetError(fmt.Sprintf("unable to read %s: %v", sheetPath, err))
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+
+		var xlsxRow xlsxRow
+		if err := decoder.DecodeElement(&xlsxRow, &start); err != nil {
+			return nil, domain.NewInvalidSpreadsheetError(fmt.Sprintf("unable to read %s: %v", sheetPath, err))
+		}
+
+		row := domain.SpreadsheetRow{Number: xlsxRow.Number, Cells: []string{}}
+		// O número da linha e a referência da célula são opcionais no formato
+		if row.Number == 0 {
+			row.Number = len(rows.rows) + 1
+		}
+		for j, cell := range xlsxRow.Cells {
+			column := j
+			if cell.Ref != "" {
+				column = columnIndex(cell.Ref)
+			}
+			if column < 0 {
+				return nil, domain.NewInvalidSpreadsheetError(fmt.Sprintf("invalid cell reference %q", cell.Ref))
+			}
+
+			value := cell.Value
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(cell.Value)
+				if err != nil || index < 0 || index >= len(sharedStrings.Items) {
+					return nil, domain.NewInvalidSpreadsheetError(fmt.Sprintf("invalid shared string in cell %s", cell.Ref))
+				}
+				value = sharedStrings.Items[index].String()
+			case "inlineStr":
+				value = cell.Inline.String()
+			}
+
+			// Células vazias além do limite, como as que o Excel grava só com formatação,
+			// são ignoradas em vez de alocar a linha até elas
+			if column >= maxColumns {
+				if strings.TrimSpace(value) == "" {
+					continue
+				}
+				return nil, domain.NewInvalidSpreadsheetError(fmt.Sprintf("cell %s is beyond the limit of %d columns", cell.Ref, maxColumns))
+			}
+
+			for len(row.Cells) <= column {
+				row.Cells = append(row.Cells, "")
+			}
+			row.Cells[column] = value
+		}
+		if err := rows.add(row); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// firstSheetPath segue as relações da pasta de trabalho até o arquivo da primeira planilha.
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	var workbook xlsxWorkbook
+	if err := decodeXLSXPart(files, "xl/workbook.xml", &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", domain.NewInvalidSpreadsheetError("the XLSX file has no sheets")
+	}
+
+	var relationships xlsxRelationships
+	if err := decodeXLSXPart(files, "xl/_rels/workbook.xml.rels", &relationships); err != nil {
+		return "", err
+	}
+	for _, relationship := range relationships.Relationships {
+		if relationship.Id != workbook.Sheets[0].RelationshipId {
+			continue
+		}
+		// O destino é relativo à pasta xl/, a não ser que comece com /
+		if strings.HasPrefix(relationship.Target, "/") {
+			return strings.TrimPrefix(relationship.Target, "/"), nil
+		}
+		return path.Join("xl", relationship.Target), nil
+	}
+	// Arquivos no formato estrito usam outro namespace nas relações; a primeira planilha
+	// costuma ter o nome padrão
+	if _, ok := files["xl/worksheets/sheet1.xml"]; ok {
+		return "xl/worksheets/sheet1.xml", nil
+	}
+	return "", domain.NewInvalidSpreadsheetError("the first sheet of the XLSX file was not found")
+}
+
+func openXLSXPart(files map[string]*zip.File, name string) (io.ReadCloser, error) {
+	file, ok := files[name]
+	if !ok {
+		return nil, domain.NewInvalidSpreadsheetError(fmt.Sprintf("the XLSX file has no %s", name))
+	}
+	if file.UncompressedSize64 > maxXLSXPartSize {
+		return nil, domain.NewInvalidSpreadsheetError("the XLSX file is too large")
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return nil, domain.NewInvalidSpreadsheetError(fmt.Sprintf("unable to open %s: %v", name, err))
+	}
+	return reader, nil
+}
+
+func decodeXLSXPart(files map[string]*zip.File, name string, v any) error {
+	reader, err := openXLSXPart(files, name)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	if err := xml.NewDecoder(io.LimitReader(reader, maxXLSXPartSize)).Decode(v); err != nil {
+		return domain.NewInvalidSpreadsheetError(fmt.Sprintf("unable to read %s: %v", name, err))
+	}
+	return nil
+}
+
+// columnIndex converte a referência da célula (C7, AA12) no índice da coluna a partir de zero.
+func columnIndex(ref string) int {
+	index := 0
+	letters := 0
+	for _, char := range ref {
+		if char < 'A' || char > 'Z' {
+			break
+		}
+		index = index*26 + int(char-'A'+1)
+		letters++
+	}
+	// Limite de colunas do Excel (XFD)
+	if letters == 0 || index > 16384 {
+		return -1
+	}
+	return index - 1
+}
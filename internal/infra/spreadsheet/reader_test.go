@@ -0,0 +1,152 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+func xlsxFile(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range parts {
+		file, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		file.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestReader_Read(t *testing.T) {
+	reader := NewReader()
+
+	t.Run("should read a semicolon separated CSV keeping the line numbers", func(t *testing.T) {
+		rows, err := reader.Read([]byte("\xef\xbb\xbfnome;valor\nLasanha;\"40,00\"\n\nNhoque;55,90\n"))
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+
+		expected := []domain.SpreadsheetRow{
+			{Number: 1, Cells: []string{"nome", "valor"}},
+			{Number: 2, Cells: []string{"Lasanha", "40,00"}},
+			{Number: 4, Cells: []string{"Nhoque", "55,90"}},
+		}
+		if !reflect.DeepEqual(rows, expected) {
+			t.Errorf("expected %v, but got %v", expected, rows)
+		}
+	})
+
+	t.Run("should decode CSV files saved in Windows-1252", func(t *testing.T) {
+		rows, err := reader.Read([]byte("nome,valor\nP\xe3o de queijo,12\n"))
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if rows[1].Cells[0] != "Pão de queijo" {
+			t.Errorf("expected Pão de queijo, but got %q", rows[1].Cells[0])
+		}
+	})
+
+	t.Run("should read the first sheet of an XLSX file", func(t *testing.T) {
+		data := xlsxFile(t, map[string]string{
+			"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+				<sheets><sheet name="Preços" sheetId="1" r:id="rId3"/><sheet name="Outra" sheetId="2" r:id="rId1"/></sheets></workbook>`,
+			"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+				<Relationship Id="rId1" Target="worksheets/sheet1.xml"/><Relationship Id="rId3" Target="worksheets/sheet2.xml"/></Relationships>`,
+			"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+				<si><t>nome</t></si><si><t>valor</t></si><si><r><t>Lasa</t></r><r><t>nha</t></r></si></sst>`,
+			"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row r="1"><c r="A1" t="inlineStr"><is><t>wrong sheet</t></is></c></row></sheetData></worksheet>`,
+			"xl/worksheets/sheet2.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+				<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>
+				<row r="3"><c r="A3" t="s"><v>2</v></c><c r="C3"><v>40.5</v></c></row>
+				<row r="4"><c r="B4" t="inlineStr"><is><t>Nhoque</t></is></c></row>
+			</sheetData></worksheet>`,
+		})
+
+		rows, err := reader.Read(data)
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+
+		expected := []domain.SpreadsheetRow{
+			{Number: 1, Cells: []string{"nome", "valor"}},
+			{Number: 3, Cells: []string{"Lasanha", "", "40.5"}},
+			{Number: 4, Cells: []string{"", "Nhoque"}},
+		}
+		if !reflect.DeepEqual(rows, expected) {
+			t.Errorf("expected %v, but got %v", expected, rows)
+		}
+	})
+
+	t.Run("should reject a zip file that is not a spreadsheet", func(t *testing.T) {
+		_, err := reader.Read(xlsxFile(t, map[string]string{"readme.txt": "hello"}))
+
+		var invalid *domain.InvalidSpreadsheetError
+		if !errors.As(err, &invalid) {
+			t.Errorf("expected an invalid spreadsheet error, but got %v", err)
+		}
+	})
+
+	sheet := func(rows string) []byte {
+		return xlsxFile(t, map[string]string{
+			"xl/workbook.xml": `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+				<sheets><sheet name="Planilha1" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+			"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+			"xl/worksheets/sheet1.xml":   `<worksheet><sheetData>` + rows + `</sheetData></worksheet>`,
+		})
+	}
+
+	t.Run("should stop reading an XLSX file past the row limit", func(t *testing.T) {
+		rows := strings.Repeat(`<row><c t="inlineStr"><is><t>Lasanha</t></is></c></row>`, domain.MaxImportRows+2)
+
+		_, err := reader.Read(sheet(rows))
+
+		var invalid *domain.InvalidSpreadsheetError
+		if !errors.As(err, &invalid) || !strings.Contains(err.Error(), "more than 5000 rows") {
+			t.Errorf("expected a row limit error, but got %v", err)
+		}
+	})
+
+	t.Run("should stop reading a CSV file past the row limit", func(t *testing.T) {
+		_, err := reader.Read([]byte("nome\n" + strings.Repeat("Lasanha\n", domain.MaxImportRows+1)))
+
+		var invalid *domain.InvalidSpreadsheetError
+		if !errors.As(err, &invalid) || !strings.Contains(err.Error(), "more than 5000 rows") {
+			t.Errorf("expected a row limit error, but got %v", err)
+		}
+	})
+
+	t.Run("should not count blank rows against the limit", func(t *testing.T) {
+		rows := `<row><c t="inlineStr"><is><t>nome</t></is></c></row>` + strings.Repeat(`<row><c><v></v></c></row>`, domain.MaxImportRows+1)
+
+		if _, err := reader.Read(sheet(rows)); err != nil {
+			t.Errorf("expected no error, but got %v", err)
+		}
+	})
+
+	t.Run("should ignore empty cells beyond the column limit and reject filled ones", func(t *testing.T) {
+		rows, err := reader.Read(sheet(`<row r="1"><c r="A1" t="inlineStr"><is><t>nome</t></is></c><c r="XFD1" s="1"/></row>`))
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if len(rows) != 1 || len(rows[0].Cells) != 1 {
+			t.Errorf("expected a single cell, but got %v", rows)
+		}
+
+		_, err = reader.Read(sheet(`<row r="1"><c r="XFD1"><v>1</v></c></row>`))
+		var invalid *domain.InvalidSpreadsheetError
+		if !errors.As(err, &invalid) || !strings.Contains(err.Error(), "XFD1") {
+			t.Errorf("expected a column limit error, but got %v", err)
+		}
+	})
+}
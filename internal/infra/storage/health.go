@@ -0,0 +1,21 @@
+package storage
+
+import "context"
+
+// HealthCheck verifica o acesso ao bucket. Não é crítico: sem o storage só o upload de imagens
+// fica indisponível, então a API segue pronta, mas degradada.
+type HealthCheck struct {
+	tigris *Tigris
+}
+
+func NewHealthCheck(tigris *Tigris) *HealthCheck {
+	return &HealthCheck{tigris: tigris}
+}
+
+func (c *HealthCheck) Name() string   { return "storage" }
+func (c *HealthCheck) Critical() bool { return false }
+
+func (c *HealthCheck) Check(ctx context.Context) (map[string]any, error) {
+	details := map[string]any{"bucket": c.tigris.bucket}
+	return details, c.tigris.Ping(ctx)
+}
@@ -13,6 +13,7 @@ import (
 )
 
 type Tigris struct {
+	s3            *s3.Client
 	client        *s3.PresignClient
 	bucket        string
 	endpoint      string
@@ -38,6 +39,7 @@ func NewTigris(bucket, endpoint, publicURLBase, region, accessKey, secretKey str
 	}
 
 	return &Tigris{
+		s3:            s3Client,
 		client:        presignClient,
 		bucket:        bucket,
 		endpoint:      endpoint,
@@ -45,8 +47,8 @@ func NewTigris(bucket, endpoint, publicURLBase, region, accessKey, secretKey str
 	}, nil
 }
 
-func (t *Tigris) GetPresignedURL(objectKey string) (*services.PresignedURLResponse, error) {
-	req, err := t.client.PresignPutObject(context.Background(), &s3.PutObjectInput{
+func (t *Tigris) GetPresignedURL(ctx context.Context, objectKey string) (*services.PresignedURLResponse, error) {
+	req, err := t.client.PresignPutObject(ctx, &s3.PutObjectInput{
 		Bucket: &t.bucket,
 		Key:    &objectKey,
 	}, func(po *s3.PresignOptions) {
@@ -69,3 +71,11 @@ func (t *Tigris) buildPublicURL(objectKey string) string {
 	publicURLBase := strings.TrimSuffix(t.publicURLBase, "/")
 	return fmt.Sprintf("%s/%s", publicURLBase, objectKey)
 }
+
+// Ping confirma que o bucket existe e que as credenciais têm acesso a ele.
+func (t *Tigris) Ping(ctx context.Context) error {
+	if _, err := t.s3.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: &t.bucket}); err != nil {
+		return fmt.Errorf("failed to reach bucket %s: %w", t.bucket, err)
+	}
+	return nil
+}
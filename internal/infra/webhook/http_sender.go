@@ -0,0 +1,120 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+const (
+	SignatureHeader = "X-Zion-Signature"
+	EventHeader     = "X-Zion-Event"
+	DeliveryHeader  = "X-Zion-Delivery"
+)
+
+const maxErrorBody = 512
+
+// HTTPSender entrega os eventos com um POST JSON assinado com HMAC-SHA256.
+//
+// O header X-Zion-Signature tem o formato "t=<unix>,v1=<hex>", onde a assinatura é calculada
+// sobre "<unix>.<corpo>" com o segredo da assinatura. O receptor deve recalcular o HMAC e
+// rejeitar timestamps muito antigos para evitar replays.
+type HTTPSender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewHTTPSender() *HTTPSender {
+	return newHTTPSender(domain.IsPublicWebhookAddr)
+}
+
+// newHTTPSender só conecta em IPs aceitos por allow. A verificação é feita no IP já resolvido,
+// então um domínio que aponta (ou redireciona) para a rede interna também é recusado.
+func newHTTPSender(allow func(netip.Addr) bool) *HTTPSender {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil || !allow(addr) {
+				return fmt.Errorf("webhook address %s is not allowed", host)
+			}
+			return nil
+		},
+	}
+
+	return &HTTPSender{
+		client: &http.Client{
+			Timeout: domain.WebhookDeliveryTimeout,
+			// Sem proxy: a conexão precisa ir direto ao IP verificado pelo dialer
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: 5 * time.Second,
+				MaxIdleConns:        20,
+				IdleConnTimeout:     90 * time.Second,
+			},
+		},
+		now: time.Now,
+	}
+}
+
+func (s *HTTPSender) Send(ctx context.Context, subscription domain.WebhookSubscription, delivery domain.WebhookDelivery) domain.WebhookDeliveryAttempt {
+	timestamp := s.now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return failedAttempt(nil, fmt.Errorf("error creating webhook request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "zion-webhooks/1.0")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, delivery.Id)
+	req.Header.Set(SignatureHeader, Sign(subscription.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return failedAttempt(nil, fmt.Errorf("error sending webhook: %w", err))
+	}
+	defer resp.Body.Close()
+
+	statusCode := resp.StatusCode
+	if statusCode < 200 || statusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return failedAttempt(&statusCode, fmt.Errorf("webhook endpoint returned status %d: %s", statusCode, strings.TrimSpace(string(body))))
+	}
+
+	return domain.WebhookDeliveryAttempt{StatusCode: &statusCode}
+}
+
+// Sign monta o valor do header de assinatura para o payload.
+func Sign(secret string, timestamp int64, payload []byte) string {
+	ts := strconv.FormatInt(timestamp, 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func failedAttempt(statusCode *int, err error) domain.WebhookDeliveryAttempt {
+	message := err.Error()
+	return domain.WebhookDeliveryAttempt{StatusCode: statusCode, Error: &message}
+}
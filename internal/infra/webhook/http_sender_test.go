@@ -0,0 +1,131 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+// verifySignature reproduz a validação que um receptor faria com o segredo compartilhado.
+func verifySignature(secret string, header string, body []byte) bool {
+	parts := strings.Split(header, ",")
+	if len(parts) != 2 || !strings.HasPrefix(parts[0], "t=") || !strings.HasPrefix(parts[1], "v1=") {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.TrimPrefix(parts[0], "t=") + "."))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	return hmac.Equal([]byte(expected), []byte(strings.TrimPrefix(parts[1], "v1=")))
+}
+
+// newTestSender aceita o loopback do httptest, recusado pelo NewHTTPSender.
+func newTestSender() *HTTPSender {
+	return newHTTPSender(func(addr netip.Addr) bool { return true })
+}
+
+func TestHTTPSender_Send(t *testing.T) {
+	delivery := domain.WebhookDelivery{
+		Id:        "delivery-1",
+		EventType: domain.WebhookOrderCreated,
+		Payload:   []byte(`{"id":"event-1","type":"order.created","data":{"id":"order-1"}}`),
+	}
+
+	t.Run("should post the signed payload to the receiver", func(t *testing.T) {
+		var body []byte
+		var headers http.Header
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			headers = r.Header
+			body, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		sender := newTestSender()
+		sender.now = func() time.Time { return time.Unix(1760000000, 0) }
+		subscription := domain.WebhookSubscription{Url: server.URL, Secret: "whsec_test"}
+
+		attempt := sender.Send(context.Background(), subscription, delivery)
+
+		if !attempt.Succeeded() {
+			t.Fatalf("expected delivery to succeed, but got %+v", attempt)
+		}
+		if string(body) != string(delivery.Payload) {
+			t.Errorf("expected body %s, but got %s", delivery.Payload, body)
+		}
+		if headers.Get(EventHeader) != domain.WebhookOrderCreated || headers.Get(DeliveryHeader) != "delivery-1" {
+			t.Errorf("expected event and delivery headers, but got %v", headers)
+		}
+		signature := headers.Get(SignatureHeader)
+		if !strings.HasPrefix(signature, "t=1760000000,") {
+			t.Errorf("expected signature timestamp 1760000000, but got %s", signature)
+		}
+		if !verifySignature("whsec_test", signature, body) {
+			t.Errorf("expected signature %s to be valid for the secret", signature)
+		}
+		if verifySignature("other-secret", signature, body) {
+			t.Errorf("expected signature not to be valid for another secret")
+		}
+	})
+
+	t.Run("should report non 2xx responses as failures", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte("upstream down"))
+		}))
+		defer server.Close()
+
+		attempt := newTestSender().Send(context.Background(), domain.WebhookSubscription{Url: server.URL, Secret: "s"}, delivery)
+
+		if attempt.Succeeded() {
+			t.Fatalf("expected delivery to fail")
+		}
+		if attempt.StatusCode == nil || *attempt.StatusCode != http.StatusBadGateway {
+			t.Errorf("expected status code 502, but got %v", attempt.StatusCode)
+		}
+		if attempt.Error == nil || !strings.Contains(*attempt.Error, "upstream down") {
+			t.Errorf("expected error with response body, but got %v", attempt.Error)
+		}
+	})
+
+	t.Run("should report connection errors without status code", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		url := server.URL
+		server.Close()
+
+		attempt := newTestSender().Send(context.Background(), domain.WebhookSubscription{Url: url, Secret: "s"}, delivery)
+
+		if attempt.Succeeded() || attempt.StatusCode != nil || attempt.Error == nil {
+			t.Errorf("expected connection failure without status code, but got %+v", attempt)
+		}
+	})
+
+	t.Run("should refuse to connect to local addresses", func(t *testing.T) {
+		received := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = true
+		}))
+		defer server.Close()
+
+		attempt := NewHTTPSender().Send(context.Background(), domain.WebhookSubscription{Url: server.URL, Secret: "s"}, delivery)
+
+		if attempt.Succeeded() || received {
+			t.Errorf("expected delivery to a loopback address to be refused, but got %+v", attempt)
+		}
+		if attempt.Error == nil || !strings.Contains(*attempt.Error, "not allowed") {
+			t.Errorf("expected error about the address, but got %v", attempt.Error)
+		}
+	})
+}
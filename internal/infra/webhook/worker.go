@@ -0,0 +1,51 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/deividr/zion-api/internal/infra/logger"
+)
+
+const workerBatchSize = 20
+
+type deliverer interface {
+	DeliverDue(ctx context.Context, limit int) (int, error)
+}
+
+// Worker verifica periodicamente a fila de entregas pendentes. Como as entregas são reservadas
+// com FOR UPDATE SKIP LOCKED, é seguro rodar um worker em cada instância da API.
+type Worker struct {
+	deliverer deliverer
+	interval  time.Duration
+	logger    *logger.Logger
+}
+
+func NewWorker(deliverer deliverer, interval time.Duration) *Worker {
+	return &Worker{deliverer: deliverer, interval: interval, logger: logger.New()}
+}
+
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Continua processando enquanto houver lotes cheios, sem esperar o próximo tick.
+		for {
+			processed, err := w.deliverer.DeliverDue(ctx, workerBatchSize)
+			if err != nil {
+				w.logger.Error("Error delivering webhooks", err)
+				break
+			}
+			if processed < workerBatchSize || ctx.Err() != nil {
+				break
+			}
+		}
+	}
+}
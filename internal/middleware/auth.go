@@ -5,14 +5,35 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/deividr/zion-api/internal/infra/logger"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func AuthMiddleware(publicKey string) gin.HandlerFunc {
+const UserIdKey = "userId"
+
+// GetUserId retorna o id (subject do token Clerk) do usuário autenticado na requisição.
+func GetUserId(c *gin.Context) string {
+	return c.GetString(UserIdKey)
+}
+
+// AuthMiddleware valida o token Clerk do header Authorization. As rotas em queryTokenRoutes
+// (streams SSE) também aceitam o token no parâmetro token da query, já que o EventSource não
+// permite enviar headers; nas demais rotas ele é ignorado, para não vazar em logs e históricos.
+func AuthMiddleware(publicKey string, queryTokenRoutes ...string) gin.HandlerFunc {
+	queryToken := make(map[string]bool, len(queryTokenRoutes))
+	for _, route := range queryTokenRoutes {
+		queryToken[route] = true
+	}
+
 	return func(c *gin.Context) {
 		// Obtém o token do header Authorization
 		authHeader := c.GetHeader("Authorization")
+
+		if authHeader == "" && queryToken[c.FullPath()] && c.GetHeader("Accept") == "text/event-stream" {
+			authHeader = c.Query("token")
+		}
+
 		if authHeader == "" {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
 			return
@@ -42,6 +63,12 @@ func AuthMiddleware(publicKey string) gin.HandlerFunc {
 			return
 		}
 
+		// Disponibiliza o usuário autenticado para os handlers
+		if subject, err := token.Claims.GetSubject(); err == nil {
+			c.Set(UserIdKey, subject)
+			c.Request = c.Request.WithContext(logger.WithField(c.Request.Context(), "user_id", subject))
+		}
+
 		c.Next()
 	}
 }
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "user-1"}).SignedString(key)
+	if err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+
+	router := gin.New()
+	router.Use(AuthMiddleware(string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey})), "/orders/events"))
+	router.GET("/orders/events", func(c *gin.Context) {
+		c.String(http.StatusOK, GetUserId(c))
+	})
+	router.GET("/orders", func(c *gin.Context) {
+		c.String(http.StatusOK, GetUserId(c))
+	})
+
+	serve := func(path string, header string, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(header, value)
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+		return res
+	}
+
+	t.Run("should accept the token in the Authorization header", func(t *testing.T) {
+		res := serve("/orders", "Authorization", "Bearer "+token)
+		if res.Code != http.StatusOK || res.Body.String() != "user-1" {
+			t.Errorf("expected 200 for user-1, but got %d %s", res.Code, res.Body.String())
+		}
+	})
+
+	t.Run("should accept the query token on the event stream", func(t *testing.T) {
+		res := serve("/orders/events?token="+token, "Accept", "text/event-stream")
+		if res.Code != http.StatusOK || res.Body.String() != "user-1" {
+			t.Errorf("expected 200 for user-1, but got %d %s", res.Code, res.Body.String())
+		}
+	})
+
+	t.Run("should ignore the query token on other routes", func(t *testing.T) {
+		res := serve("/orders?token="+token, "Accept", "text/event-stream")
+		if res.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401, but got %d %s", res.Code, res.Body.String())
+		}
+	})
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConnDeadline substitui os prazos de leitura e escrita do servidor para rotas de longa duração.
+// Com timeout zero a conexão não tem prazo, como nos streams SSE que ficam abertos enquanto o
+// cliente estiver conectado. O prazo de leitura também precisa ser trocado: ao expirar, o
+// servidor cancela o contexto da requisição mesmo com o handler ainda respondendo.
+func ConnDeadline(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		deadline := time.Time{}
+		if timeout > 0 {
+			deadline = time.Now().Add(timeout)
+		}
+
+		// Sem suporte do ResponseWriter (ex: testes com httptest) os prazos do servidor continuam valendo
+		rc := http.NewResponseController(c.Writer)
+		_ = rc.SetReadDeadline(deadline)
+		_ = rc.SetWriteDeadline(deadline)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestConnDeadline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	stream := func(c *gin.Context) {
+		for i := 0; i < 5; i++ {
+			select {
+			case <-c.Request.Context().Done():
+				return
+			case <-time.After(50 * time.Millisecond):
+			}
+			fmt.Fprintf(c.Writer, "tick %d\n", i)
+			c.Writer.Flush()
+		}
+	}
+
+	newServer := func(handlers ...gin.HandlerFunc) *httptest.Server {
+		router := gin.New()
+		router.GET("/stream", handlers...)
+
+		server := httptest.NewUnstartedServer(router)
+		server.Config.ReadTimeout = 100 * time.Millisecond
+		server.Config.WriteTimeout = 100 * time.Millisecond
+		server.Start()
+		return server
+	}
+
+	read := func(t *testing.T, url string) string {
+		t.Helper()
+		resp, err := http.Get(url)
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	t.Run("should keep streaming past the server timeouts", func(t *testing.T) {
+		server := newServer(ConnDeadline(0), stream)
+		defer server.Close()
+
+		if body := read(t, server.URL+"/stream"); !strings.Contains(body, "tick 4") {
+			t.Errorf("expected the full stream, but got %q", body)
+		}
+	})
+
+	t.Run("should cut the stream at the server timeouts without the middleware", func(t *testing.T) {
+		server := newServer(stream)
+		defer server.Close()
+
+		if body := read(t, server.URL+"/stream"); strings.Contains(body, "tick 4") {
+			t.Errorf("expected the stream to be cut, but got %q", body)
+		}
+	})
+}
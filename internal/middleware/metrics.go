@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/deividr/zion-api/internal/infra/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics registra contagem e latência das requisições por rota. Requisições que não casam com
+// nenhuma rota ficam agrupadas em "unmatched", para que scanners não criem uma série por caminho.
+func Metrics() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		metrics.RequestStarted()
+
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.ObserveRequest(ctx.Request.Method, route, ctx.Writer.Status(), time.Since(start))
+	}
+}
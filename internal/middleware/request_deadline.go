@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/deividr/zion-api/internal/infra/database"
+	"github.com/gin-gonic/gin"
+)
+
+// StatusClientClosedRequest segue a convenção do nginx para requisições abandonadas pelo
+// cliente antes da resposta.
+const StatusClientClosedRequest = 499
+
+// databaseRetryAfter é o tempo sugerido ao cliente para tentar de novo quando o banco está lento.
+const databaseRetryAfter = "5"
+
+// RequestDeadline aplica timeout ao contexto da requisição, que chega até o pgx e cancela as
+// consultas em andamento quando o prazo expira ou o cliente desconecta. As rotas em skip
+// (streams e long polling, que controlam o próprio prazo) não recebem o timeout, mas continuam
+// tendo as respostas traduzidas.
+//
+// Como os controllers respondem 404 ou 500 para qualquer erro do caso de uso, a tradução é
+// feita aqui: um erro respondido depois de o prazo da requisição expirar vira 504, e um erro
+// respondido depois de uma operação do banco estourar o próprio limite vira 503.
+func RequestDeadline(timeout time.Duration, skip ...string) gin.HandlerFunc {
+	skipped := make(map[string]bool, len(skip))
+	for _, route := range skip {
+		skipped[route] = true
+	}
+
+	return func(c *gin.Context) {
+		ctx := database.TrackQueryTimeouts(c.Request.Context())
+		if timeout > 0 && !skipped[c.FullPath()] {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Writer = &deadlineWriter{ResponseWriter: c.Writer, ctx: ctx}
+
+		c.Next()
+	}
+}
+
+// deadlineWriter troca a resposta de erro do handler pela resposta de timeout no momento em que
+// o status é definido, antes de qualquer byte do corpo original ser escrito.
+type deadlineWriter struct {
+	gin.ResponseWriter
+	ctx      context.Context
+	replaced bool
+}
+
+func (w *deadlineWriter) WriteHeader(code int) {
+	if w.replaced || w.Written() || code < http.StatusBadRequest {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+
+	switch {
+	case errors.Is(w.ctx.Err(), context.Canceled):
+		w.ResponseWriter.WriteHeader(StatusClientClosedRequest)
+	case errors.Is(w.ctx.Err(), context.DeadlineExceeded):
+		w.replace(http.StatusGatewayTimeout, gin.H{
+			"message": "The request took too long to complete",
+			"error":   "request_timeout",
+		})
+	case database.QueryTimedOut(w.ctx):
+		w.Header().Set("Retry-After", databaseRetryAfter)
+		w.replace(http.StatusServiceUnavailable, gin.H{
+			"message": "The database is taking too long to respond, try again shortly",
+			"error":   "database_timeout",
+		})
+	default:
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (w *deadlineWriter) replace(code int, body gin.H) {
+	w.replaced = true
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Del("Content-Length")
+	w.ResponseWriter.WriteHeader(code)
+
+	encoded, _ := json.Marshal(body)
+	_, _ = w.ResponseWriter.Write(encoded)
+}
+
+// Write e WriteString descartam o corpo original quando a resposta foi substituída.
+func (w *deadlineWriter) Write(data []byte) (int, error) {
+	if w.replaced {
+		return len(data), nil
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *deadlineWriter) WriteString(s string) (int, error) {
+	if w.replaced {
+		return len(s), nil
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Unwrap permite que http.ResponseController (usado por ConnDeadline) chegue à conexão.
+func (w *deadlineWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
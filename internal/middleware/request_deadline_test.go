@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/deividr/zion-api/internal/infra/database"
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestDeadline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(RequestDeadline(50*time.Millisecond, "/stream"))
+	router.GET("/slow", func(c *gin.Context) {
+		<-c.Request.Context().Done()
+		c.IndentedJSON(http.StatusNotFound, gin.H{"message": "Order not found"})
+	})
+	router.GET("/query-timeout", func(c *gin.Context) {
+		database.MarkQueryTimeout(c.Request.Context())
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch orders"})
+	})
+	router.GET("/ok", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	router.GET("/stream", func(c *gin.Context) {
+		if _, ok := c.Request.Context().Deadline(); ok {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	serve := func(path string) (*httptest.ResponseRecorder, map[string]string) {
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, httptest.NewRequest(http.MethodGet, path, nil))
+
+		body := map[string]string{}
+		_ = json.Unmarshal(res.Body.Bytes(), &body)
+		return res, body
+	}
+
+	t.Run("should answer 504 when the request deadline expires", func(t *testing.T) {
+		res, body := serve("/slow")
+		if res.Code != http.StatusGatewayTimeout || body["error"] != "request_timeout" {
+			t.Errorf("expected 504 request_timeout, but got %d %s", res.Code, res.Body.String())
+		}
+	})
+
+	t.Run("should answer 503 when a query hit its own timeout", func(t *testing.T) {
+		res, body := serve("/query-timeout")
+		if res.Code != http.StatusServiceUnavailable || body["error"] != "database_timeout" {
+			t.Errorf("expected 503 database_timeout, but got %d %s", res.Code, res.Body.String())
+		}
+		if res.Header().Get("Retry-After") == "" {
+			t.Error("expected Retry-After header")
+		}
+	})
+
+	t.Run("should keep successful responses", func(t *testing.T) {
+		res, body := serve("/ok")
+		if res.Code != http.StatusOK || body["status"] != "ok" {
+			t.Errorf("expected 200, but got %d %s", res.Code, res.Body.String())
+		}
+	})
+
+	t.Run("should not set a deadline on skipped routes", func(t *testing.T) {
+		if res, _ := serve("/stream"); res.Code != http.StatusNoContent {
+			t.Errorf("expected 204, but got %d", res.Code)
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"github.com/deividr/zion-api/internal/infra/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	RequestIdHeader = "X-Request-ID"
+	RequestIdKey    = "requestId"
+
+	maxRequestIdLength = 128
+)
+
+// GetRequestId retorna o id da requisição definido pelo middleware RequestID.
+func GetRequestId(c *gin.Context) string {
+	return c.GetString(RequestIdKey)
+}
+
+// RequestID reaproveita o X-Request-ID enviado pelo cliente ou pelo proxy, ou gera um novo, e o
+// devolve na resposta. O id vai para o contexto da requisição, então todo log feito com
+// logger.FromContext a partir dali sai com request_id.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestId := c.GetHeader(RequestIdHeader)
+		if !validRequestId(requestId) {
+			requestId = uuid.NewString()
+		}
+
+		c.Set(RequestIdKey, requestId)
+		c.Header(RequestIdHeader, requestId)
+		c.Request = c.Request.WithContext(logger.WithField(c.Request.Context(), "request_id", requestId))
+		trace.SpanFromContext(c.Request.Context()).SetAttributes(attribute.String("request.id", requestId))
+
+		c.Next()
+	}
+}
+
+// validRequestId recusa ids vazios, longos demais ou com caracteres de controle, que poderiam
+// quebrar as linhas de log.
+func validRequestId(id string) bool {
+	if id == "" || len(id) > maxRequestIdLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
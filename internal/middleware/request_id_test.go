@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(seen *string) *gin.Engine {
+		router := gin.New()
+		router.Use(RequestID())
+		router.GET("/orders", func(c *gin.Context) {
+			*seen = GetRequestId(c)
+			c.Status(http.StatusNoContent)
+		})
+		return router
+	}
+
+	t.Run("should reuse the id sent by the client", func(t *testing.T) {
+		var seen string
+		req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+		req.Header.Set(RequestIdHeader, "edge-123")
+		res := httptest.NewRecorder()
+
+		newRouter(&seen).ServeHTTP(res, req)
+
+		if seen != "edge-123" || res.Header().Get(RequestIdHeader) != "edge-123" {
+			t.Errorf("expected id edge-123 in handler and response, but got %q and %q", seen, res.Header().Get(RequestIdHeader))
+		}
+	})
+
+	t.Run("should generate an id when missing or invalid", func(t *testing.T) {
+		for _, sent := range []string{"", "bad id", strings.Repeat("a", maxRequestIdLength+1)} {
+			var seen string
+			req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+			req.Header.Set(RequestIdHeader, sent)
+			res := httptest.NewRecorder()
+
+			newRouter(&seen).ServeHTTP(res, req)
+
+			if seen == "" || seen == sent || res.Header().Get(RequestIdHeader) != seen {
+				t.Errorf("expected a generated id for %q, but got %q (header %q)", sent, seen, res.Header().Get(RequestIdHeader))
+			}
+		}
+	})
+}
@@ -0,0 +1,12 @@
+package middleware
+
+import "net/http"
+
+// TraceFilter deixa de fora do tracing as sondas do orquestrador, que chegam a cada poucos segundos.
+func TraceFilter(r *http.Request) bool {
+	switch r.URL.Path {
+	case "/healthz", "/readyz":
+		return false
+	}
+	return true
+}
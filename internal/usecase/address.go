@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/deividr/zion-api/internal/domain"
@@ -14,8 +15,11 @@ func NewAddressUseCase(repo domain.AddressRepository) *AddressUseCase {
 	return &AddressUseCase{repo: repo}
 }
 
-func (uc *AddressUseCase) GetAll(pagination domain.Pagination) ([]domain.Address, domain.Pagination, error) {
-	addresses, pagination, err := uc.repo.FindAll(pagination)
+func (uc *AddressUseCase) GetAll(ctx context.Context, pagination domain.Pagination) ([]domain.Address, domain.Pagination, error) {
+	ctx, span := startSpan(ctx, "AddressUseCase.GetAll")
+	defer span.End()
+
+	addresses, pagination, err := uc.repo.FindAll(ctx, pagination)
 	if err != nil {
 		return nil, domain.Pagination{}, fmt.Errorf("error fetching addresses: %v", err)
 	}
@@ -23,33 +27,45 @@ func (uc *AddressUseCase) GetAll(pagination domain.Pagination) ([]domain.Address
 	return addresses, pagination, nil
 }
 
-func (uc *AddressUseCase) GetById(id string) (*domain.Address, error) {
-	address, err := uc.repo.FindById(id)
+func (uc *AddressUseCase) GetById(ctx context.Context, id string) (*domain.Address, error) {
+	ctx, span := startSpan(ctx, "AddressUseCase.GetById")
+	defer span.End()
+
+	address, err := uc.repo.FindById(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching address by id: %v", err)
 	}
 	return address, nil
 }
 
-func (uc *AddressUseCase) GetBy(filters map[string]any) ([]domain.Address, error) {
-	address, err := uc.repo.FindBy(filters)
+func (uc *AddressUseCase) GetBy(ctx context.Context, filters map[string]any) ([]domain.Address, error) {
+	ctx, span := startSpan(ctx, "AddressUseCase.GetBy")
+	defer span.End()
+
+	address, err := uc.repo.FindBy(ctx, filters)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching address by id: %v", err)
 	}
 	return address, nil
 }
 
-func (uc *AddressUseCase) GetByCustomerId(customerId string) ([]domain.Address, error) {
-	addresses, err := uc.repo.FindByCustomerId(customerId)
+func (uc *AddressUseCase) GetByCustomerId(ctx context.Context, customerId string) ([]domain.Address, error) {
+	ctx, span := startSpan(ctx, "AddressUseCase.GetByCustomerId")
+	defer span.End()
+
+	addresses, err := uc.repo.FindByCustomerId(ctx, customerId)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching addresses by customer id: %v", err)
 	}
 	return addresses, nil
 }
 
-func (uc *AddressUseCase) Update(customerId string, addressId string, updateData domain.NewAddress) (*domain.Address, error) {
+func (uc *AddressUseCase) Update(ctx context.Context, customerId string, addressId string, updateData domain.NewAddress) (*domain.Address, error) {
+	ctx, span := startSpan(ctx, "AddressUseCase.Update")
+	defer span.End()
+
 	// Verify if the address belongs to the customer
-	addresses, err := uc.repo.FindByCustomerId(customerId)
+	addresses, err := uc.repo.FindByCustomerId(ctx, customerId)
 	if err != nil {
 		return nil, fmt.Errorf("error on verify customer addresses: %v", err)
 	}
@@ -78,13 +94,13 @@ func (uc *AddressUseCase) Update(customerId string, addressId string, updateData
 	// If CEP or number changed, we need to delete the old address association and create a new one
 	if cepChanged || numberChanged {
 		// Delete the old address association
-		err := uc.repo.Delete(customerId, addressId)
+		err := uc.repo.Delete(ctx, customerId, addressId)
 		if err != nil {
 			return nil, fmt.Errorf("error on delete old address: %v", err)
 		}
 
 		// Create the new address association
-		newAddress, err := uc.repo.Create(customerId, updateData)
+		newAddress, err := uc.repo.Create(ctx, customerId, updateData)
 		if err != nil {
 			return nil, fmt.Errorf("error on create new address: %v", err)
 		}
@@ -106,21 +122,21 @@ func (uc *AddressUseCase) Update(customerId string, addressId string, updateData
 		IsDefault:        updateData.IsDefault,
 	}
 
-	err = uc.repo.Update(addressToUpdate)
+	err = uc.repo.Update(ctx, addressToUpdate)
 	if err != nil {
 		return nil, fmt.Errorf("error on update address informations: %v", err)
 	}
 
 	// If the address is marked as default, remove default flag from other addresses
 	if updateData.IsDefault != nil && *updateData.IsDefault {
-		err = uc.repo.UpdateDefaultAddress(customerId, addressId)
+		err = uc.repo.UpdateDefaultAddress(ctx, customerId, addressId)
 		if err != nil {
 			return nil, fmt.Errorf("error on update default address: %v", err)
 		}
 	}
 
 	// Fetch and return the updated address
-	updatedAddress, err := uc.repo.FindById(addressId)
+	updatedAddress, err := uc.repo.FindById(ctx, addressId)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching updated address: %v", err)
 	}
@@ -130,9 +146,12 @@ func (uc *AddressUseCase) Update(customerId string, addressId string, updateData
 	return updatedAddress, nil
 }
 
-func (uc *AddressUseCase) Delete(customerId string, addressId string) error {
+func (uc *AddressUseCase) Delete(ctx context.Context, customerId string, addressId string) error {
+	ctx, span := startSpan(ctx, "AddressUseCase.Delete")
+	defer span.End()
+
 	// Verify if the address belongs to the customer
-	addresses, err := uc.repo.FindByCustomerId(customerId)
+	addresses, err := uc.repo.FindByCustomerId(ctx, customerId)
 	if err != nil {
 		return fmt.Errorf("error on verify customer addresses: %v", err)
 	}
@@ -150,15 +169,18 @@ func (uc *AddressUseCase) Delete(customerId string, addressId string) error {
 		return fmt.Errorf("address does not belong to this customer")
 	}
 
-	if err := uc.repo.Delete(customerId, addressId); err != nil {
+	if err := uc.repo.Delete(ctx, customerId, addressId); err != nil {
 		return fmt.Errorf("error on delete address: %v", err)
 	}
 
 	return nil
 }
 
-func (uc *AddressUseCase) Create(customerId string, newAddress domain.NewAddress) (*domain.Address, error) {
-	createdAddress, err := uc.repo.Create(customerId, newAddress)
+func (uc *AddressUseCase) Create(ctx context.Context, customerId string, newAddress domain.NewAddress) (*domain.Address, error) {
+	ctx, span := startSpan(ctx, "AddressUseCase.Create")
+	defer span.End()
+
+	createdAddress, err := uc.repo.Create(ctx, customerId, newAddress)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+type BackgroundJobUseCase struct {
+	repo domain.BackgroundJobRepository
+}
+
+func NewBackgroundJobUseCase(repo domain.BackgroundJobRepository) *BackgroundJobUseCase {
+	return &BackgroundJobUseCase{repo: repo}
+}
+
+func (uc *BackgroundJobUseCase) GetStats(ctx context.Context) ([]domain.QueueStats, error) {
+	ctx, span := startSpan(ctx, "BackgroundJobUseCase.GetStats")
+	defer span.End()
+
+	stats, err := uc.repo.Stats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching queue stats: %v", err)
+	}
+	return stats, nil
+}
+
+func (uc *BackgroundJobUseCase) GetDead(ctx context.Context, pagination domain.Pagination) ([]domain.BackgroundJob, domain.Pagination, error) {
+	ctx, span := startSpan(ctx, "BackgroundJobUseCase.GetDead")
+	defer span.End()
+
+	jobs, pagination, err := uc.repo.FindDead(ctx, pagination)
+	if err != nil {
+		return nil, domain.Pagination{}, fmt.Errorf("error fetching dead background jobs: %v", err)
+	}
+	return jobs, pagination, nil
+}
+
+// Retry devolve um job da dead-letter para a fila com as tentativas zeradas.
+func (uc *BackgroundJobUseCase) Retry(ctx context.Context, id string) error {
+	ctx, span := startSpan(ctx, "BackgroundJobUseCase.Retry")
+	defer span.End()
+
+	return uc.repo.Retry(ctx, id)
+}
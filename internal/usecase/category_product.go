@@ -1,6 +1,8 @@
 package usecase
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
 	"github.com/deividr/zion-api/internal/domain"
@@ -14,42 +16,105 @@ func NewCategoryProductUseCase(repo domain.CategoryProductRepository) *CategoryP
 	return &CategoryProductUseCase{repo: repo}
 }
 
-func (uc *CategoryProductUseCase) GetAll() ([]domain.CategoryProduct, error) {
-	categories, err := uc.repo.FindAll()
+func (uc *CategoryProductUseCase) GetAll(ctx context.Context) ([]domain.CategoryProduct, error) {
+	ctx, span := startSpan(ctx, "CategoryProductUseCase.GetAll")
+	defer span.End()
+
+	categories, err := uc.repo.FindAll(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao buscar categorias: %v", err)
 	}
 	return categories, nil
 }
 
-func (uc *CategoryProductUseCase) GetById(id string) (*domain.CategoryProduct, error) {
-	category, err := uc.repo.FindById(id)
+func (uc *CategoryProductUseCase) GetById(ctx context.Context, id string) (*domain.CategoryProduct, error) {
+	ctx, span := startSpan(ctx, "CategoryProductUseCase.GetById")
+	defer span.End()
+
+	category, err := uc.repo.FindById(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao buscar categoria: %v", err)
 	}
 	return category, nil
 }
 
-func (uc *CategoryProductUseCase) Update(category domain.CategoryProduct) error {
-	err := uc.repo.Update(category)
+func (uc *CategoryProductUseCase) Update(ctx context.Context, category domain.CategoryProduct) error {
+	ctx, span := startSpan(ctx, "CategoryProductUseCase.Update")
+	defer span.End()
+
+	if err := uc.validateParent(ctx, category.Id, category.ParentId); err != nil {
+		return err
+	}
+
+	err := uc.repo.Update(ctx, category)
 	if err != nil {
 		return fmt.Errorf("erro ao atualizar categoria: %v", err)
 	}
 	return nil
 }
 
-func (uc *CategoryProductUseCase) Delete(id string) error {
-	err := uc.repo.Delete(id)
+func (uc *CategoryProductUseCase) Delete(ctx context.Context, id string, reassignTo *string) error {
+	ctx, span := startSpan(ctx, "CategoryProductUseCase.Delete")
+	defer span.End()
+
+	if reassignTo != nil {
+		if *reassignTo == id {
+			return domain.NewInvalidCategoryReassignError(id, *reassignTo)
+		}
+		if _, err := uc.repo.FindById(ctx, *reassignTo); err != nil {
+			var notFoundErr *domain.CategoryNotFoundError
+			if errors.As(err, &notFoundErr) {
+				return domain.NewInvalidCategoryReassignError(id, *reassignTo)
+			}
+			return fmt.Errorf("erro ao buscar categoria de destino: %v", err)
+		}
+	}
+
+	err := uc.repo.Delete(ctx, id, reassignTo)
 	if err != nil {
+		var hasProductsErr *domain.CategoryHasProductsError
+		var notFoundErr *domain.CategoryNotFoundError
+		if errors.As(err, &hasProductsErr) || errors.As(err, &notFoundErr) {
+			return err
+		}
 		return fmt.Errorf("erro ao deletar categoria: %v", err)
 	}
 	return nil
 }
 
-func (uc *CategoryProductUseCase) Create(category domain.CategoryProduct) (*domain.CategoryProduct, error) {
-	createdCategory, err := uc.repo.Create(category)
+func (uc *CategoryProductUseCase) Create(ctx context.Context, category domain.CategoryProduct) (*domain.CategoryProduct, error) {
+	ctx, span := startSpan(ctx, "CategoryProductUseCase.Create")
+	defer span.End()
+
+	if err := uc.validateParent(ctx, "", category.ParentId); err != nil {
+		return nil, err
+	}
+
+	createdCategory, err := uc.repo.Create(ctx, category)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao criar categoria: %v", err)
 	}
 	return createdCategory, nil
 }
+
+// validateParent garante que a categoria pai existe e que a hierarquia não forma ciclos.
+func (uc *CategoryProductUseCase) validateParent(ctx context.Context, categoryId string, parentId *string) error {
+	if parentId == nil {
+		return nil
+	}
+
+	current := parentId
+	for current != nil {
+		if *current == categoryId {
+			return domain.NewInvalidCategoryParentError(categoryId, *parentId)
+		}
+
+		parent, err := uc.repo.FindById(ctx, *current)
+		if err != nil {
+			return domain.NewInvalidCategoryParentError(categoryId, *parentId)
+		}
+		current = parent.ParentId
+	}
+
+	return nil
+}
@@ -1,9 +1,11 @@
 package usecase
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/deividr/zion-api/internal/domain"
+	"github.com/google/uuid"
 )
 
 type CustomerUseCase struct {
@@ -14,46 +16,151 @@ func NewCustomerUseCase(repo domain.CustomerRepository) *CustomerUseCase {
 	return &CustomerUseCase{repo: repo}
 }
 
-func (uc *CustomerUseCase) GetAll(pagination domain.Pagination, filters domain.FindAllCustomerFilters) ([]domain.Customer, domain.Pagination, error) {
-	products, pagination, err := uc.repo.FindAll(pagination, filters)
+func (uc *CustomerUseCase) GetAll(ctx context.Context, pagination domain.Pagination, filters domain.FindAllCustomerFilters) ([]domain.Customer, domain.Pagination, error) {
+	ctx, span := startSpan(ctx, "CustomerUseCase.GetAll")
+	defer span.End()
+
+	products, pagination, err := uc.repo.FindAll(ctx, pagination, filters)
 
 	if err != nil {
 		return nil, domain.Pagination{}, fmt.Errorf("erro ao buscar clientes: %v", err)
 	}
 
+	for i := range products {
+		products[i].SetDisplayPhones()
+	}
+
 	return products, pagination, nil
 }
 
-func (uc *CustomerUseCase) GetById(id string) (*domain.Customer, error) {
-	product, err := uc.repo.FindById(id)
+func (uc *CustomerUseCase) GetById(ctx context.Context, id string) (*domain.Customer, error) {
+	ctx, span := startSpan(ctx, "CustomerUseCase.GetById")
+	defer span.End()
+
+	product, err := uc.repo.FindById(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao buscar cliente: %v", err)
 	}
+	product.SetDisplayPhones()
 	return product, nil
 }
 
-func (uc *CustomerUseCase) Update(product domain.Customer) error {
-	err := uc.repo.Update(product)
+// GetByPhone busca o cliente pelo telefone principal ou secundário, aceitando qualquer formato de entrada.
+func (uc *CustomerUseCase) GetByPhone(ctx context.Context, phone string) (*domain.Customer, error) {
+	ctx, span := startSpan(ctx, "CustomerUseCase.GetByPhone")
+	defer span.End()
+
+	normalized, err := domain.NormalizePhone(phone)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := uc.repo.FindByPhone(ctx, normalized)
 	if err != nil {
-		return fmt.Errorf("erro ao atualizar cliente: %v", err)
+		return nil, fmt.Errorf("erro ao buscar cliente por telefone: %w", err)
+	}
+	customer.SetDisplayPhones()
+	return customer, nil
+}
+
+func (uc *CustomerUseCase) Update(ctx context.Context, product domain.Customer) error {
+	ctx, span := startSpan(ctx, "CustomerUseCase.Update")
+	defer span.End()
+
+	phone, err := domain.NormalizePhone(product.Phone)
+	if err != nil {
+		return err
+	}
+	product.Phone = phone
+
+	product.Phone2, err = domain.NormalizeOptionalPhone(product.Phone2)
+	if err != nil {
+		return err
+	}
+
+	product.SetDisplayPhones()
+	job, err := webhookJob(domain.WebhookCustomerUpdated, product)
+	if err != nil {
+		return err
+	}
+
+	err = uc.repo.Update(ctx, product, []domain.NewBackgroundJob{job})
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar cliente: %w", err)
 	}
 	return nil
 }
 
-func (uc *CustomerUseCase) Delete(id string) error {
-	err := uc.repo.Delete(id)
+func (uc *CustomerUseCase) UpdatePreferences(ctx context.Context, id string, preferences domain.CustomerPreferences) (*domain.CustomerPreferences, error) {
+	ctx, span := startSpan(ctx, "CustomerUseCase.UpdatePreferences")
+	defer span.End()
+
+	normalized, err := domain.NormalizeCustomerPreferences(preferences)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.UpdatePreferences(ctx, id, normalized); err != nil {
+		return nil, fmt.Errorf("erro ao atualizar preferências do cliente: %v", err)
+	}
+	return &normalized, nil
+}
+
+func (uc *CustomerUseCase) Delete(ctx context.Context, id string) error {
+	ctx, span := startSpan(ctx, "CustomerUseCase.Delete")
+	defer span.End()
+
+	job, err := webhookJob(domain.WebhookCustomerDeleted, map[string]string{"id": id})
+	if err != nil {
+		return err
+	}
+
+	err = uc.repo.Delete(ctx, id, []domain.NewBackgroundJob{job})
 	if err != nil {
 		return fmt.Errorf("erro ao deletar cliente: %v", err)
 	}
 	return nil
 }
 
-func (uc *CustomerUseCase) Create(newCustomer domain.NewCustomer) (*domain.Customer, error) {
-	createdCustomer, err := uc.repo.Create(newCustomer)
+func (uc *CustomerUseCase) Create(ctx context.Context, newCustomer domain.NewCustomer) (*domain.Customer, error) {
+	ctx, span := startSpan(ctx, "CustomerUseCase.Create")
+	defer span.End()
+
+	phone, err := domain.NormalizePhone(newCustomer.Phone)
+	if err != nil {
+		return nil, err
+	}
+	newCustomer.Phone = phone
+
+	newCustomer.Phone2, err = domain.NormalizeOptionalPhone(newCustomer.Phone2)
+	if err != nil {
+		return nil, err
+	}
 
+	// O id é gerado aqui para que o evento gravado no outbox já leve o cliente completo
+	newCustomer.Id = uuid.New().String()
+	customer := domain.Customer{
+		Id:                 newCustomer.Id,
+		Name:               newCustomer.Name,
+		Phone:              newCustomer.Phone,
+		Phone2:             newCustomer.Phone2,
+		Email:              newCustomer.Email,
+		Tags:               []string{},
+		DietaryPreferences: []string{},
+	}
+	customer.SetDisplayPhones()
+	job, err := webhookJob(domain.WebhookCustomerCreated, customer)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao criar cliente: %v", err)
+		return nil, err
 	}
 
+	createdCustomer, err := uc.repo.Create(ctx, newCustomer, []domain.NewBackgroundJob{job})
+
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar cliente: %w", err)
+	}
+
+	createdCustomer.SetDisplayPhones()
+
 	return createdCustomer, nil
 }
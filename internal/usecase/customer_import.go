@@ -0,0 +1,266 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/domain/services"
+	"github.com/google/uuid"
+)
+
+const (
+	customerImportName   = "name"
+	customerImportPhone  = "phone"
+	customerImportPhone2 = "phone2"
+	customerImportEmail  = "email"
+)
+
+var customerImportAliases = map[string][]string{
+	customerImportName:   {"name", "nome", "cliente", "customer"},
+	customerImportPhone:  {"phone", "telefone", "celular", "fone", "whatsapp"},
+	customerImportPhone2: {"phone2", "telefone2", "celular2", "fone2", "outrotelefone"},
+	customerImportEmail:  {"email"},
+}
+
+// CustomerImportUseCase importa clientes de uma planilha. O cliente que já tem um dos telefones
+// da linha, no principal ou no secundário, é atualizado em vez de duplicado.
+type CustomerImportUseCase struct {
+	reader services.SpreadsheetReader
+	repo   domain.CustomerImportRepository
+}
+
+func NewCustomerImportUseCase(reader services.SpreadsheetReader, repo domain.CustomerImportRepository) *CustomerImportUseCase {
+	return &CustomerImportUseCase{reader: reader, repo: repo}
+}
+
+// customerImportLine é uma linha já normalizada, antes de ser comparada com o cadastro.
+type customerImportLine struct {
+	row    domain.ImportRow[domain.Customer]
+	name   string
+	phone  string
+	phone2 *string
+	email  *string
+}
+
+// Import valida a planilha e devolve o que mudaria. Com confirm, grava tudo em uma transação,
+// desde que nenhuma linha seja inválida; senão devolve a prévia junto com
+// domain.InvalidImportRowsError.
+func (uc *CustomerImportUseCase) Import(ctx context.Context, data []byte, confirm bool) (*domain.ImportPreview[domain.Customer], error) {
+	ctx, span := startSpan(ctx, "CustomerImportUseCase.Import")
+	defer span.End()
+
+	sheet, err := uc.reader.Read(data)
+	if err != nil {
+		return nil, err
+	}
+	columns, rows, err := readImportSheet(sheet, customerImportAliases, customerImportPhone)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]customerImportLine, 0, len(rows))
+	phones := []string{}
+	for _, sheetRow := range rows {
+		line := customerImportLine{
+			row:  domain.ImportRow[domain.Customer]{Row: sheetRow.Number, Changes: []string{}, Errors: []domain.ImportRowError{}},
+			name: strings.Join(strings.Fields(columns.value(sheetRow, customerImportName)), " "),
+		}
+		addError := func(column string, message string) {
+			line.row.Errors = append(line.row.Errors, domain.ImportRowError{Column: column, Message: message})
+		}
+
+		if value := columns.value(sheetRow, customerImportPhone); value == "" {
+			addError(customerImportPhone, "phone is required")
+		} else if phone, err := domain.NormalizePhone(value); err != nil {
+			addError(customerImportPhone, err.Error())
+		} else {
+			line.phone = phone
+			phones = append(phones, phone)
+		}
+		if value := columns.value(sheetRow, customerImportPhone2); value != "" {
+			if phone2, err := domain.NormalizePhone(value); err != nil {
+				addError(customerImportPhone2, err.Error())
+			} else if phone2 == line.phone {
+				addError(customerImportPhone2, "phone2 is the same as phone")
+			} else {
+				line.phone2 = &phone2
+				phones = append(phones, phone2)
+			}
+		}
+		if value := columns.value(sheetRow, customerImportEmail); value != "" {
+			if address, err := mail.ParseAddress(value); err != nil || address.Address != value {
+				addError(customerImportEmail, fmt.Sprintf("invalid email %q", value))
+			} else {
+				line.email = &value
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	existing, err := uc.repo.FindByPhones(ctx, phones)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar clientes por telefone: %v", err)
+	}
+	deletedPhones, err := uc.repo.DeletedPhones(ctx, phones)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar telefones de clientes excluídos: %v", err)
+	}
+	byPhone := map[string]domain.Customer{}
+	for _, customer := range existing {
+		byPhone[customer.Phone] = customer
+		if customer.Phone2 != nil {
+			byPhone[*customer.Phone2] = customer
+		}
+	}
+
+	preview := &domain.ImportPreview[domain.Customer]{IgnoredColumns: columns.ignored, Rows: make([]domain.ImportRow[domain.Customer], 0, len(lines))}
+	seenPhones := map[string]int{}
+	seenCustomers := map[string]int{}
+	for _, line := range lines {
+		row := line.row
+		addError := func(column string, message string) {
+			row.Errors = append(row.Errors, domain.ImportRowError{Column: column, Message: message})
+		}
+
+		// Os telefones da linha não podem apontar para dois clientes nem repetir outra linha
+		var match *domain.Customer
+		for _, phone := range []struct {
+			column string
+			value  *string
+		}{{customerImportPhone, &line.phone}, {customerImportPhone2, line.phone2}} {
+			if phone.value == nil || *phone.value == "" {
+				continue
+			}
+			if previous := seenPhones[*phone.value]; previous > 0 {
+				addError(phone.column, fmt.Sprintf("phone already listed in row %d", previous))
+				continue
+			}
+			seenPhones[*phone.value] = row.Row
+
+			customer, found := byPhone[*phone.value]
+			switch {
+			case found && match != nil && match.Id != customer.Id:
+				addError(phone.column, fmt.Sprintf("phone belongs to another customer, %s", customer.Name))
+			case found:
+				match = &customer
+			case slices.Contains(deletedPhones, *phone.value):
+				addError(phone.column, "phone belongs to a deleted customer")
+			}
+		}
+		if match != nil {
+			if previous := seenCustomers[match.Id]; previous > 0 {
+				addError(customerImportPhone, fmt.Sprintf("customer %s already updated in row %d", match.Name, previous))
+			}
+			seenCustomers[match.Id] = row.Row
+			row.Id = &match.Id
+			row.Record = *match
+		}
+
+		current := row.Record
+		if line.name != "" {
+			row.Record.Name = line.name
+		} else if match == nil {
+			addError(customerImportName, "name is required for new customers")
+		}
+		if line.phone != "" {
+			row.Record.Phone = line.phone
+		}
+		switch {
+		case line.phone2 != nil:
+			row.Record.Phone2 = line.phone2
+		case match != nil && match.Phone2 != nil && *match.Phone2 == line.phone:
+			// O telefone da linha era o secundário: o antigo principal passa a ser o secundário
+			previous := match.Phone
+			row.Record.Phone2 = &previous
+		}
+		if line.email != nil {
+			row.Record.Email = line.email
+		}
+		if match == nil {
+			row.Record.Tags = []string{}
+			row.Record.DietaryPreferences = []string{}
+		} else {
+			if row.Record.Name != current.Name {
+				row.Changes = append(row.Changes, customerImportName)
+			}
+			if row.Record.Phone != current.Phone {
+				row.Changes = append(row.Changes, customerImportPhone)
+			}
+			if !equalOptional(row.Record.Phone2, current.Phone2) {
+				row.Changes = append(row.Changes, customerImportPhone2)
+			}
+			if !equalOptional(row.Record.Email, current.Email) {
+				row.Changes = append(row.Changes, customerImportEmail)
+			}
+		}
+		row.Record.SetDisplayPhones()
+		importAction(&row)
+		preview.Rows = append(preview.Rows, row)
+	}
+	preview.Summary = summarizeImport(preview.Rows)
+
+	if !confirm {
+		return preview, nil
+	}
+	if preview.Summary.Invalid > 0 {
+		return preview, domain.NewInvalidImportRowsError(preview.Summary.Invalid)
+	}
+
+	// Os ids dos novos clientes são gerados aqui para que os eventos gravados no outbox, na
+	// mesma transação da importação, já levem os clientes completos
+	creates := []domain.NewCustomer{}
+	updates := []domain.Customer{}
+	jobs := []domain.NewBackgroundJob{}
+	for i, row := range preview.Rows {
+		eventType := domain.WebhookCustomerUpdated
+		switch row.Action {
+		case domain.ImportActionCreate:
+			id := uuid.New().String()
+			preview.Rows[i].Id = &id
+			preview.Rows[i].Record.Id = id
+			preview.Rows[i].Record.Tags = []string{}
+			preview.Rows[i].Record.DietaryPreferences = []string{}
+			creates = append(creates, domain.NewCustomer{
+				Id:     id,
+				Name:   row.Record.Name,
+				Phone:  row.Record.Phone,
+				Phone2: row.Record.Phone2,
+				Email:  row.Record.Email,
+			})
+			eventType = domain.WebhookCustomerCreated
+		case domain.ImportActionUpdate:
+			updates = append(updates, row.Record)
+		default:
+			continue
+		}
+
+		job, err := webhookJob(eventType, preview.Rows[i].Record)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	if err := uc.repo.Import(ctx, creates, updates, jobs); err != nil {
+		var duplicatePhoneErr *domain.DuplicatePhoneError
+		var deletedErr *domain.ImportRowDeletedError
+		if errors.As(err, &duplicatePhoneErr) || errors.As(err, &deletedErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("erro ao importar clientes: %v", err)
+	}
+
+	preview.Applied = true
+	return preview, nil
+}
+
+func equalOptional(a *string, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
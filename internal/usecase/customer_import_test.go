@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+type mockCustomerImportRepository struct {
+	customers     []domain.Customer
+	deletedPhones []string
+	creates       []domain.NewCustomer
+	updates       []domain.Customer
+	jobs          []domain.NewBackgroundJob
+	importErr     error
+}
+
+func (m *mockCustomerImportRepository) FindByPhones(ctx context.Context, phones []string) ([]domain.Customer, error) {
+	found := []domain.Customer{}
+	for _, customer := range m.customers {
+		if slices.Contains(phones, customer.Phone) || (customer.Phone2 != nil && slices.Contains(phones, *customer.Phone2)) {
+			found = append(found, customer)
+		}
+	}
+	return found, nil
+}
+
+func (m *mockCustomerImportRepository) DeletedPhones(ctx context.Context, phones []string) ([]string, error) {
+	deleted := []string{}
+	for _, phone := range m.deletedPhones {
+		if slices.Contains(phones, phone) {
+			deleted = append(deleted, phone)
+		}
+	}
+	return deleted, nil
+}
+
+func (m *mockCustomerImportRepository) Import(ctx context.Context, creates []domain.NewCustomer, updates []domain.Customer, jobs []domain.NewBackgroundJob) error {
+	if m.importErr != nil {
+		return m.importErr
+	}
+	m.creates = creates
+	m.updates = updates
+	m.jobs = jobs
+	return nil
+}
+
+func newCustomerImportUseCase(rows [][]string) (*CustomerImportUseCase, *mockCustomerImportRepository) {
+	secondary := "+5511988880000"
+	email := "maria@example.com"
+	repo := &mockCustomerImportRepository{
+		customers: []domain.Customer{
+			{Id: "c1", Name: "Maria Silva", Phone: "+5511999990000", Phone2: &secondary, Email: &email},
+			{Id: "c2", Name: "João Souza", Phone: "+5511977770000"},
+		},
+		deletedPhones: []string{"+5511966660000"},
+	}
+	return NewCustomerImportUseCase(&mockSpreadsheetReader{rows: rows}, repo), repo
+}
+
+func TestCustomerImportUseCase_Import(t *testing.T) {
+	t.Run("should match customers by either phone and swap the phones when the secondary becomes the main one", func(t *testing.T) {
+		uc, repo := newCustomerImportUseCase([][]string{
+			{"Nome", "Celular", "E-mail"},
+			{"", "(11) 98888-0000", ""},
+			{"João Souza", "11 97777-0000", ""},
+			{"Ana Lima", "(11) 95555-0000", "ana@example.com"},
+		})
+
+		preview, err := uc.Import(context.Background(), nil, false)
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+
+		maria := preview.Rows[0]
+		if maria.Action != domain.ImportActionUpdate || *maria.Id != "c1" {
+			t.Fatalf("expected maria to be updated, but got %+v", maria)
+		}
+		if maria.Record.Name != "Maria Silva" || maria.Record.Phone != "+5511988880000" || *maria.Record.Phone2 != "+5511999990000" {
+			t.Errorf("expected the phones to be swapped keeping the name, but got %+v", maria.Record)
+		}
+		if !slices.Equal(maria.Changes, []string{customerImportPhone, customerImportPhone2}) {
+			t.Errorf("expected phone and phone2 to change, but got %v", maria.Changes)
+		}
+		if preview.Rows[1].Action != domain.ImportActionUnchanged {
+			t.Errorf("expected joão to be unchanged, but got %+v", preview.Rows[1])
+		}
+		if ana := preview.Rows[2]; ana.Action != domain.ImportActionCreate || ana.Record.PhoneDisplay != "(11) 95555-0000" {
+			t.Errorf("expected ana to be created, but got %+v", ana)
+		}
+		if preview.Summary != (domain.ImportSummary{Rows: 3, Create: 1, Update: 1, Unchanged: 1}) {
+			t.Errorf("unexpected summary %+v", preview.Summary)
+		}
+		if repo.creates != nil || preview.Applied {
+			t.Errorf("expected the preview not to be applied")
+		}
+	})
+
+	t.Run("should report conflicting phones and refuse to apply them", func(t *testing.T) {
+		uc, repo := newCustomerImportUseCase([][]string{
+			{"name", "phone", "phone2", "email"},
+			{"Carlos", "(11) 96666-0000", "", ""},
+			{"Maria", "(11) 99999-0000", "(11) 97777-0000", ""},
+			{"Pedro", "(11) 94444-0000", "", "pedro@"},
+			{"Paulo", "11944440000", "", ""},
+			{"", "(11) 93333-0000", "123", ""},
+		})
+
+		preview, err := uc.Import(context.Background(), nil, true)
+		var invalidRows *domain.InvalidImportRowsError
+		if !errors.As(err, &invalidRows) || invalidRows.Invalid != 5 {
+			t.Fatalf("expected 5 invalid rows, but got %v", err)
+		}
+
+		expected := [][]string{
+			{"phone belongs to a deleted customer"},
+			{"phone belongs to another customer, João Souza"},
+			{`invalid email "pedro@"`},
+			{"phone already listed in row 4"},
+		}
+		for i, messages := range expected {
+			got := []string{}
+			for _, rowErr := range preview.Rows[i].Errors {
+				got = append(got, rowErr.Message)
+			}
+			if !slices.Equal(got, messages) {
+				t.Errorf("row %d: expected %v, but got %v", preview.Rows[i].Row, messages, got)
+			}
+		}
+		if errs := preview.Rows[4].Errors; len(errs) != 2 || errs[0].Column != customerImportPhone2 || errs[1].Column != customerImportName {
+			t.Errorf("expected phone2 and name errors, but got %+v", errs)
+		}
+		if repo.creates != nil || preview.Applied {
+			t.Errorf("expected nothing to be applied")
+		}
+	})
+
+	t.Run("should apply creates and updates and publish their events", func(t *testing.T) {
+		uc, repo := newCustomerImportUseCase([][]string{
+			{"cliente", "telefone"},
+			{"João Souza Filho", "(11) 97777-0000"},
+			{"Ana Lima", "(11) 95555-0000"},
+		})
+
+		preview, err := uc.Import(context.Background(), nil, true)
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+
+		if len(repo.updates) != 1 || repo.updates[0].Id != "c2" || repo.updates[0].Name != "João Souza Filho" {
+			t.Errorf("expected joão to be updated, but got %+v", repo.updates)
+		}
+		if len(repo.creates) != 1 || repo.creates[0].Phone != "+5511955550000" {
+			t.Errorf("expected ana to be created, but got %+v", repo.creates)
+		}
+		if *preview.Rows[1].Id != repo.creates[0].Id || preview.Rows[1].Record.Id != repo.creates[0].Id || !preview.Applied {
+			t.Errorf("expected the created id in the preview, but got %+v", preview.Rows[1])
+		}
+		if events := webhookEvents(repo.jobs); !slices.Equal(events, []string{domain.WebhookCustomerUpdated, domain.WebhookCustomerCreated}) {
+			t.Errorf("unexpected events %v", events)
+		}
+	})
+
+	t.Run("should keep the duplicate phone error raised while applying", func(t *testing.T) {
+		uc, repo := newCustomerImportUseCase([][]string{
+			{"nome", "telefone"},
+			{"Ana Lima", "(11) 95555-0000"},
+		})
+		repo.importErr = domain.NewDuplicatePhoneError("+5511955550000")
+
+		_, err := uc.Import(context.Background(), nil, true)
+		var duplicatePhoneErr *domain.DuplicatePhoneError
+		if !errors.As(err, &duplicatePhoneErr) {
+			t.Errorf("expected a duplicate phone error, but got %v", err)
+		}
+		if len(repo.jobs) != 0 {
+			t.Errorf("expected no events, but got %v", repo.jobs)
+		}
+	})
+}
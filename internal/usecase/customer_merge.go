@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+const defaultDuplicatesLimit = 50
+
+type MergeCustomersInput struct {
+	SurvivorId string   `json:"survivorId"`
+	MergedIds  []string `json:"mergedIds"`
+}
+
+type CustomerMergeUseCase struct {
+	repo domain.CustomerMergeRepository
+}
+
+func NewCustomerMergeUseCase(repo domain.CustomerMergeRepository) *CustomerMergeUseCase {
+	return &CustomerMergeUseCase{repo: repo}
+}
+
+func (uc *CustomerMergeUseCase) GetDuplicates(ctx context.Context, limit int) ([]domain.DuplicateCandidate, error) {
+	ctx, span := startSpan(ctx, "CustomerMergeUseCase.GetDuplicates")
+	defer span.End()
+
+	if limit <= 0 {
+		limit = defaultDuplicatesLimit
+	}
+
+	candidates, err := uc.repo.FindDuplicates(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching duplicate customers: %v", err)
+	}
+	return candidates, nil
+}
+
+func (uc *CustomerMergeUseCase) GetMerges(ctx context.Context, pagination domain.Pagination) ([]domain.CustomerMerge, domain.Pagination, error) {
+	ctx, span := startSpan(ctx, "CustomerMergeUseCase.GetMerges")
+	defer span.End()
+
+	merges, pagination, err := uc.repo.FindMerges(ctx, pagination)
+	if err != nil {
+		return nil, domain.Pagination{}, fmt.Errorf("error fetching customer merges: %v", err)
+	}
+	return merges, pagination, nil
+}
+
+func (uc *CustomerMergeUseCase) Merge(ctx context.Context, input MergeCustomersInput, mergedBy string) (*domain.CustomerMerge, error) {
+	ctx, span := startSpan(ctx, "CustomerMergeUseCase.Merge")
+	defer span.End()
+
+	if input.SurvivorId == "" {
+		return nil, domain.NewInvalidMergeError("survivorId is required")
+	}
+
+	seen := map[string]bool{}
+	mergedIds := []string{}
+	for _, id := range input.MergedIds {
+		if id == input.SurvivorId {
+			return nil, domain.NewInvalidMergeError("survivor customer cannot be merged into itself")
+		}
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		mergedIds = append(mergedIds, id)
+	}
+
+	if len(mergedIds) == 0 {
+		return nil, domain.NewInvalidMergeError("at least one customer to merge is required")
+	}
+
+	merge, err := uc.repo.Merge(ctx, input.SurvivorId, mergedIds, mergedBy)
+	if err != nil {
+		var notFoundErr *domain.CustomerNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error merging customers: %v", err)
+	}
+	return merge, nil
+}
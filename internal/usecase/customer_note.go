@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+type CustomerNoteUseCase struct {
+	repo         domain.CustomerNoteRepository
+	customerRepo domain.CustomerRepository
+}
+
+func NewCustomerNoteUseCase(repo domain.CustomerNoteRepository, customerRepo domain.CustomerRepository) *CustomerNoteUseCase {
+	return &CustomerNoteUseCase{repo: repo, customerRepo: customerRepo}
+}
+
+func (uc *CustomerNoteUseCase) GetByCustomerId(ctx context.Context, customerId string) ([]domain.CustomerNote, error) {
+	ctx, span := startSpan(ctx, "CustomerNoteUseCase.GetByCustomerId")
+	defer span.End()
+
+	notes, err := uc.repo.FindByCustomerId(ctx, customerId)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching customer notes: %v", err)
+	}
+	return notes, nil
+}
+
+func (uc *CustomerNoteUseCase) Create(ctx context.Context, customerId string, author string, newNote domain.NewCustomerNote) (*domain.CustomerNote, error) {
+	ctx, span := startSpan(ctx, "CustomerNoteUseCase.Create")
+	defer span.End()
+
+	newNote, err := domain.NormalizeCustomerNote(newNote)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.customerRepo.FindById(ctx, customerId); err != nil {
+		var notFoundErr *domain.CustomerNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error fetching customer: %v", err)
+	}
+
+	note, err := uc.repo.Create(ctx, customerId, author, newNote)
+	if err != nil {
+		return nil, fmt.Errorf("error creating customer note: %v", err)
+	}
+	return note, nil
+}
+
+func (uc *CustomerNoteUseCase) Delete(ctx context.Context, customerId string, noteId string) error {
+	ctx, span := startSpan(ctx, "CustomerNoteUseCase.Delete")
+	defer span.End()
+
+	if err := uc.repo.Delete(ctx, customerId, noteId); err != nil {
+		var notFoundErr *domain.CustomerNoteNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return err
+		}
+		return fmt.Errorf("error deleting customer note: %v", err)
+	}
+	return nil
+}
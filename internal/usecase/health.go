@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/domain/services"
+)
+
+// HealthCheckTimeout limita o /readyz: uma dependência lenta conta como indisponível.
+const HealthCheckTimeout = 2 * time.Second
+
+type HealthUseCase struct {
+	checks    []services.HealthCheck
+	buildInfo domain.BuildInfo
+	timeout   time.Duration
+	now       func() time.Time
+}
+
+func NewHealthUseCase(checks []services.HealthCheck, buildInfo domain.BuildInfo) *HealthUseCase {
+	return &HealthUseCase{checks: checks, buildInfo: buildInfo, timeout: HealthCheckTimeout, now: time.Now}
+}
+
+// Ready executa as verificações em paralelo. Falha em um check crítico deixa a instância fora
+// do balanceador; falha nos demais só marca o relatório como degradado.
+func (uc *HealthUseCase) Ready(ctx context.Context) domain.ReadinessReport {
+	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
+	defer cancel()
+
+	results := make([]domain.HealthCheckResult, len(uc.checks))
+	var wg sync.WaitGroup
+	for i, check := range uc.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = uc.run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	status := domain.HealthStatusOk
+	for _, result := range results {
+		if result.Status == domain.HealthStatusOk {
+			continue
+		}
+		if result.Critical {
+			status = domain.HealthStatusFail
+			break
+		}
+		status = domain.HealthStatusDegraded
+	}
+
+	return domain.ReadinessReport{Status: status, Checks: results, CheckedAt: uc.now()}
+}
+
+func (uc *HealthUseCase) Version() domain.BuildInfo {
+	return uc.buildInfo
+}
+
+func (uc *HealthUseCase) run(ctx context.Context, check services.HealthCheck) domain.HealthCheckResult {
+	start := time.Now()
+	details, err := check.Check(ctx)
+
+	result := domain.HealthCheckResult{
+		Name:      check.Name(),
+		Status:    domain.HealthStatusOk,
+		Critical:  check.Critical(),
+		LatencyMs: time.Since(start).Milliseconds(),
+		Details:   details,
+	}
+	if err != nil {
+		message := err.Error()
+		result.Status = domain.HealthStatusFail
+		result.Error = &message
+	}
+	return result
+}
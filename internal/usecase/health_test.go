@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/domain/services"
+)
+
+type mockHealthCheck struct {
+	name     string
+	critical bool
+	err      error
+	delay    time.Duration
+}
+
+func (m *mockHealthCheck) Name() string   { return m.name }
+func (m *mockHealthCheck) Critical() bool { return m.critical }
+
+func (m *mockHealthCheck) Check(ctx context.Context) (map[string]any, error) {
+	select {
+	case <-time.After(m.delay):
+		return map[string]any{"checked": true}, m.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestHealthUseCase_Ready(t *testing.T) {
+	t.Run("should be ok when every check passes", func(t *testing.T) {
+		useCase := NewHealthUseCase([]services.HealthCheck{
+			&mockHealthCheck{name: "postgres", critical: true},
+			&mockHealthCheck{name: "storage"},
+		}, domain.BuildInfo{})
+
+		report := useCase.Ready(context.Background())
+		if report.Status != domain.HealthStatusOk || !report.Ready() {
+			t.Fatalf("expected ok, but got %q", report.Status)
+		}
+		if len(report.Checks) != 2 || report.Checks[0].Name != "postgres" || report.Checks[1].Name != "storage" {
+			t.Errorf("expected checks in registration order, but got %+v", report.Checks)
+		}
+		if report.Checks[0].Details["checked"] != true {
+			t.Errorf("expected details to be reported, but got %v", report.Checks[0].Details)
+		}
+	})
+
+	t.Run("should be degraded but ready when a non critical check fails", func(t *testing.T) {
+		useCase := NewHealthUseCase([]services.HealthCheck{
+			&mockHealthCheck{name: "postgres", critical: true},
+			&mockHealthCheck{name: "storage", err: errors.New("bucket unreachable")},
+		}, domain.BuildInfo{})
+
+		report := useCase.Ready(context.Background())
+		if report.Status != domain.HealthStatusDegraded || !report.Ready() {
+			t.Fatalf("expected degraded, but got %q", report.Status)
+		}
+		if report.Checks[1].Error == nil || *report.Checks[1].Error != "bucket unreachable" {
+			t.Errorf("expected storage error to be reported, but got %v", report.Checks[1].Error)
+		}
+	})
+
+	t.Run("should fail when a critical check fails", func(t *testing.T) {
+		useCase := NewHealthUseCase([]services.HealthCheck{
+			&mockHealthCheck{name: "postgres", critical: true, err: errors.New("connection refused")},
+			&mockHealthCheck{name: "storage", err: errors.New("bucket unreachable")},
+		}, domain.BuildInfo{})
+
+		report := useCase.Ready(context.Background())
+		if report.Status != domain.HealthStatusFail || report.Ready() {
+			t.Fatalf("expected fail, but got %q", report.Status)
+		}
+	})
+
+	t.Run("should fail a check that exceeds the timeout", func(t *testing.T) {
+		useCase := NewHealthUseCase([]services.HealthCheck{
+			&mockHealthCheck{name: "postgres", critical: true, delay: time.Second},
+		}, domain.BuildInfo{})
+		useCase.timeout = 10 * time.Millisecond
+
+		report := useCase.Ready(context.Background())
+		if report.Status != domain.HealthStatusFail {
+			t.Fatalf("expected fail, but got %q", report.Status)
+		}
+		if report.Checks[0].Error == nil {
+			t.Error("expected the timeout to be reported as error")
+		}
+	})
+}
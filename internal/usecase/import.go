@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+// importColumns localiza os campos na planilha pelo cabeçalho, que pode vir em português ou
+// em inglês, com ou sem acentos.
+type importColumns struct {
+	index   map[string]int
+	ignored []string
+}
+
+// importHeaderKey reduz o cabeçalho a letras e números, para que "Preço variável",
+// "preco_variavel" e "precoVariavel" sejam a mesma coluna.
+func importHeaderKey(header string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, domain.ImportKey(header))
+}
+
+// readImportSheet separa o cabeçalho, a primeira linha preenchida, das linhas de dados. aliases
+// lista, para cada campo, os cabeçalhos aceitos já normalizados.
+func readImportSheet(rows []domain.SpreadsheetRow, aliases map[string][]string, required ...string) (importColumns, []domain.SpreadsheetRow, error) {
+	rows = nonEmptyImportRows(rows)
+	if len(rows) == 0 {
+		return importColumns{}, nil, domain.NewInvalidSpreadsheetError("the spreadsheet is empty")
+	}
+
+	columns := importColumns{index: map[string]int{}, ignored: []string{}}
+	for i, cell := range rows[0].Cells {
+		header := strings.TrimSpace(cell)
+		if header == "" {
+			continue
+		}
+		field := importField(aliases, importHeaderKey(header))
+		if field == "" {
+			columns.ignored = append(columns.ignored, header)
+			continue
+		}
+		if _, ok := columns.index[field]; ok {
+			return importColumns{}, nil, domain.NewInvalidSpreadsheetError(fmt.Sprintf("column %q appears more than once", header))
+		}
+		columns.index[field] = i
+	}
+	for _, field := range required {
+		if _, ok := columns.index[field]; !ok {
+			return importColumns{}, nil, domain.NewInvalidSpreadsheetError(fmt.Sprintf("missing column %q", field))
+		}
+	}
+
+	data := rows[1:]
+	if len(data) == 0 {
+		return importColumns{}, nil, domain.NewInvalidSpreadsheetError("the spreadsheet has no rows below the header")
+	}
+	if len(data) > domain.MaxImportRows {
+		return importColumns{}, nil, domain.NewInvalidSpreadsheetError(fmt.Sprintf("the spreadsheet has %d rows, the limit is %d", len(data), domain.MaxImportRows))
+	}
+	return columns, data, nil
+}
+
+func importField(aliases map[string][]string, key string) string {
+	for field, names := range aliases {
+		for _, name := range names {
+			if name == key {
+				return field
+			}
+		}
+	}
+	return ""
+}
+
+func nonEmptyImportRows(rows []domain.SpreadsheetRow) []domain.SpreadsheetRow {
+	filled := []domain.SpreadsheetRow{}
+	for _, row := range rows {
+		for _, cell := range row.Cells {
+			if strings.TrimSpace(cell) != "" {
+				filled = append(filled, row)
+				break
+			}
+		}
+	}
+	return filled
+}
+
+// value devolve a célula do campo sem espaços nas pontas. Vazio quando a coluna não existe
+// ou a célula está em branco, que na atualização mantêm o valor atual.
+func (c importColumns) value(row domain.SpreadsheetRow, field string) string {
+	index, ok := c.index[field]
+	if !ok || index >= len(row.Cells) {
+		return ""
+	}
+	return strings.TrimSpace(row.Cells[index])
+}
+
+// importAction decide a ação da linha depois da validação.
+func importAction[T any](row *domain.ImportRow[T]) {
+	switch {
+	case len(row.Errors) > 0:
+		row.Action = domain.ImportActionInvalid
+	case row.Id == nil:
+		row.Action = domain.ImportActionCreate
+	case len(row.Changes) > 0:
+		row.Action = domain.ImportActionUpdate
+	default:
+		row.Action = domain.ImportActionUnchanged
+	}
+}
+
+func summarizeImport[T any](rows []domain.ImportRow[T]) domain.ImportSummary {
+	summary := domain.ImportSummary{Rows: len(rows)}
+	for _, row := range rows {
+		switch row.Action {
+		case domain.ImportActionCreate:
+			summary.Create++
+		case domain.ImportActionUpdate:
+			summary.Update++
+		case domain.ImportActionUnchanged:
+			summary.Unchanged++
+		case domain.ImportActionInvalid:
+			summary.Invalid++
+		}
+	}
+	return summary
+}
+
+// parseImportBool aceita as respostas comuns em planilhas; vazio é falso.
+func parseImportBool(value string) (bool, error) {
+	switch domain.ImportKey(value) {
+	case "", "0", "false", "no", "n", "nao":
+		return false, nil
+	case "1", "true", "yes", "y", "sim", "s", "x":
+		return true, nil
+	}
+	return false, fmt.Errorf("expected yes or no, got %q", value)
+}
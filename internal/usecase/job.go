@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/domain/services"
+)
+
+type JobUseCase struct {
+	scheduler services.JobScheduler
+	runs      domain.JobRunRepository
+}
+
+func NewJobUseCase(scheduler services.JobScheduler, runs domain.JobRunRepository) *JobUseCase {
+	return &JobUseCase{scheduler: scheduler, runs: runs}
+}
+
+func (uc *JobUseCase) GetAll(ctx context.Context) ([]domain.JobInfo, error) {
+	ctx, span := startSpan(ctx, "JobUseCase.GetAll")
+	defer span.End()
+
+	scheduled := uc.scheduler.Jobs()
+
+	jobs := make([]domain.JobInfo, 0, len(scheduled))
+	for _, job := range scheduled {
+		lastRun, err := uc.runs.FindLastByJob(ctx, job.Name)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching last run of job %s: %v", job.Name, err)
+		}
+		jobs = append(jobs, domain.JobInfo{
+			Name:     job.Name,
+			Schedule: job.Schedule,
+			NextRun:  job.NextRun,
+			LastRun:  lastRun,
+		})
+	}
+
+	return jobs, nil
+}
+
+func (uc *JobUseCase) GetRuns(ctx context.Context, name string, pagination domain.Pagination) ([]domain.JobRun, domain.Pagination, error) {
+	ctx, span := startSpan(ctx, "JobUseCase.GetRuns")
+	defer span.End()
+
+	if !uc.isRegistered(name) {
+		return nil, domain.Pagination{}, domain.NewJobNotFoundError(name)
+	}
+
+	runs, pagination, err := uc.runs.FindByJob(ctx, name, pagination)
+	if err != nil {
+		return nil, domain.Pagination{}, fmt.Errorf("error fetching runs of job %s: %v", name, err)
+	}
+	return runs, pagination, nil
+}
+
+func (uc *JobUseCase) Trigger(ctx context.Context, name string, userId string) (*domain.JobRun, error) {
+	ctx, span := startSpan(ctx, "JobUseCase.Trigger")
+	defer span.End()
+
+	triggeredBy := domain.JobTriggerManual
+	if userId != "" {
+		triggeredBy = domain.JobTriggerManual + ":" + userId
+	}
+	return uc.scheduler.Trigger(name, triggeredBy)
+}
+
+func (uc *JobUseCase) isRegistered(name string) bool {
+	for _, job := range uc.scheduler.Jobs() {
+		if job.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+type KitchenUseCase struct {
+	repo domain.KitchenRepository
+	now  func() time.Time
+}
+
+func NewKitchenUseCase(repo domain.KitchenRepository) *KitchenUseCase {
+	return &KitchenUseCase{repo: repo, now: time.Now}
+}
+
+func (uc *KitchenUseCase) GetTickets(ctx context.Context, filters domain.KitchenFilters) ([]domain.KitchenTicket, error) {
+	ctx, span := startSpan(ctx, "KitchenUseCase.GetTickets")
+	defer span.End()
+
+	tickets, err := uc.repo.FindTickets(ctx, uc.withDefaultWindow(filters))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching kitchen tickets: %v", err)
+	}
+	return tickets, nil
+}
+
+func (uc *KitchenUseCase) GetBatches(ctx context.Context, filters domain.KitchenFilters) ([]domain.KitchenBatch, error) {
+	ctx, span := startSpan(ctx, "KitchenUseCase.GetBatches")
+	defer span.End()
+
+	filters.IncludeDone = false
+
+	tickets, err := uc.repo.FindTickets(ctx, uc.withDefaultWindow(filters))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching kitchen tickets: %v", err)
+	}
+	return domain.GroupKitchenTickets(tickets), nil
+}
+
+// SetTicketStatus dá baixa (ou desfaz a baixa) em uma linha. Quando a última linha pendente
+// é finalizada o pedido passa para pronto; ao desfazer a baixa de um pedido pronto ele volta
+// para pendente. A linha e o pedido mudam na mesma transação.
+func (uc *KitchenUseCase) SetTicketStatus(ctx context.Context, ticketId string, status string, userId string) (*domain.KitchenOrderProgress, error) {
+	ctx, span := startSpan(ctx, "KitchenUseCase.SetTicketStatus")
+	defer span.End()
+
+	if status != domain.ProductionStatusPending && status != domain.ProductionStatusDone {
+		return nil, domain.NewInvalidProductionStatusError(status)
+	}
+
+	results, err := uc.setTicketsStatus(ctx, []string{ticketId}, status, userId)
+	if err != nil {
+		return nil, err
+	}
+	return &results[0], nil
+}
+
+// BumpBatch finaliza de uma vez as linhas de um lote produzido em conjunto. Ou todas as linhas
+// recebem baixa, ou nenhuma.
+func (uc *KitchenUseCase) BumpBatch(ctx context.Context, ticketIds []string, userId string) ([]domain.KitchenOrderProgress, error) {
+	ctx, span := startSpan(ctx, "KitchenUseCase.BumpBatch")
+	defer span.End()
+
+	return uc.setTicketsStatus(ctx, ticketIds, domain.ProductionStatusDone, userId)
+}
+
+func (uc *KitchenUseCase) setTicketsStatus(ctx context.Context, ticketIds []string, status string, userId string) ([]domain.KitchenOrderProgress, error) {
+	results, err := uc.repo.SetTicketsStatus(ctx, ticketIds, status, userId, orderStatusJobs)
+	if err != nil {
+		var notFoundErr *domain.KitchenTicketNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error updating kitchen ticket: %v", err)
+	}
+	return results, nil
+}
+
+// withDefaultWindow usa de hoje até o fim de amanhã quando o período não é informado.
+func (uc *KitchenUseCase) withDefaultWindow(filters domain.KitchenFilters) domain.KitchenFilters {
+	if filters.PickupDateStart.IsZero() {
+		local := uc.now().In(domain.StoreLocation)
+		filters.PickupDateStart = time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, domain.StoreLocation)
+	}
+	if filters.PickupDateEnd.IsZero() {
+		filters.PickupDateEnd = filters.PickupDateStart.AddDate(0, 0, 2).Add(-time.Nanosecond)
+	}
+	return filters
+}
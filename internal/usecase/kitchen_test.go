@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+// mockKitchenRepository aplica KitchenOrderStatus como o repositório faz na transação e guarda
+// os jobs gerados para o pedido.
+type mockKitchenRepository struct {
+	domain.KitchenRepository
+	progress  domain.KitchenOrderProgress
+	ticketIds []string
+	jobs      []domain.NewBackgroundJob
+}
+
+func (m *mockKitchenRepository) SetTicketsStatus(ctx context.Context, ticketIds []string, status string, userId string, orderJobs func(orderId string, status string) []domain.NewBackgroundJob) ([]domain.KitchenOrderProgress, error) {
+	m.ticketIds = ticketIds
+	results := []domain.KitchenOrderProgress{}
+	for _, ticketId := range ticketIds {
+		progress := m.progress
+		progress.TicketId = ticketId
+		if next := domain.KitchenOrderStatus(progress.OrderStatus, progress.PendingTickets); next != "" {
+			m.jobs = append(m.jobs, orderJobs(progress.OrderId, next)...)
+			progress.OrderStatus = next
+		}
+		results = append(results, progress)
+	}
+	return results, nil
+}
+
+// orderJobKinds resume os jobs como "tipo:evento ou template" para comparar nos testes.
+func orderJobKinds(jobs []domain.NewBackgroundJob) []string {
+	kinds := []string{}
+	for _, job := range jobs {
+		switch payload := job.Payload.(type) {
+		case domain.WebhookPublishJob:
+			kinds = append(kinds, payload.EventType)
+		case domain.OrderNotificationJob:
+			kinds = append(kinds, payload.Template)
+		}
+	}
+	return kinds
+}
+
+func TestKitchenUseCase_SetTicketStatus(t *testing.T) {
+	t.Run("should move the order to ready when the last line is done", func(t *testing.T) {
+		repo := &mockKitchenRepository{progress: domain.KitchenOrderProgress{OrderId: "order-1", OrderStatus: domain.OrderStatusPending}}
+		useCase := NewKitchenUseCase(repo)
+
+		progress, err := useCase.SetTicketStatus(context.Background(), "ticket-1", domain.ProductionStatusDone, "user-1")
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if kinds := orderJobKinds(repo.jobs); !slices.Equal(kinds, []string{domain.WebhookOrderStatusChanged, domain.NotificationOrderReady}) {
+			t.Errorf("expected the status change and ready notification jobs, but got %v", kinds)
+		}
+		if progress.OrderStatus != domain.OrderStatusReady {
+			t.Errorf("expected progress status ready, but got %s", progress.OrderStatus)
+		}
+	})
+
+	t.Run("should keep the order pending while lines remain", func(t *testing.T) {
+		repo := &mockKitchenRepository{progress: domain.KitchenOrderProgress{OrderId: "order-1", OrderStatus: domain.OrderStatusPending, PendingTickets: 1}}
+		useCase := NewKitchenUseCase(repo)
+
+		if _, err := useCase.SetTicketStatus(context.Background(), "ticket-1", domain.ProductionStatusDone, "user-1"); err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if len(repo.jobs) != 0 {
+			t.Errorf("expected no order update, but got %v", orderJobKinds(repo.jobs))
+		}
+	})
+
+	t.Run("should move a ready order back to pending when a line is reopened", func(t *testing.T) {
+		repo := &mockKitchenRepository{progress: domain.KitchenOrderProgress{OrderId: "order-1", OrderStatus: domain.OrderStatusReady, PendingTickets: 1}}
+		useCase := NewKitchenUseCase(repo)
+
+		progress, err := useCase.SetTicketStatus(context.Background(), "ticket-1", domain.ProductionStatusPending, "user-1")
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if progress.OrderStatus != domain.OrderStatusPending || !slices.Equal(orderJobKinds(repo.jobs), []string{domain.WebhookOrderStatusChanged}) {
+			t.Errorf("expected the order to go back to pending, but got %+v and %v", progress, orderJobKinds(repo.jobs))
+		}
+	})
+
+	t.Run("should reject unknown production status", func(t *testing.T) {
+		useCase := NewKitchenUseCase(&mockKitchenRepository{})
+
+		if _, err := useCase.SetTicketStatus(context.Background(), "ticket-1", "cooking", "user-1"); err == nil {
+			t.Error("expected an error, but got nil")
+		}
+	})
+}
+
+func TestKitchenUseCase_BumpBatch(t *testing.T) {
+	t.Run("should finish every ticket of the batch in one call", func(t *testing.T) {
+		repo := &mockKitchenRepository{progress: domain.KitchenOrderProgress{OrderId: "order-1", OrderStatus: domain.OrderStatusPending, PendingTickets: 1}}
+		useCase := NewKitchenUseCase(repo)
+
+		results, err := useCase.BumpBatch(context.Background(), []string{"ticket-1", "ticket-2"}, "user-1")
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if !slices.Equal(repo.ticketIds, []string{"ticket-1", "ticket-2"}) || len(results) != 2 {
+			t.Errorf("expected both tickets in a single repository call, but got %v", repo.ticketIds)
+		}
+	})
+}
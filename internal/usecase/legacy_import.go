@@ -0,0 +1,402 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/infra/logger"
+)
+
+const defaultLegacyImportBatchSize = 500
+
+// Categorias em que os produtos do legado são classificados na importação.
+const (
+	legacyCategoryDrinks = "Bebidas"
+	legacyCategorySalads = "Saladas"
+	legacyCategoryPastas = "Massas"
+	legacyCategoryOthers = "Diversos"
+)
+
+var legacyCategories = []domain.LegacyCategory{
+	{Name: legacyCategoryDrinks, Description: "Bebidas e liquidos"},
+	{Name: legacyCategorySalads, Description: "Maionese, salpicão, antepastos, etc."},
+	{Name: legacyCategoryPastas, Description: "Massas e diversos derivados da farinha de trigo"},
+	{Name: legacyCategoryOthers, Description: "Diversos produtos"},
+}
+
+var (
+	legacyDrinkPattern = regexp.MustCompile(`(?i)(coca|fanta|guarana|guaraná|tubaina|suco|água|agua|vinho)`)
+	legacySaladPattern = regexp.MustCompile(`(?i)(maionese|salpic[aã]o)`)
+)
+
+type LegacyImportOptions struct {
+	BatchSize int
+	DryRun    bool
+	// Restart descarta os checkpoints e o relatório de erros e processa o legado do início.
+	Restart bool
+}
+
+type LegacyImportStageResult struct {
+	Stage string `json:"stage"`
+	// ResumedFrom é a chave do legado a partir da qual a etapa continuou; zero quando começou
+	// do início.
+	ResumedFrom int `json:"resumedFrom"`
+	Imported    int `json:"imported"`
+	Skipped     int `json:"skipped"`
+	// AlreadyCompleted indica que a etapa terminou em uma execução anterior e não rodou agora.
+	AlreadyCompleted bool `json:"alreadyCompleted"`
+}
+
+type LegacyImportResult struct {
+	Stages       []LegacyImportStageResult  `json:"stages"`
+	Errors       []domain.LegacyImportError `json:"errors"`
+	OrderNumbers *domain.OrderNumberSeed    `json:"orderNumbers,omitempty"`
+	Applied      bool                       `json:"applied"`
+}
+
+// LegacyImportUseCase importa o MySQL do sistema antigo em etapas ordenadas. O progresso de
+// cada etapa é salvo a cada lote, então uma execução interrompida continua de onde parou e
+// repetir a importação atualiza os registros em vez de duplicá-los.
+type LegacyImportUseCase struct {
+	source          domain.LegacySource
+	repo            domain.LegacyImportRepository
+	maintenanceRepo domain.MaintenanceRepository
+}
+
+func NewLegacyImportUseCase(source domain.LegacySource, repo domain.LegacyImportRepository, maintenanceRepo domain.MaintenanceRepository) *LegacyImportUseCase {
+	return &LegacyImportUseCase{source: source, repo: repo, maintenanceRepo: maintenanceRepo}
+}
+
+func (uc *LegacyImportUseCase) Run(ctx context.Context, options LegacyImportOptions) (*LegacyImportResult, error) {
+	ctx, span := startSpan(ctx, "LegacyImportUseCase.Run")
+	defer span.End()
+
+	if options.BatchSize <= 0 {
+		options.BatchSize = defaultLegacyImportBatchSize
+	}
+
+	repo := uc.repo
+	if options.DryRun {
+		dryRunRepo, rollback, err := uc.repo.DryRun(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer rollback()
+		repo = dryRunRepo
+	}
+
+	if options.Restart {
+		if err := repo.Reset(ctx); err != nil {
+			return nil, fmt.Errorf("erro ao reiniciar importação: %v", err)
+		}
+	}
+
+	checkpoints, err := repo.Checkpoints(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar progresso da importação: %v", err)
+	}
+
+	result := &LegacyImportResult{Applied: !options.DryRun}
+	for _, stage := range domain.LegacyImportStages {
+		checkpoint := checkpoints[stage]
+		stageResult := LegacyImportStageResult{Stage: stage, AlreadyCompleted: checkpoint.CompletedAt != nil}
+
+		if !stageResult.AlreadyCompleted {
+			stageResult.ResumedFrom = checkpoint.LastOldId
+			logger.FromContext(ctx).Info(fmt.Sprintf("Importing legacy %s from key %d", stage, checkpoint.LastOldId))
+
+			runner := legacyImportStage{source: uc.source, repo: repo, batchSize: options.BatchSize, checkpoint: checkpoint}
+			if err := runner.run(ctx, stage); err != nil {
+				return withLegacyImportErrors(ctx, repo, result, fmt.Errorf("erro na etapa %s da importação: %v", stage, err))
+			}
+			if err := repo.CompleteStage(ctx, stage); err != nil {
+				return withLegacyImportErrors(ctx, repo, result, err)
+			}
+			checkpoint = runner.checkpoint
+		}
+
+		stageResult.Imported = checkpoint.Imported
+		stageResult.Skipped = checkpoint.Skipped
+		result.Stages = append(result.Stages, stageResult)
+	}
+
+	// A sequência não pode ser ajustada dentro da transação do dry-run, então só é feito de fato
+	if !options.DryRun {
+		seed, err := uc.maintenanceRepo.ReseedOrderNumbers(ctx, 0, false)
+		if err != nil {
+			return withLegacyImportErrors(ctx, repo, result, fmt.Errorf("erro ao ajustar numeração dos pedidos: %v", err))
+		}
+		result.OrderNumbers = seed
+	}
+
+	if result.Errors, err = repo.Errors(ctx); err != nil {
+		return nil, fmt.Errorf("erro ao buscar relatório de erros da importação: %v", err)
+	}
+	return result, nil
+}
+
+// withLegacyImportErrors devolve o resultado parcial de uma importação interrompida junto com
+// os erros gravados até ali, para que o relatório saia mesmo quando a execução falha.
+func withLegacyImportErrors(ctx context.Context, repo domain.LegacyImportRepository, result *LegacyImportResult, err error) (*LegacyImportResult, error) {
+	errs, errorsErr := repo.Errors(context.WithoutCancel(ctx))
+	if errorsErr != nil {
+		logger.FromContext(ctx).Warn(fmt.Sprintf("Unable to read the legacy import errors: %v", errorsErr))
+		return result, err
+	}
+	result.Errors = errs
+	return result, err
+}
+
+// legacyImportStage percorre uma etapa em lotes a partir do checkpoint.
+type legacyImportStage struct {
+	source     domain.LegacySource
+	repo       domain.LegacyImportRepository
+	batchSize  int
+	checkpoint domain.LegacyImportCheckpoint
+}
+
+func (s *legacyImportStage) run(ctx context.Context, stage string) error {
+	switch stage {
+	case domain.LegacyStageCategories:
+		checkpoint, err := s.repo.ImportCategories(ctx, domain.LegacyImportBatch[domain.LegacyCategory]{Stage: stage, Rows: legacyCategories})
+		if err != nil {
+			return err
+		}
+		s.checkpoint = *checkpoint
+		return nil
+	case domain.LegacyStageProducts:
+		return importLegacyStage(ctx, s, stage, s.source.Products, func(r domain.LegacyProductRecord) int { return r.OldId }, convertLegacyProduct, s.repo.ImportProducts)
+	case domain.LegacyStageCustomers:
+		return importLegacyStage(ctx, s, stage, s.source.Customers, func(r domain.LegacyCustomerRecord) int { return r.OldId }, convertLegacyCustomer, s.repo.ImportCustomers)
+	case domain.LegacyStageAddresses:
+		return importLegacyStage(ctx, s, stage, s.source.Addresses, func(r domain.LegacyAddressRecord) int { return r.OldId }, convertLegacyAddress, s.repo.ImportAddresses)
+	case domain.LegacyStageOrders:
+		return importLegacyStage(ctx, s, stage, s.source.Orders, func(r domain.LegacyOrderRecord) int { return r.OldId }, convertLegacyOrder, s.repo.ImportOrders)
+	}
+	return fmt.Errorf("etapa desconhecida %q", stage)
+}
+
+// importLegacyStage lê lotes do legado até acabar, converte cada linha e grava o lote. Linhas
+// recusadas na conversão seguem no lote como Skipped, para irem ao relatório de erros.
+func importLegacyStage[R any, T any](
+	ctx context.Context,
+	s *legacyImportStage,
+	stage string,
+	fetch func(ctx context.Context, afterId int, limit int) ([]R, error),
+	oldId func(R) int,
+	convert func(R) (T, error),
+	save func(ctx context.Context, batch domain.LegacyImportBatch[T]) (*domain.LegacyImportCheckpoint, error),
+) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		records, err := fetch(ctx, s.checkpoint.LastOldId, s.batchSize)
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return nil
+		}
+
+		batch := domain.LegacyImportBatch[T]{Stage: stage, LastOldId: oldId(records[len(records)-1])}
+		for _, record := range records {
+			row, err := convert(record)
+			if err != nil {
+				batch.Skipped = append(batch.Skipped, domain.LegacyImportError{Stage: stage, OldId: strconv.Itoa(oldId(record)), Reason: err.Error()})
+				continue
+			}
+			batch.Rows = append(batch.Rows, row)
+			batch.Fingerprints = append(batch.Fingerprints, legacyFingerprint(row))
+		}
+
+		checkpoint, err := save(ctx, batch)
+		if err != nil {
+			return err
+		}
+		s.checkpoint = *checkpoint
+		logger.FromContext(ctx).Info(fmt.Sprintf("Legacy %s: %d imported, %d skipped, last key %d", stage, checkpoint.Imported, checkpoint.Skipped, checkpoint.LastOldId))
+	}
+}
+
+func convertLegacyProduct(record domain.LegacyProductRecord) (domain.LegacyProduct, error) {
+	name := strings.TrimSpace(record.Name)
+	if name == "" {
+		return domain.LegacyProduct{}, fmt.Errorf("missing name")
+	}
+	unityType := strings.ToUpper(strings.TrimSpace(record.UnityType))
+	// unity_type é char(2) no modelo novo
+	if unityType == "" || len(unityType) > 2 {
+		return domain.LegacyProduct{}, fmt.Errorf("unknown unity type %q", record.UnityType)
+	}
+	if record.Value < 0 {
+		return domain.LegacyProduct{}, fmt.Errorf("negative price %.2f", record.Value)
+	}
+
+	return domain.LegacyProduct{
+		OldId:     record.OldId,
+		Name:      name,
+		Value:     int(math.Round(record.Value * 100)),
+		UnityType: unityType,
+		Category:  legacyProductCategory(name, unityType),
+	}, nil
+}
+
+// legacyProductCategory classifica o produto pelo nome e pela unidade, já que o legado não
+// tinha categorias. Bebidas são reconhecidas antes da unidade porque algumas eram vendidas
+// por unidade e outras por litro.
+func legacyProductCategory(name string, unityType string) string {
+	switch {
+	case unityType == "LT" || legacyDrinkPattern.MatchString(name):
+		return legacyCategoryDrinks
+	case legacySaladPattern.MatchString(name):
+		return legacyCategorySalads
+	case unityType == domain.UnityTypeKilogram:
+		return legacyCategoryPastas
+	default:
+		return legacyCategoryOthers
+	}
+}
+
+func convertLegacyCustomer(record domain.LegacyCustomerRecord) (domain.LegacyCustomer, error) {
+	name := strings.TrimSpace(record.Name)
+	if name == "" {
+		return domain.LegacyCustomer{}, fmt.Errorf("missing name")
+	}
+	phone := legacyPhone(&record.Phone)
+	if phone == nil {
+		return domain.LegacyCustomer{}, fmt.Errorf("missing phone")
+	}
+
+	return domain.LegacyCustomer{
+		OldId:     record.OldId,
+		Name:      name,
+		Phone:     *phone,
+		Phone2:    legacyPhone(record.Phone2),
+		Email:     legacyText(record.Email),
+		CreatedAt: record.CreatedAt,
+	}, nil
+}
+
+// legacyPhone normaliza para E.164 e mantém o valor original quando não for possível, como a
+// migration de normalização fez com os clientes já existentes.
+func legacyPhone(raw *string) *string {
+	phone := legacyText(raw)
+	if phone == nil {
+		return nil
+	}
+	if normalized, err := domain.NormalizePhone(*phone); err == nil {
+		return &normalized
+	}
+	return phone
+}
+
+func convertLegacyAddress(record domain.LegacyAddressRecord) (domain.LegacyAddress, error) {
+	if record.OldCustomerId == nil {
+		return domain.LegacyAddress{}, fmt.Errorf("address without customer")
+	}
+	if strings.TrimSpace(record.Street) == "" || strings.TrimSpace(record.Cep) == "" {
+		return domain.LegacyAddress{}, fmt.Errorf("missing street or CEP")
+	}
+
+	distance := 0
+	if raw := legacyText(record.Distance); raw != nil {
+		// Gravada como texto livre, ex.: "3.2km" ou "3,5 km"
+		cleaned := strings.NewReplacer("km", "", ",", ".").Replace(strings.ToLower(*raw))
+		value, err := strconv.ParseFloat(strings.TrimSpace(cleaned), 64)
+		if err != nil {
+			return domain.LegacyAddress{}, fmt.Errorf("invalid distance %q", *raw)
+		}
+		distance = int(math.Ceil(value))
+	}
+
+	return domain.LegacyAddress{
+		OldId:            record.OldId,
+		OldCustomerId:    *record.OldCustomerId,
+		Cep:              strings.TrimSpace(record.Cep),
+		Street:           strings.TrimSpace(record.Street),
+		Number:           strings.TrimSpace(record.Number),
+		Neighborhood:     strings.TrimSpace(record.Neighborhood),
+		City:             strings.TrimSpace(record.City),
+		State:            strings.TrimSpace(record.State),
+		AditionalDetails: strings.TrimSpace(record.AditionalDetails),
+		Distance:         distance,
+	}, nil
+}
+
+func convertLegacyOrder(record domain.LegacyOrderRecord) (domain.LegacyOrder, error) {
+	if record.PickupDate == nil {
+		return domain.LegacyOrder{}, fmt.Errorf("missing pickup date")
+	}
+	if len(record.Items) == 0 {
+		return domain.LegacyOrder{}, fmt.Errorf("order without items")
+	}
+
+	// O legado só guardava o dia da retirada; vale a meia-noite no fuso da loja
+	pickup := record.PickupDate.In(domain.StoreLocation)
+	pickupDate := time.Date(pickup.Year(), pickup.Month(), pickup.Day(), 0, 0, 0, 0, domain.StoreLocation).UTC()
+	createdAt := pickupDate
+	if record.CreatedAt != nil {
+		createdAt = record.CreatedAt.UTC()
+	}
+
+	status := domain.OrderStatusPending
+	if record.IsPickedUp {
+		status = domain.OrderStatusPickedUp
+	}
+
+	order := domain.LegacyOrder{
+		OldId:         record.OldId,
+		Number:        record.Number,
+		PickupDate:    pickupDate,
+		CreatedAt:     createdAt,
+		OldCustomerId: record.OldCustomerId,
+		OrderLocal:    legacyText(record.OrderLocal),
+		Observations:  legacyText(record.Observations),
+		Status:        status,
+		Items:         make([]domain.LegacyOrderItem, 0, len(record.Items)),
+	}
+	occurrences := map[string]int{}
+	for _, item := range record.Items {
+		quantity := domain.LegacyQuantity(item.UnityType, item.Quantity)
+		if quantity <= 0 {
+			return domain.LegacyOrder{}, fmt.Errorf("product %d has invalid quantity %v", item.OldProductId, item.Quantity)
+		}
+		// A taxa de entrega era lançada como um produto
+		if strings.Contains(strings.ToUpper(item.ProductName), "ENTREGA") {
+			order.IsDelivery = true
+		}
+		sauce := "-"
+		if item.OldSubProductId != nil {
+			sauce = strconv.Itoa(*item.OldSubProductId)
+		}
+		pair := fmt.Sprintf("%d:%s", item.OldProductId, sauce)
+		occurrences[pair]++
+		order.Items = append(order.Items, domain.LegacyOrderItem{
+			OldKey:          fmt.Sprintf("%s:%d", pair, occurrences[pair]),
+			OldProductId:    item.OldProductId,
+			OldSubProductId: item.OldSubProductId,
+			Quantity:        quantity,
+		})
+	}
+	return order, nil
+}
+
+// legacyText devolve nil para textos vazios, que o legado gravava no lugar de NULL.
+func legacyText(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
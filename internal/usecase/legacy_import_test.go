@@ -0,0 +1,259 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+type mockLegacySource struct {
+	products  []domain.LegacyProductRecord
+	customers []domain.LegacyCustomerRecord
+	addresses []domain.LegacyAddressRecord
+	orders    []domain.LegacyOrderRecord
+	afterIds  map[string][]int
+}
+
+func legacyPage[T any](records []T, oldId func(T) int, afterId int, limit int) []T {
+	page := []T{}
+	for _, record := range records {
+		if oldId(record) > afterId && len(page) < limit {
+			page = append(page, record)
+		}
+	}
+	return page
+}
+
+func (m *mockLegacySource) Products(ctx context.Context, afterId int, limit int) ([]domain.LegacyProductRecord, error) {
+	m.afterIds[domain.LegacyStageProducts] = append(m.afterIds[domain.LegacyStageProducts], afterId)
+	return legacyPage(m.products, func(r domain.LegacyProductRecord) int { return r.OldId }, afterId, limit), nil
+}
+
+func (m *mockLegacySource) Customers(ctx context.Context, afterId int, limit int) ([]domain.LegacyCustomerRecord, error) {
+	m.afterIds[domain.LegacyStageCustomers] = append(m.afterIds[domain.LegacyStageCustomers], afterId)
+	return legacyPage(m.customers, func(r domain.LegacyCustomerRecord) int { return r.OldId }, afterId, limit), nil
+}
+
+func (m *mockLegacySource) Addresses(ctx context.Context, afterId int, limit int) ([]domain.LegacyAddressRecord, error) {
+	return legacyPage(m.addresses, func(r domain.LegacyAddressRecord) int { return r.OldId }, afterId, limit), nil
+}
+
+func (m *mockLegacySource) Orders(ctx context.Context, afterId int, limit int) ([]domain.LegacyOrderRecord, error) {
+	return legacyPage(m.orders, func(r domain.LegacyOrderRecord) int { return r.OldId }, afterId, limit), nil
+}
+
+type mockLegacyImportRepository struct {
+	checkpoints map[string]domain.LegacyImportCheckpoint
+	errors      []domain.LegacyImportError
+	products    []domain.LegacyProduct
+	customers   []domain.LegacyCustomer
+	dryRun      bool
+	ordersErr   error
+}
+
+func (m *mockLegacyImportRepository) Checkpoints(ctx context.Context) (map[string]domain.LegacyImportCheckpoint, error) {
+	return m.checkpoints, nil
+}
+
+func (m *mockLegacyImportRepository) Reset(ctx context.Context) error {
+	m.checkpoints = map[string]domain.LegacyImportCheckpoint{}
+	m.errors = nil
+	return nil
+}
+
+func mockImportBatch[T any](m *mockLegacyImportRepository, batch domain.LegacyImportBatch[T]) *domain.LegacyImportCheckpoint {
+	checkpoint := m.checkpoints[batch.Stage]
+	checkpoint.Stage = batch.Stage
+	checkpoint.LastOldId = batch.LastOldId
+	checkpoint.Imported += len(batch.Rows)
+	checkpoint.Skipped += len(batch.Skipped)
+	m.checkpoints[batch.Stage] = checkpoint
+	m.errors = append(m.errors, batch.Skipped...)
+	return &checkpoint
+}
+
+func (m *mockLegacyImportRepository) ImportCategories(ctx context.Context, batch domain.LegacyImportBatch[domain.LegacyCategory]) (*domain.LegacyImportCheckpoint, error) {
+	return mockImportBatch(m, batch), nil
+}
+
+func (m *mockLegacyImportRepository) ImportProducts(ctx context.Context, batch domain.LegacyImportBatch[domain.LegacyProduct]) (*domain.LegacyImportCheckpoint, error) {
+	m.products = append(m.products, batch.Rows...)
+	return mockImportBatch(m, batch), nil
+}
+
+func (m *mockLegacyImportRepository) ImportCustomers(ctx context.Context, batch domain.LegacyImportBatch[domain.LegacyCustomer]) (*domain.LegacyImportCheckpoint, error) {
+	m.customers = append(m.customers, batch.Rows...)
+	return mockImportBatch(m, batch), nil
+}
+
+func (m *mockLegacyImportRepository) ImportAddresses(ctx context.Context, batch domain.LegacyImportBatch[domain.LegacyAddress]) (*domain.LegacyImportCheckpoint, error) {
+	return mockImportBatch(m, batch), nil
+}
+
+func (m *mockLegacyImportRepository) ImportOrders(ctx context.Context, batch domain.LegacyImportBatch[domain.LegacyOrder]) (*domain.LegacyImportCheckpoint, error) {
+	if m.ordersErr != nil {
+		return nil, m.ordersErr
+	}
+	return mockImportBatch(m, batch), nil
+}
+
+func (m *mockLegacyImportRepository) CompleteStage(ctx context.Context, stage string) error {
+	checkpoint := m.checkpoints[stage]
+	now := time.Now()
+	checkpoint.Stage = stage
+	checkpoint.CompletedAt = &now
+	m.checkpoints[stage] = checkpoint
+	return nil
+}
+
+func (m *mockLegacyImportRepository) Errors(ctx context.Context) ([]domain.LegacyImportError, error) {
+	return m.errors, nil
+}
+
+func (m *mockLegacyImportRepository) DryRun(ctx context.Context) (domain.LegacyImportRepository, func(), error) {
+	m.dryRun = true
+	return m, func() {}, nil
+}
+
+func TestLegacyImportUseCase_Run(t *testing.T) {
+	t.Run("should resume each stage from its checkpoint and report skipped rows", func(t *testing.T) {
+		completedAt := time.Now()
+		source := &mockLegacySource{
+			afterIds: map[string][]int{},
+			products: []domain.LegacyProductRecord{
+				{OldId: 1, Name: "Lasanha", UnityType: "UN", Value: 40},
+				{OldId: 2, Name: "Nhoque", UnityType: "KG", Value: 55.9},
+				{OldId: 3, Name: "Coca-Cola 2L", UnityType: "un", Value: 12.5},
+				{OldId: 4, Name: " ", UnityType: "UN", Value: 1},
+			},
+			customers: []domain.LegacyCustomerRecord{{OldId: 1, Name: "Maria", Phone: "(11) 99999-0000"}},
+		}
+		repo := &mockLegacyImportRepository{checkpoints: map[string]domain.LegacyImportCheckpoint{
+			domain.LegacyStageCategories: {Stage: domain.LegacyStageCategories, CompletedAt: &completedAt},
+			domain.LegacyStageProducts:   {Stage: domain.LegacyStageProducts, LastOldId: 2, Imported: 2},
+		}}
+		uc := NewLegacyImportUseCase(source, repo, &mockMaintenanceRepository{})
+
+		result, err := uc.Run(context.Background(), LegacyImportOptions{BatchSize: 1})
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+
+		if afterIds := source.afterIds[domain.LegacyStageProducts]; len(afterIds) != 3 || afterIds[0] != 2 || afterIds[1] != 3 {
+			t.Errorf("expected products to resume after key 2 in batches of one, but got %v", afterIds)
+		}
+		if len(repo.products) != 1 || repo.products[0].Category != legacyCategoryDrinks || repo.products[0].Value != 1250 || repo.products[0].UnityType != "UN" {
+			t.Errorf("expected only the drink to be imported, but got %+v", repo.products)
+		}
+		if len(result.Errors) != 1 || result.Errors[0].OldId != "4" || result.Errors[0].Stage != domain.LegacyStageProducts {
+			t.Errorf("expected product 4 in the error report, but got %+v", result.Errors)
+		}
+		if !result.Stages[0].AlreadyCompleted || result.Stages[1].ResumedFrom != 2 || result.Stages[1].Imported != 3 {
+			t.Errorf("expected categories to be skipped and products resumed, but got %+v", result.Stages)
+		}
+		if len(repo.customers) != 1 || repo.customers[0].Phone != "+5511999990000" {
+			t.Errorf("expected the customer phone to be normalized, but got %+v", repo.customers)
+		}
+		if result.OrderNumbers == nil || !result.Applied {
+			t.Errorf("expected order numbers to be reseeded after the import, but got %+v", result)
+		}
+	})
+
+	t.Run("should import inside the dry run repository without reseeding order numbers", func(t *testing.T) {
+		source := &mockLegacySource{afterIds: map[string][]int{}}
+		repo := &mockLegacyImportRepository{checkpoints: map[string]domain.LegacyImportCheckpoint{}}
+		uc := NewLegacyImportUseCase(source, repo, &mockMaintenanceRepository{})
+
+		result, err := uc.Run(context.Background(), LegacyImportOptions{DryRun: true})
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+
+		if !repo.dryRun || result.Applied || result.OrderNumbers != nil {
+			t.Errorf("expected a dry run without reseed, but got %+v", result)
+		}
+	})
+
+	t.Run("should return the skipped rows recorded before a failure", func(t *testing.T) {
+		pickup := time.Now()
+		source := &mockLegacySource{
+			afterIds: map[string][]int{},
+			products: []domain.LegacyProductRecord{{OldId: 4, Name: " ", UnityType: "UN", Value: 1}},
+			orders: []domain.LegacyOrderRecord{{OldId: 1, Number: 1, PickupDate: &pickup, Items: []domain.LegacyOrderItemRecord{
+				{OldProductId: 1, UnityType: "UN", Quantity: 1},
+			}}},
+		}
+		repo := &mockLegacyImportRepository{checkpoints: map[string]domain.LegacyImportCheckpoint{}, ordersErr: errors.New("connection reset")}
+		uc := NewLegacyImportUseCase(source, repo, &mockMaintenanceRepository{})
+
+		result, err := uc.Run(context.Background(), LegacyImportOptions{})
+		if err == nil {
+			t.Fatalf("expected the orders stage to fail")
+		}
+
+		if result == nil || len(result.Errors) != 1 || result.Errors[0].OldId != "4" {
+			t.Fatalf("expected the partial result with product 4 in the error report, but got %+v", result)
+		}
+		if result.OrderNumbers != nil {
+			t.Errorf("expected order numbers not to be reseeded after a failure")
+		}
+	})
+}
+
+func TestConvertLegacyOrder(t *testing.T) {
+	t.Run("should convert quantities and detect deliveries", func(t *testing.T) {
+		pickup := time.Date(2024, 5, 10, 15, 0, 0, 0, domain.StoreLocation)
+		sauce := 7
+		record := domain.LegacyOrderRecord{
+			OldId:      10,
+			Number:     321,
+			PickupDate: &pickup,
+			IsPickedUp: true,
+			Items: []domain.LegacyOrderItemRecord{
+				{OldProductId: 1, ProductName: "Nhoque", UnityType: "KG", Quantity: 1.5, OldSubProductId: &sauce},
+				{OldProductId: 1, ProductName: "Nhoque", UnityType: "KG", Quantity: 0.5, OldSubProductId: &sauce},
+				{OldProductId: 2, ProductName: "TAXA DE ENTREGA", UnityType: "UN", Quantity: 1},
+			},
+		}
+
+		order, err := convertLegacyOrder(record)
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+
+		expectedPickup := time.Date(2024, 5, 10, 0, 0, 0, 0, domain.StoreLocation)
+		if !order.PickupDate.Equal(expectedPickup) || !order.CreatedAt.Equal(expectedPickup) {
+			t.Errorf("expected pickup and creation at %v, but got %v and %v", expectedPickup, order.PickupDate, order.CreatedAt)
+		}
+		if order.Items[0].OldKey != "1:7:1" || order.Items[1].OldKey != "1:7:2" || order.Items[2].OldKey != "2:-:1" {
+			t.Errorf("expected items keyed by product, sauce and occurrence, but got %+v", order.Items)
+		}
+		if order.Items[0].Quantity != 1500 || !order.IsDelivery || order.Status != domain.OrderStatusPickedUp {
+			t.Errorf("expected 1500g, a delivery and picked up status, but got %+v", order)
+		}
+	})
+
+	t.Run("should reject orders without items", func(t *testing.T) {
+		pickup := time.Now()
+		if _, err := convertLegacyOrder(domain.LegacyOrderRecord{OldId: 1, PickupDate: &pickup}); err == nil {
+			t.Errorf("expected an error for an order without items")
+		}
+	})
+}
+
+func TestConvertLegacyAddress(t *testing.T) {
+	customerId := 5
+	distance := "perto"
+	if _, err := convertLegacyAddress(domain.LegacyAddressRecord{OldId: 1, OldCustomerId: &customerId, Cep: "01001000", Street: "Rua A", Distance: &distance}); err == nil {
+		t.Errorf("expected an error for a distance that is not a number")
+	}
+
+	distance = "3,2 km"
+	address, err := convertLegacyAddress(domain.LegacyAddressRecord{OldId: 1, OldCustomerId: &customerId, Cep: "01001000", Street: "Rua A", Distance: &distance})
+	if err != nil || address.Distance != 4 {
+		t.Errorf("expected distance rounded up to 4, but got %d (%v)", address.Distance, err)
+	}
+}
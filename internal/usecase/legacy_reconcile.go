@@ -0,0 +1,345 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/infra/logger"
+)
+
+// Campos comparados na reconciliação, usados em LegacyReconcileDiff.Field.
+const (
+	LegacyDiffMissing   = "missing"
+	LegacyDiffCopies    = "copies"
+	LegacyDiffValue     = "value"
+	LegacyDiffUnityType = "unity_type"
+	LegacyDiffPhone     = "phone"
+	LegacyDiffPhone2    = "phone2"
+	LegacyDiffCustomer  = "customer"
+	LegacyDiffNumber    = "number"
+	LegacyDiffStatus    = "status"
+	LegacyDiffDeleted   = "deleted"
+	LegacyDiffItems     = "items"
+	LegacyDiffTotal     = "total"
+)
+
+// LegacyReconcileDiff é uma divergência entre uma linha do legado e o Postgres. Legacy e
+// Current trazem o valor esperado, já convertido como a importação faria, e o valor atual.
+type LegacyReconcileDiff struct {
+	Stage   string `json:"stage"`
+	OldId   int    `json:"oldId"`
+	Field   string `json:"field"`
+	Legacy  string `json:"legacy"`
+	Current string `json:"current"`
+	// Note traz o motivo registrado pela importação quando a linha foi ignorada.
+	Note string `json:"note,omitempty"`
+}
+
+type LegacyReconcileCount struct {
+	Stage    string `json:"stage"`
+	Legacy   int    `json:"legacy"`
+	Postgres int    `json:"postgres"`
+	Missing  int    `json:"missing"`
+	// Extra são old_ids do Postgres que não existem mais no legado.
+	Extra      int `json:"extra"`
+	Mismatched int `json:"mismatched"`
+}
+
+type LegacyReconcileResult struct {
+	Counts []LegacyReconcileCount `json:"counts"`
+	// Somas dos pedidos em centavos: a do legado inclui todos os pedidos, com as conversões de
+	// quantidade e preço da importação, e a do Postgres só os pedidos importados.
+	LegacyOrdersTotal   int                   `json:"legacyOrdersTotal"`
+	PostgresOrdersTotal int                   `json:"postgresOrdersTotal"`
+	Diffs               []LegacyReconcileDiff `json:"diffs"`
+}
+
+// LegacyReconcileUseCase compara o MySQL do sistema antigo com o que foi importado no
+// Postgres, para provar que nada se perdeu antes de desligar o legado. Só faz leituras.
+type LegacyReconcileUseCase struct {
+	source     domain.LegacySource
+	repo       domain.LegacyReconcileRepository
+	importRepo domain.LegacyImportRepository
+}
+
+func NewLegacyReconcileUseCase(source domain.LegacySource, repo domain.LegacyReconcileRepository, importRepo domain.LegacyImportRepository) *LegacyReconcileUseCase {
+	return &LegacyReconcileUseCase{source: source, repo: repo, importRepo: importRepo}
+}
+
+func (uc *LegacyReconcileUseCase) Run(ctx context.Context, batchSize int) (*LegacyReconcileResult, error) {
+	ctx, span := startSpan(ctx, "LegacyReconcileUseCase.Run")
+	defer span.End()
+
+	if batchSize <= 0 {
+		batchSize = defaultLegacyImportBatchSize
+	}
+
+	counts, err := uc.repo.Counts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao contar registros importados: %v", err)
+	}
+	importErrors, err := uc.importRepo.Errors(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar relatório de erros da importação: %v", err)
+	}
+
+	r := &legacyReconciliation{
+		batchSize: batchSize,
+		counts:    counts,
+		notes:     map[string]string{},
+		result:    &LegacyReconcileResult{Counts: []LegacyReconcileCount{}, Diffs: []LegacyReconcileDiff{}},
+	}
+	for _, importError := range importErrors {
+		r.notes[importError.Stage+":"+importError.OldId] = importError.Reason
+	}
+
+	// A soma do legado inclui os pedidos que não chegaram ao Postgres, por isso é feita na leitura
+	fetchOrders := func(ctx context.Context, afterId int, limit int) ([]domain.LegacyOrderRecord, error) {
+		orders, err := uc.source.Orders(ctx, afterId, limit)
+		for _, order := range orders {
+			_, total := legacyOrderItems(order)
+			r.result.LegacyOrdersTotal += total
+		}
+		return orders, err
+	}
+
+	if err := reconcileLegacyStage(ctx, r, domain.LegacyStageProducts, uc.source.Products, func(p domain.LegacyProductRecord) int { return p.OldId }, uc.repo.Products, compareLegacyProduct); err != nil {
+		return nil, fmt.Errorf("erro ao reconciliar produtos: %v", err)
+	}
+	if err := reconcileLegacyStage(ctx, r, domain.LegacyStageCustomers, uc.source.Customers, func(c domain.LegacyCustomerRecord) int { return c.OldId }, uc.repo.Customers, compareLegacyCustomer); err != nil {
+		return nil, fmt.Errorf("erro ao reconciliar clientes: %v", err)
+	}
+	if err := reconcileLegacyStage(ctx, r, domain.LegacyStageAddresses, uc.source.Addresses, func(a domain.LegacyAddressRecord) int { return a.OldId }, uc.repo.Addresses, compareLegacyAddress); err != nil {
+		return nil, fmt.Errorf("erro ao reconciliar endereços: %v", err)
+	}
+	if err := reconcileLegacyStage(ctx, r, domain.LegacyStageOrders, fetchOrders, func(o domain.LegacyOrderRecord) int { return o.OldId }, uc.repo.Orders, r.compareOrder); err != nil {
+		return nil, fmt.Errorf("erro ao reconciliar pedidos: %v", err)
+	}
+	return r.result, nil
+}
+
+// legacyReconciliation acumula o resultado enquanto as etapas percorrem o legado.
+type legacyReconciliation struct {
+	batchSize int
+	counts    map[string]int
+	// notes indexa os motivos da importação por "etapa:old_id".
+	notes  map[string]string
+	result *LegacyReconcileResult
+}
+
+// reconcileLegacyStage lê o legado em lotes, busca as mesmas chaves no Postgres e compara linha
+// a linha.
+func reconcileLegacyStage[R any, S any](
+	ctx context.Context,
+	r *legacyReconciliation,
+	stage string,
+	fetch func(ctx context.Context, afterId int, limit int) ([]R, error),
+	oldId func(R) int,
+	lookup func(ctx context.Context, oldIds []int) (map[int]S, error),
+	compare func(R, S) []LegacyReconcileDiff,
+) error {
+	count := LegacyReconcileCount{Stage: stage, Postgres: r.counts[stage]}
+	matched := 0
+	afterId := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		records, err := fetch(ctx, afterId, r.batchSize)
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			break
+		}
+
+		oldIds := make([]int, len(records))
+		for i, record := range records {
+			oldIds[i] = oldId(record)
+		}
+		current, err := lookup(ctx, oldIds)
+		if err != nil {
+			return err
+		}
+
+		for _, record := range records {
+			id := oldId(record)
+			count.Legacy++
+			row, ok := current[id]
+			if !ok {
+				count.Missing++
+				r.add(stage, id, []LegacyReconcileDiff{{Field: LegacyDiffMissing}})
+				continue
+			}
+			matched++
+			if diffs := compare(record, row); len(diffs) > 0 {
+				count.Mismatched++
+				r.add(stage, id, diffs)
+			}
+		}
+		afterId = oldIds[len(oldIds)-1]
+	}
+
+	count.Extra = count.Postgres - matched
+	r.result.Counts = append(r.result.Counts, count)
+	logger.FromContext(ctx).Info(fmt.Sprintf("Legacy %s reconciled: %d missing, %d mismatched", stage, count.Missing, count.Mismatched))
+	return nil
+}
+
+func (r *legacyReconciliation) add(stage string, oldId int, diffs []LegacyReconcileDiff) {
+	for _, diff := range diffs {
+		diff.Stage = stage
+		diff.OldId = oldId
+		diff.Note = r.notes[stage+":"+strconv.Itoa(oldId)]
+		r.result.Diffs = append(r.result.Diffs, diff)
+	}
+}
+
+func legacyCopiesDiff(copies int) []LegacyReconcileDiff {
+	if copies > 1 {
+		return []LegacyReconcileDiff{{Field: LegacyDiffCopies, Legacy: "1", Current: strconv.Itoa(copies)}}
+	}
+	return nil
+}
+
+func compareLegacyProduct(record domain.LegacyProductRecord, product domain.LegacyReconcileProduct) []LegacyReconcileDiff {
+	diffs := legacyCopiesDiff(product.Copies)
+	if value := int(math.Round(record.Value * 100)); value != product.Value {
+		diffs = append(diffs, LegacyReconcileDiff{Field: LegacyDiffValue, Legacy: strconv.Itoa(value), Current: strconv.Itoa(product.Value)})
+	}
+	if unityType := strings.ToUpper(strings.TrimSpace(record.UnityType)); unityType != product.UnityType {
+		diffs = append(diffs, LegacyReconcileDiff{Field: LegacyDiffUnityType, Legacy: unityType, Current: product.UnityType})
+	}
+	return diffs
+}
+
+func compareLegacyCustomer(record domain.LegacyCustomerRecord, customer domain.LegacyReconcileCustomer) []LegacyReconcileDiff {
+	diffs := legacyCopiesDiff(customer.Copies)
+	if phone := optionalText(legacyPhone(&record.Phone)); phone != customer.Phone {
+		diffs = append(diffs, LegacyReconcileDiff{Field: LegacyDiffPhone, Legacy: phone, Current: customer.Phone})
+	}
+	if phone2, current := optionalText(legacyPhone(record.Phone2)), optionalText(customer.Phone2); phone2 != current {
+		diffs = append(diffs, LegacyReconcileDiff{Field: LegacyDiffPhone2, Legacy: phone2, Current: current})
+	}
+	return diffs
+}
+
+func compareLegacyAddress(record domain.LegacyAddressRecord, address domain.LegacyReconcileAddress) []LegacyReconcileDiff {
+	diffs := legacyCopiesDiff(address.Copies)
+	if record.OldCustomerId != nil && !slices.Contains(address.CustomerOldIds, *record.OldCustomerId) {
+		diffs = append(diffs, LegacyReconcileDiff{Field: LegacyDiffCustomer, Legacy: strconv.Itoa(*record.OldCustomerId), Current: joinOldIds(address.CustomerOldIds)})
+	}
+	return diffs
+}
+
+// compareOrder também soma os totais do resultado, por isso é um método.
+func (r *legacyReconciliation) compareOrder(record domain.LegacyOrderRecord, order domain.LegacyReconcileOrder) []LegacyReconcileDiff {
+	diffs := legacyCopiesDiff(order.Copies)
+	if record.Number != order.Number {
+		diffs = append(diffs, LegacyReconcileDiff{Field: LegacyDiffNumber, Legacy: strconv.Itoa(record.Number), Current: strconv.Itoa(order.Number)})
+	}
+	if !slices.Contains(order.CustomerOldIds, record.OldCustomerId) {
+		diffs = append(diffs, LegacyReconcileDiff{Field: LegacyDiffCustomer, Legacy: strconv.Itoa(record.OldCustomerId), Current: joinOldIds(order.CustomerOldIds)})
+	}
+	status := domain.OrderStatusPending
+	if record.IsPickedUp {
+		status = domain.OrderStatusPickedUp
+	}
+	if status != order.Status {
+		diffs = append(diffs, LegacyReconcileDiff{Field: LegacyDiffStatus, Legacy: status, Current: order.Status})
+	}
+	if order.IsDeleted {
+		diffs = append(diffs, LegacyReconcileDiff{Field: LegacyDiffDeleted, Legacy: "false", Current: "true"})
+	}
+
+	legacyItems, legacyTotal := legacyOrderItems(record)
+	currentItems, currentTotal := currentOrderItems(order)
+	if onlyLegacy, onlyCurrent := itemsDifference(legacyItems, currentItems), itemsDifference(currentItems, legacyItems); len(onlyLegacy) > 0 || len(onlyCurrent) > 0 {
+		diffs = append(diffs, LegacyReconcileDiff{Field: LegacyDiffItems, Legacy: strings.Join(onlyLegacy, "; "), Current: strings.Join(onlyCurrent, "; ")})
+	}
+	if legacyTotal != currentTotal {
+		diffs = append(diffs, LegacyReconcileDiff{Field: LegacyDiffTotal, Legacy: strconv.Itoa(legacyTotal), Current: strconv.Itoa(currentTotal)})
+	}
+
+	r.result.PostgresOrdersTotal += currentTotal
+	return diffs
+}
+
+// legacyOrderItems converte os itens do legado com as regras do script de carga de pedidos e
+// devolve as chaves de legacyItemKey com a quantidade de cada uma e o total do pedido em
+// centavos. A quantidade usa domain.LegacyQuantity, que reproduz o script e é a mesma da
+// importação; o preço segue o script, que gravava o preço do produto e não o da venda.
+func legacyOrderItems(record domain.LegacyOrderRecord) (map[string]int, int) {
+	items := map[string]int{}
+	total := 0
+	for _, item := range record.Items {
+		product := domain.OrderProduct{
+			Quantity:  domain.LegacyQuantity(item.UnityType, item.Quantity),
+			UnityType: strings.ToUpper(strings.TrimSpace(item.UnityType)),
+			// O script gravava o preço do produto, que veio do vl_produto em centavos
+			Price: int(math.Round(item.Value * 100)),
+		}
+		subProducts := []int{}
+		if item.OldSubProductId != nil {
+			subProducts = append(subProducts, *item.OldSubProductId)
+		}
+		items[legacyItemKey(item.OldProductId, subProducts, product.Quantity, product.UnityType)]++
+		total += product.Total()
+	}
+	return items, total
+}
+
+func currentOrderItems(order domain.LegacyReconcileOrder) (map[string]int, int) {
+	items := map[string]int{}
+	total := 0
+	for _, item := range order.Items {
+		product := domain.OrderProduct{Quantity: item.Quantity, UnityType: item.UnityType, Price: item.Price}
+		items[legacyItemKey(item.OldProductId, item.OldSubProductIds, item.Quantity, item.UnityType)]++
+		total += product.Total()
+	}
+	return items, total
+}
+
+// legacyItemKey descreve o item para comparar os itens dos pedidos como multiconjuntos, já
+// que nem o legado nem o modelo novo têm uma chave por item.
+func legacyItemKey(oldProductId int, oldSubProductIds []int, quantity int, unityType string) string {
+	key := "product " + strconv.Itoa(oldProductId)
+	if len(oldSubProductIds) > 0 {
+		key += " with " + joinOldIds(oldSubProductIds)
+	}
+	return fmt.Sprintf("%s x %d %s", key, quantity, unityType)
+}
+
+// itemsDifference lista, ordenados, os itens de a que faltam em b, repetidos quantas vezes faltarem.
+func itemsDifference(a map[string]int, b map[string]int) []string {
+	difference := []string{}
+	for key, count := range a {
+		for range count - b[key] {
+			difference = append(difference, key)
+		}
+	}
+	sort.Strings(difference)
+	return difference
+}
+
+func joinOldIds(oldIds []int) string {
+	ids := make([]string, len(oldIds))
+	for i, id := range oldIds {
+		ids[i] = strconv.Itoa(id)
+	}
+	return strings.Join(ids, ",")
+}
+
+func optionalText(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
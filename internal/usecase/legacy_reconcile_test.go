@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+type mockLegacyReconcileRepository struct {
+	counts    map[string]int
+	products  map[int]domain.LegacyReconcileProduct
+	customers map[int]domain.LegacyReconcileCustomer
+	addresses map[int]domain.LegacyReconcileAddress
+	orders    map[int]domain.LegacyReconcileOrder
+}
+
+func mockReconcileLookup[T any](rows map[int]T, oldIds []int) map[int]T {
+	found := map[int]T{}
+	for _, oldId := range oldIds {
+		if row, ok := rows[oldId]; ok {
+			found[oldId] = row
+		}
+	}
+	return found
+}
+
+func (m *mockLegacyReconcileRepository) Counts(ctx context.Context) (map[string]int, error) {
+	return m.counts, nil
+}
+
+func (m *mockLegacyReconcileRepository) Products(ctx context.Context, oldIds []int) (map[int]domain.LegacyReconcileProduct, error) {
+	return mockReconcileLookup(m.products, oldIds), nil
+}
+
+func (m *mockLegacyReconcileRepository) Customers(ctx context.Context, oldIds []int) (map[int]domain.LegacyReconcileCustomer, error) {
+	return mockReconcileLookup(m.customers, oldIds), nil
+}
+
+func (m *mockLegacyReconcileRepository) Addresses(ctx context.Context, oldIds []int) (map[int]domain.LegacyReconcileAddress, error) {
+	return mockReconcileLookup(m.addresses, oldIds), nil
+}
+
+func (m *mockLegacyReconcileRepository) Orders(ctx context.Context, oldIds []int) (map[int]domain.LegacyReconcileOrder, error) {
+	return mockReconcileLookup(m.orders, oldIds), nil
+}
+
+func findLegacyDiff(diffs []LegacyReconcileDiff, stage string, oldId int, field string) *LegacyReconcileDiff {
+	for _, diff := range diffs {
+		if diff.Stage == stage && diff.OldId == oldId && diff.Field == field {
+			return &diff
+		}
+	}
+	return nil
+}
+
+func TestLegacyReconcileUseCase_Run(t *testing.T) {
+	pickup := time.Date(2024, 5, 10, 0, 0, 0, 0, domain.StoreLocation)
+	phone2 := "11 3333-4444"
+	customerId := 1
+	sauce := 9
+	source := &mockLegacySource{
+		afterIds: map[string][]int{},
+		products: []domain.LegacyProductRecord{
+			{OldId: 1, Name: "Nhoque", UnityType: "KG", Value: 60},
+			{OldId: 2, Name: "Lasanha", UnityType: "UN", Value: 45.5},
+		},
+		customers: []domain.LegacyCustomerRecord{
+			{OldId: 1, Name: "Maria", Phone: "(11) 99999-0000", Phone2: &phone2},
+			{OldId: 2, Name: "Maria S.", Phone: "11 98888-7777"},
+			{OldId: 3, Name: " ", Phone: "11 97777-6666"},
+		},
+		addresses: []domain.LegacyAddressRecord{{OldId: 1, OldCustomerId: &customerId, Cep: "01001000", Street: "Rua A"}},
+		orders: []domain.LegacyOrderRecord{
+			{OldId: 1, Number: 10, PickupDate: &pickup, OldCustomerId: 2, IsPickedUp: true, Items: []domain.LegacyOrderItemRecord{
+				{OldProductId: 1, UnityType: "KG", Quantity: 1.5, Value: 60, OldSubProductId: &sauce},
+				{OldProductId: 2, UnityType: "UN", Quantity: 2, Value: 45.5},
+			}},
+			{OldId: 2, Number: 11, PickupDate: &pickup, OldCustomerId: 1, Items: []domain.LegacyOrderItemRecord{
+				{OldProductId: 1, UnityType: "KG", Quantity: 0.5, Value: 60},
+			}},
+		},
+	}
+	repo := &mockLegacyReconcileRepository{
+		counts: map[string]int{domain.LegacyStageProducts: 3, domain.LegacyStageCustomers: 2, domain.LegacyStageAddresses: 1, domain.LegacyStageOrders: 2},
+		products: map[int]domain.LegacyReconcileProduct{
+			1: {OldId: 1, Value: 6000, UnityType: "KG", Copies: 1},
+			2: {OldId: 2, Value: 4550, UnityType: "UN", Copies: 1},
+		},
+		customers: map[int]domain.LegacyReconcileCustomer{
+			1: {OldId: 1, Phone: "+5511999990000", Copies: 1},
+			2: {OldId: 2, Phone: "+5511988887777", Copies: 1},
+		},
+		// O cliente 1 foi mesclado no 2, que ficou com o endereço
+		addresses: map[int]domain.LegacyReconcileAddress{1: {OldId: 1, CustomerOldIds: []int{1, 2}, Copies: 1}},
+		orders: map[int]domain.LegacyReconcileOrder{
+			1: {OldId: 1, Number: 10, CustomerOldIds: []int{1, 2}, Status: domain.OrderStatusPickedUp, Copies: 1, Items: []domain.LegacyReconcileOrderItem{
+				{OldProductId: 2, Quantity: 2, UnityType: "UN", Price: 4550},
+				{OldProductId: 1, OldSubProductIds: []int{9}, Quantity: 1500, UnityType: "KG", Price: 6000},
+			}},
+			// Quantidade gravada sem a conversão de quilos para gramas
+			2: {OldId: 2, Number: 11, CustomerOldIds: []int{1, 2}, Status: domain.OrderStatusPending, Copies: 2, Items: []domain.LegacyReconcileOrderItem{
+				{OldProductId: 1, Quantity: 1, UnityType: "KG", Price: 6000},
+			}},
+		},
+	}
+	importRepo := &mockLegacyImportRepository{errors: []domain.LegacyImportError{{Stage: domain.LegacyStageCustomers, OldId: "3", Reason: "missing name"}}}
+	uc := NewLegacyReconcileUseCase(source, repo, importRepo)
+
+	result, err := uc.Run(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+
+	t.Run("should match orders whose quantities were converted from kilos to grams", func(t *testing.T) {
+		for _, diff := range result.Diffs {
+			if diff.Stage == domain.LegacyStageOrders && diff.OldId == 1 {
+				t.Errorf("expected order 1 to match, but got %+v", diff)
+			}
+		}
+	})
+
+	t.Run("should report item, total and duplicate differences", func(t *testing.T) {
+		items := findLegacyDiff(result.Diffs, domain.LegacyStageOrders, 2, LegacyDiffItems)
+		if items == nil || items.Legacy != "product 1 x 500 KG" || items.Current != "product 1 x 1 KG" {
+			t.Errorf("expected the unconverted quantity to be reported, but got %+v", items)
+		}
+		total := findLegacyDiff(result.Diffs, domain.LegacyStageOrders, 2, LegacyDiffTotal)
+		if total == nil || total.Legacy != "3000" || total.Current != "6" {
+			t.Errorf("expected totals 3000 and 6, but got %+v", total)
+		}
+		if findLegacyDiff(result.Diffs, domain.LegacyStageOrders, 2, LegacyDiffCopies) == nil {
+			t.Errorf("expected the duplicated order to be reported")
+		}
+		if result.LegacyOrdersTotal != 9000+9100+3000 || result.PostgresOrdersTotal != 9000+9100+6 {
+			t.Errorf("expected order totals 21100 and 18106, but got %d and %d", result.LegacyOrdersTotal, result.PostgresOrdersTotal)
+		}
+	})
+
+	t.Run("should compare normalized phones and annotate rows skipped by the import", func(t *testing.T) {
+		phone := findLegacyDiff(result.Diffs, domain.LegacyStageCustomers, 1, LegacyDiffPhone2)
+		if phone == nil || phone.Legacy != "+551133334444" || phone.Current != "" {
+			t.Errorf("expected the missing second phone to be reported, but got %+v", phone)
+		}
+		missing := findLegacyDiff(result.Diffs, domain.LegacyStageCustomers, 3, LegacyDiffMissing)
+		if missing == nil || missing.Note != "missing name" {
+			t.Errorf("expected customer 3 to be missing with the import reason, but got %+v", missing)
+		}
+		if findLegacyDiff(result.Diffs, domain.LegacyStageAddresses, 1, LegacyDiffCustomer) != nil {
+			t.Errorf("expected the address linked to the merged customer to match")
+		}
+	})
+
+	t.Run("should count missing, extra and mismatched rows per stage", func(t *testing.T) {
+		expected := map[string]LegacyReconcileCount{
+			domain.LegacyStageProducts:  {Stage: domain.LegacyStageProducts, Legacy: 2, Postgres: 3, Extra: 1},
+			domain.LegacyStageCustomers: {Stage: domain.LegacyStageCustomers, Legacy: 3, Postgres: 2, Missing: 1, Mismatched: 1},
+			domain.LegacyStageOrders:    {Stage: domain.LegacyStageOrders, Legacy: 2, Postgres: 2, Mismatched: 1},
+		}
+		for _, count := range result.Counts {
+			if want, ok := expected[count.Stage]; ok && count != want {
+				t.Errorf("expected %+v, but got %+v", want, count)
+			}
+		}
+	})
+}
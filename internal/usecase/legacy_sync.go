@@ -0,0 +1,409 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/infra/logger"
+)
+
+// defaultLegacySyncLookback é quanto tempo depois da retirada um pedido do legado continua
+// sendo relido, já que o sistema antigo não registra quando um pedido foi alterado.
+const defaultLegacySyncLookback = 72 * time.Hour
+
+type LegacySyncOptions struct {
+	BatchSize int
+	Lookback  time.Duration
+	DryRun    bool
+}
+
+type LegacySyncStageResult struct {
+	Stage string `json:"stage"`
+	// Checked conta as linhas lidas do legado; só as novas ou alteradas são gravadas.
+	Checked   int `json:"checked"`
+	Synced    int `json:"synced"`
+	Failed    int `json:"failed"`
+	Conflicts int `json:"conflicts"`
+}
+
+type LegacySyncResult struct {
+	Stages    []LegacySyncStageResult     `json:"stages"`
+	Errors    []domain.LegacyImportError  `json:"errors"`
+	Conflicts []domain.LegacySyncConflict `json:"conflicts"`
+	Applied   bool                        `json:"applied"`
+}
+
+// LegacySyncUseCase traz para o Postgres o que ainda é digitado no sistema antigo durante a
+// transição. Cada execução grava as linhas criadas depois da última chave processada e relê os
+// pedidos com retirada recente e seus clientes, gravando só os que mudaram desde a última vez.
+type LegacySyncUseCase struct {
+	source     domain.LegacySyncSource
+	repo       domain.LegacySyncRepository
+	importRepo domain.LegacyImportRepository
+	now        func() time.Time
+}
+
+func NewLegacySyncUseCase(source domain.LegacySyncSource, repo domain.LegacySyncRepository, importRepo domain.LegacyImportRepository) *LegacySyncUseCase {
+	return &LegacySyncUseCase{source: source, repo: repo, importRepo: importRepo, now: time.Now}
+}
+
+func (uc *LegacySyncUseCase) Sync(ctx context.Context, options LegacySyncOptions) (*LegacySyncResult, error) {
+	ctx, span := startSpan(ctx, "LegacySyncUseCase.Sync")
+	defer span.End()
+
+	if options.BatchSize <= 0 {
+		options.BatchSize = defaultLegacyImportBatchSize
+	}
+	if options.Lookback <= 0 {
+		options.Lookback = defaultLegacySyncLookback
+	}
+
+	// A sincronização parte dos checkpoints e das versões gravadas pela importação
+	checkpoints, err := uc.importRepo.Checkpoints(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar progresso da importação: %v", err)
+	}
+	if checkpoints[domain.LegacyStageOrders].CompletedAt == nil {
+		return nil, fmt.Errorf("a importação do legado ainda não terminou; execute legacy import antes de sincronizar")
+	}
+
+	repo := uc.repo
+	if options.DryRun {
+		dryRunRepo, rollback, err := uc.repo.DryRun(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer rollback()
+		repo = dryRunRepo
+	}
+
+	watermarks, err := repo.Watermarks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar progresso da sincronização: %v", err)
+	}
+	// A marca d'água passa pelas linhas que falharam, então elas são relidas pela chave
+	failed, err := repo.FailedOldIds(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar linhas com falha na sincronização: %v", err)
+	}
+
+	// Os pedidos são lidos antes de tudo porque definem quais clientes precisam ser relidos
+	pickupSince := pickupDay(uc.now().Add(-options.Lookback))
+	recentOrders := func(ctx context.Context, afterId int, limit int) ([]domain.LegacyOrderRecord, error) {
+		return uc.source.RecentOrders(ctx, pickupSince, afterId, limit)
+	}
+	orderOldId := func(o domain.LegacyOrderRecord) int { return o.OldId }
+	orders, err := fetchLegacyAfter(ctx, uc.source.Orders, orderOldId, watermarks[domain.LegacyStageOrders], options.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler pedidos novos do legado: %v", err)
+	}
+	recent, err := fetchLegacyAfter(ctx, recentOrders, orderOldId, 0, options.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler pedidos recentes do legado: %v", err)
+	}
+	orders = mergeLegacyRecords(orders, recent, orderOldId)
+	failedOrders, err := fetchLegacyByIds(ctx, uc.source.OrdersByIds, failed[domain.LegacyStageOrders], options.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao reler pedidos com falha do legado: %v", err)
+	}
+	orders = mergeLegacyRecords(orders, failedOrders, orderOldId)
+
+	customerOldId := func(c domain.LegacyCustomerRecord) int { return c.OldId }
+	customers, err := fetchLegacyAfter(ctx, uc.source.Customers, customerOldId, watermarks[domain.LegacyStageCustomers], options.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler clientes novos do legado: %v", err)
+	}
+	rereadCustomerIds := slices.Clone(failed[domain.LegacyStageCustomers])
+	for _, order := range orders {
+		if !slices.Contains(rereadCustomerIds, order.OldCustomerId) {
+			rereadCustomerIds = append(rereadCustomerIds, order.OldCustomerId)
+		}
+	}
+	rereadCustomers, err := fetchLegacyByIds(ctx, uc.source.CustomersByIds, rereadCustomerIds, options.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler clientes dos pedidos do legado: %v", err)
+	}
+	customers = mergeLegacyRecords(customers, rereadCustomers, customerOldId)
+
+	productOldId := func(p domain.LegacyProductRecord) int { return p.OldId }
+	products, err := fetchLegacyAfter(ctx, uc.source.Products, productOldId, watermarks[domain.LegacyStageProducts], options.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler produtos novos do legado: %v", err)
+	}
+	failedProducts, err := fetchLegacyByIds(ctx, uc.source.ProductsByIds, failed[domain.LegacyStageProducts], options.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao reler produtos com falha do legado: %v", err)
+	}
+	products = mergeLegacyRecords(products, failedProducts, productOldId)
+
+	addressOldId := func(a domain.LegacyAddressRecord) int { return a.OldId }
+	addresses, err := fetchLegacyAfter(ctx, uc.source.Addresses, addressOldId, watermarks[domain.LegacyStageAddresses], options.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler endereços novos do legado: %v", err)
+	}
+	failedAddresses, err := fetchLegacyByIds(ctx, uc.source.AddressesByIds, failed[domain.LegacyStageAddresses], options.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao reler endereços com falha do legado: %v", err)
+	}
+	addresses = mergeLegacyRecords(addresses, failedAddresses, addressOldId)
+
+	s := legacySync{repo: repo, batchSize: options.BatchSize, result: &LegacySyncResult{
+		Stages:    []LegacySyncStageResult{},
+		Errors:    []domain.LegacyImportError{},
+		Conflicts: []domain.LegacySyncConflict{},
+		Applied:   !options.DryRun,
+	}}
+	if err := syncLegacyStage(ctx, s, domain.LegacyStageProducts, products, productOldId, convertLegacyProduct, repo.SyncProducts); err != nil {
+		return nil, fmt.Errorf("erro ao sincronizar produtos: %v", err)
+	}
+	if err := syncLegacyStage(ctx, s, domain.LegacyStageCustomers, customers, customerOldId, convertLegacyCustomer, repo.SyncCustomers); err != nil {
+		return nil, fmt.Errorf("erro ao sincronizar clientes: %v", err)
+	}
+	if err := syncLegacyStage(ctx, s, domain.LegacyStageAddresses, addresses, addressOldId, convertLegacyAddress, repo.SyncAddresses); err != nil {
+		return nil, fmt.Errorf("erro ao sincronizar endereços: %v", err)
+	}
+	if err := syncLegacyStage(ctx, s, domain.LegacyStageOrders, orders, orderOldId, convertLegacyOrder, repo.SyncOrders); err != nil {
+		return nil, fmt.Errorf("erro ao sincronizar pedidos: %v", err)
+	}
+	return s.result, nil
+}
+
+func (uc *LegacySyncUseCase) Conflicts(ctx context.Context) ([]domain.LegacySyncConflict, error) {
+	ctx, span := startSpan(ctx, "LegacySyncUseCase.Conflicts")
+	defer span.End()
+
+	conflicts, err := uc.repo.Conflicts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar conflitos da sincronização: %v", err)
+	}
+	return conflicts, nil
+}
+
+// ResolveConflict escolhe qual versão do pedido ou cliente fica. Manter o legado relê a linha no
+// sistema antigo e a grava por cima das edições da API; manter a API descarta a versão do legado.
+func (uc *LegacySyncUseCase) ResolveConflict(ctx context.Context, stage string, oldId int, keep string) (*domain.LegacySyncConflict, error) {
+	ctx, span := startSpan(ctx, "LegacySyncUseCase.ResolveConflict")
+	defer span.End()
+
+	switch keep {
+	case domain.LegacySyncKeepApi:
+		conflict, err := uc.repo.KeepApiVersion(ctx, stage, oldId)
+		if err != nil {
+			return nil, err
+		}
+		return conflict, nil
+	case domain.LegacySyncKeepLegacy:
+	default:
+		return nil, domain.NewInvalidLegacySyncKeepError(keep)
+	}
+
+	conflicts, err := uc.repo.Conflicts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar conflitos da sincronização: %v", err)
+	}
+	index := slices.IndexFunc(conflicts, func(c domain.LegacySyncConflict) bool {
+		return c.Stage == stage && c.OldId == oldId
+	})
+	if index < 0 {
+		return nil, domain.NewLegacySyncConflictNotFoundError(stage, oldId)
+	}
+
+	var result *domain.LegacySyncBatchResult
+	switch stage {
+	case domain.LegacyStageCustomers:
+		result, err = forceLegacyRow(ctx, stage, oldId, uc.source.CustomersByIds, convertLegacyCustomer, uc.repo.SyncCustomers)
+	default:
+		result, err = forceLegacyRow(ctx, stage, oldId, uc.source.OrdersByIds, convertLegacyOrder, uc.repo.SyncOrders)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Failed) > 0 {
+		return nil, fmt.Errorf("erro ao gravar %s %d do legado: %s", stage, oldId, result.Failed[0].Reason)
+	}
+
+	conflict := conflicts[index]
+	resolvedAt := uc.now()
+	resolution := domain.LegacySyncKeepLegacy
+	conflict.ResolvedAt = &resolvedAt
+	conflict.Resolution = &resolution
+	return &conflict, nil
+}
+
+// forceLegacyRow relê a linha no legado e a grava mesmo que tenha sido editada pela API.
+func forceLegacyRow[R any, T any](
+	ctx context.Context,
+	stage string,
+	oldId int,
+	fetch func(ctx context.Context, ids []int) ([]R, error),
+	convert func(R) (T, error),
+	save func(ctx context.Context, batch domain.LegacySyncBatch[T]) (*domain.LegacySyncBatchResult, error),
+) (*domain.LegacySyncBatchResult, error) {
+	records, err := fetch(ctx, []int{oldId})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler %s %d do legado: %v", stage, oldId, err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%s %d não existe mais no legado", stage, oldId)
+	}
+	row, err := convert(records[0])
+	if err != nil {
+		return nil, fmt.Errorf("%s %d do legado é inválido: %v", stage, oldId, err)
+	}
+
+	result, err := save(ctx, domain.LegacySyncBatch[T]{
+		Stage: stage,
+		Rows:  []domain.LegacySyncRow[T]{{OldId: oldId, Fingerprint: legacyFingerprint(row), Row: row}},
+		Force: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gravar %s %d do legado: %v", stage, oldId, err)
+	}
+	return result, nil
+}
+
+// legacySync acumula o resultado enquanto as etapas são gravadas.
+type legacySync struct {
+	repo      domain.LegacySyncRepository
+	batchSize int
+	result    *LegacySyncResult
+}
+
+// syncLegacyStage converte as linhas lidas e grava, em lotes, só as que mudaram desde a última
+// versão gravada. Linhas inválidas vão no lote com o erro, para que ele fique registrado.
+func syncLegacyStage[R any, T any](
+	ctx context.Context,
+	s legacySync,
+	stage string,
+	records []R,
+	oldId func(R) int,
+	convert func(R) (T, error),
+	save func(ctx context.Context, batch domain.LegacySyncBatch[T]) (*domain.LegacySyncBatchResult, error),
+) error {
+	stageResult := LegacySyncStageResult{Stage: stage, Checked: len(records)}
+	for chunk := range slices.Chunk(records, s.batchSize) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		oldIds := make([]int, len(chunk))
+		for i, record := range chunk {
+			oldIds[i] = oldId(record)
+		}
+		fingerprints, err := s.repo.Fingerprints(ctx, stage, oldIds)
+		if err != nil {
+			return err
+		}
+
+		batch := domain.LegacySyncBatch[T]{Stage: stage}
+		for _, record := range chunk {
+			row := domain.LegacySyncRow[T]{OldId: oldId(record)}
+			converted, err := convert(record)
+			if err != nil {
+				row.Error = err.Error()
+				batch.Rows = append(batch.Rows, row)
+				continue
+			}
+			row.Row = converted
+			row.Fingerprint = legacyFingerprint(converted)
+			if fingerprints[row.OldId] != row.Fingerprint {
+				batch.Rows = append(batch.Rows, row)
+			}
+		}
+		if len(batch.Rows) == 0 {
+			continue
+		}
+
+		result, err := save(ctx, batch)
+		if err != nil {
+			return err
+		}
+		stageResult.Synced += result.Synced
+		stageResult.Failed += len(result.Failed)
+		stageResult.Conflicts += len(result.Conflicts)
+		s.result.Errors = append(s.result.Errors, result.Failed...)
+		s.result.Conflicts = append(s.result.Conflicts, result.Conflicts...)
+	}
+
+	s.result.Stages = append(s.result.Stages, stageResult)
+	if stageResult.Synced > 0 || stageResult.Failed > 0 || stageResult.Conflicts > 0 {
+		logger.FromContext(ctx).Info(fmt.Sprintf("Legacy %s synced: %d written, %d failed, %d conflicts", stage, stageResult.Synced, stageResult.Failed, stageResult.Conflicts))
+	}
+	return nil
+}
+
+// fetchLegacyAfter lê todas as linhas depois de afterId, em lotes.
+func fetchLegacyAfter[R any](
+	ctx context.Context,
+	fetch func(ctx context.Context, afterId int, limit int) ([]R, error),
+	oldId func(R) int,
+	afterId int,
+	batchSize int,
+) ([]R, error) {
+	records := []R{}
+	for {
+		page, err := fetch(ctx, afterId, batchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			return records, nil
+		}
+		records = append(records, page...)
+		afterId = oldId(page[len(page)-1])
+	}
+}
+
+// fetchLegacyByIds relê as linhas das chaves informadas, em lotes.
+func fetchLegacyByIds[R any](
+	ctx context.Context,
+	fetch func(ctx context.Context, ids []int) ([]R, error),
+	ids []int,
+	batchSize int,
+) ([]R, error) {
+	records := []R{}
+	for chunk := range slices.Chunk(ids, batchSize) {
+		page, err := fetch(ctx, chunk)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, page...)
+	}
+	return records, nil
+}
+
+// mergeLegacyRecords junta as linhas de extra que ainda não estão em records.
+func mergeLegacyRecords[R any](records []R, extra []R, oldId func(R) int) []R {
+	seen := make(map[int]bool, len(records))
+	for _, record := range records {
+		seen[oldId(record)] = true
+	}
+	for _, record := range extra {
+		if !seen[oldId(record)] {
+			seen[oldId(record)] = true
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// legacyFingerprint identifica o conteúdo de uma linha convertida; a mesma linha do legado gera
+// sempre a mesma impressão digital enquanto não for alterada.
+func legacyFingerprint(row any) string {
+	data, _ := json.Marshal(row)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// pickupDay devolve a meia-noite do dia de t no fuso da loja, a mesma granularidade da data de
+// retirada do legado.
+func pickupDay(t time.Time) time.Time {
+	local := t.In(domain.StoreLocation)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, domain.StoreLocation)
+}
@@ -0,0 +1,418 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+type mockLegacySyncSource struct {
+	*mockLegacySource
+	pickupSince time.Time
+}
+
+func (m *mockLegacySyncSource) RecentOrders(ctx context.Context, pickupSince time.Time, afterId int, limit int) ([]domain.LegacyOrderRecord, error) {
+	m.pickupSince = pickupSince
+	recent := []domain.LegacyOrderRecord{}
+	for _, order := range m.orders {
+		if order.PickupDate != nil && !order.PickupDate.Before(pickupSince) {
+			recent = append(recent, order)
+		}
+	}
+	return legacyPage(recent, func(r domain.LegacyOrderRecord) int { return r.OldId }, afterId, limit), nil
+}
+
+func (m *mockLegacySyncSource) OrdersByIds(ctx context.Context, ids []int) ([]domain.LegacyOrderRecord, error) {
+	found := []domain.LegacyOrderRecord{}
+	for _, order := range m.orders {
+		if slices.Contains(ids, order.OldId) {
+			found = append(found, order)
+		}
+	}
+	return found, nil
+}
+
+func (m *mockLegacySyncSource) ProductsByIds(ctx context.Context, ids []int) ([]domain.LegacyProductRecord, error) {
+	found := []domain.LegacyProductRecord{}
+	for _, product := range m.products {
+		if slices.Contains(ids, product.OldId) {
+			found = append(found, product)
+		}
+	}
+	return found, nil
+}
+
+func (m *mockLegacySyncSource) AddressesByIds(ctx context.Context, ids []int) ([]domain.LegacyAddressRecord, error) {
+	found := []domain.LegacyAddressRecord{}
+	for _, address := range m.addresses {
+		if slices.Contains(ids, address.OldId) {
+			found = append(found, address)
+		}
+	}
+	return found, nil
+}
+
+func (m *mockLegacySyncSource) CustomersByIds(ctx context.Context, ids []int) ([]domain.LegacyCustomerRecord, error) {
+	found := []domain.LegacyCustomerRecord{}
+	for _, customer := range m.customers {
+		if slices.Contains(ids, customer.OldId) {
+			found = append(found, customer)
+		}
+	}
+	return found, nil
+}
+
+type mockLegacySyncRepository struct {
+	watermarks   map[string]int
+	failed       map[string][]int
+	fingerprints map[string]map[int]string
+	synced       map[string][]int
+	// edited são as linhas alteradas pela API desde a última sincronização, por etapa
+	edited    map[string][]int
+	conflicts []domain.LegacySyncConflict
+	keptApi   []int
+	dryRun    bool
+}
+
+func mockSyncBatch[T any](m *mockLegacySyncRepository, batch domain.LegacySyncBatch[T]) *domain.LegacySyncBatchResult {
+	result := &domain.LegacySyncBatchResult{}
+	for _, row := range batch.Rows {
+		if row.Error != "" {
+			result.Failed = append(result.Failed, domain.LegacyImportError{Stage: batch.Stage, OldId: strconv.Itoa(row.OldId), Reason: row.Error})
+			continue
+		}
+		if !batch.Force && slices.Contains(m.edited[batch.Stage], row.OldId) {
+			open := slices.ContainsFunc(m.conflicts, func(c domain.LegacySyncConflict) bool {
+				return c.Stage == batch.Stage && c.OldId == row.OldId
+			})
+			if !open {
+				conflict := domain.LegacySyncConflict{Id: "conflict-" + strconv.Itoa(row.OldId), Stage: batch.Stage, OldId: row.OldId}
+				m.conflicts = append(m.conflicts, conflict)
+				result.Conflicts = append(result.Conflicts, conflict)
+			}
+			continue
+		}
+		if m.fingerprints[batch.Stage] == nil {
+			m.fingerprints[batch.Stage] = map[int]string{}
+		}
+		m.fingerprints[batch.Stage][row.OldId] = row.Fingerprint
+		m.synced[batch.Stage] = append(m.synced[batch.Stage], row.OldId)
+		result.Synced++
+	}
+	return result
+}
+
+func (m *mockLegacySyncRepository) Watermarks(ctx context.Context) (map[string]int, error) {
+	return m.watermarks, nil
+}
+
+func (m *mockLegacySyncRepository) FailedOldIds(ctx context.Context) (map[string][]int, error) {
+	return m.failed, nil
+}
+
+func (m *mockLegacySyncRepository) Fingerprints(ctx context.Context, stage string, oldIds []int) (map[int]string, error) {
+	return mockReconcileLookup(m.fingerprints[stage], oldIds), nil
+}
+
+func (m *mockLegacySyncRepository) SyncProducts(ctx context.Context, batch domain.LegacySyncBatch[domain.LegacyProduct]) (*domain.LegacySyncBatchResult, error) {
+	return mockSyncBatch(m, batch), nil
+}
+
+func (m *mockLegacySyncRepository) SyncCustomers(ctx context.Context, batch domain.LegacySyncBatch[domain.LegacyCustomer]) (*domain.LegacySyncBatchResult, error) {
+	return mockSyncBatch(m, batch), nil
+}
+
+func (m *mockLegacySyncRepository) SyncAddresses(ctx context.Context, batch domain.LegacySyncBatch[domain.LegacyAddress]) (*domain.LegacySyncBatchResult, error) {
+	return mockSyncBatch(m, batch), nil
+}
+
+func (m *mockLegacySyncRepository) SyncOrders(ctx context.Context, batch domain.LegacySyncBatch[domain.LegacyOrder]) (*domain.LegacySyncBatchResult, error) {
+	return mockSyncBatch(m, batch), nil
+}
+
+func (m *mockLegacySyncRepository) Conflicts(ctx context.Context) ([]domain.LegacySyncConflict, error) {
+	return m.conflicts, nil
+}
+
+func (m *mockLegacySyncRepository) KeepApiVersion(ctx context.Context, stage string, oldId int) (*domain.LegacySyncConflict, error) {
+	for _, conflict := range m.conflicts {
+		if conflict.Stage == stage && conflict.OldId == oldId {
+			m.keptApi = append(m.keptApi, oldId)
+			resolution := domain.LegacySyncKeepApi
+			conflict.Resolution = &resolution
+			return &conflict, nil
+		}
+	}
+	return nil, domain.NewLegacySyncConflictNotFoundError(stage, oldId)
+}
+
+func (m *mockLegacySyncRepository) DryRun(ctx context.Context) (domain.LegacySyncRepository, func(), error) {
+	m.dryRun = true
+	return m, func() {}, nil
+}
+
+func newMockLegacySyncRepository() *mockLegacySyncRepository {
+	return &mockLegacySyncRepository{fingerprints: map[string]map[int]string{}, synced: map[string][]int{}, edited: map[string][]int{}}
+}
+
+func completedLegacyImport() *mockLegacyImportRepository {
+	completedAt := time.Now()
+	return &mockLegacyImportRepository{checkpoints: map[string]domain.LegacyImportCheckpoint{
+		domain.LegacyStageOrders: {Stage: domain.LegacyStageOrders, CompletedAt: &completedAt},
+	}}
+}
+
+func TestLegacySyncUseCase_Sync(t *testing.T) {
+	now := time.Date(2024, 5, 10, 15, 0, 0, 0, domain.StoreLocation)
+	old := time.Date(2024, 4, 1, 0, 0, 0, 0, domain.StoreLocation)
+	today := time.Date(2024, 5, 10, 0, 0, 0, 0, domain.StoreLocation)
+	item := []domain.LegacyOrderItemRecord{{OldProductId: 1, UnityType: "UN", Quantity: 1}}
+
+	t.Run("should sync new rows and only the recent orders that changed", func(t *testing.T) {
+		source := &mockLegacySyncSource{mockLegacySource: &mockLegacySource{
+			afterIds: map[string][]int{},
+			products: []domain.LegacyProductRecord{
+				{OldId: 1, Name: "Lasanha", UnityType: "UN", Value: 40},
+				{OldId: 2, Name: "Nhoque", UnityType: "KG", Value: 55.9},
+			},
+			customers: []domain.LegacyCustomerRecord{
+				{OldId: 1, Name: "Maria", Phone: "(11) 99999-0000"},
+				{OldId: 2, Name: "João", Phone: "(11) 98888-7777"},
+			},
+			orders: []domain.LegacyOrderRecord{
+				{OldId: 1, Number: 10, PickupDate: &old, OldCustomerId: 2, Items: item},
+				{OldId: 2, Number: 11, PickupDate: &today, OldCustomerId: 1, Items: item},
+				{OldId: 3, Number: 12, PickupDate: &today, OldCustomerId: 1, IsPickedUp: true, Items: item},
+				{OldId: 4, Number: 13, PickupDate: &today, OldCustomerId: 1},
+			},
+		}}
+		repo := newMockLegacySyncRepository()
+		repo.watermarks = map[string]int{domain.LegacyStageProducts: 1, domain.LegacyStageCustomers: 2, domain.LegacyStageOrders: 3}
+		// O pedido 2 não mudou no legado desde a importação; o 3 foi retirado e também editado pela API
+		unchanged, _ := convertLegacyOrder(source.orders[1])
+		repo.fingerprints[domain.LegacyStageOrders] = map[int]string{2: legacyFingerprint(unchanged), 3: "before"}
+		repo.edited[domain.LegacyStageOrders] = []int{3}
+		uc := NewLegacySyncUseCase(source, repo, completedLegacyImport())
+		uc.now = func() time.Time { return now }
+
+		result, err := uc.Sync(context.Background(), LegacySyncOptions{BatchSize: 1, Lookback: 24 * time.Hour})
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+
+		if !source.pickupSince.Equal(time.Date(2024, 5, 9, 0, 0, 0, 0, domain.StoreLocation)) {
+			t.Errorf("expected recent orders from the start of the previous day, but got %v", source.pickupSince)
+		}
+		if synced := repo.synced[domain.LegacyStageProducts]; !slices.Equal(synced, []int{2}) {
+			t.Errorf("expected only the product after the watermark to be synced, but got %v", synced)
+		}
+		if synced := repo.synced[domain.LegacyStageCustomers]; !slices.Equal(synced, []int{1}) {
+			t.Errorf("expected the customer of the recent orders to be re-read, but got %v", synced)
+		}
+		if synced := repo.synced[domain.LegacyStageOrders]; len(synced) != 0 {
+			t.Errorf("expected no order to be written, but got %v", synced)
+		}
+		if len(result.Conflicts) != 1 || result.Conflicts[0].OldId != 3 {
+			t.Errorf("expected a conflict for order 3, but got %+v", result.Conflicts)
+		}
+		if len(result.Errors) != 1 || result.Errors[0].OldId != "4" || result.Errors[0].Reason != "order without items" {
+			t.Errorf("expected order 4 to fail, but got %+v", result.Errors)
+		}
+		for _, stage := range result.Stages {
+			if stage.Stage == domain.LegacyStageOrders && (stage.Checked != 3 || stage.Synced != 0 || stage.Failed != 1 || stage.Conflicts != 1) {
+				t.Errorf("expected 3 orders checked, 1 failed and 1 conflict, but got %+v", stage)
+			}
+		}
+		if !result.Applied {
+			t.Errorf("expected the sync to be applied")
+		}
+	})
+
+	t.Run("should not write rows whose fingerprint is already recorded", func(t *testing.T) {
+		source := &mockLegacySyncSource{mockLegacySource: &mockLegacySource{
+			afterIds:  map[string][]int{},
+			customers: []domain.LegacyCustomerRecord{{OldId: 1, Name: "Maria", Phone: "(11) 99999-0000"}},
+			orders:    []domain.LegacyOrderRecord{{OldId: 1, Number: 10, PickupDate: &today, OldCustomerId: 1, Items: item}},
+		}}
+		repo := newMockLegacySyncRepository()
+		repo.watermarks = map[string]int{}
+		uc := NewLegacySyncUseCase(source, repo, completedLegacyImport())
+		uc.now = func() time.Time { return now }
+
+		if _, err := uc.Sync(context.Background(), LegacySyncOptions{}); err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		repo.synced = map[string][]int{}
+		result, err := uc.Sync(context.Background(), LegacySyncOptions{})
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+
+		if len(repo.synced) != 0 {
+			t.Errorf("expected the second run to write nothing, but got %v", repo.synced)
+		}
+		if result.Stages[1].Checked != 1 {
+			t.Errorf("expected the customer to be checked again, but got %+v", result.Stages[1])
+		}
+	})
+
+	t.Run("should read again the rows whose last write failed", func(t *testing.T) {
+		source := &mockLegacySyncSource{mockLegacySource: &mockLegacySource{
+			afterIds: map[string][]int{},
+			products: []domain.LegacyProductRecord{
+				{OldId: 1, Name: "Lasanha", UnityType: "UN", Value: 40},
+				{OldId: 2, Name: "Nhoque", UnityType: "KG", Value: 55.9},
+			},
+		}}
+		repo := newMockLegacySyncRepository()
+		repo.watermarks = map[string]int{domain.LegacyStageProducts: 2}
+		repo.failed = map[string][]int{domain.LegacyStageProducts: {1}}
+		uc := NewLegacySyncUseCase(source, repo, completedLegacyImport())
+		uc.now = func() time.Time { return now }
+
+		if _, err := uc.Sync(context.Background(), LegacySyncOptions{}); err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if synced := repo.synced[domain.LegacyStageProducts]; !slices.Equal(synced, []int{1}) {
+			t.Errorf("expected the failed product below the watermark to be synced, but got %v", synced)
+		}
+	})
+
+	t.Run("should report a conflict only when it is detected", func(t *testing.T) {
+		source := &mockLegacySyncSource{mockLegacySource: &mockLegacySource{
+			afterIds:  map[string][]int{},
+			customers: []domain.LegacyCustomerRecord{{OldId: 1, Name: "Maria", Phone: "(11) 99999-0000"}},
+			orders:    []domain.LegacyOrderRecord{{OldId: 1, Number: 10, PickupDate: &today, OldCustomerId: 1, Items: item}},
+		}}
+		repo := newMockLegacySyncRepository()
+		repo.watermarks = map[string]int{}
+		repo.edited[domain.LegacyStageCustomers] = []int{1}
+		repo.edited[domain.LegacyStageOrders] = []int{1}
+		uc := NewLegacySyncUseCase(source, repo, completedLegacyImport())
+		uc.now = func() time.Time { return now }
+
+		first, err := uc.Sync(context.Background(), LegacySyncOptions{})
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		second, err := uc.Sync(context.Background(), LegacySyncOptions{})
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+
+		if len(first.Conflicts) != 2 || first.Conflicts[0].Stage != domain.LegacyStageCustomers || first.Conflicts[1].Stage != domain.LegacyStageOrders {
+			t.Errorf("expected conflicts for the customer and the order, but got %+v", first.Conflicts)
+		}
+		if len(second.Conflicts) != 0 {
+			t.Errorf("expected open conflicts not to be reported again, but got %+v", second.Conflicts)
+		}
+		if len(repo.synced) != 0 {
+			t.Errorf("expected nothing edited in the API to be overwritten, but got %v", repo.synced)
+		}
+	})
+
+	t.Run("should sync inside the dry run repository", func(t *testing.T) {
+		source := &mockLegacySyncSource{mockLegacySource: &mockLegacySource{afterIds: map[string][]int{}}}
+		repo := newMockLegacySyncRepository()
+		uc := NewLegacySyncUseCase(source, repo, completedLegacyImport())
+
+		result, err := uc.Sync(context.Background(), LegacySyncOptions{DryRun: true})
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if !repo.dryRun || result.Applied {
+			t.Errorf("expected a dry run that is not applied")
+		}
+	})
+
+	t.Run("should refuse to sync before the import finishes", func(t *testing.T) {
+		source := &mockLegacySyncSource{mockLegacySource: &mockLegacySource{afterIds: map[string][]int{}}}
+		importRepo := &mockLegacyImportRepository{checkpoints: map[string]domain.LegacyImportCheckpoint{}}
+		uc := NewLegacySyncUseCase(source, newMockLegacySyncRepository(), importRepo)
+
+		if _, err := uc.Sync(context.Background(), LegacySyncOptions{}); err == nil {
+			t.Errorf("expected an error, but got nil")
+		}
+	})
+}
+
+func TestLegacySyncUseCase_ResolveConflict(t *testing.T) {
+	pickup := time.Date(2024, 5, 10, 0, 0, 0, 0, domain.StoreLocation)
+	newUseCase := func() (*LegacySyncUseCase, *mockLegacySyncRepository) {
+		source := &mockLegacySyncSource{mockLegacySource: &mockLegacySource{
+			afterIds: map[string][]int{},
+			orders: []domain.LegacyOrderRecord{{OldId: 7, Number: 10, PickupDate: &pickup, OldCustomerId: 1, Items: []domain.LegacyOrderItemRecord{
+				{OldProductId: 1, UnityType: "UN", Quantity: 1},
+			}}},
+		}}
+		repo := newMockLegacySyncRepository()
+		repo.edited[domain.LegacyStageOrders] = []int{7}
+		repo.conflicts = []domain.LegacySyncConflict{{Id: "conflict-7", Stage: domain.LegacyStageOrders, OldId: 7}}
+		return NewLegacySyncUseCase(source, repo, completedLegacyImport()), repo
+	}
+
+	t.Run("should overwrite the order with the legacy version", func(t *testing.T) {
+		uc, repo := newUseCase()
+
+		conflict, err := uc.ResolveConflict(context.Background(), domain.LegacyStageOrders, 7, domain.LegacySyncKeepLegacy)
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if !slices.Equal(repo.synced[domain.LegacyStageOrders], []int{7}) {
+			t.Errorf("expected order 7 to be forced, but got %v", repo.synced)
+		}
+		if conflict.Resolution == nil || *conflict.Resolution != domain.LegacySyncKeepLegacy || conflict.ResolvedAt == nil {
+			t.Errorf("expected the conflict to be resolved as legacy, but got %+v", conflict)
+		}
+	})
+
+	t.Run("should overwrite a customer with the legacy version", func(t *testing.T) {
+		uc, repo := newUseCase()
+		uc.source.(*mockLegacySyncSource).customers = []domain.LegacyCustomerRecord{{OldId: 3, Name: "Maria", Phone: "(11) 99999-0000"}}
+		repo.edited[domain.LegacyStageCustomers] = []int{3}
+		repo.conflicts = append(repo.conflicts, domain.LegacySyncConflict{Id: "conflict-3", Stage: domain.LegacyStageCustomers, OldId: 3})
+
+		if _, err := uc.ResolveConflict(context.Background(), domain.LegacyStageCustomers, 3, domain.LegacySyncKeepLegacy); err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if !slices.Equal(repo.synced[domain.LegacyStageCustomers], []int{3}) {
+			t.Errorf("expected customer 3 to be forced, but got %v", repo.synced)
+		}
+	})
+
+	t.Run("should keep the api version", func(t *testing.T) {
+		uc, repo := newUseCase()
+
+		if _, err := uc.ResolveConflict(context.Background(), domain.LegacyStageOrders, 7, domain.LegacySyncKeepApi); err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if !slices.Equal(repo.keptApi, []int{7}) || len(repo.synced) != 0 {
+			t.Errorf("expected only the api version to be kept, but got %v and %v", repo.keptApi, repo.synced)
+		}
+	})
+
+	t.Run("should fail when the order has no open conflict", func(t *testing.T) {
+		uc, _ := newUseCase()
+
+		_, err := uc.ResolveConflict(context.Background(), domain.LegacyStageOrders, 8, domain.LegacySyncKeepLegacy)
+		var notFound *domain.LegacySyncConflictNotFoundError
+		if !errors.As(err, &notFound) {
+			t.Errorf("expected a not found error, but got %v", err)
+		}
+	})
+
+	t.Run("should reject an unknown side", func(t *testing.T) {
+		uc, _ := newUseCase()
+
+		_, err := uc.ResolveConflict(context.Background(), domain.LegacyStageOrders, 7, "both")
+		var invalid *domain.InvalidLegacySyncKeepError
+		if !errors.As(err, &invalid) {
+			t.Errorf("expected an invalid side error, but got %v", err)
+		}
+	})
+}
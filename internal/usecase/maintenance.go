@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+// Situações de cada notificação no reenvio.
+const (
+	ResendStatusSent    = "sent"
+	ResendStatusFailed  = "failed"
+	ResendStatusSkipped = "skipped"
+	ResendStatusPending = "pending"
+)
+
+// NotificationResend é o resultado do reenvio de uma notificação que não chegou ao cliente.
+type NotificationResend struct {
+	OrderId       string  `json:"orderId"`
+	OrderNumber   string  `json:"orderNumber,omitempty"`
+	Template      string  `json:"template"`
+	LastChannel   string  `json:"lastChannel"`
+	LastAttemptAt string  `json:"lastAttemptAt"`
+	Status        string  `json:"status"`
+	Error         *string `json:"error,omitempty"`
+}
+
+type ResendNotificationsResult struct {
+	Sent          int                  `json:"sent"`
+	Failed        int                  `json:"failed"`
+	Skipped       int                  `json:"skipped"`
+	Applied       bool                 `json:"applied"`
+	Notifications []NotificationResend `json:"notifications"`
+}
+
+// MaintenanceUseCase concentra as rotinas administrativas executadas pelo zionctl.
+type MaintenanceUseCase struct {
+	repo             domain.MaintenanceRepository
+	orderRepo        domain.OrderRepository
+	notificationRepo domain.NotificationRepository
+	notifier         OrderNotifier
+	now              func() time.Time
+}
+
+func NewMaintenanceUseCase(repo domain.MaintenanceRepository, orderRepo domain.OrderRepository, notificationRepo domain.NotificationRepository, notifier OrderNotifier) *MaintenanceUseCase {
+	return &MaintenanceUseCase{
+		repo:             repo,
+		orderRepo:        orderRepo,
+		notificationRepo: notificationRepo,
+		notifier:         notifier,
+		now:              time.Now,
+	}
+}
+
+// ReseedOrderNumbers ajusta a sequência para continuar depois do maior número de pedido, ou a
+// partir de minNext quando ele for maior (ex.: para seguir a numeração do sistema antigo).
+func (uc *MaintenanceUseCase) ReseedOrderNumbers(ctx context.Context, minNext int, dryRun bool) (*domain.OrderNumberSeed, error) {
+	ctx, span := startSpan(ctx, "MaintenanceUseCase.ReseedOrderNumbers")
+	defer span.End()
+
+	if minNext < 0 {
+		return nil, fmt.Errorf("erro ao ajustar numeração dos pedidos: o próximo número não pode ser negativo")
+	}
+
+	seed, err := uc.repo.ReseedOrderNumbers(ctx, minNext, dryRun)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ajustar numeração dos pedidos: %v", err)
+	}
+	return seed, nil
+}
+
+// PurgeDeleted remove definitivamente os registros excluídos há mais de olderThan.
+func (uc *MaintenanceUseCase) PurgeDeleted(ctx context.Context, entity string, olderThan time.Duration, dryRun bool) (*domain.PurgeResult, error) {
+	ctx, span := startSpan(ctx, "MaintenanceUseCase.PurgeDeleted")
+	defer span.End()
+
+	if !domain.IsValidPurgeEntity(entity) {
+		return nil, domain.NewInvalidPurgeEntityError(entity)
+	}
+	if olderThan < 0 {
+		return nil, fmt.Errorf("erro ao expurgar registros: o período de retenção não pode ser negativo")
+	}
+
+	result, err := uc.repo.PurgeDeleted(ctx, entity, uc.now().Add(-olderThan), dryRun)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao expurgar registros: %v", err)
+	}
+	return result, nil
+}
+
+// ResendNotifications reenvia as notificações criadas nas últimas since que falharam em todos
+// os canais. Mensagens que deixaram de fazer sentido pelo status atual do pedido (ex.: pedido
+// pronto que já foi retirado) são ignoradas.
+func (uc *MaintenanceUseCase) ResendNotifications(ctx context.Context, since time.Duration, orderId string, dryRun bool) (*ResendNotificationsResult, error) {
+	ctx, span := startSpan(ctx, "MaintenanceUseCase.ResendNotifications")
+	defer span.End()
+
+	undelivered, err := uc.notificationRepo.FindUndelivered(ctx, uc.now().Add(-since), orderId)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar notificações não entregues: %v", err)
+	}
+
+	result := &ResendNotificationsResult{Applied: !dryRun, Notifications: []NotificationResend{}}
+	for _, notification := range undelivered {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		resend := NotificationResend{
+			OrderId:       *notification.OrderId,
+			Template:      notification.Template,
+			LastChannel:   notification.Channel,
+			LastAttemptAt: notification.CreatedAt.Format(time.RFC3339),
+		}
+
+		order, err := uc.orderRepo.FindById(ctx, resend.OrderId)
+		switch {
+		case err != nil:
+			resend.Status = ResendStatusSkipped
+			errorMessage := err.Error()
+			resend.Error = &errorMessage
+		case !isNotificationCurrent(notification.Template, order.Status):
+			resend.Status = ResendStatusSkipped
+			resend.OrderNumber = order.Number
+		case dryRun:
+			resend.Status = ResendStatusPending
+			resend.OrderNumber = order.Number
+		default:
+			resend.OrderNumber = order.Number
+			resend.Status = ResendStatusSent
+			if err := uc.notifier.NotifyOrder(ctx, notification.Template, *order); err != nil {
+				resend.Status = ResendStatusFailed
+				errorMessage := err.Error()
+				resend.Error = &errorMessage
+			}
+		}
+
+		switch resend.Status {
+		case ResendStatusSent:
+			result.Sent++
+		case ResendStatusFailed:
+			result.Failed++
+		case ResendStatusSkipped:
+			result.Skipped++
+		}
+		result.Notifications = append(result.Notifications, resend)
+	}
+
+	return result, nil
+}
+
+// isNotificationCurrent indica se a mensagem ainda vale para o pedido no status atual.
+func isNotificationCurrent(template string, status string) bool {
+	if template == domain.NotificationOrderCancelled {
+		return status == domain.OrderStatusCancelled
+	}
+	return status == domain.OrderStatusPending || status == domain.OrderStatusReady
+}
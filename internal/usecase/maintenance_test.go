@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+type mockMaintenanceRepository struct {
+	deletedBefore time.Time
+}
+
+func (m *mockMaintenanceRepository) ReseedOrderNumbers(ctx context.Context, minNext int, dryRun bool) (*domain.OrderNumberSeed, error) {
+	return &domain.OrderNumberSeed{NextNumber: minNext, Applied: !dryRun}, nil
+}
+
+func (m *mockMaintenanceRepository) PurgeDeleted(ctx context.Context, entity string, deletedBefore time.Time, dryRun bool) (*domain.PurgeResult, error) {
+	m.deletedBefore = deletedBefore
+	return &domain.PurgeResult{Entity: entity, DeletedBefore: deletedBefore, Applied: !dryRun}, nil
+}
+
+func TestMaintenanceUseCase_PurgeDeleted(t *testing.T) {
+	t.Run("should reject unknown entities", func(t *testing.T) {
+		uc := NewMaintenanceUseCase(&mockMaintenanceRepository{}, &mockOrderRepository{}, &mockNotificationRepository{}, &mockOrderNotifier{})
+
+		_, err := uc.PurgeDeleted(context.Background(), "users", time.Hour, false)
+
+		var invalidErr *domain.InvalidPurgeEntityError
+		if !errors.As(err, &invalidErr) {
+			t.Fatalf("expected InvalidPurgeEntityError, but got %v", err)
+		}
+	})
+
+	t.Run("should purge rows deleted before the retention period", func(t *testing.T) {
+		repo := &mockMaintenanceRepository{}
+		uc := NewMaintenanceUseCase(repo, &mockOrderRepository{}, &mockNotificationRepository{}, &mockOrderNotifier{})
+		now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+		uc.now = func() time.Time { return now }
+
+		if _, err := uc.PurgeDeleted(context.Background(), domain.PurgeProducts, 30*24*time.Hour, false); err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+
+		expected := time.Date(2026, 9, 19, 12, 0, 0, 0, time.UTC)
+		if !repo.deletedBefore.Equal(expected) {
+			t.Errorf("expected rows deleted before %v, but got %v", expected, repo.deletedBefore)
+		}
+	})
+}
+
+func TestMaintenanceUseCase_ResendNotifications(t *testing.T) {
+	undelivered := func(orderId string, template string) domain.Notification {
+		return domain.Notification{OrderId: &orderId, Template: template, Channel: "whatsapp", Status: domain.NotificationStatusFailed}
+	}
+	orderRepo := &mockOrderRepository{orders: []domain.Order{
+		{Id: "order-1", Number: "501", Status: domain.OrderStatusPending},
+		{Id: "order-2", Number: "502", Status: domain.OrderStatusPickedUp},
+		{Id: "order-3", Number: "503", Status: domain.OrderStatusCancelled},
+	}}
+	notificationRepo := &mockNotificationRepository{undelivered: []domain.Notification{
+		undelivered("order-1", domain.NotificationOrderConfirmation),
+		undelivered("order-2", domain.NotificationOrderReady),
+		undelivered("order-3", domain.NotificationOrderCancelled),
+		undelivered("order-4", domain.NotificationOrderConfirmation),
+	}}
+
+	t.Run("should resend only notifications that still apply to the order", func(t *testing.T) {
+		notifier := &mockOrderNotifier{}
+		uc := NewMaintenanceUseCase(&mockMaintenanceRepository{}, orderRepo, notificationRepo, notifier)
+
+		result, err := uc.ResendNotifications(context.Background(), 24*time.Hour, "", false)
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+
+		if len(notifier.notified) != 2 || notifier.notified[0] != "order-1" || notifier.notified[1] != "order-3" {
+			t.Errorf("expected orders order-1 and order-3 to be notified, but got %v", notifier.notified)
+		}
+		if result.Sent != 2 || result.Skipped != 2 || result.Failed != 0 {
+			t.Errorf("expected 2 sent and 2 skipped, but got %+v", result)
+		}
+		if result.Notifications[3].Error == nil {
+			t.Errorf("expected an error for the missing order, but got none")
+		}
+	})
+
+	t.Run("should not send anything on dry run", func(t *testing.T) {
+		notifier := &mockOrderNotifier{}
+		uc := NewMaintenanceUseCase(&mockMaintenanceRepository{}, orderRepo, notificationRepo, notifier)
+
+		result, err := uc.ResendNotifications(context.Background(), 24*time.Hour, "order-1", true)
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+
+		if len(notifier.notified) != 0 {
+			t.Errorf("expected no notifications on dry run, but got %v", notifier.notified)
+		}
+		if len(result.Notifications) != 1 || result.Notifications[0].Status != ResendStatusPending || result.Applied {
+			t.Errorf("expected one pending resend for order-1, but got %+v", result)
+		}
+	})
+}
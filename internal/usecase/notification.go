@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/domain/services"
+	"github.com/deividr/zion-api/internal/infra/logger"
+)
+
+type NotificationUseCase struct {
+	repo    domain.NotificationRepository
+	senders []services.NotificationSender
+}
+
+func NewNotificationUseCase(repo domain.NotificationRepository, senders []services.NotificationSender) *NotificationUseCase {
+	return &NotificationUseCase{repo: repo, senders: senders}
+}
+
+func (uc *NotificationUseCase) GetByOrderId(ctx context.Context, orderId string) ([]domain.Notification, error) {
+	ctx, span := startSpan(ctx, "NotificationUseCase.GetByOrderId")
+	defer span.End()
+
+	notifications, err := uc.repo.FindByOrderId(ctx, orderId)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching order notifications: %v", err)
+	}
+	return notifications, nil
+}
+
+// NotifyOrder envia a mensagem do template pelo primeiro canal disponível para o cliente,
+// tentando o próximo canal quando o envio falha. Cada tentativa fica registrada com seu status.
+func (uc *NotificationUseCase) NotifyOrder(ctx context.Context, template string, order domain.Order) error {
+	ctx, span := startSpan(ctx, "NotificationUseCase.NotifyOrder")
+	defer span.End()
+
+	return uc.notify(ctx, nil, template, order)
+}
+
+// NotifyOrderForJob é o NotifyOrder de um job do outbox. As tentativas ficam ligadas ao job e,
+// se uma execução anterior já enviou (ou pode ter enviado) a mensagem por algum canal, o job
+// termina sem reenviar.
+func (uc *NotificationUseCase) NotifyOrderForJob(ctx context.Context, jobId string, template string, order domain.Order) error {
+	ctx, span := startSpan(ctx, "NotificationUseCase.NotifyOrderForJob")
+	defer span.End()
+
+	previous, err := uc.repo.FindByJobId(ctx, jobId)
+	if err != nil {
+		return fmt.Errorf("error fetching previous notification attempts: %v", err)
+	}
+	for _, notification := range previous {
+		// Uma tentativa ainda pendente pode ter chegado ao provedor antes da falha
+		if notification.Status != domain.NotificationStatusFailed {
+			logger.FromContext(ctx).Info(fmt.Sprintf("Notification %s for order %s already %s by %s, not sending again", template, order.Id, notification.Status, notification.Channel))
+			return nil
+		}
+	}
+
+	return uc.notify(ctx, &jobId, template, order)
+}
+
+func (uc *NotificationUseCase) notify(ctx context.Context, jobId *string, template string, order domain.Order) error {
+	subject, body, err := domain.RenderOrderNotification(template, order)
+	if err != nil {
+		return err
+	}
+
+	message := services.NotificationMessage{
+		Phone:   order.Customer.Phone,
+		Email:   order.Customer.Email,
+		Subject: subject,
+		Body:    body,
+	}
+
+	var lastErr error
+	for _, sender := range uc.senders {
+		recipient := sender.Recipient(message)
+		if recipient == "" {
+			continue
+		}
+
+		notification, err := uc.repo.Create(ctx, domain.Notification{
+			CustomerId: order.Customer.Id,
+			OrderId:    &order.Id,
+			Template:   template,
+			Channel:    sender.Channel(),
+			Recipient:  recipient,
+			Subject:    &subject,
+			Body:       body,
+			Status:     domain.NotificationStatusPending,
+			JobId:      jobId,
+		})
+		if err != nil {
+			return fmt.Errorf("error recording notification: %v", err)
+		}
+
+		providerMessageId, err := sender.Send(ctx, recipient, message)
+		if err != nil {
+			lastErr = err
+			errorMessage := err.Error()
+			if updateErr := uc.repo.UpdateStatus(ctx, notification.Id, domain.NotificationStatusFailed, nil, &errorMessage); updateErr != nil {
+				return fmt.Errorf("error updating notification status: %v", updateErr)
+			}
+			logger.FromContext(ctx).Warn(fmt.Sprintf("Failed to send %s notification %s for order %s, trying next channel: %v", sender.Channel(), notification.Id, order.Id, err))
+			continue
+		}
+
+		var providerId *string
+		if providerMessageId != "" {
+			providerId = &providerMessageId
+		}
+		// A mensagem já saiu: falhar aqui faria o envio ser repetido, então o erro só é registrado
+		if err := uc.repo.UpdateStatus(ctx, notification.Id, domain.NotificationStatusSent, providerId, nil); err != nil {
+			logger.FromContext(ctx).Error(fmt.Sprintf("Error marking notification %s as sent", notification.Id), err)
+		}
+		return nil
+	}
+
+	if lastErr != nil {
+		return fmt.Errorf("error sending %s notification for order %s: %v", template, order.Id, lastErr)
+	}
+	return domain.NewNoNotificationChannelError(order.Customer.Id)
+}
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/domain/services"
+)
+
+type mockNotificationRepository struct {
+	created     []domain.Notification
+	statuses    map[string]string
+	undelivered []domain.Notification
+}
+
+func (m *mockNotificationRepository) Create(ctx context.Context, notification domain.Notification) (*domain.Notification, error) {
+	notification.Id = notification.Channel
+	m.created = append(m.created, notification)
+	return &notification, nil
+}
+
+func (m *mockNotificationRepository) UpdateStatus(ctx context.Context, id string, status string, providerMessageId *string, errorMessage *string) error {
+	m.statuses[id] = status
+	return nil
+}
+
+func (m *mockNotificationRepository) FindByOrderId(ctx context.Context, orderId string) ([]domain.Notification, error) {
+	return m.created, nil
+}
+
+func (m *mockNotificationRepository) ExistsForOrder(ctx context.Context, orderId string, template string) (bool, error) {
+	for _, notification := range m.created {
+		if notification.OrderId != nil && *notification.OrderId == orderId && notification.Template == template {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockNotificationRepository) FindByJobId(ctx context.Context, jobId string) ([]domain.Notification, error) {
+	notifications := []domain.Notification{}
+	for _, notification := range m.created {
+		if notification.JobId != nil && *notification.JobId == jobId {
+			notification.Status = m.statuses[notification.Id]
+			notifications = append(notifications, notification)
+		}
+	}
+	return notifications, nil
+}
+
+func (m *mockNotificationRepository) FindUndelivered(ctx context.Context, since time.Time, orderId string) ([]domain.Notification, error) {
+	notifications := []domain.Notification{}
+	for _, notification := range m.undelivered {
+		if orderId == "" || *notification.OrderId == orderId {
+			notifications = append(notifications, notification)
+		}
+	}
+	return notifications, nil
+}
+
+type mockNotificationSender struct {
+	channel   string
+	recipient string
+	err       error
+	sent      []string
+}
+
+func (m *mockNotificationSender) Channel() string {
+	return m.channel
+}
+
+func (m *mockNotificationSender) Recipient(message services.NotificationMessage) string {
+	return m.recipient
+}
+
+func (m *mockNotificationSender) Send(ctx context.Context, recipient string, message services.NotificationMessage) (string, error) {
+	m.sent = append(m.sent, message.Body)
+	return "provider-id", m.err
+}
+
+func TestNotificationUseCase_NotifyOrder(t *testing.T) {
+	order := domain.Order{
+		Id:         "order-1",
+		Number:     "42",
+		PickupDate: time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC),
+		Customer:   domain.Customer{Id: "customer-1", Name: "João", Phone: "+5511999990000"},
+	}
+
+	t.Run("should fall back to the next channel when sending fails", func(t *testing.T) {
+		repo := &mockNotificationRepository{statuses: map[string]string{}}
+		whatsapp := &mockNotificationSender{channel: "whatsapp", recipient: "5511999990000", err: errors.New("unavailable")}
+		sms := &mockNotificationSender{channel: "sms", recipient: "+5511999990000"}
+		useCase := NewNotificationUseCase(repo, []services.NotificationSender{whatsapp, sms})
+
+		if err := useCase.NotifyOrder(context.Background(), domain.NotificationOrderReady, order); err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+
+		if repo.statuses["whatsapp"] != domain.NotificationStatusFailed {
+			t.Errorf("expected whatsapp notification to be failed, but got %s", repo.statuses["whatsapp"])
+		}
+		if repo.statuses["sms"] != domain.NotificationStatusSent {
+			t.Errorf("expected sms notification to be sent, but got %s", repo.statuses["sms"])
+		}
+		if len(sms.sent) != 1 || !strings.Contains(sms.sent[0], "pedido nº 42 está pronto") {
+			t.Errorf("unexpected message %v", sms.sent)
+		}
+	})
+
+	t.Run("should skip channels without recipient", func(t *testing.T) {
+		repo := &mockNotificationRepository{statuses: map[string]string{}}
+		email := &mockNotificationSender{channel: "email"}
+		useCase := NewNotificationUseCase(repo, []services.NotificationSender{email})
+
+		err := useCase.NotifyOrder(context.Background(), domain.NotificationOrderConfirmation, order)
+		var noChannelErr *domain.NoNotificationChannelError
+		if !errors.As(err, &noChannelErr) {
+			t.Fatalf("expected no notification channel error, but got %v", err)
+		}
+		if len(repo.created) != 0 {
+			t.Errorf("expected no notification to be recorded, but got %d", len(repo.created))
+		}
+	})
+}
+
+func TestNotificationUseCase_NotifyOrderForJob(t *testing.T) {
+	order := domain.Order{
+		Id:         "order-1",
+		Number:     "42",
+		PickupDate: time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC),
+		Customer:   domain.Customer{Id: "customer-1", Name: "João", Phone: "+5511999990000"},
+	}
+
+	t.Run("should not send again when a previous run of the job already sent the message", func(t *testing.T) {
+		repo := &mockNotificationRepository{statuses: map[string]string{}}
+		sms := &mockNotificationSender{channel: "sms", recipient: "+5511999990000"}
+		useCase := NewNotificationUseCase(repo, []services.NotificationSender{sms})
+
+		for range 2 {
+			if err := useCase.NotifyOrderForJob(context.Background(), "job-1", domain.NotificationOrderReady, order); err != nil {
+				t.Fatalf("expected no error, but got %v", err)
+			}
+		}
+
+		if len(sms.sent) != 1 {
+			t.Errorf("expected the message to be sent once, but got %d", len(sms.sent))
+		}
+	})
+
+	t.Run("should try again when every previous attempt of the job failed", func(t *testing.T) {
+		repo := &mockNotificationRepository{statuses: map[string]string{}}
+		sms := &mockNotificationSender{channel: "sms", recipient: "+5511999990000", err: errors.New("unavailable")}
+		useCase := NewNotificationUseCase(repo, []services.NotificationSender{sms})
+
+		if err := useCase.NotifyOrderForJob(context.Background(), "job-1", domain.NotificationOrderReady, order); err == nil {
+			t.Fatal("expected an error, but got nil")
+		}
+
+		sms.err = nil
+		if err := useCase.NotifyOrderForJob(context.Background(), "job-1", domain.NotificationOrderReady, order); err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if len(sms.sent) != 2 || repo.statuses["sms"] != domain.NotificationStatusSent {
+			t.Errorf("expected the retry to send the message, but got %d sends and status %s", len(sms.sent), repo.statuses["sms"])
+		}
+	})
+}
@@ -1,10 +1,13 @@
 package usecase
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/deividr/zion-api/internal/domain"
+	"github.com/google/uuid"
 )
 
 type CreateOrderInput struct {
@@ -39,8 +42,11 @@ func NewOrderUseCase(repo domain.OrderRepository, addressRepo domain.AddressRepo
 	return &OrderUseCase{repo: repo, addressRepo: addressRepo, customerRepo: customerRepo}
 }
 
-func (uc *OrderUseCase) GetAll(pagination domain.Pagination, filters domain.FindAllOrderFilters) ([]domain.Order, domain.Pagination, error) {
-	orders, pagination, err := uc.repo.FindAll(pagination, filters)
+func (uc *OrderUseCase) GetAll(ctx context.Context, pagination domain.Pagination, filters domain.FindAllOrderFilters) ([]domain.Order, domain.Pagination, error) {
+	ctx, span := startSpan(ctx, "OrderUseCase.GetAll")
+	defer span.End()
+
+	orders, pagination, err := uc.repo.FindAll(ctx, pagination, filters)
 	if err != nil {
 		return []domain.Order{}, domain.Pagination{}, fmt.Errorf("error fetching orders: %v", err)
 	}
@@ -48,15 +54,21 @@ func (uc *OrderUseCase) GetAll(pagination domain.Pagination, filters domain.Find
 	return orders, pagination, nil
 }
 
-func (uc *OrderUseCase) GetById(id string) (*domain.Order, error) {
-	order, err := uc.repo.FindById(id)
+func (uc *OrderUseCase) GetById(ctx context.Context, id string) (*domain.Order, error) {
+	ctx, span := startSpan(ctx, "OrderUseCase.GetById")
+	defer span.End()
+
+	order, err := uc.repo.FindById(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching order by id: %v", err)
 	}
 	return order, nil
 }
 
-func (uc *OrderUseCase) Update(input UpdateOrderInput) error {
+func (uc *OrderUseCase) Update(ctx context.Context, input UpdateOrderInput) error {
+	ctx, span := startSpan(ctx, "OrderUseCase.Update")
+	defer span.End()
+
 	order := domain.Order{
 		Id:           input.Id,
 		PickupDate:   input.PickupDate,
@@ -68,54 +80,132 @@ func (uc *OrderUseCase) Update(input UpdateOrderInput) error {
 	}
 
 	if input.AddressId != nil {
-		address, err := uc.addressRepo.FindById(*input.AddressId)
+		address, err := uc.addressRepo.FindById(ctx, *input.AddressId)
 		if err != nil {
 			return fmt.Errorf("address not found: %v", err)
 		}
 		order.SetAddress(address)
 	}
 
-	if err := uc.repo.Update(order); err != nil {
+	if err := uc.repo.Update(ctx, order, []domain.NewBackgroundJob{orderWebhookJob(order.Id, domain.WebhookOrderUpdated)}); err != nil {
 		return fmt.Errorf("error updating order: %v", err)
 	}
 	return nil
 }
 
-func (uc *OrderUseCase) Delete(id string) error {
-	if err := uc.repo.Delete(id); err != nil {
+func (uc *OrderUseCase) UpdateStatus(ctx context.Context, id string, status string) (*domain.Order, error) {
+	ctx, span := startSpan(ctx, "OrderUseCase.UpdateStatus")
+	defer span.End()
+
+	if !domain.IsValidOrderStatus(status) {
+		return nil, domain.NewInvalidOrderStatusError(status)
+	}
+
+	// A mudança é decidida pelo UPDATE condicional, e não por uma leitura anterior, para que só
+	// uma de duas chamadas concorrentes publique a notificação e o webhook
+	if err := uc.repo.UpdateStatus(ctx, id, status, orderStatusJobs(id, status)); err != nil {
+		var notFoundErr *domain.OrderNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error updating order status: %v", err)
+	}
+
+	order, err := uc.repo.FindById(ctx, id)
+	if err != nil {
+		var notFoundErr *domain.OrderNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error fetching order: %v", err)
+	}
+	return order, nil
+}
+
+func (uc *OrderUseCase) Delete(ctx context.Context, id string) error {
+	ctx, span := startSpan(ctx, "OrderUseCase.Delete")
+	defer span.End()
+
+	// O pedido excluído não é mais encontrado na publicação, então o evento já leva o id
+	job, err := webhookJob(domain.WebhookOrderDeleted, map[string]string{"id": id})
+	if err != nil {
+		return err
+	}
+
+	if err := uc.repo.Delete(ctx, id, []domain.NewBackgroundJob{job}); err != nil {
+		var notFoundErr *domain.OrderNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return err
+		}
 		return fmt.Errorf("error deleting order: %v", err)
 	}
 	return nil
 }
 
-func (uc *OrderUseCase) Create(input CreateOrderInput) (*domain.Order, error) {
-	customer, err := uc.customerRepo.FindById(input.CustomerId)
+func (uc *OrderUseCase) Create(ctx context.Context, input CreateOrderInput) (*domain.Order, error) {
+	ctx, span := startSpan(ctx, "OrderUseCase.Create")
+	defer span.End()
+
+	customer, err := uc.customerRepo.FindById(ctx, input.CustomerId)
 	if err != nil {
 		return nil, fmt.Errorf("customer not found: %v", err)
 	}
 
 	order := domain.Order{
+		Id:           uuid.New().String(),
 		PickupDate:   input.PickupDate,
+		Status:       domain.OrderStatusPending,
 		Customer:     *customer,
 		Employee:     input.Employee,
 		OrderLocal:   input.OrderLocal,
 		Observations: input.Observations,
-		IsPickedUp:   input.IsPickedUp,
 		Products:     input.Products,
 	}
+	order.SetPickedUp(input.IsPickedUp != nil && *input.IsPickedUp)
 
 	if input.AddressId != nil {
-		address, err := uc.addressRepo.FindById(*input.AddressId)
+		address, err := uc.addressRepo.FindById(ctx, *input.AddressId)
 		if err != nil {
 			return nil, fmt.Errorf("address not found: %v", err)
 		}
 		order.SetAddress(address)
 	}
 
-	createdOrder, err := uc.repo.Create(order)
+	// A confirmação e o webhook são gravados no outbox na mesma transação do pedido,
+	// então não se perdem se o processo cair logo após o commit.
+	createdOrder, err := uc.repo.Create(ctx, order, []domain.NewBackgroundJob{
+		orderNotificationJob(order.Id, domain.NotificationOrderConfirmation),
+		orderWebhookJob(order.Id, domain.WebhookOrderCreated),
+	})
 	if err != nil {
 		return nil, fmt.Errorf("error creating order: %v", err)
 	}
 
 	return createdOrder, nil
 }
+
+// orderStatusJobs são os eventos e as notificações da mudança de status do pedido.
+func orderStatusJobs(orderId string, status string) []domain.NewBackgroundJob {
+	jobs := []domain.NewBackgroundJob{orderWebhookJob(orderId, domain.WebhookOrderStatusChanged)}
+	switch status {
+	case domain.OrderStatusReady:
+		jobs = append(jobs, orderNotificationJob(orderId, domain.NotificationOrderReady))
+	case domain.OrderStatusCancelled:
+		jobs = append(jobs, orderNotificationJob(orderId, domain.NotificationOrderCancelled))
+	}
+	return jobs
+}
+
+func orderNotificationJob(orderId string, template string) domain.NewBackgroundJob {
+	return domain.NewBackgroundJob{
+		Kind:    domain.BackgroundJobOrderNotification,
+		Payload: domain.OrderNotificationJob{OrderId: orderId, Template: template},
+	}
+}
+
+func orderWebhookJob(orderId string, eventType string) domain.NewBackgroundJob {
+	return domain.NewBackgroundJob{
+		Kind:    domain.BackgroundJobWebhookPublish,
+		Payload: domain.WebhookPublishJob{EventType: eventType, OrderId: orderId},
+	}
+}
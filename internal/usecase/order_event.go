@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"context"
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/domain/services"
+)
+
+type OrderEventUseCase struct {
+	subscriber services.OrderEventSubscriber
+}
+
+func NewOrderEventUseCase(subscriber services.OrderEventSubscriber) *OrderEventUseCase {
+	return &OrderEventUseCase{subscriber: subscriber}
+}
+
+func (uc *OrderEventUseCase) Subscribe(ctx context.Context, filter domain.OrderEventFilter) (<-chan domain.OrderEvent, func(), error) {
+	ctx, span := startSpan(ctx, "OrderEventUseCase.Subscribe")
+	defer span.End()
+
+	for _, status := range filter.Statuses {
+		if !domain.IsValidOrderStatus(status) {
+			return nil, nil, domain.NewInvalidOrderStatusError(status)
+		}
+	}
+
+	events, unsubscribe := uc.subscriber.Subscribe(filter)
+	return events, unsubscribe, nil
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+// WebhookEnqueuer agenda as entregas de um evento para os webhooks assinantes.
+type WebhookEnqueuer interface {
+	Enqueue(ctx context.Context, eventId string, eventType string, data any) error
+}
+
+// JobNotifier envia a notificação de um job do outbox sem repetir os envios de tentativas anteriores.
+type JobNotifier interface {
+	NotifyOrderForJob(ctx context.Context, jobId string, template string, order domain.Order) error
+}
+
+// OrderJobHandler executa os jobs do outbox gerados pelos pedidos.
+type OrderJobHandler struct {
+	orderRepo domain.OrderRepository
+	notifier  JobNotifier
+	webhooks  WebhookEnqueuer
+}
+
+func NewOrderJobHandler(orderRepo domain.OrderRepository, notifier JobNotifier, webhooks WebhookEnqueuer) *OrderJobHandler {
+	return &OrderJobHandler{orderRepo: orderRepo, notifier: notifier, webhooks: webhooks}
+}
+
+func (h *OrderJobHandler) HandleNotification(ctx context.Context, backgroundJob domain.BackgroundJob) error {
+	ctx, span := startSpan(ctx, "OrderJobHandler.HandleNotification")
+	defer span.End()
+
+	var job domain.OrderNotificationJob
+	if err := json.Unmarshal(backgroundJob.Payload, &job); err != nil {
+		return domain.NewPermanentJobError(fmt.Errorf("invalid order notification payload: %v", err))
+	}
+
+	order, err := h.orderRepo.FindById(ctx, job.OrderId)
+	if err != nil {
+		return fmt.Errorf("error fetching order %s: %v", job.OrderId, err)
+	}
+
+	err = h.notifier.NotifyOrderForJob(ctx, backgroundJob.Id, job.Template, *order)
+	var noChannelErr *domain.NoNotificationChannelError
+	if errors.As(err, &noChannelErr) {
+		return domain.NewPermanentJobError(err)
+	}
+	return err
+}
+
+// HandleWebhookPublish agenda as entregas do evento. Eventos de pedido são montados com o
+// pedido atual; os demais já trazem os dados no payload.
+func (h *OrderJobHandler) HandleWebhookPublish(ctx context.Context, backgroundJob domain.BackgroundJob) error {
+	ctx, span := startSpan(ctx, "OrderJobHandler.HandleWebhookPublish")
+	defer span.End()
+
+	var job domain.WebhookPublishJob
+	if err := json.Unmarshal(backgroundJob.Payload, &job); err != nil {
+		return domain.NewPermanentJobError(fmt.Errorf("invalid webhook publish payload: %v", err))
+	}
+
+	var data any = job.Data
+	if job.OrderId != "" {
+		order, err := h.orderRepo.FindById(ctx, job.OrderId)
+		if err != nil {
+			return fmt.Errorf("error fetching order %s: %v", job.OrderId, err)
+		}
+		data = order
+	}
+
+	// O id do job é o id do evento: repetir o job não gera um evento novo para os assinantes
+	return h.webhooks.Enqueue(ctx, backgroundJob.Id, job.EventType, data)
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+// mockOrderUseCaseRepository guarda só os pedidos ativos: o excluído sai do mapa e não é mais
+// encontrado, como no UPDATE condicional do repositório.
+type mockOrderUseCaseRepository struct {
+	domain.OrderRepository
+	orders map[string]domain.Order
+	jobs   []domain.NewBackgroundJob
+}
+
+func (m *mockOrderUseCaseRepository) Delete(ctx context.Context, id string, jobs []domain.NewBackgroundJob) error {
+	if _, ok := m.orders[id]; !ok {
+		return domain.NewOrderNotFoundError(id)
+	}
+	delete(m.orders, id)
+	m.jobs = append(m.jobs, jobs...)
+	return nil
+}
+
+func (m *mockOrderUseCaseRepository) FindById(ctx context.Context, id string) (*domain.Order, error) {
+	order, ok := m.orders[id]
+	if !ok {
+		return nil, domain.NewOrderNotFoundError(id)
+	}
+	return &order, nil
+}
+
+// UpdateStatus só grava os jobs quando o status muda, como o UPDATE ... WHERE status <> $1.
+func (m *mockOrderUseCaseRepository) UpdateStatus(ctx context.Context, id string, status string, jobs []domain.NewBackgroundJob) error {
+	order, ok := m.orders[id]
+	if !ok {
+		return domain.NewOrderNotFoundError(id)
+	}
+	if order.Status == status {
+		return nil
+	}
+	order.Status = status
+	m.orders[id] = order
+	m.jobs = append(m.jobs, jobs...)
+	return nil
+}
+
+func TestOrderUseCase_UpdateStatus(t *testing.T) {
+	t.Run("should publish the status change only for the call that changed it", func(t *testing.T) {
+		repo := &mockOrderUseCaseRepository{orders: map[string]domain.Order{"order-1": {Id: "order-1", Status: domain.OrderStatusPending}}}
+		uc := NewOrderUseCase(repo, nil, nil)
+
+		for range 2 {
+			order, err := uc.UpdateStatus(context.Background(), "order-1", domain.OrderStatusReady)
+			if err != nil {
+				t.Fatalf("expected no error, but got %v", err)
+			}
+			if order.Status != domain.OrderStatusReady {
+				t.Errorf("expected the order to be ready, but got %s", order.Status)
+			}
+		}
+		if events := webhookEvents(repo.jobs); !slices.Equal(events, []string{domain.WebhookOrderStatusChanged}) {
+			t.Errorf("expected a single order.status_changed event, but got %v", events)
+		}
+		if len(repo.jobs) != 2 {
+			t.Errorf("expected the webhook and the ready notification once, but got %d jobs", len(repo.jobs))
+		}
+	})
+
+	t.Run("should return not found for unknown orders", func(t *testing.T) {
+		uc := NewOrderUseCase(&mockOrderUseCaseRepository{orders: map[string]domain.Order{}}, nil, nil)
+
+		_, err := uc.UpdateStatus(context.Background(), "missing", domain.OrderStatusReady)
+		var notFoundErr *domain.OrderNotFoundError
+		if !errors.As(err, &notFoundErr) {
+			t.Errorf("expected OrderNotFoundError, but got %v", err)
+		}
+	})
+}
+
+func TestOrderUseCase_Delete(t *testing.T) {
+	t.Run("should publish order.deleted only once", func(t *testing.T) {
+		repo := &mockOrderUseCaseRepository{orders: map[string]domain.Order{"order-1": {Id: "order-1"}}}
+		uc := NewOrderUseCase(repo, nil, nil)
+
+		if err := uc.Delete(context.Background(), "order-1"); err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		err := uc.Delete(context.Background(), "order-1")
+		var notFoundErr *domain.OrderNotFoundError
+		if !errors.As(err, &notFoundErr) {
+			t.Errorf("expected OrderNotFoundError deleting twice, but got %v", err)
+		}
+		if events := webhookEvents(repo.jobs); !slices.Equal(events, []string{domain.WebhookOrderDeleted}) {
+			t.Errorf("expected a single order.deleted event, but got %v", events)
+		}
+	})
+
+	t.Run("should return not found for unknown orders", func(t *testing.T) {
+		uc := NewOrderUseCase(&mockOrderUseCaseRepository{orders: map[string]domain.Order{}}, nil, nil)
+
+		err := uc.Delete(context.Background(), "missing")
+		var notFoundErr *domain.OrderNotFoundError
+		if !errors.As(err, &notFoundErr) {
+			t.Errorf("expected OrderNotFoundError, but got %v", err)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/domain/services"
+)
+
+// OrderStatusUpdater altera o status do pedido disparando as notificações correspondentes.
+type OrderStatusUpdater interface {
+	UpdateStatus(ctx context.Context, id string, status string) (*domain.Order, error)
+}
+
+type PickupUseCase struct {
+	repo     domain.OrderRepository
+	orders   OrderStatusUpdater
+	renderer services.BarcodeRenderer
+}
+
+func NewPickupUseCase(repo domain.OrderRepository, orders OrderStatusUpdater, renderer services.BarcodeRenderer) *PickupUseCase {
+	return &PickupUseCase{repo: repo, orders: orders, renderer: renderer}
+}
+
+// Scan localiza o pedido pelo código lido no balcão e o marca como retirado.
+func (uc *PickupUseCase) Scan(ctx context.Context, code string) (*domain.Order, error) {
+	ctx, span := startSpan(ctx, "PickupUseCase.Scan")
+	defer span.End()
+
+	order, err := uc.repo.FindByPickupCode(ctx, domain.NormalizePickupCode(code))
+	if err != nil {
+		var notFoundErr *domain.PickupCodeNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error fetching order by pickup code: %v", err)
+	}
+
+	if !domain.OrderCanBePickedUp(order.Status) {
+		return nil, domain.NewOrderNotPickableError(order.Number, order.Status)
+	}
+
+	updated, err := uc.orders.UpdateStatus(ctx, order.Id, domain.OrderStatusPickedUp)
+	if err != nil {
+		return nil, fmt.Errorf("error marking order as picked up: %v", err)
+	}
+	return updated, nil
+}
+
+// GetCodeImage gera a imagem do código de retirada do pedido.
+func (uc *PickupUseCase) GetCodeImage(ctx context.Context, orderId string, format string, size int) ([]byte, error) {
+	ctx, span := startSpan(ctx, "PickupUseCase.GetCodeImage")
+	defer span.End()
+
+	order, err := uc.repo.FindById(ctx, orderId)
+	if err != nil {
+		var notFoundErr *domain.OrderNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error fetching order: %v", err)
+	}
+
+	image, err := uc.renderer.RenderPNG(order.PickupCode, format, size)
+	if err != nil {
+		var formatErr *domain.InvalidPickupCodeFormatError
+		if errors.As(err, &formatErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error rendering pickup code: %v", err)
+	}
+	return image, nil
+}
@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+// OrderNotifier avisa o cliente sobre mudanças no pedido.
+type OrderNotifier interface {
+	NotifyOrder(ctx context.Context, template string, order domain.Order) error
+}
+
+const PickupReminderJobName = "pickup_reminder"
+
+const pickupReminderPageSize = 100
+
+type PickupReminderUseCase struct {
+	orderRepo        domain.OrderRepository
+	notificationRepo domain.NotificationRepository
+	notifier         OrderNotifier
+	now              func() time.Time
+}
+
+func NewPickupReminderUseCase(orderRepo domain.OrderRepository, notificationRepo domain.NotificationRepository, notifier OrderNotifier) *PickupReminderUseCase {
+	return &PickupReminderUseCase{
+		orderRepo:        orderRepo,
+		notificationRepo: notificationRepo,
+		notifier:         notifier,
+		now:              time.Now,
+	}
+}
+
+// Run envia o lembrete para todos os pedidos com retirada no dia seguinte que ainda não
+// foram retirados nem cancelados. Pedidos que já receberam o lembrete são ignorados, então
+// executar o job mais de uma vez no mesmo dia não duplica mensagens.
+func (uc *PickupReminderUseCase) Run(ctx context.Context) (string, error) {
+	ctx, span := startSpan(ctx, "PickupReminderUseCase.Run")
+	defer span.End()
+
+	start, end := pickupReminderWindow(uc.now())
+
+	var sent, skipped, failed int
+	pagination := domain.Pagination{Page: 1, Limit: pickupReminderPageSize}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		orders, result, err := uc.orderRepo.FindAll(ctx, pagination, domain.FindAllOrderFilters{PickupDateStart: start, PickupDateEnd: end})
+		if err != nil {
+			return "", fmt.Errorf("erro ao buscar pedidos para lembrete: %v", err)
+		}
+
+		for _, order := range orders {
+			if order.Status != domain.OrderStatusPending && order.Status != domain.OrderStatusReady {
+				skipped++
+				continue
+			}
+
+			exists, err := uc.notificationRepo.ExistsForOrder(ctx, order.Id, domain.NotificationPickupReminder)
+			if err != nil {
+				return "", fmt.Errorf("erro ao verificar lembrete do pedido %s: %v", order.Id, err)
+			}
+			if exists {
+				skipped++
+				continue
+			}
+
+			if err := uc.notifier.NotifyOrder(ctx, domain.NotificationPickupReminder, order); err != nil {
+				failed++
+				continue
+			}
+			sent++
+		}
+
+		if len(orders) == 0 || pagination.Page*pagination.Limit >= result.Total {
+			break
+		}
+		pagination.Page++
+	}
+
+	summary := fmt.Sprintf("%d lembretes enviados, %d ignorados, %d falharam", sent, skipped, failed)
+	if failed > 0 {
+		return summary, fmt.Errorf("falha ao enviar %d lembretes", failed)
+	}
+	return summary, nil
+}
+
+// pickupReminderWindow devolve o intervalo que cobre o dia seguinte no fuso da loja.
+func pickupReminderWindow(now time.Time) (time.Time, time.Time) {
+	local := now.In(domain.StoreLocation)
+	start := time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, domain.StoreLocation)
+	end := start.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	return start, end
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+type mockOrderRepository struct {
+	domain.OrderRepository
+	orders  []domain.Order
+	filters domain.FindAllOrderFilters
+}
+
+func (m *mockOrderRepository) FindAll(ctx context.Context, pagination domain.Pagination, filters domain.FindAllOrderFilters) ([]domain.Order, domain.Pagination, error) {
+	m.filters = filters
+	start := (pagination.Page - 1) * pagination.Limit
+	if start >= len(m.orders) {
+		return []domain.Order{}, domain.Pagination{Page: pagination.Page, Limit: pagination.Limit, Total: len(m.orders)}, nil
+	}
+	end := min(start+pagination.Limit, len(m.orders))
+	return m.orders[start:end], domain.Pagination{Page: pagination.Page, Limit: pagination.Limit, Total: len(m.orders)}, nil
+}
+
+func (m *mockOrderRepository) FindById(ctx context.Context, id string) (*domain.Order, error) {
+	for _, order := range m.orders {
+		if order.Id == id {
+			return &order, nil
+		}
+	}
+	return nil, fmt.Errorf("order %s not found", id)
+}
+
+type mockOrderNotifier struct {
+	notified []string
+}
+
+func (m *mockOrderNotifier) NotifyOrder(ctx context.Context, template string, order domain.Order) error {
+	m.notified = append(m.notified, order.Id)
+	return nil
+}
+
+func TestPickupReminderWindow(t *testing.T) {
+	t.Run("should cover the whole next day in the store timezone", func(t *testing.T) {
+		// 23:30 em São Paulo já é o dia seguinte em UTC
+		now := time.Date(2026, 10, 20, 2, 30, 0, 0, time.UTC)
+
+		start, end := pickupReminderWindow(now)
+
+		expectedStart := time.Date(2026, 10, 20, 0, 0, 0, 0, domain.StoreLocation)
+		if !start.Equal(expectedStart) {
+			t.Errorf("expected start %v, but got %v", expectedStart, start)
+		}
+		if !end.Before(expectedStart.AddDate(0, 0, 1)) || end.Before(expectedStart.Add(23*time.Hour)) {
+			t.Errorf("expected end at the end of %v, but got %v", expectedStart, end)
+		}
+	})
+}
+
+func TestPickupReminderUseCase_Run(t *testing.T) {
+	t.Run("should remind only open orders without a previous reminder", func(t *testing.T) {
+		remindedOrderId := "order-3"
+		orderRepo := &mockOrderRepository{orders: []domain.Order{
+			{Id: "order-1", Status: domain.OrderStatusPending},
+			{Id: "order-2", Status: domain.OrderStatusCancelled},
+			{Id: remindedOrderId, Status: domain.OrderStatusReady},
+			{Id: "order-4", Status: domain.OrderStatusReady},
+		}}
+		notificationRepo := &mockNotificationRepository{
+			statuses: map[string]string{},
+			created:  []domain.Notification{{OrderId: &remindedOrderId, Template: domain.NotificationPickupReminder}},
+		}
+		notifier := &mockOrderNotifier{}
+
+		uc := NewPickupReminderUseCase(orderRepo, notificationRepo, notifier)
+		uc.now = func() time.Time { return time.Date(2026, 10, 19, 13, 0, 0, 0, time.UTC) }
+
+		summary, err := uc.Run(context.Background())
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+
+		if len(notifier.notified) != 2 || notifier.notified[0] != "order-1" || notifier.notified[1] != "order-4" {
+			t.Errorf("expected orders order-1 and order-4 to be reminded, but got %v", notifier.notified)
+		}
+		if summary != "2 lembretes enviados, 2 ignorados, 0 falharam" {
+			t.Errorf("expected summary of 2 sent and 2 skipped, but got %q", summary)
+		}
+		if orderRepo.filters.PickupDateStart.Day() != 20 {
+			t.Errorf("expected orders of day 20, but got %v", orderRepo.filters.PickupDateStart)
+		}
+	})
+
+	t.Run("should walk through every page of orders", func(t *testing.T) {
+		orders := make([]domain.Order, pickupReminderPageSize+5)
+		for i := range orders {
+			orders[i] = domain.Order{Id: time.Duration(i).String(), Status: domain.OrderStatusPending}
+		}
+		notifier := &mockOrderNotifier{}
+
+		uc := NewPickupReminderUseCase(&mockOrderRepository{orders: orders}, &mockNotificationRepository{statuses: map[string]string{}}, notifier)
+
+		if _, err := uc.Run(context.Background()); err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if len(notifier.notified) != len(orders) {
+			t.Errorf("expected %d reminders, but got %d", len(orders), len(notifier.notified))
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+type mockPickupOrderRepository struct {
+	domain.OrderRepository
+	orders map[string]domain.Order
+}
+
+func (m *mockPickupOrderRepository) FindById(ctx context.Context, id string) (*domain.Order, error) {
+	for _, order := range m.orders {
+		if order.Id == id {
+			return &order, nil
+		}
+	}
+	return nil, domain.NewOrderNotFoundError(id)
+}
+
+func (m *mockPickupOrderRepository) FindByPickupCode(ctx context.Context, code string) (*domain.Order, error) {
+	order, ok := m.orders[code]
+	if !ok {
+		return nil, domain.NewPickupCodeNotFoundError(code)
+	}
+	return &order, nil
+}
+
+type mockOrderStatusUpdater struct {
+	updates map[string]string
+}
+
+func (m *mockOrderStatusUpdater) UpdateStatus(ctx context.Context, id string, status string) (*domain.Order, error) {
+	m.updates[id] = status
+	return &domain.Order{Id: id, Status: status}, nil
+}
+
+func TestPickupUseCase_Scan(t *testing.T) {
+	repo := &mockPickupOrderRepository{orders: map[string]domain.Order{
+		"AB3K7X9Q": {Id: "order-1", Number: "42", Status: domain.OrderStatusReady},
+		"ZZ3K7X9Q": {Id: "order-2", Number: "43", Status: domain.OrderStatusPickedUp},
+	}}
+
+	t.Run("should mark the order as picked up using the normalized code", func(t *testing.T) {
+		orders := &mockOrderStatusUpdater{updates: map[string]string{}}
+		useCase := NewPickupUseCase(repo, orders, nil)
+
+		order, err := useCase.Scan(context.Background(), "ab3k-7x9q")
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if orders.updates["order-1"] != domain.OrderStatusPickedUp {
+			t.Errorf("expected order to be picked up, but got %q", orders.updates["order-1"])
+		}
+		if order.Status != domain.OrderStatusPickedUp {
+			t.Errorf("expected returned status picked_up, but got %s", order.Status)
+		}
+	})
+
+	t.Run("should refuse orders already picked up", func(t *testing.T) {
+		orders := &mockOrderStatusUpdater{updates: map[string]string{}}
+		useCase := NewPickupUseCase(repo, orders, nil)
+
+		_, err := useCase.Scan(context.Background(), "ZZ3K7X9Q")
+		var notPickableErr *domain.OrderNotPickableError
+		if !errors.As(err, &notPickableErr) {
+			t.Fatalf("expected OrderNotPickableError, but got %v", err)
+		}
+		if len(orders.updates) != 0 {
+			t.Errorf("expected no status update, but got %v", orders.updates)
+		}
+	})
+
+	t.Run("should return not found for unknown codes", func(t *testing.T) {
+		useCase := NewPickupUseCase(repo, &mockOrderStatusUpdater{updates: map[string]string{}}, nil)
+
+		_, err := useCase.Scan(context.Background(), "NOPE")
+		var notFoundErr *domain.PickupCodeNotFoundError
+		if !errors.As(err, &notFoundErr) {
+			t.Errorf("expected PickupCodeNotFoundError, but got %v", err)
+		}
+	})
+}
+
+func TestPickupUseCase_GetCodeImage(t *testing.T) {
+	t.Run("should return not found for unknown orders", func(t *testing.T) {
+		useCase := NewPickupUseCase(&mockPickupOrderRepository{orders: map[string]domain.Order{}}, nil, nil)
+
+		_, err := useCase.GetCodeImage(context.Background(), "order-1", domain.PickupCodeFormatQR, 0)
+		var notFoundErr *domain.OrderNotFoundError
+		if !errors.As(err, &notFoundErr) {
+			t.Errorf("expected OrderNotFoundError, but got %v", err)
+		}
+	})
+}
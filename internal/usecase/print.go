@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/domain/services"
+)
+
+const (
+	// printJobLease é o tempo que o agente tem para confirmar a impressão antes do job voltar à fila.
+	printJobLease        = 2 * time.Minute
+	printJobPollInterval = time.Second
+	PrintJobMaxWait      = 30 * time.Second
+	// PrintJobMaxClaim limita os jobs entregues ao agente em uma reserva.
+	PrintJobMaxClaim = 20
+)
+
+type PrintUseCase struct {
+	repo     domain.PrintJobRepository
+	orders   domain.OrderRepository
+	notes    domain.CustomerNoteRepository
+	kitchen  domain.KitchenRepository
+	renderer services.ReceiptRenderer
+	now      func() time.Time
+}
+
+func NewPrintUseCase(
+	repo domain.PrintJobRepository,
+	orders domain.OrderRepository,
+	notes domain.CustomerNoteRepository,
+	kitchen domain.KitchenRepository,
+	renderer services.ReceiptRenderer,
+) *PrintUseCase {
+	return &PrintUseCase{repo: repo, orders: orders, notes: notes, kitchen: kitchen, renderer: renderer, now: time.Now}
+}
+
+func (uc *PrintUseCase) GetAll(ctx context.Context, filters domain.PrintJobFilters, pagination domain.Pagination) ([]domain.PrintJob, domain.Pagination, error) {
+	ctx, span := startSpan(ctx, "PrintUseCase.GetAll")
+	defer span.End()
+
+	jobs, pagination, err := uc.repo.FindAll(ctx, filters, pagination)
+	if err != nil {
+		return nil, domain.Pagination{}, fmt.Errorf("error fetching print jobs: %v", err)
+	}
+	return jobs, pagination, nil
+}
+
+func (uc *PrintUseCase) GetById(ctx context.Context, id string) (*domain.PrintJob, error) {
+	ctx, span := startSpan(ctx, "PrintUseCase.GetById")
+	defer span.End()
+
+	return uc.repo.FindById(ctx, id)
+}
+
+// PrintOrder enfileira o comprovante (balcão) ou o ticket de produção (cozinha) do pedido.
+// Sem impressora informada é usada a impressora padrão do tipo de documento.
+func (uc *PrintUseCase) PrintOrder(ctx context.Context, orderId string, kind string, printer string, userId string) (*domain.PrintJob, error) {
+	ctx, span := startSpan(ctx, "PrintUseCase.PrintOrder")
+	defer span.End()
+
+	if kind == "" {
+		kind = domain.PrintJobOrderReceipt
+	}
+	if kind != domain.PrintJobOrderReceipt && kind != domain.PrintJobKitchenTicket {
+		return nil, domain.NewInvalidPrintJobError(fmt.Sprintf("kind %q cannot be printed for an order", kind))
+	}
+
+	printer, err := resolvePrinter(kind, printer)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := uc.orders.FindById(ctx, orderId)
+	if err != nil {
+		var notFoundErr *domain.OrderNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error fetching order: %v", err)
+	}
+
+	var payload []byte
+	var description string
+	switch kind {
+	case domain.PrintJobKitchenTicket:
+		notes, err := uc.notes.FindByCustomerId(ctx, order.Customer.Id)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching customer notes: %v", err)
+		}
+		order.CustomerAlerts = domain.BuildCustomerAlerts(order.Customer, notes)
+
+		payload, err = uc.renderer.RenderKitchenTicket(*order)
+		description = fmt.Sprintf("Ticket de produção do pedido nº %s", order.Number)
+		if err != nil {
+			return nil, fmt.Errorf("error rendering kitchen ticket: %v", err)
+		}
+	default:
+		payload, err = uc.renderer.RenderOrderReceipt(*order)
+		description = fmt.Sprintf("Comprovante do pedido nº %s", order.Number)
+		if err != nil {
+			return nil, fmt.Errorf("error rendering order receipt: %v", err)
+		}
+	}
+
+	return uc.create(ctx, domain.PrintJob{
+		Printer:     printer,
+		Kind:        kind,
+		OrderId:     &order.Id,
+		Description: description,
+		Payload:     payload,
+		RequestedBy: userId,
+	})
+}
+
+// PrintProductionList enfileira a lista de produção do dia (hoje quando date é zero) com os
+// itens pendentes agrupados em lotes.
+func (uc *PrintUseCase) PrintProductionList(ctx context.Context, date time.Time, printer string, userId string) (*domain.PrintJob, error) {
+	ctx, span := startSpan(ctx, "PrintUseCase.PrintProductionList")
+	defer span.End()
+
+	printer, err := resolvePrinter(domain.PrintJobProductionList, printer)
+	if err != nil {
+		return nil, err
+	}
+
+	if date.IsZero() {
+		date = uc.now()
+	}
+	local := date.In(domain.StoreLocation)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, domain.StoreLocation)
+
+	tickets, err := uc.kitchen.FindTickets(ctx, domain.KitchenFilters{
+		PickupDateStart: start,
+		PickupDateEnd:   start.AddDate(0, 0, 1).Add(-time.Nanosecond),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error fetching kitchen tickets: %v", err)
+	}
+
+	payload, err := uc.renderer.RenderProductionList(start, domain.GroupKitchenTickets(tickets))
+	if err != nil {
+		return nil, fmt.Errorf("error rendering production list: %v", err)
+	}
+
+	return uc.create(ctx, domain.PrintJob{
+		Printer:     printer,
+		Kind:        domain.PrintJobProductionList,
+		Description: "Lista de produção de " + start.Format("02/01/2006"),
+		Payload:     payload,
+		RequestedBy: userId,
+	})
+}
+
+// Reprint enfileira novamente o mesmo documento, opcionalmente em outra impressora.
+func (uc *PrintUseCase) Reprint(ctx context.Context, id string, printer string, userId string) (*domain.PrintJob, error) {
+	ctx, span := startSpan(ctx, "PrintUseCase.Reprint")
+	defer span.End()
+
+	original, err := uc.repo.FindById(ctx, id)
+	if err != nil {
+		var notFoundErr *domain.PrintJobNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error fetching print job: %v", err)
+	}
+
+	if printer == "" {
+		printer = original.Printer
+	}
+	if !domain.IsValidPrinter(printer) {
+		return nil, domain.NewInvalidPrintJobError(fmt.Sprintf("unknown printer %q", printer))
+	}
+
+	return uc.create(ctx, domain.PrintJob{
+		Printer:     printer,
+		Kind:        original.Kind,
+		OrderId:     original.OrderId,
+		Description: original.Description,
+		Payload:     original.Payload,
+		ReprintOf:   &original.Id,
+		RequestedBy: userId,
+	})
+}
+
+// Claim entrega ao agente os próximos jobs da impressora. Com wait maior que zero a chamada
+// aguarda (long polling) até surgir um job, o prazo acabar ou o agente desconectar.
+func (uc *PrintUseCase) Claim(ctx context.Context, printer string, agentId string, limit int, wait time.Duration) ([]domain.PrintJob, error) {
+	ctx, span := startSpan(ctx, "PrintUseCase.Claim")
+	defer span.End()
+
+	if !domain.IsValidPrinter(printer) {
+		return nil, domain.NewInvalidPrintJobError(fmt.Sprintf("unknown printer %q", printer))
+	}
+	if wait > PrintJobMaxWait {
+		wait = PrintJobMaxWait
+	}
+	limit = min(max(limit, 1), PrintJobMaxClaim)
+
+	deadline := time.NewTimer(wait)
+	defer deadline.Stop()
+
+	for {
+		jobs, err := uc.repo.Claim(ctx, printer, agentId, limit, printJobLease)
+		if err != nil {
+			return nil, fmt.Errorf("error claiming print jobs: %v", err)
+		}
+		if len(jobs) > 0 || wait <= 0 {
+			return jobs, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return jobs, nil
+		case <-deadline.C:
+			return jobs, nil
+		case <-time.After(printJobPollInterval):
+		}
+	}
+}
+
+// Ack registra o resultado informado pelo agente que reservou o job. Jobs com falha ficam
+// visíveis para reimpressão.
+func (uc *PrintUseCase) Ack(ctx context.Context, id string, agentId string, printed bool, errorMessage string) error {
+	ctx, span := startSpan(ctx, "PrintUseCase.Ack")
+	defer span.End()
+
+	if printed {
+		return uc.repo.MarkPrinted(ctx, id, agentId)
+	}
+	if errorMessage == "" {
+		errorMessage = "print failed"
+	}
+	return uc.repo.MarkFailed(ctx, id, agentId, errorMessage)
+}
+
+func (uc *PrintUseCase) create(ctx context.Context, job domain.PrintJob) (*domain.PrintJob, error) {
+	created, err := uc.repo.Create(ctx, job)
+	if err != nil {
+		return nil, fmt.Errorf("error creating print job: %v", err)
+	}
+	return created, nil
+}
+
+func resolvePrinter(kind string, printer string) (string, error) {
+	if printer == "" {
+		printer = domain.DefaultPrinterFor(kind)
+	}
+	if !domain.IsValidPrinter(printer) {
+		return "", domain.NewInvalidPrintJobError(fmt.Sprintf("unknown printer %q", printer))
+	}
+	return printer, nil
+}
@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+type mockPrintJobRepository struct {
+	domain.PrintJobRepository
+	jobs        map[string]domain.PrintJob
+	claimedBy   map[string]string
+	claimLimits []int
+}
+
+func (m *mockPrintJobRepository) Claim(ctx context.Context, printer string, agentId string, limit int, lease time.Duration) ([]domain.PrintJob, error) {
+	m.claimLimits = append(m.claimLimits, limit)
+	return []domain.PrintJob{}, nil
+}
+
+func (m *mockPrintJobRepository) Create(ctx context.Context, job domain.PrintJob) (*domain.PrintJob, error) {
+	job.Id = fmt.Sprintf("job-%d", len(m.jobs)+1)
+	job.Status = domain.PrintJobPending
+	m.jobs[job.Id] = job
+	return &job, nil
+}
+
+// MarkPrinted e MarkFailed aceitam o ack só do agente em claimedBy, como o UPDATE condicional do repositório
+func (m *mockPrintJobRepository) MarkPrinted(ctx context.Context, id string, agentId string) error {
+	return m.ack(id, agentId, domain.PrintJobPrinted)
+}
+
+func (m *mockPrintJobRepository) MarkFailed(ctx context.Context, id string, agentId string, errorMessage string) error {
+	return m.ack(id, agentId, domain.PrintJobFailed)
+}
+
+func (m *mockPrintJobRepository) ack(id string, agentId string, status string) error {
+	job, ok := m.jobs[id]
+	if !ok {
+		return domain.NewPrintJobNotFoundError(id)
+	}
+	if job.Status != domain.PrintJobPrinting || m.claimedBy[id] != agentId {
+		return domain.NewPrintJobNotClaimedError(id, agentId)
+	}
+	job.Status = status
+	m.jobs[id] = job
+	return nil
+}
+
+func (m *mockPrintJobRepository) FindById(ctx context.Context, id string) (*domain.PrintJob, error) {
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, domain.NewPrintJobNotFoundError(id)
+	}
+	return &job, nil
+}
+
+type mockPrintOrderRepository struct {
+	domain.OrderRepository
+}
+
+func (m *mockPrintOrderRepository) FindById(ctx context.Context, id string) (*domain.Order, error) {
+	if id == "missing" {
+		return nil, domain.NewOrderNotFoundError(id)
+	}
+	return &domain.Order{Id: id, Number: "42", Customer: domain.Customer{Id: "customer-1"}}, nil
+}
+
+type mockReceiptRenderer struct{}
+
+func (m *mockReceiptRenderer) RenderOrderReceipt(order domain.Order) ([]byte, error) {
+	return []byte("receipt " + order.Number), nil
+}
+
+func (m *mockReceiptRenderer) RenderKitchenTicket(order domain.Order) ([]byte, error) {
+	return []byte("ticket " + order.Number), nil
+}
+
+func (m *mockReceiptRenderer) RenderProductionList(date time.Time, batches []domain.KitchenBatch) ([]byte, error) {
+	return []byte("production"), nil
+}
+
+func TestPrintUseCase(t *testing.T) {
+	newUseCase := func() (*PrintUseCase, *mockPrintJobRepository) {
+		repo := &mockPrintJobRepository{jobs: map[string]domain.PrintJob{}}
+		return NewPrintUseCase(repo, &mockPrintOrderRepository{}, nil, nil, &mockReceiptRenderer{}), repo
+	}
+
+	t.Run("should send the order receipt to the counter printer by default", func(t *testing.T) {
+		useCase, _ := newUseCase()
+
+		job, err := useCase.PrintOrder(context.Background(), "order-1", "", "", "user-1")
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if job.Printer != domain.PrinterCounter || job.Kind != domain.PrintJobOrderReceipt {
+			t.Errorf("expected counter receipt, but got %s %s", job.Printer, job.Kind)
+		}
+		if string(job.Payload) != "receipt 42" {
+			t.Errorf("expected rendered receipt payload, but got %q", job.Payload)
+		}
+	})
+
+	t.Run("should reject unknown printers", func(t *testing.T) {
+		useCase, _ := newUseCase()
+
+		_, err := useCase.PrintOrder(context.Background(), "order-1", domain.PrintJobOrderReceipt, "bar", "user-1")
+		var invalidErr *domain.InvalidPrintJobError
+		if !errors.As(err, &invalidErr) {
+			t.Errorf("expected InvalidPrintJobError, but got %v", err)
+		}
+	})
+
+	t.Run("should return not found for unknown orders", func(t *testing.T) {
+		useCase, repo := newUseCase()
+
+		_, err := useCase.PrintOrder(context.Background(), "missing", "", "", "user-1")
+		var notFoundErr *domain.OrderNotFoundError
+		if !errors.As(err, &notFoundErr) {
+			t.Errorf("expected OrderNotFoundError, but got %v", err)
+		}
+		if len(repo.jobs) != 0 {
+			t.Errorf("expected no print job, but got %v", repo.jobs)
+		}
+	})
+
+	t.Run("should reprint the same payload on another printer", func(t *testing.T) {
+		useCase, repo := newUseCase()
+		original, err := useCase.PrintOrder(context.Background(), "order-1", "", "", "user-1")
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+
+		reprint, err := useCase.Reprint(context.Background(), original.Id, domain.PrinterKitchen, "user-2")
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if reprint.ReprintOf == nil || *reprint.ReprintOf != original.Id {
+			t.Errorf("expected reprint of %s, but got %v", original.Id, reprint.ReprintOf)
+		}
+		if reprint.Printer != domain.PrinterKitchen {
+			t.Errorf("expected kitchen printer, but got %s", reprint.Printer)
+		}
+		if string(repo.jobs[reprint.Id].Payload) != "receipt 42" {
+			t.Errorf("expected original payload, but got %q", repo.jobs[reprint.Id].Payload)
+		}
+	})
+
+	t.Run("should clamp the number of jobs claimed at once", func(t *testing.T) {
+		useCase, repo := newUseCase()
+
+		for _, limit := range []int{500, 0} {
+			if _, err := useCase.Claim(context.Background(), domain.PrinterCounter, "agent-1", limit, 0); err != nil {
+				t.Fatalf("expected no error, but got %v", err)
+			}
+		}
+		if repo.claimLimits[0] != PrintJobMaxClaim || repo.claimLimits[1] != 1 {
+			t.Errorf("expected limits %d and 1, but got %v", PrintJobMaxClaim, repo.claimLimits)
+		}
+	})
+	t.Run("should only accept the ack of the agent holding the claim", func(t *testing.T) {
+		useCase, repo := newUseCase()
+		repo.jobs["job-1"] = domain.PrintJob{Id: "job-1", Status: domain.PrintJobPrinting}
+		repo.jobs["job-2"] = domain.PrintJob{Id: "job-2", Status: domain.PrintJobPending}
+		repo.claimedBy = map[string]string{"job-1": "agent-2"}
+
+		var notClaimedErr *domain.PrintJobNotClaimedError
+		if err := useCase.Ack(context.Background(), "job-1", "agent-1", false, "paper jam"); !errors.As(err, &notClaimedErr) {
+			t.Errorf("expected PrintJobNotClaimedError for a late ack, but got %v", err)
+		}
+		if err := useCase.Ack(context.Background(), "job-2", "agent-1", true, ""); !errors.As(err, &notClaimedErr) {
+			t.Errorf("expected PrintJobNotClaimedError for a pending job, but got %v", err)
+		}
+		if err := useCase.Ack(context.Background(), "job-1", "agent-2", true, ""); err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if repo.jobs["job-1"].Status != domain.PrintJobPrinted || repo.jobs["job-2"].Status != domain.PrintJobPending {
+			t.Errorf("expected only job-1 to be printed, but got %v", repo.jobs)
+		}
+	})
+}
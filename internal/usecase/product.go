@@ -1,9 +1,11 @@
 package usecase
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/deividr/zion-api/internal/domain"
+	"github.com/google/uuid"
 )
 
 type ProductUseCase struct {
@@ -14,8 +16,11 @@ func NewProductUseCase(repo domain.ProductRepository) *ProductUseCase {
 	return &ProductUseCase{repo: repo}
 }
 
-func (uc *ProductUseCase) GetAll(filters domain.FindAllProductFilters) ([]domain.Product, error) {
-	products, err := uc.repo.FindAll(filters)
+func (uc *ProductUseCase) GetAll(ctx context.Context, filters domain.FindAllProductFilters) ([]domain.Product, error) {
+	ctx, span := startSpan(ctx, "ProductUseCase.GetAll")
+	defer span.End()
+
+	products, err := uc.repo.FindAll(ctx, filters)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao buscar produtos: %v", err)
 	}
@@ -23,32 +28,69 @@ func (uc *ProductUseCase) GetAll(filters domain.FindAllProductFilters) ([]domain
 	return products, nil
 }
 
-func (uc *ProductUseCase) GetById(id string) (*domain.Product, error) {
-	product, err := uc.repo.FindById(id)
+func (uc *ProductUseCase) GetById(ctx context.Context, id string) (*domain.Product, error) {
+	ctx, span := startSpan(ctx, "ProductUseCase.GetById")
+	defer span.End()
+
+	product, err := uc.repo.FindById(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao buscar produto: %v", err)
 	}
 	return product, nil
 }
 
-func (uc *ProductUseCase) Update(product domain.Product) error {
-	err := uc.repo.Update(product)
+func (uc *ProductUseCase) Update(ctx context.Context, product domain.Product) error {
+	ctx, span := startSpan(ctx, "ProductUseCase.Update")
+	defer span.End()
+
+	job, err := webhookJob(domain.WebhookProductUpdated, product)
+	if err != nil {
+		return err
+	}
+
+	err = uc.repo.Update(ctx, product, []domain.NewBackgroundJob{job})
 	if err != nil {
 		return fmt.Errorf("erro ao atualizar produto: %v", err)
 	}
 	return nil
 }
 
-func (uc *ProductUseCase) Delete(id string) error {
-	err := uc.repo.Delete(id)
+func (uc *ProductUseCase) Delete(ctx context.Context, id string) error {
+	ctx, span := startSpan(ctx, "ProductUseCase.Delete")
+	defer span.End()
+
+	job, err := webhookJob(domain.WebhookProductDeleted, map[string]string{"id": id})
+	if err != nil {
+		return err
+	}
+
+	err = uc.repo.Delete(ctx, id, []domain.NewBackgroundJob{job})
 	if err != nil {
 		return fmt.Errorf("erro ao deletar produto: %v", err)
 	}
 	return nil
 }
 
-func (uc *ProductUseCase) Create(newProduct domain.NewProduct) (*domain.Product, error) {
-	createdProduct, err := uc.repo.Create(newProduct)
+func (uc *ProductUseCase) Create(ctx context.Context, newProduct domain.NewProduct) (*domain.Product, error) {
+	ctx, span := startSpan(ctx, "ProductUseCase.Create")
+	defer span.End()
+
+	// O id é gerado aqui para que o evento gravado no outbox já leve o produto completo
+	newProduct.Id = uuid.New().String()
+	job, err := webhookJob(domain.WebhookProductCreated, domain.Product{
+		Id:              newProduct.Id,
+		Name:            newProduct.Name,
+		Value:           newProduct.Value,
+		UnityType:       newProduct.UnityType,
+		CategoryId:      newProduct.CategoryId,
+		ImageUrl:        newProduct.ImageUrl,
+		IsVariablePrice: newProduct.IsVariablePrice,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	createdProduct, err := uc.repo.Create(ctx, newProduct, []domain.NewBackgroundJob{job})
 	if err != nil {
 		return nil, fmt.Errorf("erro ao criar produto: %v", err)
 	}
@@ -0,0 +1,284 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/domain/services"
+	"github.com/google/uuid"
+)
+
+const (
+	productImportName            = "name"
+	productImportValue           = "value"
+	productImportUnityType       = "unityType"
+	productImportCategory        = "category"
+	productImportIsVariablePrice = "isVariablePrice"
+)
+
+var productImportAliases = map[string][]string{
+	productImportName:            {"name", "nome", "produto", "product"},
+	productImportValue:           {"value", "valor", "preco", "price"},
+	productImportUnityType:       {"unitytype", "unidade", "unity", "unit"},
+	productImportCategory:        {"category", "categoria", "categoryid"},
+	productImportIsVariablePrice: {"isvariableprice", "variableprice", "precovariavel"},
+}
+
+// ProductImportUseCase importa a tabela de preços mantida em planilha. Produtos com o mesmo
+// nome, sem diferença de acentos e maiúsculas, são atualizados em vez de duplicados.
+type ProductImportUseCase struct {
+	reader     services.SpreadsheetReader
+	repo       domain.ProductImportRepository
+	products   domain.ProductRepository
+	categories domain.CategoryProductRepository
+}
+
+func NewProductImportUseCase(
+	reader services.SpreadsheetReader,
+	repo domain.ProductImportRepository,
+	products domain.ProductRepository,
+	categories domain.CategoryProductRepository,
+) *ProductImportUseCase {
+	return &ProductImportUseCase{reader: reader, repo: repo, products: products, categories: categories}
+}
+
+// Import valida a planilha e devolve o que mudaria. Com confirm, grava tudo em uma transação,
+// desde que nenhuma linha seja inválida; senão devolve a prévia junto com
+// domain.InvalidImportRowsError.
+func (uc *ProductImportUseCase) Import(ctx context.Context, data []byte, confirm bool) (*domain.ImportPreview[domain.Product], error) {
+	ctx, span := startSpan(ctx, "ProductImportUseCase.Import")
+	defer span.End()
+
+	sheet, err := uc.reader.Read(data)
+	if err != nil {
+		return nil, err
+	}
+	columns, rows, err := readImportSheet(sheet, productImportAliases, productImportName)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := uc.products.FindAll(ctx, domain.FindAllProductFilters{})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar produtos: %v", err)
+	}
+	categories, err := uc.categories.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar categorias: %v", err)
+	}
+
+	byName := map[string][]domain.Product{}
+	for _, product := range products {
+		key := domain.ImportKey(product.Name)
+		byName[key] = append(byName[key], product)
+	}
+
+	preview := &domain.ImportPreview[domain.Product]{IgnoredColumns: columns.ignored, Rows: make([]domain.ImportRow[domain.Product], 0, len(rows))}
+	seen := map[string]int{}
+	for _, sheetRow := range rows {
+		row := domain.ImportRow[domain.Product]{Row: sheetRow.Number, Changes: []string{}, Errors: []domain.ImportRowError{}}
+		addError := func(column string, message string) {
+			row.Errors = append(row.Errors, domain.ImportRowError{Column: column, Message: message})
+		}
+
+		name := strings.Join(strings.Fields(columns.value(sheetRow, productImportName)), " ")
+		key := domain.ImportKey(name)
+		switch matches := byName[key]; {
+		case name == "":
+			addError(productImportName, "name is required")
+		case seen[key] > 0:
+			addError(productImportName, fmt.Sprintf("product already listed in row %d", seen[key]))
+		case len(matches) > 1:
+			addError(productImportName, fmt.Sprintf("%d products are already named %q, remove the duplicates first", len(matches), matches[0].Name))
+		case len(matches) == 1:
+			row.Record = matches[0]
+			row.Id = &matches[0].Id
+		}
+		if key != "" && seen[key] == 0 {
+			seen[key] = sheetRow.Number
+		}
+
+		current := row.Record
+		row.Record.Name = name
+
+		if value := columns.value(sheetRow, productImportValue); value != "" {
+			price, err := parseImportPrice(value)
+			if err != nil {
+				addError(productImportValue, err.Error())
+			} else {
+				row.Record.Value = price
+			}
+		} else if row.Id == nil {
+			addError(productImportValue, "value is required for new products")
+		}
+
+		if unityType := strings.ToUpper(columns.value(sheetRow, productImportUnityType)); unityType != "" {
+			// unity_type é char(2)
+			if len(unityType) > 2 {
+				addError(productImportUnityType, fmt.Sprintf("unknown unity type %q, use up to two letters like UN or KG", unityType))
+			} else {
+				row.Record.UnityType = unityType
+			}
+		} else if row.Id == nil {
+			addError(productImportUnityType, "unity type is required for new products")
+		}
+
+		if category := columns.value(sheetRow, productImportCategory); category != "" {
+			categoryId, err := findImportCategory(categories, category)
+			if err != nil {
+				addError(productImportCategory, err.Error())
+			} else {
+				row.Record.CategoryId = categoryId
+			}
+		} else if row.Id == nil {
+			addError(productImportCategory, "category is required for new products")
+		}
+
+		if value := columns.value(sheetRow, productImportIsVariablePrice); value != "" {
+			isVariablePrice, err := parseImportBool(value)
+			if err != nil {
+				addError(productImportIsVariablePrice, err.Error())
+			} else {
+				row.Record.IsVariablePrice = isVariablePrice
+			}
+		}
+
+		if row.Id != nil {
+			// O nome da planilha pode corrigir maiúsculas e acentos do cadastro
+			if row.Record.Name != current.Name {
+				row.Changes = append(row.Changes, productImportName)
+			}
+			if row.Record.Value != current.Value {
+				row.Changes = append(row.Changes, productImportValue)
+			}
+			if row.Record.UnityType != current.UnityType {
+				row.Changes = append(row.Changes, productImportUnityType)
+			}
+			if row.Record.CategoryId != current.CategoryId {
+				row.Changes = append(row.Changes, productImportCategory)
+			}
+			if row.Record.IsVariablePrice != current.IsVariablePrice {
+				row.Changes = append(row.Changes, productImportIsVariablePrice)
+			}
+		}
+		importAction(&row)
+		preview.Rows = append(preview.Rows, row)
+	}
+	preview.Summary = summarizeImport(preview.Rows)
+
+	if !confirm {
+		return preview, nil
+	}
+	if preview.Summary.Invalid > 0 {
+		return preview, domain.NewInvalidImportRowsError(preview.Summary.Invalid)
+	}
+
+	// Os ids dos novos produtos são gerados aqui para que os eventos gravados no outbox, na
+	// mesma transação da importação, já levem os produtos completos
+	creates := []domain.NewProduct{}
+	updates := []domain.Product{}
+	jobs := []domain.NewBackgroundJob{}
+	for i, row := range preview.Rows {
+		eventType := domain.WebhookProductUpdated
+		switch row.Action {
+		case domain.ImportActionCreate:
+			id := uuid.New().String()
+			preview.Rows[i].Id = &id
+			preview.Rows[i].Record.Id = id
+			creates = append(creates, domain.NewProduct{
+				Id:              id,
+				Name:            row.Record.Name,
+				Value:           row.Record.Value,
+				UnityType:       row.Record.UnityType,
+				CategoryId:      row.Record.CategoryId,
+				IsVariablePrice: row.Record.IsVariablePrice,
+			})
+			eventType = domain.WebhookProductCreated
+		case domain.ImportActionUpdate:
+			updates = append(updates, row.Record)
+		default:
+			continue
+		}
+
+		job, err := webhookJob(eventType, preview.Rows[i].Record)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	if err := uc.repo.Import(ctx, creates, updates, jobs); err != nil {
+		var deletedErr *domain.ImportRowDeletedError
+		if errors.As(err, &deletedErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("erro ao importar produtos: %v", err)
+	}
+
+	preview.Applied = true
+	return preview, nil
+}
+
+// findImportCategory aceita o id ou o nome da categoria.
+func findImportCategory(categories []domain.CategoryProduct, value string) (string, error) {
+	key := domain.ImportKey(value)
+	for _, category := range categories {
+		if category.Id == value || domain.ImportKey(category.Name) == key {
+			return category.Id, nil
+		}
+	}
+	return "", fmt.Errorf("category %q not found", value)
+}
+
+// parseImportPrice converte o preço em reais para centavos. Aceita "R$ 1.234,56", "1234,56" e o
+// número que o Excel grava ("1234.56"): com os dois separadores, o último é o decimal. Com só
+// um, repetido ele separa milhares ("1.500.000") e uma única vez ele é o decimal, a não ser que
+// venha seguido de três dígitos: "1.500" tanto pode ser mil e quinhentos quanto 1,5, então é
+// recusado em vez de gravar um preço mil vezes menor.
+func parseImportPrice(value string) (uint32, error) {
+	raw := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(value), "R$"))
+	normalized := raw
+	comma, dot := strings.LastIndex(raw, ","), strings.LastIndex(raw, ".")
+	switch {
+	case comma >= 0 && dot >= 0:
+		thousands := "."
+		if dot > comma {
+			thousands = ","
+		}
+		normalized = strings.ReplaceAll(raw, thousands, "")
+	case comma >= 0 || dot >= 0:
+		separator := ","
+		if dot >= 0 {
+			separator = "."
+		}
+		parts := strings.Split(raw, separator)
+		if len(parts) > 2 {
+			for _, group := range parts[1:] {
+				if len(group) != 3 {
+					return 0, fmt.Errorf("invalid price %q", value)
+				}
+			}
+			normalized = strings.Join(parts, "")
+		} else if len(parts[1]) == 3 && strings.TrimLeft(parts[0], "0") != "" {
+			return 0, fmt.Errorf("ambiguous price %q, write the cents as in %s%s00", value, strings.ReplaceAll(raw, separator, ""), separator)
+		}
+	}
+	normalized = strings.ReplaceAll(normalized, ",", ".")
+
+	price, err := strconv.ParseFloat(normalized, 64)
+	if err != nil || math.IsNaN(price) || math.IsInf(price, 0) {
+		return 0, fmt.Errorf("invalid price %q", value)
+	}
+	if price < 0 {
+		return 0, errors.New("price cannot be negative")
+	}
+	cents := math.Round(price * 100)
+	if cents > math.MaxInt32 {
+		return 0, fmt.Errorf("price %q is too high", value)
+	}
+	return uint32(cents), nil
+}
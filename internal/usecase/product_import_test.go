@@ -0,0 +1,226 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+type mockSpreadsheetReader struct {
+	rows [][]string
+}
+
+func (m *mockSpreadsheetReader) Read(data []byte) ([]domain.SpreadsheetRow, error) {
+	rows := make([]domain.SpreadsheetRow, len(m.rows))
+	for i, cells := range m.rows {
+		rows[i] = domain.SpreadsheetRow{Number: i + 1, Cells: cells}
+	}
+	return rows, nil
+}
+
+// webhookEvents devolve os tipos dos eventos que os jobs do outbox vão publicar.
+func webhookEvents(jobs []domain.NewBackgroundJob) []string {
+	events := []string{}
+	for _, job := range jobs {
+		if payload, ok := job.Payload.(domain.WebhookPublishJob); ok {
+			events = append(events, payload.EventType)
+		}
+	}
+	return events
+}
+
+type mockProductImportRepository struct {
+	domain.ProductRepository
+	products []domain.Product
+	creates  []domain.NewProduct
+	updates  []domain.Product
+	jobs     []domain.NewBackgroundJob
+	err      error
+}
+
+func (m *mockProductImportRepository) FindAll(ctx context.Context, filters domain.FindAllProductFilters) ([]domain.Product, error) {
+	return m.products, nil
+}
+
+func (m *mockProductImportRepository) Import(ctx context.Context, creates []domain.NewProduct, updates []domain.Product, jobs []domain.NewBackgroundJob) error {
+	m.creates = creates
+	m.updates = updates
+	m.jobs = jobs
+	return m.err
+}
+
+type mockCategoryProductRepository struct {
+	domain.CategoryProductRepository
+	categories []domain.CategoryProduct
+}
+
+func (m *mockCategoryProductRepository) FindAll(ctx context.Context) ([]domain.CategoryProduct, error) {
+	return m.categories, nil
+}
+
+func newProductImportUseCase(rows [][]string) (*ProductImportUseCase, *mockProductImportRepository) {
+	repo := &mockProductImportRepository{products: []domain.Product{
+		{Id: "p1", Name: "Lasanha à Bolonhesa", Value: 4000, UnityType: "UN", CategoryId: "c1"},
+		{Id: "p2", Name: "Nhoque", Value: 5590, UnityType: "KG", CategoryId: "c1"},
+		{Id: "p3", Name: "Molho", Value: 1000, UnityType: "UN", CategoryId: "c1"},
+		{Id: "p4", Name: "molho ", Value: 1200, UnityType: "UN", CategoryId: "c1"},
+	}}
+	categories := &mockCategoryProductRepository{categories: []domain.CategoryProduct{{Id: "c1", Name: "Massas"}, {Id: "c2", Name: "Bebidas"}}}
+	return NewProductImportUseCase(&mockSpreadsheetReader{rows: rows}, repo, repo, categories), repo
+}
+
+func TestProductImportUseCase_Import(t *testing.T) {
+	t.Run("should match products by name and preview the changes without writing", func(t *testing.T) {
+		uc, repo := newProductImportUseCase([][]string{
+			{"Nome", "Preço", "Unidade", "Categoria", "Estoque"},
+			{"lasanha a bolonhesa", "R$ 42,50", "", ""},
+			{"Nhoque", "55.9", "kg", "massas"},
+			{"Coca-Cola 2L", "12,5", "UN", "Bebidas"},
+		})
+
+		preview, err := uc.Import(context.Background(), nil, false)
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+
+		lasanha := preview.Rows[0]
+		if lasanha.Action != domain.ImportActionUpdate || *lasanha.Id != "p1" || lasanha.Record.Value != 4250 || lasanha.Record.UnityType != "UN" {
+			t.Errorf("expected lasanha to be updated to 4250 keeping the unity type, but got %+v", lasanha)
+		}
+		if !slices.Equal(lasanha.Changes, []string{productImportName, productImportValue}) {
+			t.Errorf("expected name and value to change, but got %v", lasanha.Changes)
+		}
+		if preview.Rows[1].Action != domain.ImportActionUnchanged {
+			t.Errorf("expected nhoque to be unchanged, but got %+v", preview.Rows[1])
+		}
+		if coca := preview.Rows[2]; coca.Action != domain.ImportActionCreate || coca.Record.Value != 1250 || coca.Record.CategoryId != "c2" {
+			t.Errorf("expected coca-cola to be created, but got %+v", coca)
+		}
+		if preview.Summary != (domain.ImportSummary{Rows: 3, Create: 1, Update: 1, Unchanged: 1}) {
+			t.Errorf("unexpected summary %+v", preview.Summary)
+		}
+		if !slices.Equal(preview.IgnoredColumns, []string{"Estoque"}) {
+			t.Errorf("expected the stock column to be ignored, but got %v", preview.IgnoredColumns)
+		}
+		if preview.Applied || repo.creates != nil {
+			t.Errorf("expected the preview not to be applied")
+		}
+	})
+
+	t.Run("should report every invalid row and refuse to apply them", func(t *testing.T) {
+		uc, repo := newProductImportUseCase([][]string{
+			{"name", "value", "unityType", "category"},
+			{"Rondelli", "abc", "UNID", "Sobremesas"},
+			{"Nhoque", "60", "KG", "Massas"},
+			{"NHOQUE", "61", "KG", "Massas"},
+			{"Molho", "10", "UN", "Massas"},
+		})
+
+		preview, err := uc.Import(context.Background(), nil, true)
+		var invalidRows *domain.InvalidImportRowsError
+		if !errors.As(err, &invalidRows) || invalidRows.Invalid != 3 {
+			t.Fatalf("expected 3 invalid rows, but got %v", err)
+		}
+
+		if errs := preview.Rows[0].Errors; len(errs) != 3 || errs[0].Column != productImportValue || errs[1].Column != productImportUnityType || errs[2].Column != productImportCategory {
+			t.Errorf("expected value, unity type and category errors, but got %+v", errs)
+		}
+		if errs := preview.Rows[2].Errors; len(errs) == 0 || errs[0].Message != "product already listed in row 3" {
+			t.Errorf("expected the repeated product to be reported, but got %+v", errs)
+		}
+		if errs := preview.Rows[3].Errors; len(errs) != 1 || errs[0].Column != productImportName {
+			t.Errorf("expected the ambiguous product to be reported, but got %+v", errs)
+		}
+		if repo.creates != nil || preview.Applied {
+			t.Errorf("expected nothing to be applied")
+		}
+	})
+
+	t.Run("should apply creates and updates and publish their events", func(t *testing.T) {
+		uc, repo := newProductImportUseCase([][]string{
+			{"nome", "valor", "unidade", "categoria", "preço variável"},
+			{"Nhoque", "58", "", "", "sim"},
+			{"Suco de uva", "9,90", "UN", "c2", ""},
+		})
+
+		preview, err := uc.Import(context.Background(), nil, true)
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+
+		if len(repo.updates) != 1 || repo.updates[0].Value != 5800 || !repo.updates[0].IsVariablePrice {
+			t.Errorf("expected nhoque to be updated, but got %+v", repo.updates)
+		}
+		if len(repo.creates) != 1 || repo.creates[0].Value != 990 || repo.creates[0].CategoryId != "c2" {
+			t.Errorf("expected suco de uva to be created, but got %+v", repo.creates)
+		}
+		if *preview.Rows[1].Id != repo.creates[0].Id || preview.Rows[1].Record.Id != repo.creates[0].Id || !preview.Applied {
+			t.Errorf("expected the created id in the preview, but got %+v", preview.Rows[1])
+		}
+		if events := webhookEvents(repo.jobs); !slices.Equal(events, []string{domain.WebhookProductUpdated, domain.WebhookProductCreated}) {
+			t.Errorf("unexpected events %v", events)
+		}
+	})
+
+	t.Run("should keep the error of a row deleted during the import", func(t *testing.T) {
+		uc, repo := newProductImportUseCase([][]string{{"nome", "valor"}, {"Nhoque", "58"}})
+		repo.err = domain.NewImportRowDeletedError("Nhoque")
+
+		_, err := uc.Import(context.Background(), nil, true)
+		var deletedErr *domain.ImportRowDeletedError
+		if !errors.As(err, &deletedErr) {
+			t.Errorf("expected an import row deleted error, but got %v", err)
+		}
+	})
+
+	t.Run("should reject a spreadsheet without the name column", func(t *testing.T) {
+		uc, _ := newProductImportUseCase([][]string{{"valor"}, {"10"}})
+
+		_, err := uc.Import(context.Background(), nil, false)
+		var invalid *domain.InvalidSpreadsheetError
+		if !errors.As(err, &invalid) {
+			t.Errorf("expected an invalid spreadsheet error, but got %v", err)
+		}
+	})
+}
+
+func TestParseImportPrice(t *testing.T) {
+	for input, expected := range map[string]uint32{
+		"40":          4000,
+		"R$ 1.234,56": 123456,
+		"1,234.56":    123456,
+		"12,5":        1250,
+		"55.9":        5590,
+		"0.1":         10,
+		"0,500":       50,
+		"1.500.000":   150000000,
+		"1.500,00":    150000,
+	} {
+		t.Run("should parse "+input, func(t *testing.T) {
+			price, err := parseImportPrice(input)
+			if err != nil || price != expected {
+				t.Errorf("expected %d, but got %d (%v)", expected, price, err)
+			}
+		})
+	}
+
+	t.Run("should reject a single separator followed by three digits", func(t *testing.T) {
+		for _, input := range []string{"1.500", "1,500", "R$ 12.345"} {
+			if _, err := parseImportPrice(input); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+				t.Errorf("expected %q to be rejected as ambiguous, but got %v", input, err)
+			}
+		}
+	})
+
+	t.Run("should reject negative and invalid prices", func(t *testing.T) {
+		for _, input := range []string{"-1", "abc", "1.2.3,4,5", "1.2.3"} {
+			if _, err := parseImportPrice(input); err == nil {
+				t.Errorf("expected %q to be rejected", input)
+			}
+		}
+	})
+}
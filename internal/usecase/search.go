@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/deividr/zion-api/internal/domain"
+)
+
+const defaultSearchLimit = 10
+
+type SearchUseCase struct {
+	repo domain.SearchRepository
+}
+
+func NewSearchUseCase(repo domain.SearchRepository) *SearchUseCase {
+	return &SearchUseCase{repo: repo}
+}
+
+func (uc *SearchUseCase) Search(ctx context.Context, term string, limit int) (*domain.SearchResult, error) {
+	ctx, span := startSpan(ctx, "SearchUseCase.Search")
+	defer span.End()
+
+	term = strings.TrimSpace(term)
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+
+	customers, err := uc.repo.SearchCustomers(ctx, term, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error searching customers: %v", err)
+	}
+
+	products, err := uc.repo.SearchProducts(ctx, term, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error searching products: %v", err)
+	}
+
+	orders, err := uc.repo.SearchOrders(ctx, term, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error searching orders: %v", err)
+	}
+
+	return &domain.SearchResult{Customers: customers, Products: products, Orders: orders}, nil
+}
@@ -0,0 +1,20 @@
+package usecase
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/deividr/zion-api/internal/usecase")
+
+// startSpan abre o span do método do caso de uso como filho do span da requisição ou do job.
+// Sem um trace em andamento (ex.: polling dos workers) nenhum span é criado, para não gerar
+// traces soltos a cada poucos segundos.
+func startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	if parent := trace.SpanFromContext(ctx); !parent.IsRecording() {
+		return ctx, parent
+	}
+	return tracer.Start(ctx, name)
+}
@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStartSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	repo := &mockPickupOrderRepository{orders: map[string]domain.Order{
+		"AB3K7X9Q": {Id: "order-1", Number: "42", Status: domain.OrderStatusReady},
+	}}
+	useCase := NewPickupUseCase(repo, &mockOrderStatusUpdater{updates: map[string]string{}}, nil)
+
+	t.Run("should not start a trace without a parent span", func(t *testing.T) {
+		if _, err := useCase.Scan(context.Background(), "AB3K7X9Q"); err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if spans := recorder.Ended(); len(spans) != 0 {
+			t.Errorf("expected no spans, but got %d", len(spans))
+		}
+	})
+
+	t.Run("should record the use case span as child of the request span", func(t *testing.T) {
+		ctx, parent := provider.Tracer("test").Start(context.Background(), "GET /orders/scan/:code")
+		if _, err := useCase.Scan(ctx, "AB3K7X9Q"); err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		parent.End()
+
+		spans := recorder.Ended()
+		if len(spans) != 2 || spans[0].Name() != "PickupUseCase.Scan" {
+			t.Fatalf("expected the use case span to end first, but got %v", spans)
+		}
+		if spans[0].Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("expected use case span to be child of the request span")
+		}
+	})
+}
@@ -0,0 +1,290 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/domain/services"
+)
+
+// webhookDeliveryLease é o tempo que um lote reservado fica fora da fila. As entregas são enviadas
+// em sequência, então o lease cobre o timeout de todas elas, com uma folga para gravar os resultados.
+func webhookDeliveryLease(limit int) time.Duration {
+	return time.Duration(limit)*domain.WebhookDeliveryTimeout + time.Minute
+}
+
+// webhookJob monta o job do outbox que publica o evento com os dados já conhecidos na escrita.
+func webhookJob(eventType string, data any) (domain.NewBackgroundJob, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return domain.NewBackgroundJob{}, fmt.Errorf("error encoding webhook event %s: %v", eventType, err)
+	}
+
+	return domain.NewBackgroundJob{
+		Kind:    domain.BackgroundJobWebhookPublish,
+		Payload: domain.WebhookPublishJob{EventType: eventType, Data: encoded},
+	}, nil
+}
+
+type WebhookUseCase struct {
+	subscriptions domain.WebhookSubscriptionRepository
+	deliveries    domain.WebhookDeliveryRepository
+	sender        services.WebhookSender
+	now           func() time.Time
+}
+
+func NewWebhookUseCase(subscriptions domain.WebhookSubscriptionRepository, deliveries domain.WebhookDeliveryRepository, sender services.WebhookSender) *WebhookUseCase {
+	return &WebhookUseCase{subscriptions: subscriptions, deliveries: deliveries, sender: sender, now: time.Now}
+}
+
+func (uc *WebhookUseCase) GetAll(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	ctx, span := startSpan(ctx, "WebhookUseCase.GetAll")
+	defer span.End()
+
+	subscriptions, err := uc.subscriptions.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching webhook subscriptions: %v", err)
+	}
+
+	// O segredo só é exibido ao consultar a assinatura individualmente.
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+
+	return subscriptions, nil
+}
+
+func (uc *WebhookUseCase) GetById(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	ctx, span := startSpan(ctx, "WebhookUseCase.GetById")
+	defer span.End()
+
+	subscription, err := uc.subscriptions.FindById(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching webhook subscription: %w", err)
+	}
+	return subscription, nil
+}
+
+func (uc *WebhookUseCase) Create(ctx context.Context, input domain.NewWebhookSubscription) (*domain.WebhookSubscription, error) {
+	ctx, span := startSpan(ctx, "WebhookUseCase.Create")
+	defer span.End()
+
+	input, err := domain.ValidateWebhookSubscription(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Secret == "" {
+		if input.Secret, err = generateWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	subscription, err := uc.subscriptions.Create(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("error creating webhook subscription: %v", err)
+	}
+	return subscription, nil
+}
+
+func (uc *WebhookUseCase) Update(ctx context.Context, id string, input domain.NewWebhookSubscription) (*domain.WebhookSubscription, error) {
+	ctx, span := startSpan(ctx, "WebhookUseCase.Update")
+	defer span.End()
+
+	input, err := domain.ValidateWebhookSubscription(input)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription, err := uc.subscriptions.FindById(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching webhook subscription: %w", err)
+	}
+
+	subscription.Url = input.Url
+	subscription.EventTypes = input.EventTypes
+	subscription.Description = input.Description
+	if input.Secret != "" {
+		subscription.Secret = input.Secret
+	}
+	if input.IsActive != nil {
+		subscription.IsActive = *input.IsActive
+	}
+
+	if err := uc.subscriptions.Update(ctx, *subscription); err != nil {
+		return nil, fmt.Errorf("error updating webhook subscription: %w", err)
+	}
+	return subscription, nil
+}
+
+func (uc *WebhookUseCase) Delete(ctx context.Context, id string) error {
+	ctx, span := startSpan(ctx, "WebhookUseCase.Delete")
+	defer span.End()
+
+	if err := uc.subscriptions.Delete(ctx, id); err != nil {
+		return fmt.Errorf("error deleting webhook subscription: %w", err)
+	}
+	return nil
+}
+
+func (uc *WebhookUseCase) GetDeliveries(ctx context.Context, subscriptionId string, pagination domain.Pagination) ([]domain.WebhookDelivery, domain.Pagination, error) {
+	ctx, span := startSpan(ctx, "WebhookUseCase.GetDeliveries")
+	defer span.End()
+
+	deliveries, pagination, err := uc.deliveries.FindBySubscription(ctx, subscriptionId, pagination)
+	if err != nil {
+		return nil, domain.Pagination{}, fmt.Errorf("error fetching webhook deliveries: %v", err)
+	}
+	return deliveries, pagination, nil
+}
+
+// Replay agenda uma nova entrega com o mesmo evento, mantendo a original no histórico.
+func (uc *WebhookUseCase) Replay(ctx context.Context, subscriptionId string, deliveryId string) (*domain.WebhookDelivery, error) {
+	ctx, span := startSpan(ctx, "WebhookUseCase.Replay")
+	defer span.End()
+
+	original, err := uc.deliveries.FindById(ctx, deliveryId)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching webhook delivery: %v", err)
+	}
+	if original.SubscriptionId != subscriptionId {
+		return nil, domain.NewInvalidWebhookError("delivery does not belong to this subscription")
+	}
+
+	replay, err := uc.deliveries.Create(ctx, domain.WebhookDelivery{
+		SubscriptionId: original.SubscriptionId,
+		EventId:        original.EventId,
+		EventType:      original.EventType,
+		Payload:        original.Payload,
+		ReplayOf:       &original.Id,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error scheduling webhook replay: %v", err)
+	}
+	return replay, nil
+}
+
+// Enqueue registra uma entrega pendente do evento para cada assinatura ativa interessada nele.
+// As entregas são gravadas juntas e as que já existem para o eventId são ignoradas, então
+// repetir a chamada só cria as que faltam.
+func (uc *WebhookUseCase) Enqueue(ctx context.Context, eventId string, eventType string, data any) error {
+	ctx, span := startSpan(ctx, "WebhookUseCase.Enqueue")
+	defer span.End()
+
+	subscriptions, err := uc.subscriptions.FindActiveByEvent(ctx, eventType)
+	if err != nil {
+		return fmt.Errorf("error fetching webhook subscriptions for %s: %v", eventType, err)
+	}
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	event := domain.WebhookEvent{
+		Id:         eventId,
+		Type:       eventType,
+		OccurredAt: uc.now().UTC(),
+		Data:       data,
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error encoding webhook event %s: %v", eventType, err)
+	}
+
+	deliveries := make([]domain.WebhookDelivery, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		deliveries = append(deliveries, domain.WebhookDelivery{
+			SubscriptionId: subscription.Id,
+			EventId:        event.Id,
+			EventType:      eventType,
+			Payload:        payload,
+		})
+	}
+	if err := uc.deliveries.CreateForEvent(ctx, deliveries); err != nil {
+		return fmt.Errorf("error scheduling webhook deliveries: %v", err)
+	}
+
+	return nil
+}
+
+// DeliverDue envia as entregas pendentes vencidas e devolve quantas foram processadas.
+// Falhas são reagendadas com backoff exponencial até WebhookMaxAttempts tentativas.
+func (uc *WebhookUseCase) DeliverDue(ctx context.Context, limit int) (int, error) {
+	ctx, span := startSpan(ctx, "WebhookUseCase.DeliverDue")
+	defer span.End()
+
+	deliveries, err := uc.deliveries.ClaimDue(ctx, limit, webhookDeliveryLease(limit))
+	if err != nil {
+		return 0, fmt.Errorf("error claiming webhook deliveries: %v", err)
+	}
+
+	// O resultado é gravado mesmo se ctx for cancelado no shutdown: um webhook já enviado sem o
+	// registro da tentativa seria enviado de novo quando o lease expirasse.
+	recordCtx := context.WithoutCancel(ctx)
+
+	subscriptions := map[string]*domain.WebhookSubscription{}
+	processed := 0
+	for _, delivery := range deliveries {
+		// As entregas que não chegaram a ser enviadas voltam para a fila quando o lease expirar
+		if ctx.Err() != nil {
+			break
+		}
+
+		subscription, ok := subscriptions[delivery.SubscriptionId]
+		if !ok {
+			subscription, err = uc.subscriptions.FindById(ctx, delivery.SubscriptionId)
+			var notFoundErr *domain.WebhookSubscriptionNotFoundError
+			if errors.As(err, &notFoundErr) {
+				subscription = nil
+			} else if err != nil {
+				return 0, fmt.Errorf("error fetching webhook subscription: %v", err)
+			}
+			subscriptions[delivery.SubscriptionId] = subscription
+		}
+
+		var attempt domain.WebhookDeliveryAttempt
+		if subscription == nil || !subscription.IsActive {
+			message := "webhook subscription is inactive or was deleted"
+			attempt = domain.WebhookDeliveryAttempt{Error: &message}
+			if err := uc.deliveries.RecordAttempt(recordCtx, delivery.Id, domain.WebhookDeliveryFailed, attempt, 0); err != nil {
+				return 0, fmt.Errorf("error recording webhook attempt: %v", err)
+			}
+			processed++
+			continue
+		}
+
+		attempt = uc.sender.Send(ctx, *subscription, delivery)
+
+		status := domain.WebhookDeliverySucceeded
+		var retryIn time.Duration
+		if !attempt.Succeeded() {
+			attempts := delivery.Attempts + 1
+			if attempts >= domain.WebhookMaxAttempts {
+				status = domain.WebhookDeliveryFailed
+			} else {
+				status = domain.WebhookDeliveryPending
+				retryIn = domain.WebhookRetryDelay(attempts)
+			}
+		}
+
+		if err := uc.deliveries.RecordAttempt(recordCtx, delivery.Id, status, attempt, retryIn); err != nil {
+			return 0, fmt.Errorf("error recording webhook attempt: %v", err)
+		}
+		processed++
+	}
+
+	return processed, nil
+}
+
+func generateWebhookSecret() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("error generating webhook secret: %v", err)
+	}
+	return "whsec_" + hex.EncodeToString(bytes), nil
+}
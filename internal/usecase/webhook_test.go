@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deividr/zion-api/internal/domain"
+	"github.com/deividr/zion-api/internal/infra/webhook"
+)
+
+type mockWebhookSubscriptionRepository struct {
+	domain.WebhookSubscriptionRepository
+	subscriptions []domain.WebhookSubscription
+}
+
+func (m *mockWebhookSubscriptionRepository) FindActiveByEvent(ctx context.Context, eventType string) ([]domain.WebhookSubscription, error) {
+	result := []domain.WebhookSubscription{}
+	for _, subscription := range m.subscriptions {
+		if subscription.Accepts(eventType) {
+			result = append(result, subscription)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockWebhookSubscriptionRepository) FindById(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	for _, subscription := range m.subscriptions {
+		if subscription.Id == id {
+			return &subscription, nil
+		}
+	}
+	return nil, nil
+}
+
+type webhookAttemptRecord struct {
+	status  string
+	attempt domain.WebhookDeliveryAttempt
+	retryIn time.Duration
+}
+
+type mockWebhookDeliveryRepository struct {
+	domain.WebhookDeliveryRepository
+	created  []domain.WebhookDelivery
+	due      []domain.WebhookDelivery
+	attempts map[string]webhookAttemptRecord
+}
+
+func (m *mockWebhookDeliveryRepository) Create(ctx context.Context, delivery domain.WebhookDelivery) (*domain.WebhookDelivery, error) {
+	m.created = append(m.created, delivery)
+	return &delivery, nil
+}
+
+func (m *mockWebhookDeliveryRepository) CreateForEvent(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	m.created = append(m.created, deliveries...)
+	return nil
+}
+
+func (m *mockWebhookDeliveryRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	return m.due, nil
+}
+
+func (m *mockWebhookDeliveryRepository) RecordAttempt(ctx context.Context, id string, status string, attempt domain.WebhookDeliveryAttempt, retryIn time.Duration) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	m.attempts[id] = webhookAttemptRecord{status: status, attempt: attempt, retryIn: retryIn}
+	return nil
+}
+
+func TestWebhookUseCase_Enqueue(t *testing.T) {
+	t.Run("should create a delivery for each subscription interested in the event", func(t *testing.T) {
+		subscriptions := &mockWebhookSubscriptionRepository{subscriptions: []domain.WebhookSubscription{
+			{Id: "orders", IsActive: true, EventTypes: []string{domain.WebhookOrderCreated}},
+			{Id: "all", IsActive: true, EventTypes: []string{domain.WebhookAllEvents}},
+			{Id: "customers", IsActive: true, EventTypes: []string{domain.WebhookCustomerCreated}},
+			{Id: "inactive", IsActive: false, EventTypes: []string{domain.WebhookOrderCreated}},
+		}}
+		deliveries := &mockWebhookDeliveryRepository{}
+
+		uc := NewWebhookUseCase(subscriptions, deliveries, webhook.NewHTTPSender())
+		if err := uc.Enqueue(context.Background(), "job-1", domain.WebhookOrderCreated, map[string]string{"id": "order-1"}); err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+
+		if len(deliveries.created) != 2 || deliveries.created[0].SubscriptionId != "orders" || deliveries.created[1].SubscriptionId != "all" {
+			t.Fatalf("expected deliveries for orders and all, but got %+v", deliveries.created)
+		}
+
+		var event domain.WebhookEvent
+		if err := json.Unmarshal(deliveries.created[0].Payload, &event); err != nil {
+			t.Fatalf("expected payload to be a webhook event, but got %v", err)
+		}
+		if event.Type != domain.WebhookOrderCreated || event.Id != "job-1" || deliveries.created[1].EventId != "job-1" {
+			t.Errorf("expected both deliveries to share the order.created event of the job, but got %+v", event)
+		}
+	})
+}
+
+// mockWebhookSender responde pelo caminho da URL, no lugar do HTTPSender que recusa o loopback.
+type mockWebhookSender struct {
+	received int
+	// afterSend simula o shutdown chegando enquanto o webhook é enviado
+	afterSend func()
+}
+
+func (m *mockWebhookSender) Send(ctx context.Context, subscription domain.WebhookSubscription, delivery domain.WebhookDelivery) domain.WebhookDeliveryAttempt {
+	m.received++
+	if m.afterSend != nil {
+		m.afterSend()
+	}
+	statusCode := http.StatusOK
+	if strings.HasSuffix(subscription.Url, "/fail") {
+		statusCode = http.StatusInternalServerError
+	}
+	return domain.WebhookDeliveryAttempt{StatusCode: &statusCode}
+}
+
+func TestWebhookUseCase_DeliverDue(t *testing.T) {
+	receiver := "https://hooks.example.com"
+	subscriptions := &mockWebhookSubscriptionRepository{subscriptions: []domain.WebhookSubscription{
+		{Id: "ok", Url: receiver + "/ok", Secret: "s", IsActive: true},
+		{Id: "fail", Url: receiver + "/fail", Secret: "s", IsActive: true},
+		{Id: "inactive", Url: receiver + "/ok", Secret: "s", IsActive: false},
+	}}
+	payload := []byte(`{"type":"order.created"}`)
+	deliveries := &mockWebhookDeliveryRepository{
+		attempts: map[string]webhookAttemptRecord{},
+		due: []domain.WebhookDelivery{
+			{Id: "delivered", SubscriptionId: "ok", Payload: payload},
+			{Id: "retry", SubscriptionId: "fail", Payload: payload, Attempts: 2},
+			{Id: "exhausted", SubscriptionId: "fail", Payload: payload, Attempts: domain.WebhookMaxAttempts - 1},
+			{Id: "inactive", SubscriptionId: "inactive", Payload: payload},
+		},
+	}
+
+	sender := &mockWebhookSender{}
+	uc := NewWebhookUseCase(subscriptions, deliveries, sender)
+	processed, err := uc.DeliverDue(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+
+	t.Run("should process every claimed delivery", func(t *testing.T) {
+		if processed != 4 {
+			t.Errorf("expected 4 processed deliveries, but got %d", processed)
+		}
+		if sender.received != 3 {
+			t.Errorf("expected 3 requests to the receiver, but got %d", sender.received)
+		}
+	})
+
+	t.Run("should mark 2xx responses as succeeded", func(t *testing.T) {
+		if record := deliveries.attempts["delivered"]; record.status != domain.WebhookDeliverySucceeded {
+			t.Errorf("expected status succeeded, but got %s", record.status)
+		}
+	})
+
+	t.Run("should reschedule failures with exponential backoff", func(t *testing.T) {
+		record := deliveries.attempts["retry"]
+		if record.status != domain.WebhookDeliveryPending {
+			t.Errorf("expected status pending, but got %s", record.status)
+		}
+		if record.retryIn != domain.WebhookRetryDelay(3) {
+			t.Errorf("expected retry in %s, but got %s", domain.WebhookRetryDelay(3), record.retryIn)
+		}
+		if record.attempt.StatusCode == nil || *record.attempt.StatusCode != http.StatusInternalServerError {
+			t.Errorf("expected status code 500 to be recorded, but got %v", record.attempt.StatusCode)
+		}
+	})
+
+	t.Run("should give up after the maximum number of attempts", func(t *testing.T) {
+		if record := deliveries.attempts["exhausted"]; record.status != domain.WebhookDeliveryFailed {
+			t.Errorf("expected status failed, but got %s", record.status)
+		}
+	})
+
+	t.Run("should fail deliveries of inactive subscriptions without sending", func(t *testing.T) {
+		if record := deliveries.attempts["inactive"]; record.status != domain.WebhookDeliveryFailed || record.attempt.StatusCode != nil {
+			t.Errorf("expected failed status without request, but got %+v", record)
+		}
+	})
+}
+
+func TestWebhookUseCase_DeliverDueShutdown(t *testing.T) {
+	t.Run("should record the sent webhook and stop when the context is cancelled", func(t *testing.T) {
+		subscriptions := &mockWebhookSubscriptionRepository{subscriptions: []domain.WebhookSubscription{
+			{Id: "ok", Url: "https://hooks.example.com/ok", Secret: "s", IsActive: true},
+		}}
+		deliveries := &mockWebhookDeliveryRepository{
+			attempts: map[string]webhookAttemptRecord{},
+			due: []domain.WebhookDelivery{
+				{Id: "sent", SubscriptionId: "ok"},
+				{Id: "not-sent", SubscriptionId: "ok"},
+			},
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		sender := &mockWebhookSender{afterSend: cancel}
+
+		processed, err := NewWebhookUseCase(subscriptions, deliveries, sender).DeliverDue(ctx, 10)
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if record, ok := deliveries.attempts["sent"]; !ok || record.status != domain.WebhookDeliverySucceeded {
+			t.Errorf("expected the sent webhook to be recorded as succeeded, but got %+v", deliveries.attempts)
+		}
+		if processed != 1 || sender.received != 1 {
+			t.Errorf("expected only the first delivery to be sent, but got %d processed and %d sent", processed, sender.received)
+		}
+	})
+}